@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,6 +20,8 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/apmserver"
 	asesassn "github.com/elastic/cloud-on-k8s/pkg/controller/apmserverelasticsearchassociation"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/namespaces"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
 	controllerscheme "github.com/elastic/cloud-on-k8s/pkg/controller/common/scheme"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch"
@@ -26,19 +29,21 @@ import (
 	kbassn "github.com/elastic/cloud-on-k8s/pkg/controller/kibanaassociation"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/license"
 	licensetrial "github.com/elastic/cloud-on-k8s/pkg/controller/license/trial"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/remotecluster"
 	"github.com/elastic/cloud-on-k8s/pkg/dev"
 	"github.com/elastic/cloud-on-k8s/pkg/dev/portforward"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/net"
-
-	// TODO (sabo): re-enable when webhooks are usable
-	// "github.com/elastic/cloud-on-k8s/pkg/webhook"
+	"github.com/elastic/cloud-on-k8s/pkg/webhook"
 
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
 )
@@ -49,18 +54,38 @@ const (
 
 	AutoPortForwardFlagName = "auto-port-forward"
 	NamespaceFlagName       = "namespace"
+	NamespacesFlag          = "namespaces"
+	NamespaceSelectorFlag   = "namespace-selector"
+	OperatorNameFlag        = "operator-name"
+	ResourceSelectorFlag    = "resource-selector"
+
+	DefaultOperatorName = "elastic-operator"
 
 	CACertValidityFlag     = "ca-cert-validity"
 	CACertRotateBeforeFlag = "ca-cert-rotate-before"
 	CertValidityFlag       = "cert-validity"
 	CertRotateBeforeFlag   = "cert-rotate-before"
+	KeyAlgorithmFlag       = "key-algorithm"
+	KeySizeFlag            = "key-size"
 
 	AutoInstallWebhooksFlag = "auto-install-webhooks"
 	OperatorNamespaceFlag   = "operator-namespace"
 	WebhookSecretFlag       = "webhook-secret"
 	WebhookPodsLabelFlag    = "webhook-pods-label"
+	WebhookCertDirFlag      = "webhook-cert-dir"
 
 	DebugHTTPServerListenAddressFlag = "debug-http-listen"
+
+	EnableLeaderElectionFlag        = "enable-leader-election"
+	LeaderElectionNamespaceFlag     = "leader-election-namespace"
+	LeaderElectionIDFlag            = "leader-election-id"
+	LeaderElectionLeaseDurationFlag = "leader-election-lease-duration"
+	LeaderElectionRenewDeadlineFlag = "leader-election-renew-deadline"
+	LeaderElectionRetryPeriodFlag   = "leader-election-retry-period"
+
+	DefaultLeaderElectionLeaseDuration = 15 * time.Second
+	DefaultLeaderElectionRenewDeadline = 10 * time.Second
+	DefaultLeaderElectionRetryPeriod   = 2 * time.Second
 )
 
 var (
@@ -85,6 +110,30 @@ func init() {
 		"",
 		"namespace in which this operator should manage resources (defaults to all namespaces)",
 	)
+	if err := Cmd.Flags().MarkDeprecated(NamespaceFlagName, "use --"+NamespacesFlag+" instead"); err != nil {
+		log.Error(err, "Unexpected error while deprecating flags")
+		os.Exit(1)
+	}
+	Cmd.Flags().StringSlice(
+		NamespacesFlag,
+		[]string{},
+		"comma separated list of namespaces in which this operator should manage resources (defaults to all namespaces)",
+	)
+	Cmd.Flags().String(
+		NamespaceSelectorFlag,
+		"",
+		"label selector of additional namespaces in which this operator should manage resources, watched for changes",
+	)
+	Cmd.Flags().String(
+		OperatorNameFlag,
+		DefaultOperatorName,
+		"name of this operator instance, reported in the status of the resources it reconciles",
+	)
+	Cmd.Flags().String(
+		ResourceSelectorFlag,
+		"",
+		"label selector of the Elasticsearch, Kibana and APM Server resources this operator should reconcile (defaults to all resources)",
+	)
 	Cmd.Flags().Bool(
 		AutoPortForwardFlagName,
 		false,
@@ -121,6 +170,16 @@ func init() {
 		certificates.DefaultRotateBefore,
 		"Duration representing how long before expiration TLS certificates should be reissued",
 	)
+	Cmd.Flags().String(
+		KeyAlgorithmFlag,
+		certificates.DefaultKeyParams.Algorithm,
+		"Algorithm of the private keys generated for CA and TLS certificates (either rsa or ecdsa)",
+	)
+	Cmd.Flags().Int(
+		KeySizeFlag,
+		0,
+		"Size of the private keys generated for CA and TLS certificates: 2048, 3072 or 4096 for rsa, 256 or 384 for ecdsa (defaults to 2048 for rsa, 256 for ecdsa)",
+	)
 	Cmd.Flags().Bool(
 		AutoInstallWebhooksFlag,
 		true,
@@ -139,7 +198,42 @@ func init() {
 	Cmd.Flags().String(
 		WebhookSecretFlag,
 		"",
-		"k8s secret mounted into /tmp/cert to be used for webhook certificates",
+		"k8s secret in the operator namespace where the operator stores the webhook certificates",
+	)
+	Cmd.Flags().String(
+		WebhookCertDirFlag,
+		webhook.DefaultCertDir,
+		"directory the webhook server reads its certificate and private key from",
+	)
+	Cmd.Flags().Bool(
+		EnableLeaderElectionFlag,
+		true,
+		"enables leader election, so that a single operator replica reconciles resources at a time",
+	)
+	Cmd.Flags().String(
+		LeaderElectionNamespaceFlag,
+		"",
+		"k8s namespace of the config map holding the leader election lock (defaults to the operator namespace)",
+	)
+	Cmd.Flags().String(
+		LeaderElectionIDFlag,
+		"",
+		"name of the config map holding the leader election lock (defaults to a name derived from the operator name, roles and managed namespaces)",
+	)
+	Cmd.Flags().Duration(
+		LeaderElectionLeaseDurationFlag,
+		DefaultLeaderElectionLeaseDuration,
+		"Duration non-leader replicas wait before acquiring a lease that was not renewed",
+	)
+	Cmd.Flags().Duration(
+		LeaderElectionRenewDeadlineFlag,
+		DefaultLeaderElectionRenewDeadline,
+		"Duration during which the leader retries to renew its lease before stopping to reconcile resources",
+	)
+	Cmd.Flags().Duration(
+		LeaderElectionRetryPeriodFlag,
+		DefaultLeaderElectionRetryPeriod,
+		"Duration between two attempts to acquire or renew the lease",
 	)
 	Cmd.Flags().String(
 		DebugHTTPServerListenAddressFlag,
@@ -209,13 +303,39 @@ func execute() {
 		os.Exit(1)
 	}
 
+	// Validate the roles and name before using them to name the leader election lock
+	roles := viper.GetStringSlice(operator.RoleFlag)
+	err = operator.ValidateRoles(roles)
+	if err != nil {
+		log.Error(err, "invalid roles specified")
+		os.Exit(1)
+	}
+	operatorName, resourceSelector := ValidateShardingFlags(OperatorNameFlag, ResourceSelectorFlag)
+
+	// Setup a client to set the operator uuid config map and review the permissions of the operator
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		log.Error(err, "unable to create k8s clientset")
+		os.Exit(1)
+	}
+
 	// Create a new Cmd to provide shared dependencies and start components
 	log.Info("Setting up manager")
 	opts := ctrl.Options{
 		Scheme: clientgoscheme.Scheme,
-		// restrict the operator to watch resources within a single namespace, unless empty
-		Namespace: viper.GetString(NamespaceFlagName),
 	}
+	managedNamespaces := ValidateNamespacesFlags(NamespaceFlagName, NamespacesFlag, NamespaceSelectorFlag)
+	if !managedNamespaces.AllNamespaces() {
+		// restrict the operator to watch resources within the managed namespaces
+		var checkPermissions namespaces.PermissionChecker
+		if operator.HasRole(operator.NamespaceOperator, roles) {
+			checkPermissions = namespaces.NewPermissionChecker(clientset)
+		}
+		opts.NewCache = namespaces.NewCacheFunc(managedNamespaces, checkPermissions)
+		log.Info("Restricting the operator to namespaces", "namespaces", managedNamespaces.Namespaces,
+			"namespace_selector", viper.GetString(NamespaceSelectorFlag))
+	}
+	setLeaderElectionOptions(&opts, operatorNamespace, operatorName, roles, managedNamespaces)
 
 	// only expose prometheus metrics if provided a non-zero port
 	metricsPort := viper.GetInt(MetricsPortFlag)
@@ -233,21 +353,7 @@ func execute() {
 	// Verify cert validity options
 	caCertValidity, caCertRotateBefore := ValidateCertExpirationFlags(CACertValidityFlag, CACertRotateBeforeFlag)
 	certValidity, certRotateBefore := ValidateCertExpirationFlags(CertValidityFlag, CertRotateBeforeFlag)
-
-	// Setup all Controllers
-	roles := viper.GetStringSlice(operator.RoleFlag)
-	err = operator.ValidateRoles(roles)
-	if err != nil {
-		log.Error(err, "invalid roles specified")
-		os.Exit(1)
-	}
-
-	// Setup a client to set the operator uuid config map
-	clientset, err := kubernetes.NewForConfig(cfg)
-	if err != nil {
-		log.Error(err, "unable to create k8s clientset")
-		os.Exit(1)
-	}
+	keyParams := ValidateKeyFlags(KeyAlgorithmFlag, KeySizeFlag)
 
 	operatorInfo, err := about.GetOperatorInfo(clientset, operatorNamespace, roles)
 	if err != nil {
@@ -267,6 +373,9 @@ func execute() {
 			Validity:     certValidity,
 			RotateBefore: certRotateBefore,
 		},
+		KeyParams:        keyParams,
+		Name:             operatorName,
+		ResourceSelector: resourceSelector,
 	}
 
 	if operator.HasRole(operator.NamespaceOperator, roles) {
@@ -300,44 +409,155 @@ func execute() {
 			log.Error(err, "unable to create controller", "controller", "LicenseTrial")
 			os.Exit(1)
 		}
+		if err = remotecluster.Add(mgr, params); err != nil {
+			log.Error(err, "unable to create controller", "controller", "RemoteCluster")
+			os.Exit(1)
+		}
 	}
 
-	// TODO (sabo): re-enable when webhooks are usable
-	// log.Info("Setting up webhooks")
-	// if err := webhook.AddToManager(mgr, roles, newWebhookParameters); err != nil {
-	// 	log.Error(err, "unable to register webhooks to the manager")
-	// 	os.Exit(1)
-	// }
+	log.Info("Setting up webhooks")
+	if err := webhook.AddToManager(mgr, roles, func() (*webhook.Parameters, error) {
+		return newWebhookParameters(params, managedNamespaces)
+	}); err != nil {
+		log.Error(err, "unable to register webhooks to the manager")
+		os.Exit(1)
+	}
 
 	log.Info("Starting the manager", "uuid", operatorInfo.OperatorUUID,
 		"namespace", operatorNamespace, "version", operatorInfo.BuildInfo.Version,
 		"build_hash", operatorInfo.BuildInfo.Hash, "build_date", operatorInfo.BuildInfo.Date,
 		"build_snapshot", operatorInfo.BuildInfo.Snapshot)
 	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
+		// this includes the loss of the leader election lease: exit so that no reconciliation
+		// is performed concurrently with the new leader
 		log.Error(err, "unable to run the manager")
 		os.Exit(1)
 	}
 }
 
-// TODO (sabo): re-enable when webhooks are usable
-// func newWebhookParameters() (*webhook.Parameters, error) {
-// 	autoInstall := viper.GetBool(AutoInstallWebhooksFlag)
-// 	ns := viper.GetString(OperatorNamespaceFlag)
-// 	if ns == "" && autoInstall {
-// 		return nil, fmt.Errorf("%s needs to be set for webhook auto installation", OperatorNamespaceFlag)
-// 	}
-// 	svcSelector := viper.GetString(WebhookPodsLabelFlag)
-// 	sec := viper.GetString(WebhookSecretFlag)
-// 	return &webhook.Parameters{
-// 		Bootstrap: webhook.NewBootstrapOptions(webhook.BootstrapOptionsParams{
-// 			Namespace:        ns,
-// 			ManagedNamespace: viper.GetString(NamespaceFlagName),
-// 			SecretName:       sec,
-// 			ServiceSelector:  svcSelector,
-// 		}),
-// 		AutoInstall: autoInstall,
-// 	}, nil
-// }
+// setLeaderElectionOptions configures the leader election of the manager from the operator flags.
+func setLeaderElectionOptions(
+	opts *ctrl.Options,
+	operatorNamespace string,
+	operatorName string,
+	roles []string,
+	managedNamespaces namespaces.Config,
+) {
+	opts.LeaderElection = viper.GetBool(EnableLeaderElectionFlag)
+	if !opts.LeaderElection {
+		return
+	}
+	opts.LeaderElectionNamespace = viper.GetString(LeaderElectionNamespaceFlag)
+	if opts.LeaderElectionNamespace == "" {
+		opts.LeaderElectionNamespace = operatorNamespace
+	}
+	opts.LeaderElectionID = viper.GetString(LeaderElectionIDFlag)
+	if opts.LeaderElectionID == "" {
+		opts.LeaderElectionID = DefaultLeaderElectionID(operatorName, roles, managedNamespaces)
+	}
+	leaseDuration, renewDeadline, retryPeriod := ValidateLeaderElectionFlags(
+		LeaderElectionLeaseDurationFlag, LeaderElectionRenewDeadlineFlag, LeaderElectionRetryPeriodFlag,
+	)
+	opts.LeaseDuration = &leaseDuration
+	opts.RenewDeadline = &renewDeadline
+	opts.RetryPeriod = &retryPeriod
+	log.Info("Leader election enabled", "namespace", opts.LeaderElectionNamespace, "id", opts.LeaderElectionID)
+}
+
+// DefaultLeaderElectionID returns the name of the leader election lock of the replicas of an operator deployment.
+// Operators with different names, roles or managed namespaces can run in the same namespace, and must not share their lock.
+// Several managed namespaces are identified by a hash, to keep the name short.
+func DefaultLeaderElectionID(operatorName string, roles []string, managedNamespaces namespaces.Config) string {
+	sortedRoles := append([]string{}, roles...)
+	sort.Strings(sortedRoles)
+	parts := append([]string{operatorName}, sortedRoles...)
+	switch {
+	case managedNamespaces.AllNamespaces():
+	case len(managedNamespaces.Namespaces) == 1 && managedNamespaces.Selector == nil:
+		parts = append(parts, managedNamespaces.Namespaces[0])
+	default:
+		selector := ""
+		if managedNamespaces.Selector != nil {
+			selector = managedNamespaces.Selector.String()
+		}
+		parts = append(parts, hash.HashObject([]string{strings.Join(managedNamespaces.Namespaces, ","), selector}))
+	}
+	return strings.Join(append(parts, "leader"), "-")
+}
+
+// ValidateNamespacesFlags returns the namespaces managed by the operator, from the deprecated single namespace flag,
+// the namespaces flag and the namespace selector flag.
+func ValidateNamespacesFlags(namespaceFlag, namespacesFlag, selectorFlag string) namespaces.Config {
+	managedNamespaces := append([]string{viper.GetString(namespaceFlag)}, viper.GetStringSlice(namespacesFlag)...)
+	config, err := namespaces.NewConfig(managedNamespaces, viper.GetString(selectorFlag))
+	if err != nil {
+		log.Error(err, "invalid namespace selector", "flag", selectorFlag)
+		os.Exit(1)
+	}
+	return config
+}
+
+func ValidateLeaderElectionFlags(leaseDurationFlag, renewDeadlineFlag, retryPeriodFlag string) (time.Duration, time.Duration, time.Duration) {
+	leaseDuration := viper.GetDuration(leaseDurationFlag)
+	renewDeadline := viper.GetDuration(renewDeadlineFlag)
+	retryPeriod := viper.GetDuration(retryPeriodFlag)
+	if renewDeadline >= leaseDuration {
+		log.Error(fmt.Errorf("%s must be larger than %s", leaseDurationFlag, renewDeadlineFlag), "")
+		os.Exit(1)
+	}
+	if float64(renewDeadline) <= leaderelection.JitterFactor*float64(retryPeriod) {
+		log.Error(fmt.Errorf("%s must be larger than %v times %s", renewDeadlineFlag, leaderelection.JitterFactor, retryPeriodFlag), "")
+		os.Exit(1)
+	}
+	return leaseDuration, renewDeadline, retryPeriod
+}
+
+// ValidateShardingFlags returns the name of the operator instance and the selector of the resources it reconciles,
+// or nil if it reconciles all resources.
+func ValidateShardingFlags(nameFlag, selectorFlag string) (string, labels.Selector) {
+	name := viper.GetString(nameFlag)
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		log.Error(fmt.Errorf("invalid %s: %s", nameFlag, strings.Join(errs, ", ")), "")
+		os.Exit(1)
+	}
+	selector := viper.GetString(selectorFlag)
+	if selector == "" {
+		return name, nil
+	}
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		log.Error(err, "invalid resource selector", "flag", selectorFlag)
+		os.Exit(1)
+	}
+	log.Info("Restricting the operator to resources matching a selector", "resource_selector", parsed.String())
+	return name, parsed
+}
+
+func newWebhookParameters(params operator.Parameters, managedNamespaces namespaces.Config) (*webhook.Parameters, error) {
+	autoInstall := viper.GetBool(AutoInstallWebhooksFlag)
+	ns := viper.GetString(OperatorNamespaceFlag)
+	if ns == "" && autoInstall {
+		return nil, fmt.Errorf("%s needs to be set for webhook auto installation", OperatorNamespaceFlag)
+	}
+	svcSelector := viper.GetString(WebhookPodsLabelFlag)
+	if svcSelector == "" && autoInstall {
+		return nil, fmt.Errorf("%s needs to be set for webhook auto installation", WebhookPodsLabelFlag)
+	}
+	sec := viper.GetString(WebhookSecretFlag)
+	if sec == "" && autoInstall {
+		return nil, fmt.Errorf("%s needs to be set for webhook auto installation", WebhookSecretFlag)
+	}
+	return &webhook.Parameters{
+		Namespace:         ns,
+		ManagedNamespaces: managedNamespaces,
+		SecretName:        sec,
+		ServiceSelector:   svcSelector,
+		CertDir:           viper.GetString(WebhookCertDirFlag),
+		AutoInstall:       autoInstall,
+		CertRotation:      params.CertRotation,
+		KeyParams:         params.KeyParams,
+	}, nil
+}
 
 func ValidateCertExpirationFlags(validityFlag string, rotateBeforeFlag string) (time.Duration, time.Duration) {
 	certValidity := viper.GetDuration(validityFlag)
@@ -348,3 +568,12 @@ func ValidateCertExpirationFlags(validityFlag string, rotateBeforeFlag string) (
 	}
 	return certValidity, certRotateBefore
 }
+
+func ValidateKeyFlags(algorithmFlag string, sizeFlag string) certificates.KeyParams {
+	keyParams := certificates.DefaultKeyParams.Override(viper.GetString(algorithmFlag), viper.GetInt(sizeFlag))
+	if err := keyParams.Validate(); err != nil {
+		log.Error(err, "invalid private key flags", "flags", []string{algorithmFlag, sizeFlag})
+		os.Exit(1)
+	}
+	return keyParams
+}
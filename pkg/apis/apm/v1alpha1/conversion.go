@@ -0,0 +1,82 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1alpha1
+
+import (
+	"github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1beta1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1alpha1"
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Convertible = &ApmServer{}
+
+// apmServerConversionData holds the fields of a v1beta1 ApmServer missing from v1alpha1.
+type apmServerConversionData struct {
+	HTTPTLS *commonv1alpha1.TLSConversionData `json:"httpTLS,omitempty"`
+
+	HTTPStatus         *commonv1beta1.HTTPStatus         `json:"httpStatus,omitempty"`
+	CertificatesStatus *commonv1beta1.CertificatesStatus `json:"certificatesStatus,omitempty"`
+}
+
+// ConvertTo converts this ApmServer to the v1beta1 hub version.
+func (as *ApmServer) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.ApmServer)
+	dst.ObjectMeta = *as.ObjectMeta.DeepCopy()
+	var data apmServerConversionData
+	if err := commonv1alpha1.PopConversionData(&dst.ObjectMeta, &data); err != nil {
+		return err
+	}
+
+	dst.Spec = v1beta1.ApmServerSpec{
+		Version:          as.Spec.Version,
+		Image:            as.Spec.Image,
+		Count:            as.Spec.NodeCount,
+		Config:           commonv1alpha1.ConvertConfigTo(as.Spec.Config),
+		HTTP:             commonv1alpha1.ConvertHTTPConfigTo(as.Spec.HTTP, data.HTTPTLS),
+		ElasticsearchRef: commonv1beta1.ObjectSelector(as.Spec.ElasticsearchRef),
+		PodTemplate:      *as.Spec.PodTemplate.DeepCopy(),
+		SecureSettings:   commonv1alpha1.ConvertSecretSourcesTo(as.Spec.SecureSettings),
+	}
+	dst.Status = v1beta1.ApmServerStatus{
+		ReconcilerStatus:      commonv1beta1.ReconcilerStatus(as.Status.ReconcilerStatus),
+		Health:                v1beta1.ApmServerHealth(as.Status.Health),
+		ExternalService:       as.Status.ExternalService,
+		SecretTokenSecretName: as.Status.SecretTokenSecretName,
+		Association:           commonv1beta1.AssociationStatus(as.Status.Association),
+		HTTP:                  data.HTTPStatus,
+		Certificates:          data.CertificatesStatus,
+	}
+	return nil
+}
+
+// ConvertFrom converts the given v1beta1 hub version to this ApmServer.
+func (as *ApmServer) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.ApmServer)
+	as.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	data := apmServerConversionData{
+		HTTPStatus:         src.Status.HTTP.DeepCopy(),
+		CertificatesStatus: src.Status.Certificates.DeepCopy(),
+	}
+	as.Spec = ApmServerSpec{
+		Version:          src.Spec.Version,
+		Image:            src.Spec.Image,
+		NodeCount:        src.Spec.Count,
+		Config:           commonv1alpha1.ConvertConfigFrom(src.Spec.Config),
+		ElasticsearchRef: commonv1alpha1.ObjectSelector(src.Spec.ElasticsearchRef),
+		PodTemplate:      *src.Spec.PodTemplate.DeepCopy(),
+		SecureSettings:   commonv1alpha1.ConvertSecretSourcesFrom(src.Spec.SecureSettings),
+	}
+	as.Spec.HTTP, data.HTTPTLS = commonv1alpha1.ConvertHTTPConfigFrom(src.Spec.HTTP)
+	as.Status = ApmServerStatus{
+		ReconcilerStatus:      commonv1alpha1.ReconcilerStatus(src.Status.ReconcilerStatus),
+		Health:                ApmServerHealth(src.Status.Health),
+		ExternalService:       src.Status.ExternalService,
+		SecretTokenSecretName: src.Status.SecretTokenSecretName,
+		Association:           commonv1alpha1.AssociationStatus(src.Status.Association),
+	}
+	return commonv1alpha1.SetConversionData(&as.ObjectMeta, data)
+}
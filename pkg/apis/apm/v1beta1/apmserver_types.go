@@ -10,7 +10,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-const APMServerContainerName = "apm-server"
+const (
+	APMServerContainerName = "apm-server"
+	// Kind is the kind of the resource, as registered in the scheme from the name of its struct.
+	Kind = "ApmServer"
+)
 
 // ApmServerSpec defines the desired state of ApmServer
 type ApmServerSpec struct {
@@ -69,6 +73,10 @@ type ApmServerStatus struct {
 	SecretTokenSecretName string `json:"secretTokenSecret,omitempty"`
 	// Association is the status of any auto-linking to Elasticsearch clusters.
 	Association commonv1beta1.AssociationStatus `json:"associationStatus,omitempty"`
+	// HTTP reports the state of the HTTP layer.
+	HTTP *commonv1beta1.HTTPStatus `json:"http,omitempty"`
+	// Certificates reports when the certificates expire.
+	Certificates *commonv1beta1.CertificatesStatus `json:"certificates,omitempty"`
 }
 
 // IsDegraded returns true if the current status is worse than the previous.
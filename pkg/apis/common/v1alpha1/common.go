@@ -14,9 +14,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-// ReconcilerStatus represents status information about desired/available nodes.
+// ReconcilerStatus represents status information about desired/available nodes, and the operator reconciling them.
 type ReconcilerStatus struct {
 	AvailableNodes int `json:"availableNodes,omitempty"`
+	// Operator is the name of the operator instance reconciling the resource.
+	Operator string `json:"operator,omitempty"`
 }
 
 // SecretRef reference a secret by name.
@@ -0,0 +1,169 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"reflect"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConversionDataAnnotation is the annotation of a v1alpha1 resource storing the fields of its v1beta1 version
+// that v1alpha1 cannot represent, so they are restored when the resource is converted back to v1beta1.
+const ConversionDataAnnotation = "common.k8s.elastic.co/conversion-data"
+
+// SetConversionData stores the given data in the conversion annotation of the given metadata, unless it is empty.
+func SetConversionData(meta *metav1.ObjectMeta, data interface{}) error {
+	if reflect.ValueOf(data).IsZero() {
+		return nil
+	}
+	serialized, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string, 1)
+	}
+	meta.Annotations[ConversionDataAnnotation] = string(serialized)
+	return nil
+}
+
+// PopConversionData removes the conversion annotation from the given metadata, and unmarshals its content in data.
+// Data is left unchanged if there is no conversion annotation.
+func PopConversionData(meta *metav1.ObjectMeta, data interface{}) error {
+	serialized, exists := meta.Annotations[ConversionDataAnnotation]
+	if !exists {
+		return nil
+	}
+	delete(meta.Annotations, ConversionDataAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	return json.Unmarshal([]byte(serialized), data)
+}
+
+// TLSConversionData holds the v1beta1 TLS options missing from v1alpha1.
+type TLSConversionData struct {
+	IssuerRef    *commonv1beta1.IssuerRef `json:"issuerRef,omitempty"`
+	KeyAlgorithm string                   `json:"keyAlgorithm,omitempty"`
+	KeySize      int                      `json:"keySize,omitempty"`
+}
+
+// ConvertHTTPConfigTo converts the given HTTP configuration to v1beta1, restoring the TLS options of the given
+// conversion data.
+func ConvertHTTPConfigTo(in HTTPConfig, data *TLSConversionData) commonv1beta1.HTTPConfig {
+	out := commonv1beta1.HTTPConfig{
+		Service: commonv1beta1.ServiceTemplate(*in.Service.DeepCopy()),
+		TLS: commonv1beta1.TLSOptions{
+			Certificate: commonv1beta1.SecretRef(in.TLS.Certificate),
+		},
+	}
+	if selfSigned := in.TLS.SelfSignedCertificate; selfSigned != nil {
+		out.TLS.SelfSignedCertificate = &commonv1beta1.SelfSignedCertificate{Disabled: selfSigned.Disabled}
+		for _, san := range selfSigned.SubjectAlternativeNames {
+			out.TLS.SelfSignedCertificate.SubjectAlternativeNames = append(
+				out.TLS.SelfSignedCertificate.SubjectAlternativeNames, commonv1beta1.SubjectAlternativeName(san),
+			)
+		}
+	}
+	if data == nil {
+		return out
+	}
+	out.TLS.IssuerRef = data.IssuerRef.DeepCopy()
+	if data.KeyAlgorithm != "" || data.KeySize != 0 {
+		if out.TLS.SelfSignedCertificate == nil {
+			out.TLS.SelfSignedCertificate = &commonv1beta1.SelfSignedCertificate{}
+		}
+		out.TLS.SelfSignedCertificate.KeyAlgorithm = data.KeyAlgorithm
+		out.TLS.SelfSignedCertificate.KeySize = data.KeySize
+	}
+	return out
+}
+
+// ConvertHTTPConfigFrom converts the given v1beta1 HTTP configuration, and returns the TLS options v1alpha1 cannot
+// represent, or nil if there is none.
+func ConvertHTTPConfigFrom(in commonv1beta1.HTTPConfig) (HTTPConfig, *TLSConversionData) {
+	out := HTTPConfig{
+		Service: ServiceTemplate(*in.Service.DeepCopy()),
+		TLS: TLSOptions{
+			Certificate: SecretRef(in.TLS.Certificate),
+		},
+	}
+	data := TLSConversionData{IssuerRef: in.TLS.IssuerRef.DeepCopy()}
+	if selfSigned := in.TLS.SelfSignedCertificate; selfSigned != nil {
+		out.TLS.SelfSignedCertificate = &SelfSignedCertificate{Disabled: selfSigned.Disabled}
+		for _, san := range selfSigned.SubjectAlternativeNames {
+			out.TLS.SelfSignedCertificate.SubjectAlternativeNames = append(
+				out.TLS.SelfSignedCertificate.SubjectAlternativeNames, SubjectAlternativeName(san),
+			)
+		}
+		data.KeyAlgorithm = selfSigned.KeyAlgorithm
+		data.KeySize = selfSigned.KeySize
+	}
+	if data == (TLSConversionData{}) {
+		return out, nil
+	}
+	return out, &data
+}
+
+// ConvertConfigTo converts the given configuration to v1beta1.
+func ConvertConfigTo(in *Config) *commonv1beta1.Config {
+	if in == nil {
+		return nil
+	}
+	return &commonv1beta1.Config{Data: in.DeepCopy().Data}
+}
+
+// ConvertConfigFrom converts the given v1beta1 configuration.
+func ConvertConfigFrom(in *commonv1beta1.Config) *Config {
+	if in == nil {
+		return nil
+	}
+	return &Config{Data: in.DeepCopy().Data}
+}
+
+// ConvertSecretSourcesTo converts the given secret sources to v1beta1.
+func ConvertSecretSourcesTo(in []SecretSource) []commonv1beta1.SecretSource {
+	if in == nil {
+		return nil
+	}
+	out := make([]commonv1beta1.SecretSource, 0, len(in))
+	for _, source := range in {
+		converted := commonv1beta1.SecretSource{SecretName: source.SecretName}
+		for _, entry := range source.Entries {
+			converted.Entries = append(converted.Entries, commonv1beta1.KeyToPath(entry))
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+// ConvertSecretSourcesFrom converts the given v1beta1 secret sources.
+func ConvertSecretSourcesFrom(in []commonv1beta1.SecretSource) []SecretSource {
+	if in == nil {
+		return nil
+	}
+	out := make([]SecretSource, 0, len(in))
+	for _, source := range in {
+		converted := SecretSource{SecretName: source.SecretName}
+		for _, entry := range source.Entries {
+			converted.Entries = append(converted.Entries, KeyToPath(entry))
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+// ConvertPodDisruptionBudgetTo converts the given pod disruption budget template to v1beta1.
+func ConvertPodDisruptionBudgetTo(in *PodDisruptionBudgetTemplate) *commonv1beta1.PodDisruptionBudgetTemplate {
+	return (*commonv1beta1.PodDisruptionBudgetTemplate)(in.DeepCopy())
+}
+
+// ConvertPodDisruptionBudgetFrom converts the given v1beta1 pod disruption budget template.
+func ConvertPodDisruptionBudgetFrom(in *commonv1beta1.PodDisruptionBudgetTemplate) *PodDisruptionBudgetTemplate {
+	return (*PodDisruptionBudgetTemplate)(in.DeepCopy())
+}
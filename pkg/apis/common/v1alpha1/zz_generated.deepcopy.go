@@ -8,7 +8,9 @@
 
 package v1alpha1
 
-import ()
+import (
+	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+)
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AssociationConf) DeepCopyInto(out *AssociationConf) {
@@ -201,6 +203,26 @@ func (in *SubjectAlternativeName) DeepCopy() *SubjectAlternativeName {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConversionData) DeepCopyInto(out *TLSConversionData) {
+	*out = *in
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(v1beta1.IssuerRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConversionData.
+func (in *TLSConversionData) DeepCopy() *TLSConversionData {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConversionData)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSOptions) DeepCopyInto(out *TLSOptions) {
 	*out = *in
@@ -14,9 +14,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-// ReconcilerStatus represents status information about desired/available nodes.
+// ReconcilerStatus represents status information about desired/available nodes, and the operator reconciling them.
 type ReconcilerStatus struct {
 	AvailableNodes int `json:"availableNodes,omitempty"`
+	// Operator is the name of the operator instance reconciling the resource.
+	Operator string `json:"operator,omitempty"`
 }
 
 // SecretRef reference a secret by name.
@@ -73,12 +75,49 @@ type TLSOptions struct {
 	// - `tls.crt`: The certificate (or a chain).
 	// - `tls.key`: The private key to the first certificate in the certificate chain.
 	Certificate SecretRef `json:"certificate,omitempty"`
+
+	// IssuerRef is a reference to a cert-manager issuer. If set, the operator requests the certificate from cert-manager
+	// through a Certificate resource, instead of issuing a self-signed certificate.
+	IssuerRef *IssuerRef `json:"issuerRef,omitempty"`
 }
 
 // Enabled returns true when TLS is enabled based on this option struct.
 func (tls TLSOptions) Enabled() bool {
 	selfSigned := tls.SelfSignedCertificate
-	return selfSigned == nil || !selfSigned.Disabled || tls.Certificate.SecretName != ""
+	return selfSigned == nil || !selfSigned.Disabled || tls.Certificate.SecretName != "" || tls.IssuerRef != nil
+}
+
+// HTTPStatus reports the state of the HTTP layer.
+type HTTPStatus struct {
+	// CertificateFingerprint is the SHA-256 fingerprint of the HTTP certificate currently served.
+	CertificateFingerprint string `json:"certificateFingerprint,omitempty"`
+}
+
+// CertificatesStatus reports when the certificates of a resource expire.
+type CertificatesStatus struct {
+	// HTTP reports when the HTTP certificates expire.
+	HTTP *CertificateExpiration `json:"http,omitempty"`
+	// Transport reports when the transport certificates expire, for Elasticsearch only.
+	Transport *CertificateExpiration `json:"transport,omitempty"`
+}
+
+// CertificateExpiration reports when a CA and the certificates it signed expire.
+type CertificateExpiration struct {
+	// CANotAfter is the expiration date of the CA certificate.
+	CANotAfter *metav1.Time `json:"caNotAfter,omitempty"`
+	// NotAfter is the expiration date of the leaf certificate expiring first.
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+}
+
+// IssuerRef is a reference to a cert-manager Issuer or ClusterIssuer.
+type IssuerRef struct {
+	// Name of the issuer.
+	Name string `json:"name"`
+	// Kind of the issuer: Issuer, in the namespace of the resource, or ClusterIssuer. Defaults to Issuer.
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	Kind string `json:"kind,omitempty"`
+	// Group of the issuer. Defaults to cert-manager.io.
+	Group string `json:"group,omitempty"`
 }
 
 type SelfSignedCertificate struct {
@@ -87,6 +126,14 @@ type SelfSignedCertificate struct {
 	SubjectAlternativeNames []SubjectAlternativeName `json:"subjectAltNames,omitempty"`
 	// Disabled turns off the provisioning of self-signed HTTP TLS certificates.
 	Disabled bool `json:"disabled,omitempty"`
+	// KeyAlgorithm is the algorithm of the private keys generated for the self-signed CA and certificate.
+	// Defaults to the algorithm configured in the operator.
+	// +kubebuilder:validation:Enum=rsa;ecdsa
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty"`
+	// KeySize is the size of the generated private keys: 2048, 3072 or 4096 bits for RSA, 256 or 384 bits for ECDSA.
+	// Defaults to the size configured in the operator, or to the default size of KeyAlgorithm if it is set.
+	// +kubebuilder:validation:Enum=256;384;2048;3072;4096
+	KeySize int `json:"keySize,omitempty"`
 }
 
 type SubjectAlternativeName struct {
@@ -0,0 +1,192 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1alpha1"
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Convertible = &Elasticsearch{}
+
+// elasticsearchConversionData holds the fields of a v1beta1 Elasticsearch missing from v1alpha1.
+type elasticsearchConversionData struct {
+	HTTPTLS        *commonv1alpha1.TLSConversionData  `json:"httpTLS,omitempty"`
+	Transport      *v1beta1.TransportConfig           `json:"transport,omitempty"`
+	NodeAttributes map[string][]v1beta1.NodeAttribute `json:"nodeAttributes,omitempty"`
+	UpdateStrategy *v1beta1.UpdateStrategy            `json:"updateStrategy,omitempty"`
+	Snapshot       *v1beta1.SnapshotSpec              `json:"snapshot,omitempty"`
+	Restore        *v1beta1.SnapshotRestore           `json:"restore,omitempty"`
+	ZoneAwareness  *v1beta1.ZoneAwareness             `json:"zoneAwareness,omitempty"`
+	IndexLifecycle *v1beta1.IndexLifecycleSpec        `json:"indexLifecycle,omitempty"`
+	Security       *v1beta1.SecuritySpec              `json:"security,omitempty"`
+	RemoteClusters []v1beta1.RemoteCluster            `json:"remoteClusters,omitempty"`
+
+	SnapshotStatus        *v1beta1.SnapshotStatus           `json:"snapshotStatus,omitempty"`
+	IndexLifecycleStatus  *v1beta1.IndexLifecycleStatus     `json:"indexLifecycleStatus,omitempty"`
+	SecurityStatus        *v1beta1.SecurityStatus           `json:"securityStatus,omitempty"`
+	RemoteClustersStatus  *v1beta1.RemoteClustersStatus     `json:"remoteClustersStatus,omitempty"`
+	TransportStatus       *v1beta1.TransportStatus          `json:"transportStatus,omitempty"`
+	HTTPStatus            *commonv1beta1.HTTPStatus         `json:"httpStatus,omitempty"`
+	CertificatesStatus    *commonv1beta1.CertificatesStatus `json:"certificatesStatus,omitempty"`
+	VolumeExpansionStatus *v1beta1.VolumeExpansionStatus    `json:"volumeExpansionStatus,omitempty"`
+}
+
+// ConvertTo converts this Elasticsearch to the v1beta1 hub version. Nodes are converted to node sets.
+func (es *Elasticsearch) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.Elasticsearch)
+	dst.ObjectMeta = *es.ObjectMeta.DeepCopy()
+	var data elasticsearchConversionData
+	if err := commonv1alpha1.PopConversionData(&dst.ObjectMeta, &data); err != nil {
+		return err
+	}
+
+	dst.Spec = v1beta1.ElasticsearchSpec{
+		Version:             es.Spec.Version,
+		Image:               es.Spec.Image,
+		HTTP:                commonv1alpha1.ConvertHTTPConfigTo(es.Spec.HTTP, data.HTTPTLS),
+		UpdateStrategy:      convertUpdateStrategyTo(es.Spec.UpdateStrategy, data.UpdateStrategy),
+		PodDisruptionBudget: commonv1alpha1.ConvertPodDisruptionBudgetTo(es.Spec.PodDisruptionBudget),
+		SecureSettings:      commonv1alpha1.ConvertSecretSourcesTo(es.Spec.SecureSettings),
+		Snapshot:            data.Snapshot,
+		Restore:             data.Restore,
+		ZoneAwareness:       data.ZoneAwareness,
+		IndexLifecycle:      data.IndexLifecycle,
+		Security:            data.Security,
+		RemoteClusters:      data.RemoteClusters,
+	}
+	if data.Transport != nil {
+		dst.Spec.Transport = *data.Transport
+	}
+	for _, node := range es.Spec.Nodes {
+		dst.Spec.NodeSets = append(dst.Spec.NodeSets, v1beta1.NodeSet{
+			Name:                 node.Name,
+			Config:               commonv1alpha1.ConvertConfigTo(node.Config),
+			Count:                node.NodeCount,
+			PodTemplate:          *node.PodTemplate.DeepCopy(),
+			VolumeClaimTemplates: node.DeepCopy().VolumeClaimTemplates,
+			NodeAttributes:       data.NodeAttributes[node.Name],
+		})
+	}
+	dst.Status = v1beta1.ElasticsearchStatus{
+		ReconcilerStatus: commonv1beta1.ReconcilerStatus(es.Status.ReconcilerStatus),
+		Health:           v1beta1.ElasticsearchHealth(es.Status.Health),
+		Phase:            v1beta1.ElasticsearchOrchestrationPhase(es.Status.Phase),
+		Snapshot:         data.SnapshotStatus,
+		IndexLifecycle:   data.IndexLifecycleStatus,
+		Security:         data.SecurityStatus,
+		RemoteClusters:   data.RemoteClustersStatus,
+		Transport:        data.TransportStatus,
+		HTTP:             data.HTTPStatus,
+		Certificates:     data.CertificatesStatus,
+		VolumeExpansion:  data.VolumeExpansionStatus,
+	}
+	return nil
+}
+
+// ConvertFrom converts the given v1beta1 hub version to this Elasticsearch. Node sets are converted to nodes.
+func (es *Elasticsearch) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.Elasticsearch)
+	es.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	data := elasticsearchConversionData{
+		Snapshot:       src.Spec.Snapshot.DeepCopy(),
+		Restore:        src.Spec.Restore.DeepCopy(),
+		ZoneAwareness:  src.Spec.ZoneAwareness.DeepCopy(),
+		IndexLifecycle: src.Spec.IndexLifecycle.DeepCopy(),
+		Security:       src.Spec.Security.DeepCopy(),
+		RemoteClusters: src.Spec.DeepCopy().RemoteClusters,
+
+		SnapshotStatus:        src.Status.Snapshot.DeepCopy(),
+		IndexLifecycleStatus:  src.Status.IndexLifecycle.DeepCopy(),
+		SecurityStatus:        src.Status.Security.DeepCopy(),
+		RemoteClustersStatus:  src.Status.RemoteClusters.DeepCopy(),
+		TransportStatus:       src.Status.Transport.DeepCopy(),
+		HTTPStatus:            src.Status.HTTP.DeepCopy(),
+		CertificatesStatus:    src.Status.Certificates.DeepCopy(),
+		VolumeExpansionStatus: src.Status.VolumeExpansion.DeepCopy(),
+	}
+	if !reflect.DeepEqual(src.Spec.Transport, v1beta1.TransportConfig{}) {
+		data.Transport = src.Spec.Transport.DeepCopy()
+	}
+	es.Spec = ElasticsearchSpec{
+		Version:             src.Spec.Version,
+		Image:               src.Spec.Image,
+		PodDisruptionBudget: commonv1alpha1.ConvertPodDisruptionBudgetFrom(src.Spec.PodDisruptionBudget),
+		SecureSettings:      commonv1alpha1.ConvertSecretSourcesFrom(src.Spec.SecureSettings),
+	}
+	es.Spec.HTTP, data.HTTPTLS = commonv1alpha1.ConvertHTTPConfigFrom(src.Spec.HTTP)
+	es.Spec.UpdateStrategy = convertUpdateStrategyFrom(src.Spec.UpdateStrategy)
+	if !reflect.DeepEqual(convertUpdateStrategyTo(es.Spec.UpdateStrategy, nil), src.Spec.UpdateStrategy) {
+		data.UpdateStrategy = src.Spec.UpdateStrategy.DeepCopy()
+	}
+	for _, nodeSet := range src.Spec.NodeSets {
+		es.Spec.Nodes = append(es.Spec.Nodes, NodeSpec{
+			Name:                 nodeSet.Name,
+			Config:               commonv1alpha1.ConvertConfigFrom(nodeSet.Config),
+			NodeCount:            nodeSet.Count,
+			PodTemplate:          *nodeSet.PodTemplate.DeepCopy(),
+			VolumeClaimTemplates: nodeSet.DeepCopy().VolumeClaimTemplates,
+		})
+		if len(nodeSet.NodeAttributes) > 0 {
+			if data.NodeAttributes == nil {
+				data.NodeAttributes = make(map[string][]v1beta1.NodeAttribute)
+			}
+			data.NodeAttributes[nodeSet.Name] = nodeSet.DeepCopy().NodeAttributes
+		}
+	}
+	es.Status = ElasticsearchStatus{
+		ReconcilerStatus: commonv1alpha1.ReconcilerStatus(src.Status.ReconcilerStatus),
+		Health:           ElasticsearchHealth(src.Status.Health),
+		Phase:            ElasticsearchOrchestrationPhase(src.Status.Phase),
+	}
+	return commonv1alpha1.SetConversionData(&es.ObjectMeta, data)
+}
+
+// convertUpdateStrategyTo converts the given update strategy to v1beta1. The restored update strategy, if any,
+// is returned instead if it has the same change budget.
+func convertUpdateStrategyTo(in UpdateStrategy, restored *v1beta1.UpdateStrategy) v1beta1.UpdateStrategy {
+	if restored != nil && reflect.DeepEqual(convertUpdateStrategyFrom(*restored), in) {
+		return *restored.DeepCopy()
+	}
+	var out v1beta1.UpdateStrategy
+	if in.ChangeBudget != nil {
+		maxUnavailable := int32(in.ChangeBudget.MaxUnavailable)
+		maxSurge := int32(in.ChangeBudget.MaxSurge)
+		out.ChangeBudget = v1beta1.ChangeBudget{MaxUnavailable: &maxUnavailable, MaxSurge: &maxSurge}
+	}
+	if restored != nil {
+		out.Type = restored.Type
+		out.FullRestart = restored.FullRestart.DeepCopy()
+		out.DisabledPredicates = restored.DeepCopy().DisabledPredicates
+		out.Predicates = restored.DeepCopy().Predicates
+	}
+	return out
+}
+
+// convertUpdateStrategyFrom converts the change budget of the given v1beta1 update strategy.
+// Unbounded v1beta1 values are converted to -1.
+func convertUpdateStrategyFrom(in v1beta1.UpdateStrategy) UpdateStrategy {
+	if in.ChangeBudget == (v1beta1.ChangeBudget{}) {
+		return UpdateStrategy{}
+	}
+	return UpdateStrategy{
+		ChangeBudget: &ChangeBudget{
+			MaxUnavailable: changeBudgetValue(in.ChangeBudget.GetMaxUnavailableOrDefault()),
+			MaxSurge:       changeBudgetValue(in.ChangeBudget.GetMaxSurgeOrDefault()),
+		},
+	}
+}
+
+func changeBudgetValue(value *int32) int {
+	if value == nil {
+		return -1
+	}
+	return int(*value)
+}
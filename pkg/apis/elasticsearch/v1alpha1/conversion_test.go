@@ -0,0 +1,106 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1alpha1"
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestElasticsearch_ConvertTo(t *testing.T) {
+	es := Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: ElasticsearchSpec{
+			Version: "7.4.0",
+			Nodes: []NodeSpec{
+				{Name: "master", NodeCount: 3, Config: &commonv1alpha1.Config{Data: map[string]interface{}{"node.data": false}}},
+				{Name: "data", NodeCount: 2},
+			},
+			UpdateStrategy: UpdateStrategy{ChangeBudget: &ChangeBudget{MaxUnavailable: 1, MaxSurge: 2}},
+		},
+	}
+	var converted v1beta1.Elasticsearch
+	require.NoError(t, es.ConvertTo(&converted))
+
+	require.Equal(t, es.ObjectMeta, converted.ObjectMeta)
+	require.Equal(t, "7.4.0", converted.Spec.Version)
+	require.Len(t, converted.Spec.NodeSets, 2)
+	require.Equal(t, "master", converted.Spec.NodeSets[0].Name)
+	require.Equal(t, int32(3), converted.Spec.NodeSets[0].Count)
+	require.Equal(t, map[string]interface{}{"node.data": false}, converted.Spec.NodeSets[0].Config.Data)
+	require.Equal(t, "data", converted.Spec.NodeSets[1].Name)
+	require.Equal(t, int32(2), converted.Spec.NodeSets[1].Count)
+	require.Equal(t, int32(1), *converted.Spec.UpdateStrategy.ChangeBudget.MaxUnavailable)
+	require.Equal(t, int32(2), *converted.Spec.UpdateStrategy.ChangeBudget.MaxSurge)
+}
+
+func TestElasticsearch_ConvertFrom_RoundTrip(t *testing.T) {
+	maxUnavailable := int32(1)
+	es := v1beta1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: map[string]string{"a": "b"}},
+		Spec: v1beta1.ElasticsearchSpec{
+			Version: "7.4.0",
+			HTTP: commonv1beta1.HTTPConfig{TLS: commonv1beta1.TLSOptions{
+				IssuerRef:             &commonv1beta1.IssuerRef{Name: "issuer", Kind: "ClusterIssuer"},
+				SelfSignedCertificate: &commonv1beta1.SelfSignedCertificate{KeyAlgorithm: "ecdsa", KeySize: 256},
+			}},
+			NodeSets: []v1beta1.NodeSet{
+				{
+					Name:           "default",
+					Count:          3,
+					NodeAttributes: []v1beta1.NodeAttribute{{Name: "zone", NodeLabel: "failure-domain.beta.kubernetes.io/zone"}},
+				},
+			},
+			UpdateStrategy: v1beta1.UpdateStrategy{
+				Type:               v1beta1.FullRestartUpdateStrategyType,
+				DisabledPredicates: []string{"do_not_restart_healthy_node_if_not_green"},
+				ChangeBudget:       v1beta1.ChangeBudget{MaxUnavailable: &maxUnavailable},
+			},
+			ZoneAwareness:  &v1beta1.ZoneAwareness{TopologyKey: "zone"},
+			RemoteClusters: []v1beta1.RemoteCluster{{Name: "remote"}},
+		},
+		Status: v1beta1.ElasticsearchStatus{
+			ReconcilerStatus: commonv1beta1.ReconcilerStatus{AvailableNodes: 3},
+			Health:           v1beta1.ElasticsearchGreenHealth,
+			Snapshot:         &v1beta1.SnapshotStatus{LastSuccessSnapshot: "snapshot"},
+			Security:         &v1beta1.SecurityStatus{AppliedNativeUsers: map[string]string{"user": "hash"}},
+			HTTP:             &commonv1beta1.HTTPStatus{CertificateFingerprint: "fingerprint"},
+			VolumeExpansion:  &v1beta1.VolumeExpansionStatus{RecreatingStatefulSets: []string{"es-default"}},
+		},
+	}
+
+	var alpha Elasticsearch
+	require.NoError(t, alpha.ConvertFrom(es.DeepCopy()))
+	require.Len(t, alpha.Spec.Nodes, 1)
+	require.Equal(t, "default", alpha.Spec.Nodes[0].Name)
+	require.Equal(t, int32(3), alpha.Spec.Nodes[0].NodeCount)
+	require.Equal(t, "b", alpha.Annotations["a"])
+	require.Equal(t, 3, alpha.Status.AvailableNodes)
+	require.Equal(t, ElasticsearchGreenHealth, alpha.Status.Health)
+	require.Contains(t, alpha.Annotations, commonv1alpha1.ConversionDataAnnotation)
+
+	// spec and status fields missing from v1alpha1 are restored from the conversion annotation
+	var beta v1beta1.Elasticsearch
+	require.NoError(t, alpha.ConvertTo(&beta))
+	require.Equal(t, es, beta)
+}
+
+func TestElasticsearch_ConvertFrom_NoConversionData(t *testing.T) {
+	es := v1beta1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: v1beta1.ElasticsearchSpec{
+			Version:  "7.4.0",
+			NodeSets: []v1beta1.NodeSet{{Name: "default", Count: 1}},
+		},
+	}
+	var alpha Elasticsearch
+	require.NoError(t, alpha.ConvertFrom(&es))
+	require.Nil(t, alpha.Annotations)
+}
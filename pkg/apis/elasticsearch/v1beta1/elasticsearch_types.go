@@ -11,7 +11,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-const ElasticsearchContainerName = "elasticsearch"
+const (
+	ElasticsearchContainerName = "elasticsearch"
+	// Kind is the kind of the resource, as registered in the scheme from the name of its struct.
+	Kind = "Elasticsearch"
+)
 
 // ElasticsearchSpec defines the desired state of Elasticsearch
 type ElasticsearchSpec struct {
@@ -24,6 +28,10 @@ type ElasticsearchSpec struct {
 	// HTTP contains settings for HTTP.
 	HTTP commonv1beta1.HTTPConfig `json:"http,omitempty"`
 
+	// Transport contains settings for the transport layer used for node to node communication.
+	// +kubebuilder:validation:Optional
+	Transport TransportConfig `json:"transport,omitempty"`
+
 	// NodeSets represents a list of groups of nodes with the same configuration to be part of the cluster
 	NodeSets []NodeSet `json:"nodeSets,omitempty"`
 
@@ -45,6 +53,35 @@ type ElasticsearchSpec struct {
 	// entries and the `path` field to change the target path of a secret entry key.
 	// The secret must exist in the same namespace as the Elasticsearch resource.
 	SecureSettings []commonv1beta1.SecretSource `json:"secureSettings,omitempty"`
+
+	// Snapshot configures snapshot repositories and snapshots periodically taken by the operator.
+	// +kubebuilder:validation:Optional
+	Snapshot *SnapshotSpec `json:"snapshot,omitempty"`
+
+	// Restore bootstraps a new cluster with the content of an existing snapshot, once the cluster is formed.
+	// The restore is only performed once, and cannot be specified after the cluster creation.
+	// +kubebuilder:validation:Optional
+	Restore *SnapshotRestore `json:"restore,omitempty"`
+
+	// ZoneAwareness enables shard allocation awareness across the zones of the Kubernetes nodes.
+	// The zone of each node is exposed as the `zone` node attribute, and rolling upgrades and downscales
+	// are performed one zone at a time.
+	// +kubebuilder:validation:Optional
+	ZoneAwareness *ZoneAwareness `json:"zoneAwareness,omitempty"`
+
+	// IndexLifecycle configures index lifecycle management policies and index templates, eg. to move indices
+	// from hot to warm nodes.
+	// +kubebuilder:validation:Optional
+	IndexLifecycle *IndexLifecycleSpec `json:"indexLifecycle,omitempty"`
+
+	// Security declares roles, role mappings and native users in the cluster.
+	// +kubebuilder:validation:Optional
+	Security *SecuritySpec `json:"security,omitempty"`
+
+	// RemoteClusters are the Elasticsearch clusters managed by the operator to connect to for cross-cluster search
+	// and cross-cluster replication.
+	// +kubebuilder:validation:Optional
+	RemoteClusters []RemoteCluster `json:"remoteClusters,omitempty"`
 }
 
 // NodeCount returns the total number of nodes of the Elasticsearch cluster
@@ -56,6 +93,27 @@ func (es ElasticsearchSpec) NodeCount() int32 {
 	return count
 }
 
+// ZoneAwareness configures shard allocation awareness across zones.
+type ZoneAwareness struct {
+	// TopologyKey is the Kubernetes node label holding the zone of the node.
+	// Defaults to `failure-domain.beta.kubernetes.io/zone`.
+	// +kubebuilder:validation:Optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+}
+
+// TopologyKeyOrDefault returns the topology key, or the default zone label if not specified.
+func (z ZoneAwareness) TopologyKeyOrDefault() string {
+	if z.TopologyKey == "" {
+		return DefaultZoneLabel
+	}
+	return z.TopologyKey
+}
+
+// IsZoneAware returns true if zone awareness is enabled.
+func (es ElasticsearchSpec) IsZoneAware() bool {
+	return es.ZoneAwareness != nil
+}
+
 // NodeSet defines a common topology for a set of Elasticsearch nodes
 type NodeSet struct {
 	// Name is a logical name for this set of nodes. Used as a part of the managed Elasticsearch node.name setting.
@@ -83,6 +141,25 @@ type NodeSet struct {
 	// TODO: define special behavior based on claim metadata.name. (e.g data / logs volumes)
 	// +kubebuilder:validation:Optional
 	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+
+	// NodeAttributes sets Elasticsearch node attributes from the labels of the Kubernetes node each Pod
+	// is scheduled on.
+	// +kubebuilder:validation:Optional
+	NodeAttributes []NodeAttribute `json:"nodeAttributes,omitempty"`
+}
+
+// NodeAttribute maps a Kubernetes node label to an Elasticsearch node attribute.
+type NodeAttribute struct {
+	// Name of the Elasticsearch node attribute, set as `node.attr.<name>`.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	Name string `json:"name"`
+
+	// NodeLabel is the Kubernetes node label holding the value of the attribute.
+	NodeLabel string `json:"nodeLabel"`
+
+	// Awareness enables shard allocation awareness on this attribute.
+	// +kubebuilder:validation:Optional
+	Awareness bool `json:"awareness,omitempty"`
 }
 
 // GetESContainerTemplate returns the Elasticsearch container (if set) from the NodeSet's PodTemplate
@@ -97,10 +174,113 @@ func (n NodeSet) GetESContainerTemplate() *corev1.Container {
 
 // UpdateStrategy specifies how updates to the cluster should be performed.
 type UpdateStrategy struct {
+	// Type of the update strategy: RollingUpgrade (default) restarts nodes one at a time, while FullRestart restarts
+	// all nodes of the cluster, or of a NodeSet, at once.
+	// +kubebuilder:validation:Enum=RollingUpgrade;FullRestart
+	// +kubebuilder:validation:Optional
+	Type UpdateStrategyType `json:"type,omitempty"`
+
+	// FullRestart configures the FullRestart update strategy.
+	// +kubebuilder:validation:Optional
+	FullRestart *FullRestartStrategy `json:"fullRestart,omitempty"`
+
+	// DisabledPredicates is a list of built-in predicates to ignore when selecting the nodes to restart during
+	// a rolling upgrade, for example `do_not_restart_healthy_node_if_not_green` for a single node cluster
+	// which can never be green. Disabling predicates may lead to unavailability or data loss.
+	// +kubebuilder:validation:Optional
+	DisabledPredicates []string `json:"disabledPredicates,omitempty"`
+
+	// Predicates are additional conditions that must be fulfilled to restart a node during a rolling upgrade.
+	// +kubebuilder:validation:Optional
+	Predicates []UpgradePredicate `json:"predicates,omitempty"`
+
 	// ChangeBudget is the change budget that should be used when performing mutations to the cluster.
 	ChangeBudget ChangeBudget `json:"changeBudget,omitempty"`
 }
 
+// UpdateStrategyType is the type of update strategy.
+type UpdateStrategyType string
+
+const (
+	// RollingUpgradeUpdateStrategyType restarts nodes one at a time, keeping the cluster available.
+	RollingUpgradeUpdateStrategyType UpdateStrategyType = "RollingUpgrade"
+	// FullRestartUpdateStrategyType stops all the nodes to upgrade at once and brings them back together.
+	// The cluster is unavailable during the restart.
+	FullRestartUpdateStrategyType UpdateStrategyType = "FullRestart"
+)
+
+// FullRestartScope is the set of nodes restarted together during a full restart.
+type FullRestartScope string
+
+const (
+	// ClusterFullRestartScope restarts all the nodes to upgrade in the cluster at once.
+	ClusterFullRestartScope FullRestartScope = "Cluster"
+	// NodeSetFullRestartScope restarts all the nodes to upgrade in a NodeSet at once, one NodeSet after the other.
+	NodeSetFullRestartScope FullRestartScope = "NodeSet"
+)
+
+// FullRestartStrategy configures the FullRestart update strategy.
+type FullRestartStrategy struct {
+	// Scope of the full restart: Cluster (default) restarts all the nodes at once, NodeSet restarts
+	// the nodes of each NodeSet at once, waiting for the cluster to be green between NodeSets.
+	// +kubebuilder:validation:Enum=Cluster;NodeSet
+	// +kubebuilder:validation:Optional
+	Scope FullRestartScope `json:"scope,omitempty"`
+}
+
+// UpgradePredicate is a declarative condition that must be fulfilled to restart a node during a rolling upgrade.
+// Exactly one condition must be specified.
+type UpgradePredicate struct {
+	// Name of the predicate, reported in the events emitted when a node restart is delayed.
+	Name string `json:"name"`
+
+	// IndicesGreen only allows restarting healthy nodes if the given indices are green.
+	// +kubebuilder:validation:Optional
+	IndicesGreen *IndicesGreenPredicate `json:"indicesGreen,omitempty"`
+
+	// OneNodePerZone only allows restarting a node if no other node in the same zone is unavailable.
+	// +kubebuilder:validation:Optional
+	OneNodePerZone *OneNodePerZonePredicate `json:"oneNodePerZone,omitempty"`
+}
+
+// IndicesGreenPredicate waits for some indices to be green.
+type IndicesGreenPredicate struct {
+	// Indices is a list of index names or patterns.
+	Indices []string `json:"indices"`
+}
+
+// DefaultZoneLabel is the Kubernetes node label holding the zone of the node.
+const DefaultZoneLabel = "failure-domain.beta.kubernetes.io/zone"
+
+// OneNodePerZonePredicate limits the number of unavailable nodes to one per zone.
+type OneNodePerZonePredicate struct {
+	// ZoneLabel is the Kubernetes node label holding the zone of the node.
+	// Defaults to `failure-domain.beta.kubernetes.io/zone`.
+	// +kubebuilder:validation:Optional
+	ZoneLabel string `json:"zoneLabel,omitempty"`
+}
+
+// ZoneLabelOrDefault returns the zone label, or the default one if not specified.
+func (p OneNodePerZonePredicate) ZoneLabelOrDefault() string {
+	if p.ZoneLabel == "" {
+		return DefaultZoneLabel
+	}
+	return p.ZoneLabel
+}
+
+// IsFullRestart returns true if the FullRestart update strategy is selected.
+func (s UpdateStrategy) IsFullRestart() bool {
+	return s.Type == FullRestartUpdateStrategyType
+}
+
+// FullRestartScope returns the scope of the full restart, defaulting to the whole cluster.
+func (s UpdateStrategy) FullRestartScope() FullRestartScope {
+	if s.FullRestart == nil || s.FullRestart.Scope == "" {
+		return ClusterFullRestartScope
+	}
+	return s.FullRestart.Scope
+}
+
 // ChangeBudget defines how Pods in a single group should be updated.
 type ChangeBudget struct {
 	// MaxUnavailable is the maximum number of pods that can be unavailable (not ready) during the update due to the
@@ -188,6 +368,8 @@ const (
 	ElasticsearchApplyingChangesPhase ElasticsearchOrchestrationPhase = "ApplyingChanges"
 	// ElasticsearchMigratingDataPhase Elasticsearch is currently migrating data to another node.
 	ElasticsearchMigratingDataPhase ElasticsearchOrchestrationPhase = "MigratingData"
+	// ElasticsearchRestoringSnapshotPhase Elasticsearch is currently restoring the snapshot specified in the spec.
+	ElasticsearchRestoringSnapshotPhase ElasticsearchOrchestrationPhase = "RestoringSnapshot"
 	// ElasticsearchResourceInvalid is marking a resource as invalid, should never happen if admission control is installed correctly.
 	ElasticsearchResourceInvalid ElasticsearchOrchestrationPhase = "Invalid"
 )
@@ -195,8 +377,16 @@ const (
 // ElasticsearchStatus defines the observed state of Elasticsearch
 type ElasticsearchStatus struct {
 	commonv1beta1.ReconcilerStatus `json:",inline"`
-	Health                         ElasticsearchHealth             `json:"health,omitempty"`
-	Phase                          ElasticsearchOrchestrationPhase `json:"phase,omitempty"`
+	Health                         ElasticsearchHealth               `json:"health,omitempty"`
+	Phase                          ElasticsearchOrchestrationPhase   `json:"phase,omitempty"`
+	Snapshot                       *SnapshotStatus                   `json:"snapshot,omitempty"`
+	IndexLifecycle                 *IndexLifecycleStatus             `json:"indexLifecycle,omitempty"`
+	Security                       *SecurityStatus                   `json:"security,omitempty"`
+	RemoteClusters                 *RemoteClustersStatus             `json:"remoteClusters,omitempty"`
+	Transport                      *TransportStatus                  `json:"transport,omitempty"`
+	HTTP                           *commonv1beta1.HTTPStatus         `json:"http,omitempty"`
+	Certificates                   *commonv1beta1.CertificatesStatus `json:"certificates,omitempty"`
+	VolumeExpansion                *VolumeExpansionStatus            `json:"volumeExpansion,omitempty"`
 }
 
 type ZenDiscoveryStatus struct {
@@ -232,8 +422,17 @@ func (e Elasticsearch) IsMarkedForDeletion() bool {
 	return !e.DeletionTimestamp.IsZero()
 }
 
+// SecureSettings returns the secure settings to inject into the keystore, including
+// the client credentials of the snapshot repositories.
 func (e Elasticsearch) SecureSettings() []commonv1beta1.SecretSource {
-	return e.Spec.SecureSettings
+	if e.Spec.Snapshot == nil {
+		return e.Spec.SecureSettings
+	}
+	secureSettings := append([]commonv1beta1.SecretSource{}, e.Spec.SecureSettings...)
+	for _, repository := range e.Spec.Snapshot.Repositories {
+		secureSettings = append(secureSettings, repository.SecureSettings...)
+	}
+	return secureSettings
 }
 
 // +kubebuilder:object:root=true
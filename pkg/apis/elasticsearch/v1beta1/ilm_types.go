@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1beta1
+
+import (
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+)
+
+// DriftPolicy specifies how the operator handles ILM policies and index templates modified outside of the operator.
+type DriftPolicy string
+
+const (
+	// RevertDriftPolicy restores the definition specified in the Elasticsearch resource.
+	RevertDriftPolicy DriftPolicy = "Revert"
+	// ReportDriftPolicy leaves the modified definition in place, and reports it in events and in the status.
+	ReportDriftPolicy DriftPolicy = "Report"
+)
+
+// IndexLifecycleSpec holds the ILM policies and index templates to create in the cluster.
+type IndexLifecycleSpec struct {
+	// Policies is a list of ILM policies to create in the cluster.
+	// +kubebuilder:validation:Optional
+	Policies []LifecyclePolicy `json:"policies,omitempty"`
+
+	// Templates is a list of index templates to create in the cluster, eg. to attach a policy to new indices.
+	// +kubebuilder:validation:Optional
+	Templates []IndexTemplate `json:"templates,omitempty"`
+
+	// DriftPolicy specifies how policies and templates modified outside of the operator (eg. through Kibana)
+	// are handled: Revert (default) restores the specified definition, Report only reports the modification.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Revert;Report
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+}
+
+// DriftPolicyOrDefault returns the drift policy, defaulting to Revert.
+func (s IndexLifecycleSpec) DriftPolicyOrDefault() DriftPolicy {
+	if s.DriftPolicy == "" {
+		return RevertDriftPolicy
+	}
+	return s.DriftPolicy
+}
+
+// LifecyclePolicy defines an ILM policy created through the ILM API.
+type LifecyclePolicy struct {
+	// Name of the policy in Elasticsearch.
+	Name string `json:"name"`
+
+	// Phases of the policy (eg. `hot`, `warm`, `cold`, `delete`) with their `min_age` and `actions`,
+	// as documented in the ILM API.
+	Phases *commonv1beta1.Config `json:"phases"`
+}
+
+// IndexTemplate defines an index template created through the index templates API.
+type IndexTemplate struct {
+	// Name of the template in Elasticsearch.
+	Name string `json:"name"`
+
+	// IndexPatterns are the patterns of the names of the indices the template applies to.
+	IndexPatterns []string `json:"indexPatterns"`
+
+	// Order of the template, templates with higher orders are merged last.
+	// +kubebuilder:validation:Optional
+	Order int32 `json:"order,omitempty"`
+
+	// Settings of the indices (eg. `index.lifecycle.name` or `index.routing.allocation.require.data`).
+	// +kubebuilder:validation:Optional
+	Settings *commonv1beta1.Config `json:"settings,omitempty"`
+
+	// Mappings of the indices.
+	// +kubebuilder:validation:Optional
+	Mappings *commonv1beta1.Config `json:"mappings,omitempty"`
+
+	// Aliases of the indices.
+	// +kubebuilder:validation:Optional
+	Aliases *commonv1beta1.Config `json:"aliases,omitempty"`
+}
+
+// IndexLifecycleStatus reports the state of the ILM policies and index templates managed by the operator.
+type IndexLifecycleStatus struct {
+	// AppliedPolicies holds the hash of the spec of each policy last applied by the operator, indexed by policy name.
+	AppliedPolicies map[string]string `json:"appliedPolicies,omitempty"`
+	// AppliedTemplates holds the hash of the spec of each template last applied by the operator, indexed by template name.
+	AppliedTemplates map[string]string `json:"appliedTemplates,omitempty"`
+	// DriftedPolicies lists the policies modified outside of the operator, and not reverted.
+	DriftedPolicies []string `json:"driftedPolicies,omitempty"`
+	// DriftedTemplates lists the templates modified outside of the operator, and not reverted.
+	DriftedTemplates []string `json:"driftedTemplates,omitempty"`
+}
@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1beta1
+
+import (
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RemoteCluster is a connection to another Elasticsearch cluster managed by the operator.
+type RemoteCluster struct {
+	// Name of the remote cluster, as used in cross-cluster search requests and in the remote cluster settings.
+	Name string `json:"name"`
+
+	// ElasticsearchRef is a reference to the remote Elasticsearch cluster. Its namespace defaults to the namespace
+	// of this cluster.
+	ElasticsearchRef commonv1beta1.ObjectSelector `json:"elasticsearchRef"`
+
+	// AutoFollowPatterns are cross-cluster replication auto-follow patterns to create for this remote cluster.
+	// +kubebuilder:validation:Optional
+	AutoFollowPatterns []AutoFollowPattern `json:"autoFollowPatterns,omitempty"`
+}
+
+// RemoteClusterRef returns the namespaced name of the remote cluster referenced from the given namespace.
+func (r RemoteCluster) RemoteClusterRef(namespace string) types.NamespacedName {
+	ref := r.ElasticsearchRef.NamespacedName()
+	if ref.Namespace == "" {
+		ref.Namespace = namespace
+	}
+	return ref
+}
+
+// AutoFollowPattern automatically follows the indices of the remote cluster matching the leader index patterns.
+type AutoFollowPattern struct {
+	// Name of the auto-follow pattern in Elasticsearch.
+	Name string `json:"name"`
+
+	// LeaderIndexPatterns are the patterns of the remote indices to follow.
+	LeaderIndexPatterns []string `json:"leaderIndexPatterns"`
+
+	// FollowIndexPattern is the name of the follower indices, where `{{leader_index}}` is replaced by the name of the
+	// leader index.
+	// +kubebuilder:validation:Optional
+	FollowIndexPattern string `json:"followIndexPattern,omitempty"`
+}
+
+// RemoteClustersStatus records the remote clusters and auto-follow patterns applied by the operator, so they can
+// be removed from the cluster once removed from the specification.
+type RemoteClustersStatus struct {
+	// AppliedRemoteClusters are the names of the remote clusters configured by the operator.
+	AppliedRemoteClusters []string `json:"appliedRemoteClusters,omitempty"`
+	// AppliedAutoFollowPatterns maps the auto-follow patterns created by the operator to the hash of their specification.
+	AppliedAutoFollowPatterns map[string]string `json:"appliedAutoFollowPatterns,omitempty"`
+}
@@ -0,0 +1,166 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1beta1
+
+import (
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SecuritySpec holds the roles, role mappings and native users to declare in the cluster.
+type SecuritySpec struct {
+	// Roles is a list of roles written to the roles.yml file of each node.
+	// +kubebuilder:validation:Optional
+	Roles []Role `json:"roles,omitempty"`
+
+	// RoleMappings is a list of role mappings created through the security API, to grant roles to users
+	// authenticated by external realms such as SAML or LDAP.
+	// +kubebuilder:validation:Optional
+	RoleMappings []RoleMapping `json:"roleMappings,omitempty"`
+
+	// NativeUsers is a list of users created in the native realm through the security API.
+	// +kubebuilder:validation:Optional
+	NativeUsers []NativeUser `json:"nativeUsers,omitempty"`
+
+	// FileRealm references secrets in the namespace of the Elasticsearch resource holding users to add to the
+	// file realm, either in the `users` and `users_roles` files format, or as a single user with `name`,
+	// `passwordHash` (bcrypt) or `password`, and `roles` (comma-separated) keys.
+	// +kubebuilder:validation:Optional
+	FileRealm []commonv1beta1.SecretRef `json:"fileRealm,omitempty"`
+}
+
+// FileRealmSecretNames returns the names of the secrets holding file realm users.
+func (s *SecuritySpec) FileRealmSecretNames() []string {
+	if s == nil {
+		return nil
+	}
+	names := make([]string, 0, len(s.FileRealm))
+	for _, ref := range s.FileRealm {
+		names = append(names, ref.SecretName)
+	}
+	return names
+}
+
+// Role defines the privileges of an Elasticsearch role.
+type Role struct {
+	// Name of the role.
+	Name string `json:"name"`
+
+	// Cluster privileges (eg. `monitor` or `manage_ilm`).
+	// +kubebuilder:validation:Optional
+	Cluster []string `json:"cluster,omitempty"`
+
+	// Indices privileges.
+	// +kubebuilder:validation:Optional
+	Indices []IndicesPrivileges `json:"indices,omitempty"`
+
+	// Applications privileges (eg. Kibana privileges).
+	// +kubebuilder:validation:Optional
+	Applications []ApplicationPrivileges `json:"applications,omitempty"`
+
+	// RunAs lists the users the owners of the role can impersonate.
+	// +kubebuilder:validation:Optional
+	RunAs []string `json:"runAs,omitempty"`
+
+	// Metadata of the role.
+	// +kubebuilder:validation:Optional
+	Metadata *commonv1beta1.Config `json:"metadata,omitempty"`
+}
+
+// IndicesPrivileges grants privileges on a set of indices.
+type IndicesPrivileges struct {
+	// Names of the indices, wildcards are supported.
+	Names []string `json:"names"`
+
+	// Privileges on the indices (eg. `read` or `write`).
+	Privileges []string `json:"privileges"`
+
+	// FieldSecurity restricts the fields that can be read.
+	// +kubebuilder:validation:Optional
+	FieldSecurity *FieldSecurity `json:"fieldSecurity,omitempty"`
+
+	// Query restricts the documents that can be read, as a JSON search query.
+	// +kubebuilder:validation:Optional
+	Query string `json:"query,omitempty"`
+
+	// AllowRestrictedIndices grants the privileges on restricted indices (eg. `.security`) matching the names.
+	// +kubebuilder:validation:Optional
+	AllowRestrictedIndices bool `json:"allowRestrictedIndices,omitempty"`
+}
+
+// FieldSecurity lists the fields granted or denied by a role.
+type FieldSecurity struct {
+	// +kubebuilder:validation:Optional
+	Grant []string `json:"grant,omitempty"`
+	// +kubebuilder:validation:Optional
+	Except []string `json:"except,omitempty"`
+}
+
+// ApplicationPrivileges grants privileges on the resources of an application.
+type ApplicationPrivileges struct {
+	// Application name (eg. `kibana-.kibana`).
+	Application string `json:"application"`
+	// Privileges on the application resources.
+	Privileges []string `json:"privileges"`
+	// Resources of the application.
+	Resources []string `json:"resources"`
+}
+
+// RoleMapping grants roles to the users matching rules.
+type RoleMapping struct {
+	// Name of the role mapping.
+	Name string `json:"name"`
+
+	// Roles granted to the matching users.
+	Roles []string `json:"roles"`
+
+	// Rules matching the users, as documented in the role mapping API (eg. `field: {realm.name: saml1}`).
+	Rules *commonv1beta1.Config `json:"rules"`
+
+	// Enabled specifies whether the role mapping is active. Defaults to true.
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Metadata of the role mapping.
+	// +kubebuilder:validation:Optional
+	Metadata *commonv1beta1.Config `json:"metadata,omitempty"`
+}
+
+// IsEnabled returns true if the role mapping is enabled.
+func (m RoleMapping) IsEnabled() bool {
+	return m.Enabled == nil || *m.Enabled
+}
+
+// NativeUser defines a user of the native realm.
+type NativeUser struct {
+	// Username of the user.
+	Username string `json:"username"`
+
+	// Roles of the user.
+	Roles []string `json:"roles"`
+
+	// PasswordSecret references the key of a secret in the namespace of the Elasticsearch resource
+	// holding the password of the user.
+	PasswordSecret corev1.SecretKeySelector `json:"passwordSecret"`
+
+	// FullName of the user.
+	// +kubebuilder:validation:Optional
+	FullName string `json:"fullName,omitempty"`
+
+	// Email of the user.
+	// +kubebuilder:validation:Optional
+	Email string `json:"email,omitempty"`
+
+	// Metadata of the user.
+	// +kubebuilder:validation:Optional
+	Metadata *commonv1beta1.Config `json:"metadata,omitempty"`
+}
+
+// SecurityStatus reports the state of the native users managed by the operator.
+type SecurityStatus struct {
+	// AppliedNativeUsers holds a hash of the spec and password secret version of each native user last applied
+	// by the operator, indexed by username.
+	AppliedNativeUsers map[string]string `json:"appliedNativeUsers,omitempty"`
+}
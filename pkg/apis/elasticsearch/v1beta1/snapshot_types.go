@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1beta1
+
+import (
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnapshotRepositoryType is the type of a snapshot repository.
+type SnapshotRepositoryType string
+
+// Supported snapshot repository types. All types but fs require the corresponding repository plugin
+// to be installed in the Elasticsearch image.
+const (
+	FsRepositoryType    SnapshotRepositoryType = "fs"
+	S3RepositoryType    SnapshotRepositoryType = "s3"
+	GCSRepositoryType   SnapshotRepositoryType = "gcs"
+	AzureRepositoryType SnapshotRepositoryType = "azure"
+)
+
+// SupportedSnapshotRepositoryTypes lists the repository types that can be specified in a SnapshotRepository.
+var SupportedSnapshotRepositoryTypes = []SnapshotRepositoryType{
+	FsRepositoryType,
+	S3RepositoryType,
+	GCSRepositoryType,
+	AzureRepositoryType,
+}
+
+// SnapshotSpec holds the snapshot repositories to register and the optional snapshot schedule.
+type SnapshotSpec struct {
+	// Repositories is a list of snapshot repositories to register in the cluster.
+	Repositories []SnapshotRepository `json:"repositories,omitempty"`
+
+	// Schedule configures snapshots taken periodically by the operator.
+	// +kubebuilder:validation:Optional
+	Schedule *SnapshotSchedule `json:"schedule,omitempty"`
+}
+
+// Repository returns the repository with the given name, or nil if it does not exist.
+func (s SnapshotSpec) Repository(name string) *SnapshotRepository {
+	for i := range s.Repositories {
+		if s.Repositories[i].Name == name {
+			return &s.Repositories[i]
+		}
+	}
+	return nil
+}
+
+// SnapshotRepository defines a snapshot repository registered in the cluster through the snapshot API.
+type SnapshotRepository struct {
+	// Name of the repository in Elasticsearch.
+	Name string `json:"name"`
+
+	// Type of the repository: fs, s3, gcs or azure.
+	// +kubebuilder:validation:Enum=fs;s3;gcs;azure
+	Type SnapshotRepositoryType `json:"type"`
+
+	// Settings are the repository settings, as documented for the repository type (eg. `bucket` or `location`).
+	// +kubebuilder:validation:Optional
+	Settings *commonv1beta1.Config `json:"settings,omitempty"`
+
+	// SecureSettings references secrets containing the repository client credentials
+	// (eg. `s3.client.default.access_key`), to be injected into the Elasticsearch keystore on each node.
+	// They follow the same rules as the secure settings of the Elasticsearch resource.
+	// +kubebuilder:validation:Optional
+	SecureSettings []commonv1beta1.SecretSource `json:"secureSettings,omitempty"`
+}
+
+// SnapshotSchedule specifies how often the operator takes snapshots, and how many of them it keeps around.
+type SnapshotSchedule struct {
+	// Repository is the name of the repository, from the repositories list, in which snapshots are stored.
+	Repository string `json:"repository"`
+
+	// Interval is the minimum duration between two consecutive snapshots (eg. `24h`).
+	Interval metav1.Duration `json:"interval"`
+
+	// Retention defines which snapshots taken by the operator are deleted.
+	// +kubebuilder:validation:Optional
+	Retention SnapshotRetention `json:"retention,omitempty"`
+}
+
+// SnapshotRetention defines when snapshots taken by the operator are deleted.
+// The most recent successful snapshot is never deleted.
+type SnapshotRetention struct {
+	// MaxCount is the maximum number of snapshots to keep. Older ones are deleted first.
+	// Zero or unset means no limit.
+	MaxCount int32 `json:"maxCount,omitempty"`
+
+	// ExpireAfter is the age after which snapshots are deleted. Unset means snapshots never expire.
+	// +kubebuilder:validation:Optional
+	ExpireAfter *metav1.Duration `json:"expireAfter,omitempty"`
+}
+
+// SnapshotRestore specifies a snapshot to restore once a new cluster is formed.
+type SnapshotRestore struct {
+	// Repository is the name of the repository, from the snapshot repositories list, holding the snapshot.
+	Repository string `json:"repository"`
+
+	// Snapshot is the name of the snapshot to restore.
+	Snapshot string `json:"snapshot"`
+
+	// Indices is the list of indices to restore. All indices in the snapshot are restored if empty.
+	// +kubebuilder:validation:Optional
+	Indices []string `json:"indices,omitempty"`
+
+	// IncludeGlobalState also restores the cluster state stored in the snapshot (templates, persistent settings, etc.).
+	// +kubebuilder:validation:Optional
+	IncludeGlobalState bool `json:"includeGlobalState,omitempty"`
+}
+
+// SnapshotStatus reports the outcome of the latest snapshots taken by the operator.
+type SnapshotStatus struct {
+	// LastSuccessSnapshot is the name of the latest successful snapshot.
+	LastSuccessSnapshot string `json:"lastSuccessSnapshot,omitempty"`
+	// LastSuccessTime is the time at which the latest successful snapshot started.
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+	// LastFailureSnapshot is the name of the latest failed snapshot.
+	LastFailureSnapshot string `json:"lastFailureSnapshot,omitempty"`
+	// LastFailureTime is the time at which the latest failed snapshot started.
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+	// LastFailureReason explains why the latest failed snapshot did not succeed.
+	LastFailureReason string `json:"lastFailureReason,omitempty"`
+}
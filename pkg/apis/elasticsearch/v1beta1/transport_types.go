@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1beta1
+
+import (
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+)
+
+// TransportConfig configures the transport layer of the nodes.
+type TransportConfig struct {
+	// TLS configures the certificates of the transport layer.
+	// +kubebuilder:validation:Optional
+	TLS TransportTLSOptions `json:"tls,omitempty"`
+}
+
+// TransportTLSOptions configures the certificates of the transport layer.
+type TransportTLSOptions struct {
+	// Certificate is a reference to a secret holding a user-provided transport CA, or user-provided node certificates,
+	// to be used instead of the self-signed CA generated by the operator.
+	//
+	// To sign the certificate of each node with a user-provided CA, the secret should contain:
+	//
+	// - `ca.crt`: the PEM encoded CA certificate
+	// - `ca.key`: the PEM encoded CA private key
+	//
+	// To use user-provided node certificates, the secret should contain:
+	//
+	// - `ca.crt`: the PEM encoded CA certificate of the node certificates
+	// - `<pod-name>.tls.crt` and `<pod-name>.tls.key`: the PEM encoded certificate and private key of each node
+	// +kubebuilder:validation:Optional
+	Certificate commonv1beta1.SecretRef `json:"certificate,omitempty"`
+
+	// IssuerRef is a reference to a cert-manager issuer. If set, the operator requests the certificate of each node
+	// from cert-manager through a Certificate resource, instead of issuing it with a self-signed CA.
+	// The issued secrets must contain the CA certificate in their `ca.crt` key.
+	// +kubebuilder:validation:Optional
+	IssuerRef *commonv1beta1.IssuerRef `json:"issuerRef,omitempty"`
+
+	// CertificateAuthorities references secrets holding additional PEM encoded CA certificates, in their `ca.crt` key,
+	// trusted by the nodes on the transport layer, eg. the CAs of remote clusters running outside Kubernetes.
+	// The secrets must exist in the same namespace as the Elasticsearch resource.
+	// +kubebuilder:validation:Optional
+	CertificateAuthorities []commonv1beta1.SecretRef `json:"certificateAuthorities,omitempty"`
+}
+
+// CertificateAuthoritiesSecretNames returns the names of the secrets holding additional transport CAs.
+func (tls TransportTLSOptions) CertificateAuthoritiesSecretNames() []string {
+	names := make([]string, 0, len(tls.CertificateAuthorities))
+	for _, ref := range tls.CertificateAuthorities {
+		names = append(names, ref.SecretName)
+	}
+	return names
+}
+
+// TransportStatus reports the state of the transport layer configuration.
+type TransportStatus struct {
+	// InvalidCertificateAuthorities explains why some of the additional CA certificates are not trusted.
+	InvalidCertificateAuthorities []string `json:"invalidCertificateAuthorities,omitempty"`
+}
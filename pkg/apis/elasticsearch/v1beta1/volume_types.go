@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// VolumeExpansionStatus reports the progress of the expansion of the persistent volume claims, following
+// an increase of the storage requested by the volume claim templates.
+type VolumeExpansionStatus struct {
+	// RecreatingStatefulSets are the StatefulSets deleted while orphaning their Pods, to be recreated
+	// with the expanded volume claim templates.
+	RecreatingStatefulSets []string `json:"recreatingStatefulSets,omitempty"`
+	// PersistentVolumeClaims are the claims whose expansion is not complete yet.
+	PersistentVolumeClaims []PersistentVolumeClaimExpansion `json:"persistentVolumeClaims,omitempty"`
+	// UnexpandableStatefulSets are the StatefulSets whose volumes cannot be expanded. They keep their current
+	// volume claim templates, to which the storage requests of the specification can be reverted.
+	UnexpandableStatefulSets []UnexpandableStatefulSet `json:"unexpandableStatefulSets,omitempty"`
+}
+
+// UnexpandableStatefulSet reports a StatefulSet whose volumes cannot be expanded.
+type UnexpandableStatefulSet struct {
+	// Name of the StatefulSet.
+	Name string `json:"name"`
+	// Reason why its volumes cannot be expanded, typically a storage class that does not allow volume expansion.
+	Reason string `json:"reason"`
+	// Storage requested by its current volume claim templates, indexed by claim template name.
+	Storage map[string]resource.Quantity `json:"storage,omitempty"`
+}
+
+// PersistentVolumeClaimExpansion reports the progress of the expansion of a persistent volume claim.
+type PersistentVolumeClaimExpansion struct {
+	// Name of the persistent volume claim.
+	Name string `json:"name"`
+	// Requested is the storage requested by the claim.
+	Requested resource.Quantity `json:"requested"`
+	// Capacity is the current capacity of the volume.
+	Capacity resource.Quantity `json:"capacity,omitempty"`
+	// FileSystemResizePending is true if the volume was expanded, but the Pod using it must restart
+	// for its file system to be resized.
+	FileSystemResizePending bool `json:"fileSystemResizePending,omitempty"`
+}
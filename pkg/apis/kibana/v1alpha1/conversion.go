@@ -0,0 +1,78 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1alpha1
+
+import (
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1alpha1"
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Convertible = &Kibana{}
+
+// kibanaConversionData holds the fields of a v1beta1 Kibana missing from v1alpha1.
+type kibanaConversionData struct {
+	HTTPTLS *commonv1alpha1.TLSConversionData `json:"httpTLS,omitempty"`
+
+	HTTPStatus         *commonv1beta1.HTTPStatus         `json:"httpStatus,omitempty"`
+	CertificatesStatus *commonv1beta1.CertificatesStatus `json:"certificatesStatus,omitempty"`
+}
+
+// ConvertTo converts this Kibana to the v1beta1 hub version.
+func (k *Kibana) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.Kibana)
+	dst.ObjectMeta = *k.ObjectMeta.DeepCopy()
+	var data kibanaConversionData
+	if err := commonv1alpha1.PopConversionData(&dst.ObjectMeta, &data); err != nil {
+		return err
+	}
+
+	dst.Spec = v1beta1.KibanaSpec{
+		Version:          k.Spec.Version,
+		Image:            k.Spec.Image,
+		Count:            k.Spec.NodeCount,
+		ElasticsearchRef: commonv1beta1.ObjectSelector(k.Spec.ElasticsearchRef),
+		Config:           commonv1alpha1.ConvertConfigTo(k.Spec.Config),
+		HTTP:             commonv1alpha1.ConvertHTTPConfigTo(k.Spec.HTTP, data.HTTPTLS),
+		PodTemplate:      *k.Spec.PodTemplate.DeepCopy(),
+		SecureSettings:   commonv1alpha1.ConvertSecretSourcesTo(k.Spec.SecureSettings),
+	}
+	dst.Status = v1beta1.KibanaStatus{
+		ReconcilerStatus:  commonv1beta1.ReconcilerStatus(k.Status.ReconcilerStatus),
+		Health:            v1beta1.KibanaHealth(k.Status.Health),
+		AssociationStatus: commonv1beta1.AssociationStatus(k.Status.AssociationStatus),
+		HTTP:              data.HTTPStatus,
+		Certificates:      data.CertificatesStatus,
+	}
+	return nil
+}
+
+// ConvertFrom converts the given v1beta1 hub version to this Kibana.
+func (k *Kibana) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.Kibana)
+	k.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	data := kibanaConversionData{
+		HTTPStatus:         src.Status.HTTP.DeepCopy(),
+		CertificatesStatus: src.Status.Certificates.DeepCopy(),
+	}
+	k.Spec = KibanaSpec{
+		Version:          src.Spec.Version,
+		Image:            src.Spec.Image,
+		NodeCount:        src.Spec.Count,
+		ElasticsearchRef: commonv1alpha1.ObjectSelector(src.Spec.ElasticsearchRef),
+		Config:           commonv1alpha1.ConvertConfigFrom(src.Spec.Config),
+		PodTemplate:      *src.Spec.PodTemplate.DeepCopy(),
+		SecureSettings:   commonv1alpha1.ConvertSecretSourcesFrom(src.Spec.SecureSettings),
+	}
+	k.Spec.HTTP, data.HTTPTLS = commonv1alpha1.ConvertHTTPConfigFrom(src.Spec.HTTP)
+	k.Status = KibanaStatus{
+		ReconcilerStatus:  commonv1alpha1.ReconcilerStatus(src.Status.ReconcilerStatus),
+		Health:            KibanaHealth(src.Status.Health),
+		AssociationStatus: commonv1alpha1.AssociationStatus(src.Status.AssociationStatus),
+	}
+	return commonv1alpha1.SetConversionData(&k.ObjectMeta, data)
+}
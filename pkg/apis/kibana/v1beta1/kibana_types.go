@@ -11,7 +11,11 @@ import (
 	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 )
 
-const KibanaContainerName = "kibana"
+const (
+	KibanaContainerName = "kibana"
+	// Kind is the kind of the resource, as registered in the scheme from the name of its struct.
+	Kind = "Kibana"
+)
 
 // KibanaSpec defines the desired state of Kibana
 type KibanaSpec struct {
@@ -63,8 +67,10 @@ const (
 // KibanaStatus defines the observed state of Kibana
 type KibanaStatus struct {
 	commonv1beta1.ReconcilerStatus `json:",inline"`
-	Health                         KibanaHealth                    `json:"health,omitempty"`
-	AssociationStatus              commonv1beta1.AssociationStatus `json:"associationStatus,omitempty"`
+	Health                         KibanaHealth                      `json:"health,omitempty"`
+	AssociationStatus              commonv1beta1.AssociationStatus   `json:"associationStatus,omitempty"`
+	HTTP                           *commonv1beta1.HTTPStatus         `json:"http,omitempty"`
+	Certificates                   *commonv1beta1.CertificatesStatus `json:"certificates,omitempty"`
 }
 
 // IsDegraded returns true if the current status is worse than the previous.
@@ -19,7 +19,7 @@ func (in *Kibana) DeepCopyInto(out *Kibana) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	if in.assocConf != nil {
 		in, out := &in.assocConf, &out.assocConf
 		*out = new(commonv1beta1.AssociationConf)
@@ -110,6 +110,16 @@ func (in *KibanaSpec) DeepCopy() *KibanaSpec {
 func (in *KibanaStatus) DeepCopyInto(out *KibanaStatus) {
 	*out = *in
 	out.ReconcilerStatus = in.ReconcilerStatus
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(commonv1beta1.HTTPStatus)
+		**out = **in
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(commonv1beta1.CertificatesStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaStatus.
@@ -98,7 +98,10 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileAp
 
 func addWatches(c controller.Controller, r *ReconcileApmServer) error {
 	// Watch for changes to ApmServer
-	err := c.Watch(&source.Kind{Type: &apmv1beta1.ApmServer{}}, &handler.EnqueueRequestForObject{})
+	err := c.Watch(
+		&source.Kind{Type: &apmv1beta1.ApmServer{}}, &handler.EnqueueRequestForObject{},
+		operator.ResourceSelectorPredicate(r.ResourceSelector),
+	)
 	if err != nil {
 		return err
 	}
@@ -177,12 +180,20 @@ var _ driver.Interface = &ReconcileApmServer{}
 // and what is in the ApmServer.Spec
 func (r *ReconcileApmServer) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	defer common.LogReconciliationRun(log, request, &r.iteration)()
+	defer reconciler.ObserveDuration(apmv1beta1.Kind, "reconcile")()
 
 	var as apmv1beta1.ApmServer
 	if ok, err := association.FetchWithAssociation(r.Client, request, &as); !ok {
 		return reconcile.Result{}, err
 	}
 
+	if !operator.MatchesResourceSelector(r.ResourceSelector, as.Labels) {
+		// the resource is reconciled by another operator: stop watching its secrets
+		log.Info("Releasing resource not matching the resource selector", "namespace", as.Namespace, "as_name", as.Name)
+		certificates.ForgetExpiration(as.Kind, k8s.ExtractNamespacedName(&as))
+		return reconcile.Result{}, r.finalizers.Release(&as, r.finalizersFor(as)...)
+	}
+
 	if common.IsPaused(as.ObjectMeta) {
 		log.Info("Object is paused. Skipping reconciliation", "namespace", as.Namespace, "as_name", as.Name)
 		return common.PauseRequeue, nil
@@ -198,6 +209,7 @@ func (r *ReconcileApmServer) Reconcile(request reconcile.Request) (reconcile.Res
 
 	if as.IsMarkedForDeletion() {
 		// APM server will be deleted nothing to do other than run finalizers
+		certificates.ForgetExpiration(as.Kind, k8s.ExtractNamespacedName(&as))
 		return reconcile.Result{}, nil
 	}
 
@@ -214,7 +226,7 @@ func (r *ReconcileApmServer) Reconcile(request reconcile.Request) (reconcile.Res
 
 func (r *ReconcileApmServer) isCompatible(as *apmv1beta1.ApmServer) (bool, error) {
 	selector := map[string]string{labels.ApmServerNameLabelName: as.Name}
-	compat, err := annotation.ReconcileCompatibility(r.Client, as, selector, r.OperatorInfo.BuildInfo.Version)
+	compat, err := annotation.ReconcileCompatibility(r.Client, as, selector, r.OperatorInfo.BuildInfo.Version, r.ResourceSelector)
 	if err != nil {
 		k8s.EmitErrorEvent(r.recorder, err, as, events.EventCompatCheckError, "Error during compatibility check: %v", err)
 	}
@@ -223,16 +235,18 @@ func (r *ReconcileApmServer) isCompatible(as *apmv1beta1.ApmServer) (bool, error
 
 func (r *ReconcileApmServer) doReconcile(request reconcile.Request, as *apmv1beta1.ApmServer) (reconcile.Result, error) {
 	state := NewState(request, as)
+	state.ApmServer.Status.Operator = r.Parameters.Name
 	svc, err := common.ReconcileService(r.Client, r.scheme, NewService(*as), as)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	results := apmcerts.Reconcile(r, as, []corev1.Service{*svc}, r.CACertRotation)
+	certificatesStatus, results := apmcerts.Reconcile(r, as, []corev1.Service{*svc}, r.CACertRotation, r.KeyParams)
 	if results.HasError() {
 		res, err := results.Aggregate()
 		k8s.EmitErrorEvent(r.recorder, err, as, events.EventReconciliationError, "Certificate reconciliation error: %v", err)
 		return res, err
 	}
+	state.UpdateCertificatesStatus(certificatesStatus)
 
 	state, err = r.reconcileApmServerDeployment(state, as)
 	if err != nil {
@@ -438,6 +452,16 @@ func (r *ReconcileApmServer) reconcileApmServerDeployment(
 		return state, err
 	}
 	state.UpdateApmServerState(result, *reconciledApmServerSecret)
+
+	var fingerprint string
+	if as.Spec.HTTP.TLS.Enabled() {
+		// the ApmServer is rolled with the new certificate through the config checksum
+		fingerprint, err = http.CertificateFingerprint(r.K8sClient(), apmname.APMNamer, as)
+		if err != nil {
+			return state, err
+		}
+	}
+	state.UpdateHTTPStatus(result, fingerprint)
 	return state, nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1beta1"
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/apmserver/labels"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/apmserver/name"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
@@ -23,15 +24,18 @@ func Reconcile(
 	apm *v1beta1.ApmServer,
 	services []coverv1.Service,
 	rotation certificates.RotationParams,
-) reconciler.Results {
+	keyParams certificates.KeyParams,
+) (*commonv1beta1.CertificatesStatus, reconciler.Results) {
 	results := reconciler.Results{}
 	selfSignedCert := apm.Spec.HTTP.TLS.SelfSignedCertificate
 	if selfSignedCert != nil && selfSignedCert.Disabled {
-		return results
+		return nil, results
 	}
 
 	labels := labels.NewLabels(apm.Name)
 
+	keyParams = http.SelfSignedKeyParams(apm.Spec.HTTP.TLS, keyParams)
+
 	// reconcile CA certs first
 	httpCa, err := certificates.ReconcileCAForOwner(
 		driver.K8sClient(),
@@ -41,9 +45,10 @@ func Reconcile(
 		labels,
 		certificates.HTTPCAType,
 		rotation,
+		keyParams,
 	)
 	if err != nil {
-		return *results.WithError(err)
+		return nil, *results.WithError(err)
 	}
 
 	// handle CA expiry via requeue
@@ -61,11 +66,23 @@ func Reconcile(
 		labels,
 		services,
 		rotation, // todo correct rotation
+		keyParams,
 	)
 	if err != nil {
-		return *results.WithError(err)
+		return nil, *results.WithError(err)
 	}
 	// reconcile http public cert secret
-	results.WithError(http.ReconcileHTTPCertsPublicSecret(driver.K8sClient(), driver.Scheme(), apm, name.APMNamer, httpCertificates))
-	return results
+	if err := http.ReconcileHTTPCertsPublicSecret(driver.K8sClient(), driver.Scheme(), apm, name.APMNamer, httpCertificates); err != nil {
+		return nil, *results.WithError(err)
+	}
+
+	expiration, err := httpCertificates.Expiration()
+	if err != nil {
+		return nil, *results.WithError(err)
+	}
+	// requeue to warn about user-provided certificates before they expire
+	results.WithResult(reconcile.Result{
+		RequeueAfter: certificates.ReportExpiration(driver.Recorder(), apm, certificates.HTTPLayer, expiration, rotation.RotateBefore),
+	})
+	return &commonv1beta1.CertificatesStatus{HTTP: &expiration}, results
 }
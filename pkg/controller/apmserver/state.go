@@ -6,6 +6,8 @@ package apmserver
 
 import (
 	"github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1beta1"
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/deployment"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -28,11 +30,11 @@ func NewState(request reconcile.Request, as *v1beta1.ApmServer) State {
 }
 
 // UpdateApmServerState updates the ApmServer status based on the given deployment.
-func (s State) UpdateApmServerState(deployment v1.Deployment, apmServerSecret corev1.Secret) {
+func (s State) UpdateApmServerState(dep v1.Deployment, apmServerSecret corev1.Secret) {
 	s.ApmServer.Status.SecretTokenSecretName = apmServerSecret.Name
-	s.ApmServer.Status.AvailableNodes = int(deployment.Status.AvailableReplicas) // TODO lossy type conversion
+	s.ApmServer.Status.AvailableNodes = int(dep.Status.AvailableReplicas) // TODO lossy type conversion
 	s.ApmServer.Status.Health = v1beta1.ApmServerRed
-	for _, c := range deployment.Status.Conditions {
+	for _, c := range dep.Status.Conditions {
 		if c.Type == v1.DeploymentAvailable && c.Status == corev1.ConditionTrue {
 			s.ApmServer.Status.Health = v1beta1.ApmServerGreen
 		}
@@ -43,3 +45,22 @@ func (s State) UpdateApmServerState(deployment v1.Deployment, apmServerSecret co
 func (s State) UpdateApmServerExternalService(svc corev1.Service) {
 	s.ApmServer.Status.ExternalService = svc.Name
 }
+
+// UpdateHTTPStatus reports the fingerprint of the HTTP certificate served by the ApmServer, once the given deployment
+// is rolled out with this certificate. An empty fingerprint means that TLS is disabled.
+func (s State) UpdateHTTPStatus(dep v1.Deployment, fingerprint string) {
+	if fingerprint == "" {
+		s.ApmServer.Status.HTTP = nil
+		return
+	}
+	if !deployment.IsRolledOut(dep) {
+		// some pods may still serve the previous certificate
+		return
+	}
+	s.ApmServer.Status.HTTP = &commonv1beta1.HTTPStatus{CertificateFingerprint: fingerprint}
+}
+
+// UpdateCertificatesStatus reports when the HTTP certificates expire.
+func (s State) UpdateCertificatesStatus(status *commonv1beta1.CertificatesStatus) {
+	s.ApmServer.Status.Certificates = status
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/finalizer"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/user"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
 	esname "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
@@ -86,7 +87,10 @@ func add(mgr manager.Manager, r reconcile.Reconciler) (controller.Controller, er
 
 func addWatches(c controller.Controller, r *ReconcileApmServerElasticsearchAssociation) error {
 	// Watch for changes to ApmServers
-	if err := c.Watch(&source.Kind{Type: &apmtype.ApmServer{}}, &handler.EnqueueRequestForObject{}); err != nil {
+	if err := c.Watch(
+		&source.Kind{Type: &apmtype.ApmServer{}}, &handler.EnqueueRequestForObject{},
+		operator.ResourceSelectorPredicate(r.ResourceSelector),
+	); err != nil {
 		return err
 	}
 
@@ -128,19 +132,27 @@ type ReconcileApmServerElasticsearchAssociation struct {
 // and what is in the ApmServerElasticsearchAssociation.Spec
 func (r *ReconcileApmServerElasticsearchAssociation) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	defer common.LogReconciliationRun(log, request, &r.iteration)()
+	defer reconciler.ObserveDuration(apmtype.Kind, "association")()
 
 	var apmServer apmtype.ApmServer
 	if ok, err := association.FetchWithAssociation(r.Client, request, &apmServer); !ok {
 		return reconcile.Result{}, err
 	}
 
+	handler := finalizer.NewHandler(r)
+	apmName := k8s.ExtractNamespacedName(&apmServer)
+	if !operator.MatchesResourceSelector(r.ResourceSelector, apmServer.Labels) {
+		// the resource is reconciled by another operator: stop watching the referenced cluster, the association user
+		// is left to the other operator
+		log.Info("Releasing resource not matching the resource selector", "namespace", apmServer.Namespace, "as_name", apmServer.Name)
+		return reconcile.Result{}, handler.Release(&apmServer, watchFinalizer(apmName, r.watches))
+	}
+
 	if common.IsPaused(apmServer.ObjectMeta) {
 		log.Info("Object is paused. Skipping reconciliation", "namespace", apmServer.Namespace, "as_name", apmServer.Name)
 		return common.PauseRequeue, nil
 	}
 
-	handler := finalizer.NewHandler(r)
-	apmName := k8s.ExtractNamespacedName(&apmServer)
 	err := handler.Handle(
 		&apmServer,
 		watchFinalizer(apmName, r.watches),
@@ -215,7 +227,7 @@ func resultFromStatus(status commonv1beta1.AssociationStatus) reconcile.Result {
 
 func (r *ReconcileApmServerElasticsearchAssociation) isCompatible(apmServer *apmtype.ApmServer) (bool, error) {
 	selector := map[string]string{labels.ApmServerNameLabelName: apmServer.Name}
-	compat, err := annotation.ReconcileCompatibility(r.Client, apmServer, selector, r.OperatorInfo.BuildInfo.Version)
+	compat, err := annotation.ReconcileCompatibility(r.Client, apmServer, selector, r.OperatorInfo.BuildInfo.Version, r.ResourceSelector)
 	if err != nil {
 		k8s.EmitErrorEvent(r.recorder, err, apmServer, events.EventCompatCheckError, "Error during compatibility check: %v", err)
 	}
@@ -262,6 +274,11 @@ func (r *ReconcileApmServerElasticsearchAssociation) reconcileInternal(apmServer
 		return commonv1beta1.AssociationFailed, err
 	}
 
+	rotation, err := association.UserRotation(r.Client, apmServer, apmUserSuffix, es)
+	if err != nil {
+		return commonv1beta1.AssociationPending, err
+	}
+
 	if err := association.ReconcileEsUser(
 		r.Client,
 		r.scheme,
@@ -272,6 +289,7 @@ func (r *ReconcileApmServerElasticsearchAssociation) reconcileInternal(apmServer
 		},
 		"superuser",
 		apmUserSuffix,
+		rotation,
 		es,
 	); err != nil { // TODO distinguish conflicts and non-recoverable errors here
 		return commonv1beta1.AssociationPending, err
@@ -283,7 +301,7 @@ func (r *ReconcileApmServerElasticsearchAssociation) reconcileInternal(apmServer
 	}
 
 	// construct the expected ES output configuration
-	authSecretRef := association.ClearTextSecretKeySelector(apmServer, apmUserSuffix)
+	authSecretRef := association.ClearTextSecretKeySelector(apmServer, apmUserSuffix, rotation)
 	expectedAssocConf := &commonv1beta1.AssociationConf{
 		AuthSecretName: authSecretRef.Name,
 		AuthSecretKey:  authSecretRef.Key,
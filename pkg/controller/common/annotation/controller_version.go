@@ -5,11 +5,13 @@
 package annotation
 
 import (
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -64,7 +66,14 @@ func UpdateControllerVersion(client k8s.Client, obj runtime.Object, version stri
 // controller versions 0.9.0+ cannot reconcile resources created with earlier controllers, so this lets our controller skip those resources until they can be manually recreated
 // if an object does not have an annotation, it will determine if it is a new object or if it has been previously reconciled by an older controller version, as this annotation
 // was not applied by earlier controller versions. it will update the object's annotations indicating it is incompatible if so
-func ReconcileCompatibility(client k8s.Client, obj runtime.Object, selector map[string]string, controllerVersion string) (bool, error) {
+// resources not matching the resource selector of the operator, if any, are reconciled by other operators and are always skipped
+func ReconcileCompatibility(
+	client k8s.Client,
+	obj runtime.Object,
+	selector map[string]string,
+	controllerVersion string,
+	resourceSelector labels.Selector,
+) (bool, error) {
 	accessor := meta.NewAccessor()
 	namespace, err := accessor.Namespace(obj)
 	if err != nil {
@@ -81,6 +90,17 @@ func ReconcileCompatibility(client k8s.Client, obj runtime.Object, selector map[
 		log.Error(err, "error getting annotations", "namespace", namespace, "name", name, "kind", obj.GetObjectKind().GroupVersionKind().Kind)
 		return false, err
 	}
+	resourceLabels, err := accessor.Labels(obj)
+	if err != nil {
+		log.Error(err, "error getting labels", "namespace", namespace, "name", name, "kind", obj.GetObjectKind().GroupVersionKind().Kind)
+		return false, err
+	}
+
+	if !operator.MatchesResourceSelector(resourceSelector, resourceLabels) {
+		log.V(1).Info("Resource does not match the resource selector of the operator, will not take action", "namespace", namespace, "name", name,
+			"resource_selector", resourceSelector.String())
+		return false, nil
+	}
 
 	annExists := annotations != nil && annotations[ControllerVersionAnnotation] != ""
 
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -90,7 +91,7 @@ func TestMissingAnnotationOldVersion(t *testing.T) {
 	sc := setupScheme(t)
 	client := k8s.WrapClient(fake.NewFakeClientWithScheme(sc, es, svc))
 	selector := getElasticsearchSelector(es)
-	compat, err := ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion)
+	compat, err := ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion, nil)
 	require.NoError(t, err)
 	assert.False(t, compat)
 
@@ -126,7 +127,7 @@ func TestMissingAnnotationNewObject(t *testing.T) {
 	// client := k8s.WrapClient(fake.NewFakeClientWithScheme(sc, es, svc))
 	client := k8s.WrapClient(fake.NewFakeClientWithScheme(sc, es))
 	selector := getElasticsearchSelector(es)
-	compat, err := ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion)
+	compat, err := ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion, nil)
 	require.NoError(t, err)
 	assert.True(t, compat)
 
@@ -149,7 +150,7 @@ func TestSameAnnotation(t *testing.T) {
 	sc := setupScheme(t)
 	client := k8s.WrapClient(fake.NewFakeClientWithScheme(sc, es))
 	selector := getElasticsearchSelector(es)
-	compat, err := ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion)
+	compat, err := ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion, nil)
 	require.NoError(t, err)
 	assert.True(t, compat)
 	assert.Equal(t, MinCompatibleControllerVersion, es.Annotations[ControllerVersionAnnotation])
@@ -168,7 +169,7 @@ func TestIncompatibleAnnotation(t *testing.T) {
 	sc := setupScheme(t)
 	client := k8s.WrapClient(fake.NewFakeClientWithScheme(sc, es))
 	selector := getElasticsearchSelector(es)
-	compat, err := ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion)
+	compat, err := ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion, nil)
 	require.NoError(t, err)
 	assert.False(t, compat)
 	// check we did not update the annotation
@@ -188,11 +189,39 @@ func TestNewerAnnotation(t *testing.T) {
 	sc := setupScheme(t)
 	client := k8s.WrapClient(fake.NewFakeClientWithScheme(sc, es))
 	selector := getElasticsearchSelector(es)
-	compat, err := ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion)
+	compat, err := ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion, nil)
 	assert.NoError(t, err)
 	assert.True(t, compat)
 }
 
+// Test ReconcileCompatibility skips resources not matching the resource selector, without annotating them
+func TestResourceSelector(t *testing.T) {
+	es := &v1beta1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "es",
+			Labels:    map[string]string{"operator": "canary"},
+		},
+	}
+	sc := setupScheme(t)
+	client := k8s.WrapClient(fake.NewFakeClientWithScheme(sc, es))
+	selector := getElasticsearchSelector(es)
+
+	stable, err := labels.Parse("operator!=canary")
+	require.NoError(t, err)
+	compat, err := ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion, stable)
+	require.NoError(t, err)
+	assert.False(t, compat)
+	assert.Empty(t, es.Annotations[ControllerVersionAnnotation])
+
+	canary, err := labels.Parse("operator=canary")
+	require.NoError(t, err)
+	compat, err = ReconcileCompatibility(client, es, selector, MinCompatibleControllerVersion, canary)
+	require.NoError(t, err)
+	assert.True(t, compat)
+	assert.Equal(t, MinCompatibleControllerVersion, es.Annotations[ControllerVersionAnnotation])
+}
+
 // setupScheme creates a scheme to use for our fake clients so they know about our custom resources
 func setupScheme(t *testing.T) *runtime.Scheme {
 	sc := scheme.Scheme
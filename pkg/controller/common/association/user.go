@@ -6,25 +6,37 @@ package association
 
 import (
 	"bytes"
+	"time"
 
 	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	commonuser "github.com/elastic/cloud-on-k8s/pkg/controller/common/user"
+	esuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"golang.org/x/crypto/bcrypt"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // elasticsearchUserName identifies the associated user in Elasticsearch namespace.
-func elasticsearchUserName(associated commonv1beta1.Associated, userSuffix string) string {
+// Each credentials rotation gets a new user name, so that the previous user remains valid while the associated
+// resource is updated with the new credentials.
+func elasticsearchUserName(associated commonv1beta1.Associated, userSuffix string, rotation string) string {
 	// must be namespace-aware since we might have several associated instances running in
 	// different namespaces with the same name: we need one user for each
 	// in the Elasticsearch namespace
-	return associated.GetNamespace() + "-" + associated.GetName() + "-" + userSuffix
+	name := associated.GetNamespace() + "-" + associated.GetName() + "-" + userSuffix
+	if rotation == "" {
+		return name
+	}
+	return name + "-" + hash.HashObject(rotation)
 }
 
 // userSecretObjectName identifies the associated secret object.
@@ -33,8 +45,8 @@ func userSecretObjectName(associated commonv1beta1.Associated, userSuffix string
 	return associated.GetName() + "-" + userSuffix
 }
 
-// UserKey is the namespaced name to identify the user resource created by the controller.
-func UserKey(associated commonv1beta1.Associated, userSuffix string) types.NamespacedName {
+// UserKey is the namespaced name to identify the user resource created by the controller for the given rotation.
+func UserKey(associated commonv1beta1.Associated, userSuffix string, rotation string) types.NamespacedName {
 	esNamespace := associated.ElasticsearchRef().Namespace
 	if esNamespace == "" {
 		// no namespace given, default to the associated object's one
@@ -43,7 +55,7 @@ func UserKey(associated commonv1beta1.Associated, userSuffix string) types.Names
 	return types.NamespacedName{
 		// user lives in the ES namespace
 		Namespace: esNamespace,
-		Name:      elasticsearchUserName(associated, userSuffix),
+		Name:      elasticsearchUserName(associated, userSuffix, rotation),
 	}
 }
 
@@ -57,16 +69,41 @@ func secretKey(associated commonv1beta1.Associated, userSuffix string) types.Nam
 }
 
 // ClearTextSecretKeySelector creates a SecretKeySelector for the associated user secret
-func ClearTextSecretKeySelector(associated commonv1beta1.Associated, userSuffix string) *corev1.SecretKeySelector {
+func ClearTextSecretKeySelector(associated commonv1beta1.Associated, userSuffix string, rotation string) *corev1.SecretKeySelector {
 	return &corev1.SecretKeySelector{
 		LocalObjectReference: corev1.LocalObjectReference{
 			Name: userSecretObjectName(associated, userSuffix),
 		},
-		Key: elasticsearchUserName(associated, userSuffix),
+		Key: elasticsearchUserName(associated, userSuffix, rotation),
+	}
+}
+
+// UserRotation returns the credentials rotation the associated user is generated for: the rotation requested on
+// the Elasticsearch resource if the password was not generated for it yet, the rotation of the current password
+// otherwise.
+func UserRotation(
+	c k8s.Client,
+	associated commonv1beta1.Associated,
+	userSuffix string,
+	es v1beta1.Elasticsearch,
+) (string, error) {
+	rotation := commonuser.RequestedRotation(&es)
+	var current corev1.Secret
+	if err := c.Get(secretKey(associated, userSuffix), &current); err != nil {
+		if errors.IsNotFound(err) {
+			return rotation, nil
+		}
+		return "", err
+	}
+	if commonuser.NeedsRotation(rotation, current) {
+		return rotation, nil
 	}
+	return current.Annotations[commonuser.CredentialsRotationAnnotation], nil
 }
 
 // ReconcileEsUser creates a User resource and a corresponding secret or updates those as appropriate.
+// The users of the same associated resource generated for previous rotations are deleted by the Elasticsearch
+// controller once the rotation grace period is over.
 func ReconcileEsUser(
 	c k8s.Client,
 	s *runtime.Scheme,
@@ -74,12 +111,13 @@ func ReconcileEsUser(
 	labels map[string]string,
 	userRoles string,
 	userObjectSuffix string,
+	rotation string,
 	es v1beta1.Elasticsearch,
 ) error {
 	pw := commonuser.RandomPasswordBytes()
 
 	secKey := secretKey(associated, userObjectSuffix)
-	usrKey := UserKey(associated, userObjectSuffix)
+	usrKey := UserKey(associated, userObjectSuffix, rotation)
 	expectedSecret := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      secKey.Name,
@@ -90,6 +128,7 @@ func ReconcileEsUser(
 			usrKey.Name: pw,
 		},
 	}
+	commonuser.SetRotation(rotation, &expectedSecret)
 
 	reconciledSecret := corev1.Secret{}
 	err := reconciler.ReconcileResource(reconciler.Params{
@@ -100,10 +139,13 @@ func ReconcileEsUser(
 		Reconciled: &reconciledSecret,
 		NeedsUpdate: func() bool {
 			_, ok := reconciledSecret.Data[usrKey.Name]
-			return !ok || !hasExpectedLabels(&expectedSecret, &reconciledSecret)
+			return !ok || !hasExpectedLabels(&expectedSecret, &reconciledSecret) ||
+				commonuser.NeedsRotation(rotation, reconciledSecret)
 		},
 		UpdateReconciled: func() {
 			setExpectedLabels(&expectedSecret, &reconciledSecret)
+			// a new password is generated when a rotation is requested on the Elasticsearch resource
+			commonuser.SetRotation(rotation, &reconciledSecret)
 			reconciledSecret.Data = expectedSecret.Data
 		},
 	})
@@ -141,7 +183,7 @@ func ReconcileEsUser(
 	}
 
 	reconciledEsSecret := corev1.Secret{}
-	return reconciler.ReconcileResource(reconciler.Params{
+	if err := reconciler.ReconcileResource(reconciler.Params{
 		Client:     c,
 		Scheme:     s,
 		Owner:      &es, // user is owned by the ES resource
@@ -157,7 +199,40 @@ func ReconcileEsUser(
 			setExpectedLabels(expectedEsUser, &reconciledEsSecret)
 			reconciledEsSecret.Data = expectedEsUser.Data
 		},
-	})
+	}); err != nil {
+		return err
+	}
+
+	return expirePreviousEsUsers(c, usrKey, labels, time.Now())
+}
+
+// expirePreviousEsUsers sets the end of the rotation grace period on the users of the associated resource other than
+// the current one, which remain valid until then.
+func expirePreviousEsUsers(c k8s.Client, current types.NamespacedName, labels map[string]string, now time.Time) error {
+	matchLabels := client.MatchingLabels(map[string]string{common.TypeLabelName: commonuser.UserType})
+	for k, v := range labels {
+		matchLabels[k] = v
+	}
+	var users corev1.SecretList
+	if err := c.List(&users, client.InNamespace(current.Namespace), matchLabels); err != nil {
+		return err
+	}
+	for _, u := range users.Items {
+		if u.Name == current.Name {
+			continue
+		}
+		if _, exists := u.Annotations[esuser.ExpiresAtAnnotation]; exists {
+			continue
+		}
+		if u.Annotations == nil {
+			u.Annotations = make(map[string]string, 1)
+		}
+		u.Annotations[esuser.ExpiresAtAnnotation] = now.Add(esuser.CredentialsRotationGracePeriod).UTC().Format(time.RFC3339)
+		if err := c.Update(&u); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // hasExpectedLabels does a left-biased comparison ensuring all key/value pairs in expected exist in actual.
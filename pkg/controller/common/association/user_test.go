@@ -11,6 +11,7 @@ import (
 	estype "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	kbtype "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/user"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	elasticsearchuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
@@ -19,6 +20,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -229,6 +231,102 @@ func Test_reconcileEsUser(t *testing.T) {
 				require.Equal(t, "$2a$10$mE3yo/AkZgR4eVW9kbA1TeIQ40Jv6WaWU494rx4C6EhLvuY0BSg4e", string(userSecret.Data[user.PasswordHash]))
 			},
 		},
+		{
+			name: "Reconcile rotates the password under a new user name when requested on Elasticsearch",
+			args: args{
+				initialObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      userSecretName,
+							Labels: map[string]string{
+								kblabel.KibanaNameLabelName: kibanaFixture.Name,
+								common.TypeLabelName:        kblabel.Type,
+								associationLabelName:        kibanaFixture.Name,
+								associationLabelNamespace:   kibanaFixture.Namespace,
+							},
+						},
+						Data: map[string][]byte{
+							userName: []byte("my-secret-pw"),
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      userName,
+							Namespace: "default",
+							Labels: map[string]string{
+								associationLabelName:       kibanaFixture.Name,
+								associationLabelNamespace:  kibanaFixture.Namespace,
+								common.TypeLabelName:       user.UserType,
+								label.ClusterNameLabelName: esFixture.Name,
+							},
+						},
+						Data: map[string][]byte{
+							user.UserName:     []byte(userName),
+							user.PasswordHash: []byte("$2a$10$mE3yo/AkZgR4eVW9kbA1TeIQ40Jv6WaWU494rx4C6EhLvuY0BSg4e"),
+							user.UserRoles:    []byte(esuser.KibanaSystemUserBuiltinRole),
+						},
+					}},
+				kibana: kibanaFixture,
+				es: func() estype.Elasticsearch {
+					es := esFixture
+					es.Annotations = map[string]string{user.RotateCredentialsAnnotation: "2019-11-01"}
+					return es
+				}(),
+			},
+			wantErr: false,
+			postCondition: func(c k8s.Client) {
+				rotatedUserName := userName + "-" + hash.HashObject("2019-11-01")
+				var s corev1.Secret
+				assert.NoError(t, c.Get(types.NamespacedName{Name: userSecretName, Namespace: "default"}, &s))
+				assert.Equal(t, []string{rotatedUserName}, keys(s.Data))
+				assert.NotEqual(t, "my-secret-pw", string(s.Data[rotatedUserName]))
+				assert.Equal(t, "2019-11-01", s.Annotations[user.CredentialsRotationAnnotation])
+				var userSecret corev1.Secret
+				assert.NoError(t, c.Get(types.NamespacedName{Name: rotatedUserName, Namespace: "default"}, &userSecret))
+				assert.Equal(t, rotatedUserName, string(userSecret.Data[user.UserName]))
+				assert.NoError(t, bcrypt.CompareHashAndPassword(userSecret.Data[user.PasswordHash], s.Data[rotatedUserName]))
+				assert.Empty(t, userSecret.Annotations[esuser.ExpiresAtAnnotation])
+				// the previous user remains until the end of the grace period
+				var previousUserSecret corev1.Secret
+				assert.NoError(t, c.Get(types.NamespacedName{Name: userName, Namespace: "default"}, &previousUserSecret))
+				assert.NotEmpty(t, previousUserSecret.Annotations[esuser.ExpiresAtAnnotation])
+			},
+		},
+		{
+			name: "Reconcile keeps the user name of the last rotation",
+			args: args{
+				initialObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      userSecretName,
+							Labels: map[string]string{
+								kblabel.KibanaNameLabelName: kibanaFixture.Name,
+								common.TypeLabelName:        kblabel.Type,
+								associationLabelName:        kibanaFixture.Name,
+								associationLabelNamespace:   kibanaFixture.Namespace,
+							},
+							Annotations: map[string]string{
+								user.CredentialsRotationAnnotation: "2019-11-01",
+							},
+						},
+						Data: map[string][]byte{
+							userName + "-" + hash.HashObject("2019-11-01"): []byte("my-secret-pw"),
+						},
+					}},
+				kibana: kibanaFixture,
+				es:     esFixture,
+			},
+			wantErr: false,
+			postCondition: func(c k8s.Client) {
+				rotatedUserName := userName + "-" + hash.HashObject("2019-11-01")
+				var s corev1.Secret
+				assert.NoError(t, c.Get(types.NamespacedName{Name: userSecretName, Namespace: "default"}, &s))
+				assert.Equal(t, "my-secret-pw", string(s.Data[rotatedUserName]))
+				assert.NoError(t, c.Get(types.NamespacedName{Name: rotatedUserName, Namespace: "default"}, &corev1.Secret{}))
+			},
+		},
 		{
 			name: "Reconcile is namespace aware",
 			args: args{
@@ -265,6 +363,8 @@ func Test_reconcileEsUser(t *testing.T) {
 	for _, tt := range tests {
 		c := k8s.WrapClient(fake.NewFakeClient(tt.args.initialObjects...))
 		t.Run(tt.name, func(t *testing.T) {
+			rotation, err := UserRotation(c, &tt.args.kibana, "kibana-user", tt.args.es)
+			require.NoError(t, err)
 			if err := ReconcileEsUser(
 				c,
 				sc,
@@ -275,6 +375,7 @@ func Test_reconcileEsUser(t *testing.T) {
 				},
 				elasticsearchuser.KibanaSystemUserBuiltinRole,
 				"kibana-user",
+				rotation,
 				tt.args.es,
 			); (err != nil) != tt.wantErr {
 				t.Errorf("reconcileEsUser() error = %v, wantErr %v", err, tt.wantErr)
@@ -283,3 +384,11 @@ func Test_reconcileEsUser(t *testing.T) {
 		})
 	}
 }
+
+func keys(data map[string][]byte) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	return keys
+}
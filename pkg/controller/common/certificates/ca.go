@@ -5,8 +5,8 @@
 package certificates
 
 import (
+	"crypto"
 	cryptorand "crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"math/big"
@@ -23,7 +23,7 @@ var (
 // CA is a simple certificate authority
 type CA struct {
 	// PrivateKey is the CA private key
-	PrivateKey *rsa.PrivateKey
+	PrivateKey crypto.Signer
 	// Cert is the certificate used to issue new certificates
 	Cert *x509.Certificate
 }
@@ -33,7 +33,7 @@ type CA struct {
 type ValidatedCertificateTemplate x509.Certificate
 
 // NewCA returns a ca with the given private key and cert
-func NewCA(privateKey *rsa.PrivateKey, cert *x509.Certificate) *CA {
+func NewCA(privateKey crypto.Signer, cert *x509.Certificate) *CA {
 	return &CA{
 		PrivateKey: privateKey,
 		Cert:       cert,
@@ -45,7 +45,9 @@ type CABuilderOptions struct {
 	// Subject of the CA to build.
 	Subject pkix.Name
 	// PrivateKey to be used for signing certificates (auto-generated if not provided).
-	PrivateKey *rsa.PrivateKey
+	PrivateKey crypto.Signer
+	// KeyParams defines the private key to generate if not provided (defaults to DefaultKeyParams if not provided).
+	KeyParams *KeyParams
 	// ExpireIn defines in how much time will the CA expire (defaults to DefaultCertValidity if not provided).
 	ExpireIn *time.Duration
 }
@@ -60,7 +62,11 @@ func NewSelfSignedCA(options CABuilderOptions) (*CA, error) {
 
 	privateKey := options.PrivateKey
 	if privateKey == nil {
-		privateKey, err = rsa.GenerateKey(cryptorand.Reader, 2048)
+		keyParams := DefaultKeyParams
+		if options.KeyParams != nil {
+			keyParams = *options.KeyParams
+		}
+		privateKey, err = keyParams.GenerateKey()
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to generate the private key")
 		}
@@ -76,7 +82,6 @@ func NewSelfSignedCA(options CABuilderOptions) (*CA, error) {
 		Subject:               options.Subject,
 		NotBefore:             time.Now().Add(-1 * time.Minute),
 		NotAfter:              notAfter,
-		SignatureAlgorithm:    x509.SHA256WithRSA,
 		IsCA:                  true,
 		BasicConstraintsValid: true,
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -111,6 +116,8 @@ func (c *CA) CreateCertificate(
 	}
 	validatedCertificateTemplate.SerialNumber = serial
 	validatedCertificateTemplate.Issuer = c.Cert.Issuer
+	// the signature algorithm depends on the type of the CA private key, not on the key of the certificate
+	validatedCertificateTemplate.SignatureAlgorithm = x509.UnknownSignatureAlgorithm
 
 	certTemplate := x509.Certificate(validatedCertificateTemplate)
 
@@ -120,6 +120,15 @@ func Test_canReuseCA(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "valid ca with another key algorithm",
+			ca: func() *CA {
+				testCa, err := NewSelfSignedCA(CABuilderOptions{KeyParams: &KeyParams{Algorithm: ECDSAKeyAlgorithm, Size: 256}})
+				require.NoError(t, err)
+				return testCa
+			},
+			want: true,
+		},
 		{
 			name: "cert public key & private key mismatch",
 			ca: func() *CA {
@@ -162,8 +171,7 @@ func checkCASecrets(
 	// if an expected Ca was passed, it should match ca
 	if expectedCa != nil {
 		require.True(t, ca.Cert.Equal(expectedCa.Cert))
-		require.Equal(t, ca.PrivateKey.E, expectedCa.PrivateKey.E)
-		require.Equal(t, ca.PrivateKey.N, expectedCa.PrivateKey.N)
+		require.True(t, PrivateMatchesPublicKey(expectedCa.Cert.PublicKey, ca.PrivateKey))
 	}
 
 	// if a not expected Ca was passed, it should not match ca
@@ -186,14 +194,14 @@ func checkCASecrets(
 	require.NotNil(t, parsedCa)
 	// and return the ca
 	require.True(t, ca.Cert.Equal(parsedCa.Cert))
-	require.Equal(t, ca.PrivateKey.E, parsedCa.PrivateKey.E)
-	require.Equal(t, ca.PrivateKey.N, parsedCa.PrivateKey.N)
+	require.True(t, PrivateMatchesPublicKey(ca.Cert.PublicKey, parsedCa.PrivateKey))
 }
 
 func Test_renewCA(t *testing.T) {
 	testCa, err := NewSelfSignedCA(CABuilderOptions{})
 	require.NoError(t, err)
-	internalCASecret := internalSecretForCA(testCa, testNamer, &testCluster, nil, TransportCAType)
+	internalCASecret, err := internalSecretForCA(testCa, testNamer, &testCluster, nil, TransportCAType)
+	require.NoError(t, err)
 
 	err = v1beta1.AddToScheme(scheme.Scheme)
 	require.NoError(t, err)
@@ -218,7 +226,7 @@ func Test_renewCA(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ca, err := renewCA(tt.client, testNamer, &testCluster, nil, tt.expireIn, scheme.Scheme, TransportCAType)
+			ca, err := renewCA(tt.client, testNamer, &testCluster, nil, tt.expireIn, DefaultKeyParams, scheme.Scheme, TransportCAType)
 			require.NoError(t, err)
 			require.NotNil(t, ca)
 			assert.Equal(t, ca.Cert.Issuer.CommonName, testName+"-"+string(TransportCAType))
@@ -233,7 +241,8 @@ func TestReconcileCAForCluster(t *testing.T) {
 
 	validCa, err := NewSelfSignedCA(CABuilderOptions{})
 	require.NoError(t, err)
-	internalCASecret := internalSecretForCA(validCa, testNamer, &testCluster, nil, TransportCAType)
+	internalCASecret, err := internalSecretForCA(validCa, testNamer, &testCluster, nil, TransportCAType)
+	require.NoError(t, err)
 
 	internalCASecretWithoutPrivateKey := internalCASecret.DeepCopy()
 	delete(internalCASecretWithoutPrivateKey.Data, KeyFileName)
@@ -246,14 +255,16 @@ func TestReconcileCAForCluster(t *testing.T) {
 		ExpireIn: &soonToExpire,
 	})
 	require.NoError(t, err)
-	soonToExpireInternalCASecret := internalSecretForCA(
+	soonToExpireInternalCASecret, err := internalSecretForCA(
 		soonToExpireCa, testNamer, &testCluster, nil, TransportCAType,
 	)
+	require.NoError(t, err)
 
 	tests := []struct {
 		name             string
 		cl               k8s.Client
 		caCertValidity   time.Duration
+		keyParams        *KeyParams
 		shouldReuseCa    *CA // ca that should be reused
 		shouldNotReuseCa *CA // ca that should not be reused
 	}{
@@ -288,17 +299,40 @@ func TestReconcileCAForCluster(t *testing.T) {
 			shouldReuseCa:    nil,            // should create a new one
 			shouldNotReuseCa: soonToExpireCa, // and not reuse existing one
 		},
+		{
+			name:           "existing valid internal secret with other key params",
+			cl:             k8s.WrapClient(fake.NewFakeClient(&internalCASecret)),
+			caCertValidity: DefaultCertValidity,
+			keyParams:      &KeyParams{Algorithm: ECDSAKeyAlgorithm, Size: 384},
+			shouldReuseCa:  validCa, // should reuse existing one
+		},
+		{
+			name:             "existing internal cert is soon to expire with other key params",
+			cl:               k8s.WrapClient(fake.NewFakeClient(&soonToExpireInternalCASecret)),
+			caCertValidity:   DefaultCertValidity,
+			keyParams:        &KeyParams{Algorithm: ECDSAKeyAlgorithm, Size: 384},
+			shouldReuseCa:    nil,            // should create a new one with the key params
+			shouldNotReuseCa: soonToExpireCa, // and not reuse existing one
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			keyParams := DefaultKeyParams
+			if tt.keyParams != nil {
+				keyParams = *tt.keyParams
+			}
 			ca, err := ReconcileCAForOwner(
 				tt.cl, scheme.Scheme, testNamer, &testCluster, nil, TransportCAType, RotationParams{
 					Validity:     tt.caCertValidity,
 					RotateBefore: DefaultRotateBefore,
 				},
+				keyParams,
 			)
 			require.NoError(t, err)
 			require.NotNil(t, ca)
+			if tt.shouldReuseCa == nil {
+				require.True(t, keyParams.Matches(ca.PrivateKey))
+			}
 			checkCASecrets(
 				t, tt.cl, testCluster, TransportCAType, ca, tt.shouldReuseCa, tt.shouldNotReuseCa, tt.caCertValidity,
 			)
@@ -312,7 +346,8 @@ func Test_internalSecretForCA(t *testing.T) {
 
 	labels := map[string]string{"foo": "bar"}
 
-	internalSecret := internalSecretForCA(testCa, testNamer, &testCluster, labels, TransportCAType)
+	internalSecret, err := internalSecretForCA(testCa, testNamer, &testCluster, labels, TransportCAType)
+	require.NoError(t, err)
 
 	assert.Equal(t, testNamespace, internalSecret.Namespace)
 	assert.Equal(t, testName+"-test-transport-ca-internal", internalSecret.Name)
@@ -328,7 +363,8 @@ func Test_buildCAFromSecret(t *testing.T) {
 	testCa, err := NewSelfSignedCA(CABuilderOptions{})
 	require.NoError(t, err)
 
-	internalSecret := internalSecretForCA(testCa, testNamer, &testCluster, nil, TransportCAType)
+	internalSecret, err := internalSecretForCA(testCa, testNamer, &testCluster, nil, TransportCAType)
+	require.NoError(t, err)
 
 	internalSecretMissingCert := internalSecret.DeepCopy()
 	delete(internalSecretMissingCert.Data, CertFileName)
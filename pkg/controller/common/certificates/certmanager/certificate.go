@@ -0,0 +1,150 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package certmanager requests certificates from cert-manager (https://cert-manager.io) issuers.
+//
+// The cert-manager API types are not a dependency of the operator: Certificate resources are managed as
+// unstructured objects, and the operator only reads the secrets they are issued in.
+package certmanager
+
+import (
+	"reflect"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// DefaultIssuerGroup is the API group of the cert-manager issuers.
+	DefaultIssuerGroup = "cert-manager.io"
+	// DefaultIssuerKind is the kind of namespaced cert-manager issuers.
+	DefaultIssuerKind = "Issuer"
+)
+
+var (
+	// CertificateGVK is the GroupVersionKind of cert-manager Certificate resources.
+	CertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1alpha2", Kind: "Certificate"}
+	// CertificateListGVK is the GroupVersionKind of lists of cert-manager Certificate resources.
+	CertificateListGVK = CertificateGVK.GroupVersion().WithKind("CertificateList")
+)
+
+// CertificateSpec is the subset of the cert-manager Certificate spec managed by the operator.
+type CertificateSpec struct {
+	SecretName   string        `json:"secretName"`
+	CommonName   string        `json:"commonName,omitempty"`
+	DNSNames     []string      `json:"dnsNames,omitempty"`
+	IPAddresses  []string      `json:"ipAddresses,omitempty"`
+	Usages       []string      `json:"usages,omitempty"`
+	IssuerRef    IssuerRefSpec `json:"issuerRef"`
+	KeyAlgorithm string        `json:"keyAlgorithm,omitempty"`
+	KeySize      int           `json:"keySize,omitempty"`
+	KeyEncoding  string        `json:"keyEncoding,omitempty"`
+}
+
+// IssuerRefSpec references the issuer of a cert-manager Certificate.
+type IssuerRefSpec struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Group string `json:"group"`
+}
+
+// NewCertificateSpec returns the spec of a Certificate issued by the given issuer in the given secret, valid for
+// both server and client authentication. The private key is generated according to the given key params, and PKCS#1
+// or SEC 1 encoded like the keys generated by the operator.
+func NewCertificateSpec(
+	issuer v1beta1.IssuerRef,
+	secretName string,
+	commonName string,
+	dnsNames []string,
+	ipAddresses []string,
+	keyParams certificates.KeyParams,
+) CertificateSpec {
+	ref := IssuerRefSpec{Name: issuer.Name, Kind: issuer.Kind, Group: issuer.Group}
+	if ref.Kind == "" {
+		ref.Kind = DefaultIssuerKind
+	}
+	if ref.Group == "" {
+		ref.Group = DefaultIssuerGroup
+	}
+	return CertificateSpec{
+		SecretName:   secretName,
+		CommonName:   commonName,
+		DNSNames:     dnsNames,
+		IPAddresses:  ipAddresses,
+		Usages:       []string{"digital signature", "key encipherment", "server auth", "client auth"},
+		IssuerRef:    ref,
+		KeyAlgorithm: keyParams.Algorithm,
+		KeySize:      keyParams.Size,
+		KeyEncoding:  "pkcs1",
+	}
+}
+
+// NewCertificate returns a cert-manager Certificate with the given name and spec.
+func NewCertificate(namespace string, name string, labels map[string]string, spec CertificateSpec) (*unstructured.Unstructured, error) {
+	unstructuredSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&spec)
+	if err != nil {
+		return nil, err
+	}
+	cert := &unstructured.Unstructured{Object: map[string]interface{}{"spec": unstructuredSpec}}
+	cert.SetGroupVersionKind(CertificateGVK)
+	cert.SetNamespace(namespace)
+	cert.SetName(name)
+	cert.SetLabels(labels)
+	return cert, nil
+}
+
+// ReconcileCertificate ensures the given Certificate exists and is controlled by the owner, and returns the secret
+// issued by cert-manager for it. The returned secret is nil while cert-manager has not issued the certificate yet.
+func ReconcileCertificate(c k8s.Client, scheme *runtime.Scheme, owner metav1.Object, expected *unstructured.Unstructured) (*corev1.Secret, error) {
+	reconciled := &unstructured.Unstructured{}
+	reconciled.SetGroupVersionKind(CertificateGVK)
+	if err := reconciler.ReconcileResource(reconciler.Params{
+		Client:     c,
+		Scheme:     scheme,
+		Owner:      owner,
+		Expected:   expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return !reflect.DeepEqual(expected.Object["spec"], reconciled.Object["spec"]) ||
+				!reflect.DeepEqual(expected.GetLabels(), reconciled.GetLabels())
+		},
+		UpdateReconciled: func() {
+			reconciled.Object["spec"] = expected.Object["spec"]
+			reconciled.SetLabels(expected.GetLabels())
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	secretName, _, err := unstructured.NestedString(expected.Object, "spec", "secretName")
+	if err != nil {
+		return nil, err
+	}
+	return IssuedSecret(c, types.NamespacedName{Namespace: expected.GetNamespace(), Name: secretName})
+}
+
+// IssuedSecret returns the secret holding a certificate issued by cert-manager, or nil if it does not exist or does
+// not contain a certificate and its private key yet.
+func IssuedSecret(c k8s.Client, secretName types.NamespacedName) (*corev1.Secret, error) {
+	var secret corev1.Secret
+	if err := c.Get(secretName, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(secret.Data[certificates.CertFileName]) == 0 || len(secret.Data[certificates.KeyFileName]) == 0 {
+		return nil, nil
+	}
+	return &secret, nil
+}
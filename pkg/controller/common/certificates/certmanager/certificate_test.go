@@ -0,0 +1,102 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certmanager
+
+import (
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewCertificateSpec(t *testing.T) {
+	spec := NewCertificateSpec(v1beta1.IssuerRef{Name: "ca-issuer"}, "secret", "cn", []string{"cn"}, []string{"1.2.3.4"}, certificates.DefaultKeyParams)
+	require.Equal(t, IssuerRefSpec{Name: "ca-issuer", Kind: "Issuer", Group: "cert-manager.io"}, spec.IssuerRef)
+	require.Equal(t, "rsa", spec.KeyAlgorithm)
+	require.Equal(t, 2048, spec.KeySize)
+
+	spec = NewCertificateSpec(v1beta1.IssuerRef{Name: "acme", Kind: "ClusterIssuer", Group: "example.com"}, "secret", "cn", nil, nil,
+		certificates.KeyParams{Algorithm: certificates.ECDSAKeyAlgorithm, Size: 384})
+	require.Equal(t, IssuerRefSpec{Name: "acme", Kind: "ClusterIssuer", Group: "example.com"}, spec.IssuerRef)
+	require.Equal(t, "ecdsa", spec.KeyAlgorithm)
+	require.Equal(t, 384, spec.KeySize)
+}
+
+// issue simulates cert-manager issuing the certificate in the given secret.
+func issue(t *testing.T, c k8s.Client, secretName types.NamespacedName) {
+	require.NoError(t, c.Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: secretName.Namespace, Name: secretName.Name},
+		Data: map[string][]byte{
+			certificates.CAFileName:   []byte("ca"),
+			certificates.CertFileName: []byte("cert"),
+			certificates.KeyFileName:  []byte("key"),
+		},
+	}))
+}
+
+func TestReconcileCertificate(t *testing.T) {
+	owner := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "owner", UID: "uid"}}
+	c := k8s.WrapClient(fake.NewFakeClient())
+	issuer := v1beta1.IssuerRef{Name: "ca-issuer"}
+
+	expected, err := NewCertificate("ns", "cert", map[string]string{"a": "b"},
+		NewCertificateSpec(issuer, "issued", "cn", []string{"cn"}, nil, certificates.DefaultKeyParams))
+	require.NoError(t, err)
+
+	// the Certificate is created, but not issued yet
+	secret, err := ReconcileCertificate(c, scheme.Scheme, owner, expected)
+	require.NoError(t, err)
+	require.Nil(t, secret)
+	var actual unstructured.Unstructured
+	actual.SetGroupVersionKind(CertificateGVK)
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: "ns", Name: "cert"}, &actual))
+	require.Equal(t, expected.Object["spec"], actual.Object["spec"])
+	require.Equal(t, "owner", actual.GetOwnerReferences()[0].Name)
+
+	// the spec is updated
+	expected, err = NewCertificate("ns", "cert", map[string]string{"a": "b"},
+		NewCertificateSpec(issuer, "issued", "cn", []string{"cn", "other"}, nil, certificates.DefaultKeyParams))
+	require.NoError(t, err)
+	_, err = ReconcileCertificate(c, scheme.Scheme, owner, expected)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: "ns", Name: "cert"}, &actual))
+	dnsNames, _, err := unstructured.NestedStringSlice(actual.Object, "spec", "dnsNames")
+	require.NoError(t, err)
+	require.Equal(t, []string{"cn", "other"}, dnsNames)
+
+	// the certificate is issued
+	issue(t, c, types.NamespacedName{Namespace: "ns", Name: "issued"})
+	secret, err = ReconcileCertificate(c, scheme.Scheme, owner, expected)
+	require.NoError(t, err)
+	require.Equal(t, []byte("cert"), secret.Data[certificates.CertFileName])
+}
+
+func TestIssuedSecret(t *testing.T) {
+	c := k8s.WrapClient(fake.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "no-key"},
+		Data:       map[string][]byte{certificates.CertFileName: []byte("cert")},
+	}))
+
+	secret, err := IssuedSecret(c, types.NamespacedName{Namespace: "ns", Name: "missing"})
+	require.NoError(t, err)
+	require.Nil(t, secret)
+
+	secret, err = IssuedSecret(c, types.NamespacedName{Namespace: "ns", Name: "no-key"})
+	require.NoError(t, err)
+	require.Nil(t, secret)
+
+	issue(t, c, types.NamespacedName{Namespace: "ns", Name: "issued"})
+	secret, err = IssuedSecret(c, types.NamespacedName{Namespace: "ns", Name: "issued"})
+	require.NoError(t, err)
+	require.NotNil(t, secret)
+}
@@ -0,0 +1,182 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	"crypto/x509"
+	"sync"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// HTTPLayer identifies the certificates of the HTTP layer.
+	HTTPLayer = "http"
+	// TransportLayer identifies the certificates of the Elasticsearch transport layer.
+	TransportLayer = "transport"
+)
+
+var (
+	expiryDesc = prometheus.NewDesc(
+		"elastic_certificate_expiry_seconds",
+		"Number of seconds before the certificate expires.",
+		[]string{"namespace", "name", "kind", "layer", "certificate"},
+		nil,
+	)
+
+	expirations = newExpirationCollector(time.Now)
+)
+
+func init() {
+	metrics.Registry.MustRegister(expirations)
+}
+
+// NewExpiration returns when the given CA and the first expiring of the given certificates expire.
+// The CA may be nil if it is unknown.
+func NewExpiration(ca *x509.Certificate, certs []*x509.Certificate) v1beta1.CertificateExpiration {
+	var expiration v1beta1.CertificateExpiration
+	if ca != nil {
+		expiration.CANotAfter = notAfter(ca)
+	}
+	for _, cert := range certs {
+		if expiration.NotAfter == nil || cert.NotAfter.Before(expiration.NotAfter.Time) {
+			expiration.NotAfter = notAfter(cert)
+		}
+	}
+	return expiration
+}
+
+// notAfter returns the expiration date of the given certificate in the local time zone, as decoded from the API
+// server, so that the status does not change between reconciliations.
+func notAfter(cert *x509.Certificate) *metav1.Time {
+	t := metav1.NewTime(cert.NotAfter.Local())
+	return &t
+}
+
+// ReportExpiration exposes when the certificates of the given layer of the owner expire as Prometheus metrics, and
+// emits a warning event for each of them expiring within the given rotation margin. The operator rotates the
+// certificates it issues before they reach this margin: only user-provided certificates, or a failing rotation,
+// trigger a warning.
+// It returns when to reconcile again in order to warn about certificates reaching the margin.
+func ReportExpiration(
+	recorder record.EventRecorder,
+	owner runtime.Object,
+	layer string,
+	expiration v1beta1.CertificateExpiration,
+	rotateBefore time.Duration,
+) time.Duration {
+	kind := owner.GetObjectKind().GroupVersionKind().Kind
+	accessor, err := meta.Accessor(owner)
+	if err != nil {
+		log.Error(err, "Cannot report certificates expiration", "kind", kind)
+		return 0
+	}
+	expirations.observe(expirationKey{
+		kind:  kind,
+		owner: types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()},
+		layer: layer,
+	}, expiration)
+
+	now := time.Now()
+	var requeueIn time.Duration
+	for _, cert := range []struct {
+		description string
+		notAfter    *metav1.Time
+	}{
+		{description: "CA certificate", notAfter: expiration.CANotAfter},
+		{description: "certificate", notAfter: expiration.NotAfter},
+	} {
+		if cert.notAfter == nil {
+			continue
+		}
+		if now.After(cert.notAfter.Add(-rotateBefore)) {
+			recorder.Eventf(owner, corev1.EventTypeWarning, events.EventReasonCertificateExpiring,
+				"The %s %s expires on %s", layer, cert.description, cert.notAfter.UTC().Format(time.RFC3339))
+			continue
+		}
+		warnIn := ShouldRotateIn(now, cert.notAfter.Time, rotateBefore)
+		if requeueIn == 0 || warnIn < requeueIn {
+			requeueIn = warnIn
+		}
+	}
+	return requeueIn
+}
+
+// ForgetExpiration stops exposing the certificates expiration of the given owner, once it is deleted.
+func ForgetExpiration(kind string, owner types.NamespacedName) {
+	expirations.forget(kind, owner)
+}
+
+type expirationKey struct {
+	kind  string
+	owner types.NamespacedName
+	layer string
+}
+
+// expirationCollector is a Prometheus collector computing the time left before the observed certificates expire
+// when the metrics are collected, so that they do not depend on the reconciliation frequency.
+type expirationCollector struct {
+	mutex       sync.RWMutex
+	expirations map[expirationKey]v1beta1.CertificateExpiration
+	now         func() time.Time
+}
+
+func newExpirationCollector(now func() time.Time) *expirationCollector {
+	return &expirationCollector{
+		expirations: make(map[expirationKey]v1beta1.CertificateExpiration),
+		now:         now,
+	}
+}
+
+func (c *expirationCollector) observe(key expirationKey, expiration v1beta1.CertificateExpiration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.expirations[key] = expiration
+}
+
+func (c *expirationCollector) forget(kind string, owner types.NamespacedName) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key := range c.expirations {
+		if key.kind == kind && key.owner == owner {
+			delete(c.expirations, key)
+		}
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *expirationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- expiryDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *expirationCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	now := c.now()
+	for key, expiration := range c.expirations {
+		for certificate, notAfter := range map[string]*metav1.Time{"ca": expiration.CANotAfter, "leaf": expiration.NotAfter} {
+			if notAfter == nil {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(
+				expiryDesc,
+				prometheus.GaugeValue,
+				notAfter.Sub(now).Seconds(),
+				key.owner.Namespace, key.owner.Name, key.kind, key.layer, certificate,
+			)
+		}
+	}
+}
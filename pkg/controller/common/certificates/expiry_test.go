@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestNewExpiration(t *testing.T) {
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	ca := &x509.Certificate{NotAfter: now.Add(365 * 24 * time.Hour)}
+	first := &x509.Certificate{NotAfter: now.Add(time.Hour)}
+	second := &x509.Certificate{NotAfter: now.Add(2 * time.Hour)}
+
+	require.Equal(t, v1beta1.CertificateExpiration{}, NewExpiration(nil, nil))
+
+	expiration := NewExpiration(ca, []*x509.Certificate{second, first})
+	require.True(t, ca.NotAfter.Equal(expiration.CANotAfter.Time))
+	require.True(t, first.NotAfter.Equal(expiration.NotAfter.Time))
+
+	expiration = NewExpiration(nil, []*x509.Certificate{second})
+	require.Nil(t, expiration.CANotAfter)
+	require.True(t, second.NotAfter.Equal(expiration.NotAfter.Time))
+}
+
+func notAfterIn(d time.Duration) *metav1.Time {
+	t := metav1.NewTime(time.Now().Add(d))
+	return &t
+}
+
+func TestReportExpiration(t *testing.T) {
+	owner := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "owner"},
+	}
+	key := expirationKey{kind: "Secret", owner: types.NamespacedName{Namespace: "ns", Name: "owner"}, layer: HTTPLayer}
+
+	tests := []struct {
+		name          string
+		expiration    v1beta1.CertificateExpiration
+		wantEvents    []string
+		wantRequeueIn time.Duration
+	}{
+		{
+			name: "no certificate",
+		},
+		{
+			name:          "certificates outside of the rotation margin",
+			expiration:    v1beta1.CertificateExpiration{CANotAfter: notAfterIn(72 * time.Hour), NotAfter: notAfterIn(48 * time.Hour)},
+			wantRequeueIn: 24 * time.Hour,
+		},
+		{
+			name:          "certificate within the rotation margin",
+			expiration:    v1beta1.CertificateExpiration{CANotAfter: notAfterIn(72 * time.Hour), NotAfter: notAfterIn(time.Hour)},
+			wantEvents:    []string{"Warning CertificateExpiring The http certificate expires on"},
+			wantRequeueIn: 48 * time.Hour,
+		},
+		{
+			name:       "expired certificates",
+			expiration: v1beta1.CertificateExpiration{CANotAfter: notAfterIn(-time.Hour), NotAfter: notAfterIn(-time.Hour)},
+			wantEvents: []string{
+				"Warning CertificateExpiring The http CA certificate expires on",
+				"Warning CertificateExpiring The http certificate expires on",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			requeueIn := ReportExpiration(recorder, owner, HTTPLayer, tt.expiration, 24*time.Hour)
+			require.InDelta(t, tt.wantRequeueIn.Seconds(), requeueIn.Seconds(), 5)
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			require.Len(t, events, len(tt.wantEvents))
+			for i, event := range events {
+				require.True(t, strings.HasPrefix(event, tt.wantEvents[i]), event)
+			}
+			require.Equal(t, tt.expiration, expirations.expirations[key])
+		})
+	}
+
+	ForgetExpiration("Secret", key.owner)
+	require.NotContains(t, expirations.expirations, key)
+}
+
+func Test_expirationCollector(t *testing.T) {
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(now.Add(d))
+		return &t
+	}
+	es := types.NamespacedName{Namespace: "ns", Name: "es"}
+	kb := types.NamespacedName{Namespace: "ns", Name: "kb"}
+	collector := newExpirationCollector(func() time.Time { return now })
+	collector.observe(
+		expirationKey{kind: "Elasticsearch", owner: es, layer: HTTPLayer},
+		v1beta1.CertificateExpiration{CANotAfter: at(2 * time.Hour), NotAfter: at(time.Hour)},
+	)
+	collector.observe(
+		expirationKey{kind: "Elasticsearch", owner: es, layer: TransportLayer},
+		v1beta1.CertificateExpiration{NotAfter: at(-time.Minute)},
+	)
+	collector.observe(
+		expirationKey{kind: "Kibana", owner: kb, layer: HTTPLayer},
+		v1beta1.CertificateExpiration{CANotAfter: at(time.Minute), NotAfter: at(time.Second)},
+	)
+	collector.forget("Kibana", kb)
+
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP elastic_certificate_expiry_seconds Number of seconds before the certificate expires.
+# TYPE elastic_certificate_expiry_seconds gauge
+elastic_certificate_expiry_seconds{certificate="ca",kind="Elasticsearch",layer="http",name="es",namespace="ns"} 7200
+elastic_certificate_expiry_seconds{certificate="leaf",kind="Elasticsearch",layer="http",name="es",namespace="ns"} 3600
+elastic_certificate_expiry_seconds{certificate="leaf",kind="Elasticsearch",layer="transport",name="es",namespace="ns"} -60
+`)))
+}
@@ -8,6 +8,9 @@ const (
 	// CAFileName is used for the CA Certificates inside a secret
 	CAFileName = "ca.crt"
 
+	// CAKeyFileName is used for the CA Private Key inside a secret
+	CAKeyFileName = "ca.key"
+
 	// CertFileName is used for Certificates inside a secret
 	CertFileName = "tls.crt"
 
@@ -0,0 +1,33 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+// Fingerprint returns the SHA-256 fingerprint of the given certificate, in the format of
+// `openssl x509 -noout -fingerprint -sha256`.
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// PEMFingerprint returns the fingerprint of the first certificate of the given PEM data, or an empty string
+// if there is no valid certificate.
+func PEMFingerprint(pemData []byte) string {
+	certs, err := ParsePEMCerts(pemData)
+	if err != nil || len(certs) == 0 {
+		return ""
+	}
+	return Fingerprint(certs[0])
+}
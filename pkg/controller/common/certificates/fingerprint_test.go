@@ -0,0 +1,30 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(cryptorand.Reader, 1024)
+	require.NoError(t, err)
+	ca, err := NewSelfSignedCA(CABuilderOptions{Subject: pkix.Name{CommonName: "test"}, PrivateKey: privateKey})
+	require.NoError(t, err)
+
+	fingerprint := Fingerprint(ca.Cert)
+	require.Regexp(t, "^([0-9A-F]{2}:){31}[0-9A-F]{2}$", fingerprint)
+	require.NotEqual(t, fingerprint, Fingerprint(&x509.Certificate{Raw: []byte("other")}))
+
+	require.Equal(t, fingerprint, PEMFingerprint(EncodePEMCert(ca.Cert.Raw)))
+	require.Equal(t, "", PEMFingerprint(nil))
+	require.Equal(t, "", PEMFingerprint([]byte("not a certificate")))
+}
@@ -5,6 +5,7 @@
 package http
 
 import (
+	"crypto/x509"
 	"fmt"
 
 	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
@@ -34,6 +35,28 @@ func (s CertificatesSecret) KeyPem() []byte {
 	return s.Data[certificates.KeyFileName]
 }
 
+// Expiration returns when the CA and the host certificate expire. The CA is omitted if unknown, which happens with
+// user-provided certificates.
+func (s CertificatesSecret) Expiration() (v1beta1.CertificateExpiration, error) {
+	caCerts, err := certificates.ParsePEMCerts(s.CAPem())
+	if err != nil {
+		return v1beta1.CertificateExpiration{}, err
+	}
+	var ca *x509.Certificate
+	if len(caCerts) > 0 {
+		ca = caCerts[0]
+	}
+	certs, err := certificates.ParsePEMCerts(s.CertPem())
+	if err != nil {
+		return v1beta1.CertificateExpiration{}, err
+	}
+	if len(certs) > 0 {
+		// ignore the intermediate certificates of the chain
+		certs = certs[:1]
+	}
+	return certificates.NewExpiration(ca, certs), nil
+}
+
 // Validate checks that mandatory fields are present.
 // It does not check that the public key matches the private key.
 func (s CertificatesSecret) Validate() error {
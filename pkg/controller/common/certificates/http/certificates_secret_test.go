@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCertificatesSecret(t *testing.T) {
@@ -70,6 +71,29 @@ func TestCertificatesSecret(t *testing.T) {
 	}
 }
 
+func TestCertificatesSecret_Expiration(t *testing.T) {
+	ca, err := certificates.ParsePEMCerts(loadFileBytes("ca.crt"))
+	require.NoError(t, err)
+	tls, err := certificates.ParsePEMCerts(loadFileBytes("tls.crt"))
+	require.NoError(t, err)
+
+	expiration, err := CertificatesSecret{Data: map[string][]byte{
+		certificates.CAFileName:   loadFileBytes("ca.crt"),
+		certificates.CertFileName: loadFileBytes("chain.crt"),
+	}}.Expiration()
+	require.NoError(t, err)
+	require.True(t, ca[0].NotAfter.Equal(expiration.CANotAfter.Time))
+	require.True(t, tls[0].NotAfter.Equal(expiration.NotAfter.Time))
+
+	// user-provided certificates without the CA
+	expiration, err = CertificatesSecret{Data: map[string][]byte{
+		certificates.CertFileName: loadFileBytes("tls.crt"),
+	}}.Expiration()
+	require.NoError(t, err)
+	require.Nil(t, expiration.CANotAfter)
+	require.True(t, tls[0].NotAfter.Equal(expiration.NotAfter.Time))
+}
+
 func TestCertificatesSecret_Validate(t *testing.T) {
 	ca := loadFileBytes("ca.crt")
 	tls := loadFileBytes("tls.crt")
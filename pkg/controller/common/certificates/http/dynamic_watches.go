@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/finalizer"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/name"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
@@ -23,16 +24,21 @@ func httpCertificateWatchKey(namer name.Namer, ownerName string) string {
 func reconcileDynamicWatches(dynamicWatches watches.DynamicWatches, owner types.NamespacedName, namer name.Namer, tls v1beta1.TLSOptions) error {
 	// watch the Secret specified in es.Spec.HTTP.TLS.Certificate because if it changes we should reconcile the new
 	// user provided certificates.
+	secretName := tls.Certificate.SecretName
+	if tls.IssuerRef != nil {
+		// watch the Secret in which cert-manager issues the certificate instead
+		secretName = certificates.HTTPCertsIssuedSecretName(namer, owner.Name)
+	}
 	httpCertificateWatch := watches.NamedWatch{
 		Name: httpCertificateWatchKey(namer, owner.Name),
 		Watched: []types.NamespacedName{{
 			Namespace: owner.Namespace,
-			Name:      tls.Certificate.SecretName,
+			Name:      secretName,
 		}},
 		Watcher: owner,
 	}
 
-	if tls.Certificate.SecretName != "" {
+	if secretName != "" {
 		if err := dynamicWatches.Secrets.AddHandler(httpCertificateWatch); err != nil {
 			return err
 		}
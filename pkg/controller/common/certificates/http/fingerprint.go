@@ -0,0 +1,24 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package http
+
+import (
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// CertificateFingerprint returns the fingerprint of the HTTP certificate mounted in the pods of the given owner.
+func CertificateFingerprint(c k8s.Client, namer name.Namer, owner metav1.Object) (string, error) {
+	var secret corev1.Secret
+	key := types.NamespacedName{Namespace: owner.GetNamespace(), Name: certificates.HTTPCertsInternalSecretName(namer, owner.GetName())}
+	if err := c.Get(key, &secret); err != nil {
+		return "", err
+	}
+	return certificates.PEMFingerprint(secret.Data[certificates.CertFileName]), nil
+}
@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package http
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates/certmanager"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// reconcileIssuedCertificate requests the HTTP certificate from the cert-manager issuer referenced in the TLS options,
+// with the same names as a self-signed certificate. It returns an error while the certificate is not issued yet:
+// the issued secret is watched to resume the reconciliation.
+func reconcileIssuedCertificate(
+	c k8s.Client,
+	scheme *runtime.Scheme,
+	owner metav1.Object,
+	namer name.Namer,
+	tls v1beta1.TLSOptions,
+	labels map[string]string,
+	svcs []corev1.Service,
+	keyParams certificates.KeyParams,
+) (*CertificatesSecret, error) {
+	template := createValidatedHTTPCertificateTemplate(
+		k8s.ExtractNamespacedName(owner), namer, tls, svcs, &x509.CertificateRequest{}, 0,
+	)
+	ipAddresses := make([]string, 0, len(template.IPAddresses))
+	for _, ip := range template.IPAddresses {
+		ipAddresses = append(ipAddresses, ip.String())
+	}
+	secretName := certificates.HTTPCertsIssuedSecretName(namer, owner.GetName())
+	expected, err := certmanager.NewCertificate(
+		owner.GetNamespace(),
+		secretName,
+		labels,
+		certmanager.NewCertificateSpec(*tls.IssuerRef, secretName, template.Subject.CommonName, template.DNSNames, ipAddresses, keyParams),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := certmanager.ReconcileCertificate(c, scheme, owner, expected)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("waiting for cert-manager to issue the HTTP certificate in secret %s", secretName)
+	}
+	result := CertificatesSecret(*secret)
+	return &result, nil
+}
@@ -5,8 +5,8 @@
 package http
 
 import (
+	"crypto"
 	cryptorand "crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"net"
@@ -33,6 +33,15 @@ var (
 	log = logf.Log.WithName("http")
 )
 
+// SelfSignedKeyParams returns the params of the private keys generated for the self-signed HTTP CA and certificate:
+// the operator defaults, unless overridden in the TLS options.
+func SelfSignedKeyParams(tls v1beta1.TLSOptions, defaults certificates.KeyParams) certificates.KeyParams {
+	if tls.SelfSignedCertificate == nil {
+		return defaults
+	}
+	return defaults.Override(tls.SelfSignedCertificate.KeyAlgorithm, tls.SelfSignedCertificate.KeySize)
+}
+
 // ReconcileHTTPCertificates reconciles the internal resources for the HTTP certificate.
 func ReconcileHTTPCertificates(
 	driver driver.Interface,
@@ -43,6 +52,7 @@ func ReconcileHTTPCertificates(
 	labels map[string]string,
 	services []corev1.Service,
 	rotationParams certificates.RotationParams,
+	keyParams certificates.KeyParams,
 ) (*CertificatesSecret, error) {
 	ownerNSN := k8s.ExtractNamespacedName(owner)
 	customCertificates, err := GetCustomCertificates(driver.K8sClient(), ownerNSN, tls)
@@ -54,8 +64,15 @@ func ReconcileHTTPCertificates(
 		return nil, err
 	}
 
+	if tls.IssuerRef != nil {
+		customCertificates, err = reconcileIssuedCertificate(driver.K8sClient(), driver.Scheme(), owner, namer, tls, labels, services, keyParams)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	internalCerts, err := reconcileHTTPInternalCertificatesSecret(
-		driver.K8sClient(), driver.Scheme(), owner, namer, tls, labels, services, customCertificates, ca, rotationParams,
+		driver.K8sClient(), driver.Scheme(), owner, namer, tls, labels, services, customCertificates, ca, rotationParams, keyParams,
 	)
 	if err != nil {
 		return nil, err
@@ -76,6 +93,7 @@ func reconcileHTTPInternalCertificatesSecret(
 	customCertificates *CertificatesSecret,
 	ca *certificates.CA,
 	rotationParams certificates.RotationParams,
+	keyParams certificates.KeyParams,
 ) (*CertificatesSecret, error) {
 	secret := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -132,7 +150,7 @@ func reconcileHTTPInternalCertificatesSecret(
 		}
 	} else {
 		selfSignedNeedsUpdate, err := ensureInternalSelfSignedCertificateSecretContents(
-			&secret, k8s.ExtractNamespacedName(owner), namer, tls, svcs, ca, rotationParams,
+			&secret, k8s.ExtractNamespacedName(owner), namer, tls, svcs, ca, rotationParams, keyParams,
 		)
 		if err != nil {
 			return nil, err
@@ -170,17 +188,21 @@ func ensureInternalSelfSignedCertificateSecretContents(
 	svcs []corev1.Service,
 	ca *certificates.CA,
 	rotationParam certificates.RotationParams,
+	keyParams certificates.KeyParams,
 ) (bool, error) {
 	secretWasChanged := false
 
-	// verify that the secret contains a parsable private key, create if it does not exist
-	var privateKey *rsa.PrivateKey
+	// verify that the secret contains a parsable private key matching the key params, create if it does not exist
+	var privateKey crypto.Signer
 	needsNewPrivateKey := true
 	if privateKeyData, ok := secret.Data[certificates.KeyFileName]; ok {
 		storedPrivateKey, err := certificates.ParsePEMPrivateKey(privateKeyData)
-		if err != nil {
+		switch {
+		case err != nil:
 			log.Error(err, "Unable to parse stored private key", "namespace", secret.Namespace, "secret_name", secret.Name)
-		} else {
+		case !keyParams.Matches(storedPrivateKey):
+			log.Info("Stored private key does not match the key params", "namespace", secret.Namespace, "secret_name", secret.Name)
+		default:
 			needsNewPrivateKey = false
 			privateKey = storedPrivateKey
 		}
@@ -188,18 +210,22 @@ func ensureInternalSelfSignedCertificateSecretContents(
 
 	// if we need a new private key, generate it
 	if needsNewPrivateKey {
-		generatedPrivateKey, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+		generatedPrivateKey, err := keyParams.GenerateKey()
+		if err != nil {
+			return secretWasChanged, err
+		}
+		encodedPrivateKey, err := certificates.EncodePEMPrivateKey(generatedPrivateKey)
 		if err != nil {
 			return secretWasChanged, err
 		}
 
 		privateKey = generatedPrivateKey
 		secretWasChanged = true
-		secret.Data[certificates.KeyFileName] = certificates.EncodePEMPrivateKey(*privateKey)
+		secret.Data[certificates.KeyFileName] = encodedPrivateKey
 	}
 
-	// check if the existing cert should be re-issued
-	if shouldIssueNewHTTPCertificate(owner, namer, tls, secret, svcs, ca, rotationParam.RotateBefore) {
+	// check if the existing cert should be re-issued, it must be if it belongs to the previous private key
+	if needsNewPrivateKey || shouldIssueNewHTTPCertificate(owner, namer, tls, secret, svcs, ca, rotationParam.RotateBefore) {
 		log.Info(
 			"Issuing new HTTP certificate",
 			"namespace", secret.Namespace,
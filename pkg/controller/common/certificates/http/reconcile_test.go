@@ -130,6 +130,30 @@ func TestReconcileHTTPCertificates(t *testing.T) {
 				assert.Contains(t, cs.Data, certificates.CertFileName)
 			},
 		},
+		{
+			name: "should generate a private key according to the self-signed certificate key params",
+			args: args{
+				c: k8s.WrapClient(fake.NewFakeClient()),
+				es: v1beta1.Elasticsearch{
+					ObjectMeta: v1.ObjectMeta{Name: "test-es-name", Namespace: "test-namespace"},
+					Spec: v1beta1.ElasticsearchSpec{
+						HTTP: commonv1beta1.HTTPConfig{
+							TLS: commonv1beta1.TLSOptions{
+								SelfSignedCertificate: &commonv1beta1.SelfSignedCertificate{
+									KeyAlgorithm: certificates.ECDSAKeyAlgorithm,
+								},
+							},
+						},
+					},
+				},
+				ca: testCA,
+			},
+			want: func(t *testing.T, cs *CertificatesSecret) {
+				key, err := certificates.ParsePEMPrivateKey(cs.Data[certificates.KeyFileName])
+				require.NoError(t, err)
+				assert.True(t, certificates.KeyParams{Algorithm: certificates.ECDSAKeyAlgorithm, Size: 256}.Matches(key))
+			},
+		},
 		{
 			name: "should use custom certificates if provided",
 			args: args{
@@ -159,6 +183,36 @@ func TestReconcileHTTPCertificates(t *testing.T) {
 				assert.Equal(t, cs.Data[certificates.CertFileName], tls)
 			},
 		},
+		{
+			name: "should wait for cert-manager to issue the certificate",
+			args: args{
+				c:  k8s.WrapClient(fake.NewFakeClient()),
+				es: withIssuer(testES),
+				ca: testCA,
+			},
+			want: func(t *testing.T, cs *CertificatesSecret) {
+				assert.Nil(t, cs)
+			},
+			wantErr: true,
+		},
+		{
+			name: "should use the certificate issued by cert-manager",
+			args: args{
+				c: k8s.WrapClient(fake.NewFakeClient(&corev1.Secret{
+					ObjectMeta: v1.ObjectMeta{Name: "test-es-name-es-http-certs-issued", Namespace: "test-namespace"},
+					Data: map[string][]byte{
+						certificates.CertFileName: tls,
+						certificates.KeyFileName:  key,
+					},
+				})),
+				es: withIssuer(testES),
+				ca: testCA,
+			},
+			want: func(t *testing.T, cs *CertificatesSecret) {
+				assert.Equal(t, cs.Data[certificates.KeyFileName], key)
+				assert.Equal(t, cs.Data[certificates.CertFileName], tls)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -176,6 +230,7 @@ func TestReconcileHTTPCertificates(t *testing.T) {
 					Validity:     certificates.DefaultCertValidity,
 					RotateBefore: certificates.DefaultRotateBefore,
 				},
+				SelfSignedKeyParams(tt.args.es.Spec.HTTP.TLS, certificates.DefaultKeyParams),
 			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ReconcileHTTPCertificates() error = %v, wantErr %v", err, tt.wantErr)
@@ -186,6 +241,11 @@ func TestReconcileHTTPCertificates(t *testing.T) {
 	}
 }
 
+func withIssuer(es v1beta1.Elasticsearch) v1beta1.Elasticsearch {
+	es.Spec.HTTP.TLS.IssuerRef = &commonv1beta1.IssuerRef{Name: "ca-issuer"}
+	return es
+}
+
 func Test_createValidatedHTTPCertificateTemplate(t *testing.T) {
 	sanDNS1 := "my.dns.com"
 	sanDNS2 := "my.second.dns.com"
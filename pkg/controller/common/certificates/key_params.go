@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"crypto/rsa"
+	"fmt"
+)
+
+const (
+	// RSAKeyAlgorithm generates RSA private keys.
+	RSAKeyAlgorithm = "rsa"
+	// ECDSAKeyAlgorithm generates ECDSA private keys.
+	ECDSAKeyAlgorithm = "ecdsa"
+)
+
+var (
+	// DefaultKeyParams generates 2048 bits RSA private keys.
+	DefaultKeyParams = KeyParams{Algorithm: RSAKeyAlgorithm, Size: 2048}
+
+	// defaultKeySizes are the key sizes used when only the algorithm is specified.
+	defaultKeySizes = map[string]int{RSAKeyAlgorithm: 2048, ECDSAKeyAlgorithm: 256}
+	// curves are the elliptic curves of the supported ECDSA key sizes.
+	curves = map[int]elliptic.Curve{256: elliptic.P256(), 384: elliptic.P384()}
+)
+
+// KeyParams defines the algorithm and size of the private keys generated by the operator.
+type KeyParams struct {
+	// Algorithm is either rsa or ecdsa.
+	Algorithm string
+	// Size is the number of bits of RSA keys (2048, 3072 or 4096), or the size of the curve of ECDSA keys (256 or 384).
+	Size int
+}
+
+// Validate returns an error if the algorithm or the key size is not supported.
+func (p KeyParams) Validate() error {
+	switch p.Algorithm {
+	case RSAKeyAlgorithm:
+		if p.Size != 2048 && p.Size != 3072 && p.Size != 4096 {
+			return fmt.Errorf("unsupported RSA key size %d, expected 2048, 3072 or 4096", p.Size)
+		}
+	case ECDSAKeyAlgorithm:
+		if _, supported := curves[p.Size]; !supported {
+			return fmt.Errorf("unsupported ECDSA key size %d, expected 256 or 384", p.Size)
+		}
+	default:
+		return fmt.Errorf("unsupported key algorithm %s, expected %s or %s", p.Algorithm, RSAKeyAlgorithm, ECDSAKeyAlgorithm)
+	}
+	return nil
+}
+
+// Override returns the params with the given algorithm and size, if they are set. The size defaults to the default
+// size of the given algorithm, if it differs from the current one.
+func (p KeyParams) Override(algorithm string, size int) KeyParams {
+	if algorithm != "" && algorithm != p.Algorithm {
+		p = KeyParams{Algorithm: algorithm, Size: defaultKeySizes[algorithm]}
+	}
+	if size != 0 {
+		p.Size = size
+	}
+	return p
+}
+
+// GenerateKey generates a new private key according to the params.
+func (p KeyParams) GenerateKey() (crypto.Signer, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if p.Algorithm == ECDSAKeyAlgorithm {
+		return ecdsa.GenerateKey(curves[p.Size], cryptorand.Reader)
+	}
+	return rsa.GenerateKey(cryptorand.Reader, p.Size)
+}
+
+// Matches returns true if the given private key has the algorithm and size of the params.
+func (p KeyParams) Matches(privateKey crypto.Signer) bool {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return p.Algorithm == RSAKeyAlgorithm && key.N.BitLen() == p.Size
+	case *ecdsa.PrivateKey:
+		return p.Algorithm == ECDSAKeyAlgorithm && key.Curve.Params().BitSize == p.Size
+	default:
+		return false
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyParams_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  KeyParams
+		wantErr string
+	}{
+		{
+			name:   "default",
+			params: DefaultKeyParams,
+		},
+		{
+			name:   "RSA 4096",
+			params: KeyParams{Algorithm: RSAKeyAlgorithm, Size: 4096},
+		},
+		{
+			name:   "ECDSA 384",
+			params: KeyParams{Algorithm: ECDSAKeyAlgorithm, Size: 384},
+		},
+		{
+			name:    "RSA 1024",
+			params:  KeyParams{Algorithm: RSAKeyAlgorithm, Size: 1024},
+			wantErr: "unsupported RSA key size 1024, expected 2048, 3072 or 4096",
+		},
+		{
+			name:    "ECDSA 521",
+			params:  KeyParams{Algorithm: ECDSAKeyAlgorithm, Size: 521},
+			wantErr: "unsupported ECDSA key size 521, expected 256 or 384",
+		},
+		{
+			name:    "unknown algorithm",
+			params:  KeyParams{Algorithm: "dsa", Size: 2048},
+			wantErr: "unsupported key algorithm dsa, expected rsa or ecdsa",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestKeyParams_Override(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    KeyParams
+		algorithm string
+		size      int
+		want      KeyParams
+	}{
+		{
+			name:   "no override",
+			params: DefaultKeyParams,
+			want:   DefaultKeyParams,
+		},
+		{
+			name:   "size only",
+			params: DefaultKeyParams,
+			size:   4096,
+			want:   KeyParams{Algorithm: RSAKeyAlgorithm, Size: 4096},
+		},
+		{
+			name:      "same algorithm keeps the size",
+			params:    KeyParams{Algorithm: RSAKeyAlgorithm, Size: 3072},
+			algorithm: RSAKeyAlgorithm,
+			want:      KeyParams{Algorithm: RSAKeyAlgorithm, Size: 3072},
+		},
+		{
+			name:      "other algorithm defaults the size",
+			params:    KeyParams{Algorithm: RSAKeyAlgorithm, Size: 3072},
+			algorithm: ECDSAKeyAlgorithm,
+			want:      KeyParams{Algorithm: ECDSAKeyAlgorithm, Size: 256},
+		},
+		{
+			name:      "algorithm and size",
+			params:    DefaultKeyParams,
+			algorithm: ECDSAKeyAlgorithm,
+			size:      384,
+			want:      KeyParams{Algorithm: ECDSAKeyAlgorithm, Size: 384},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.params.Override(tt.algorithm, tt.size))
+		})
+	}
+}
+
+func TestKeyParams_GenerateKey(t *testing.T) {
+	rsaKey, err := DefaultKeyParams.GenerateKey()
+	require.NoError(t, err)
+	require.IsType(t, &rsa.PrivateKey{}, rsaKey)
+	require.True(t, DefaultKeyParams.Matches(rsaKey))
+
+	ecdsaParams := KeyParams{Algorithm: ECDSAKeyAlgorithm, Size: 384}
+	ecdsaKey, err := ecdsaParams.GenerateKey()
+	require.NoError(t, err)
+	require.IsType(t, &ecdsa.PrivateKey{}, ecdsaKey)
+	require.True(t, ecdsaParams.Matches(ecdsaKey))
+
+	require.False(t, DefaultKeyParams.Matches(ecdsaKey))
+	require.False(t, ecdsaParams.Matches(rsaKey))
+	require.False(t, KeyParams{Algorithm: ECDSAKeyAlgorithm, Size: 256}.Matches(ecdsaKey))
+	require.False(t, KeyParams{Algorithm: RSAKeyAlgorithm, Size: 4096}.Matches(rsaKey))
+
+	_, err = KeyParams{Algorithm: RSAKeyAlgorithm, Size: 1024}.GenerateKey()
+	require.Error(t, err)
+}
@@ -5,21 +5,26 @@
 package certificates
 
 import (
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/rsa"
 
 	"github.com/pkg/errors"
 )
 
 // PrivateMatchesPublicKey returns true if the public and private keys correspond to each other.
-func PrivateMatchesPublicKey(publicKey interface{}, privateKey rsa.PrivateKey) bool {
-	pubKey, ok := publicKey.(*rsa.PublicKey)
-	if !ok {
-		log.Error(errors.New("Public key is not an RSA public key"), "")
+func PrivateMatchesPublicKey(publicKey interface{}, privateKey crypto.Signer) bool {
+	switch pubKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		key, ok := privateKey.Public().(*rsa.PublicKey)
+		// check that public and private keys share the same modulus and exponent
+		return ok && pubKey.N.Cmp(key.N) == 0 && pubKey.E == key.E
+	case *ecdsa.PublicKey:
+		key, ok := privateKey.Public().(*ecdsa.PublicKey)
+		// check that public and private keys share the same curve and point
+		return ok && pubKey.Curve == key.Curve && pubKey.X.Cmp(key.X) == 0 && pubKey.Y.Cmp(key.Y) == 0
+	default:
+		log.Error(errors.New("Public key is neither an RSA nor an ECDSA public key"), "")
 		return false
 	}
-	// check that public and private keys share the same modulus and exponent
-	if pubKey.N.Cmp(privateKey.N) != 0 || pubKey.E != privateKey.E {
-		return false
-	}
-	return true
 }
@@ -7,6 +7,9 @@
 package certificates
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	cryptorand "crypto/rand"
 	"crypto/rsa"
 	"testing"
@@ -19,22 +22,44 @@ func Test_PrivateMatchesPublicKey(t *testing.T) {
 	require.NoError(t, err)
 	privateKey2, err := rsa.GenerateKey(cryptorand.Reader, 2048)
 	require.NoError(t, err)
+	ecdsaKey1, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	require.NoError(t, err)
+	ecdsaKey2, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	require.NoError(t, err)
 	tests := []struct {
 		name       string
 		publicKey  interface{}
-		privateKey rsa.PrivateKey
+		privateKey crypto.Signer
 		want       bool
 	}{
 		{
 			name:       "with matching public and private keys",
 			publicKey:  privateKey1.Public(),
-			privateKey: *privateKey1,
+			privateKey: privateKey1,
 			want:       true,
 		},
 		{
 			name:       "with non-matching public and private keys",
 			publicKey:  privateKey1.Public(),
-			privateKey: *privateKey2,
+			privateKey: privateKey2,
+			want:       false,
+		},
+		{
+			name:       "with matching ECDSA public and private keys",
+			publicKey:  ecdsaKey1.Public(),
+			privateKey: ecdsaKey1,
+			want:       true,
+		},
+		{
+			name:       "with non-matching ECDSA public and private keys",
+			publicKey:  ecdsaKey1.Public(),
+			privateKey: ecdsaKey2,
+			want:       false,
+		},
+		{
+			name:       "with public and private keys of different algorithms",
+			publicKey:  privateKey1.Public(),
+			privateKey: ecdsaKey1,
 			want:       false,
 		},
 	}
@@ -11,6 +11,7 @@ import (
 const (
 	certsPublicSecretName   = "certs-public"
 	certsInternalSecretName = "certs-internal"
+	certsIssuedSecretName   = "certs-issued"
 )
 
 func PublicSecretName(namer name.Namer, ownerName string, caType CAType) string {
@@ -20,3 +21,8 @@ func PublicSecretName(namer name.Namer, ownerName string, caType CAType) string
 func HTTPCertsInternalSecretName(namer name.Namer, ownerName string) string {
 	return namer.Suffix(ownerName, string(HTTPCAType), certsInternalSecretName)
 }
+
+// HTTPCertsIssuedSecretName returns the name of the secret in which cert-manager issues the HTTP certificate.
+func HTTPCertsIssuedSecretName(namer name.Namer, ownerName string) string {
+	return namer.Suffix(ownerName, string(HTTPCAType), certsIssuedSecretName)
+}
@@ -6,6 +6,8 @@ package certificates
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -49,16 +51,30 @@ func EncodePEMCert(certBlocks ...[]byte) []byte {
 	return buf.Bytes()
 }
 
-// EncodePEMPrivateKey encodes the given private key in the PEM format
-func EncodePEMPrivateKey(privateKey rsa.PrivateKey) []byte {
-	return pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(&privateKey),
-	})
+// EncodePEMPrivateKey encodes the given private key in the PEM format: PKCS#1 for RSA keys, SEC 1 for ECDSA keys.
+func EncodePEMPrivateKey(privateKey crypto.Signer) ([]byte, error) {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}), nil
+	case *ecdsa.PrivateKey:
+		data, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "EC PRIVATE KEY",
+			Bytes: data,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", privateKey)
+	}
 }
 
-// ParsePEMPrivateKey parses the given private key in the PEM format
-func ParsePEMPrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+// ParsePEMPrivateKey parses the given RSA or ECDSA private key in the PEM format
+func ParsePEMPrivateKey(pemData []byte) (crypto.Signer, error) {
 	block, _ := pem.Decode(pemData)
 	if block == nil {
 		return nil, errors.New("failed to parse PEM block containing private key")
@@ -69,21 +85,25 @@ func ParsePEMPrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
 		return parsePKCS8PrivateKey(block.Bytes)
 	case block.Type == "RSA PRIVATE KEY" && len(block.Headers) == 0:
 		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case block.Type == "EC PRIVATE KEY" && len(block.Headers) == 0:
+		return x509.ParseECPrivateKey(block.Bytes)
 	default:
-		return nil, errors.New("expected PEM block to contain an RSA private key")
+		return nil, errors.New("expected PEM block to contain an RSA or ECDSA private key")
 	}
 }
 
-func parsePKCS8PrivateKey(block []byte) (*rsa.PrivateKey, error) {
+func parsePKCS8PrivateKey(block []byte) (crypto.Signer, error) {
 	key, err := x509.ParsePKCS8PrivateKey(block)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse private key")
 	}
 
-	rsaKey, ok := key.(*rsa.PrivateKey)
-	if !ok {
-		return nil, fmt.Errorf("expected an RSA private key but got %t", key)
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case *ecdsa.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("expected an RSA or ECDSA private key but got %T", key)
 	}
-
-	return rsaKey, nil
 }
@@ -0,0 +1,81 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePEMPrivateKey(t *testing.T) {
+	rsaKey, err := DefaultKeyParams.GenerateKey()
+	require.NoError(t, err)
+	ecdsaKey, err := KeyParams{Algorithm: ECDSAKeyAlgorithm, Size: 256}.GenerateKey()
+	require.NoError(t, err)
+
+	encodePKCS8 := func(key crypto.Signer) []byte {
+		data, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: data})
+	}
+	encode := func(key crypto.Signer) []byte {
+		data, err := EncodePEMPrivateKey(key)
+		require.NoError(t, err)
+		return data
+	}
+
+	tests := []struct {
+		name    string
+		pemData []byte
+		want    crypto.Signer
+		wantErr bool
+	}{
+		{
+			name:    "PKCS#1 RSA key",
+			pemData: encode(rsaKey),
+			want:    rsaKey,
+		},
+		{
+			name:    "SEC 1 ECDSA key",
+			pemData: encode(ecdsaKey),
+			want:    ecdsaKey,
+		},
+		{
+			name:    "PKCS#8 RSA key",
+			pemData: encodePKCS8(rsaKey),
+			want:    rsaKey,
+		},
+		{
+			name:    "PKCS#8 ECDSA key",
+			pemData: encodePKCS8(ecdsaKey),
+			want:    ecdsaKey,
+		},
+		{
+			name:    "not a PEM block",
+			pemData: []byte("not a key"),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported PEM block",
+			pemData: pem.EncodeToMemory(&pem.Block{Type: "DSA PRIVATE KEY", Bytes: []byte("key")}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePEMPrivateKey(tt.pemData)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want.Public(), got.Public())
+		})
+	}
+}
@@ -83,3 +83,15 @@ func WithTemplateHash(d appsv1.Deployment) appsv1.Deployment {
 	dCopy.Labels = hash.SetTemplateHashLabel(dCopy.Labels, dCopy)
 	return dCopy
 }
+
+// IsRolledOut returns true if all the replicas of the given deployment run its latest pod template and are available.
+func IsRolledOut(d appsv1.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.Replicas == replicas &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.AvailableReplicas == replicas
+}
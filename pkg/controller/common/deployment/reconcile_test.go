@@ -94,3 +94,49 @@ func TestReconcile(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, reconciled, retrieved)
 }
+
+func TestIsRolledOut(t *testing.T) {
+	rolledOut := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: common.Int32(2)},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           2,
+			UpdatedReplicas:    2,
+			AvailableReplicas:  2,
+		},
+	}
+	tests := []struct {
+		name   string
+		mutate func(d *appsv1.Deployment)
+		want   bool
+	}{
+		{
+			name:   "rolled out",
+			mutate: func(d *appsv1.Deployment) {},
+			want:   true,
+		},
+		{
+			name:   "new generation not observed yet",
+			mutate: func(d *appsv1.Deployment) { d.Generation = 3 },
+			want:   false,
+		},
+		{
+			name:   "rolling update in progress",
+			mutate: func(d *appsv1.Deployment) { d.Status.UpdatedReplicas = 1; d.Status.Replicas = 3 },
+			want:   false,
+		},
+		{
+			name:   "updated replica not available yet",
+			mutate: func(d *appsv1.Deployment) { d.Status.AvailableReplicas = 1 },
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := rolledOut.DeepCopy()
+			tt.mutate(d)
+			require.Equal(t, tt.want, IsRolledOut(*d))
+		})
+	}
+}
@@ -22,6 +22,8 @@ const (
 	EventReasonStateChange = "StateChange"
 	// EventReasonRestart describes events where one or multiple Elasticsearch nodes are scheduled for a restart.
 	EventReasonRestart = "Restart"
+	// EventReasonCertificateExpiring describes events where a certificate is about to expire.
+	EventReasonCertificateExpiring = "CertificateExpiring"
 )
 
 // Event reasons for Association controllers
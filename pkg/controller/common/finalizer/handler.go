@@ -62,6 +62,23 @@ func (h *Handler) Handle(resource runtime.Object, finalizers ...Finalizer) error
 	return finalizerErr
 }
 
+// Release executes the given finalizers registered for the resource and removes them from it, as if the resource was
+// deleted. It is used for resources which are not reconciled by this operator anymore, but by another one which
+// registers its own finalizers.
+func (h *Handler) Release(resource runtime.Object, finalizers ...Finalizer) error {
+	metaObject, err := meta.Accessor(resource)
+	if err != nil {
+		return err
+	}
+	needUpdate, finalizerErr := h.executeFinalizers(finalizers, metaObject)
+	if needUpdate {
+		if updateErr := h.client.Update(resource); updateErr != nil {
+			return updateErr
+		}
+	}
+	return finalizerErr
+}
+
 // reconcileFinalizers ensures all finalizers exist in the given objectMeta.
 // Returns a bool indicating if an update is required to the object
 func (h *Handler) reconcileFinalizers(finalizers []Finalizer, object metav1.Object) bool {
@@ -134,3 +134,22 @@ func TestHandler_Handle(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_Release(t *testing.T) {
+	executed := 0
+	released := Finalizer{Name: "released", Execute: func() error { executed++; return nil }}
+	resource := createResource([]string{"released", "other"}, false)
+	fakeClient := fake.NewFakeClient(&resource)
+	handler := Handler{client: k8s.WrapClient(fakeClient)}
+
+	// the given finalizers are executed and removed, others are kept
+	require.NoError(t, handler.Release(&resource, released))
+	var res v1.Secret
+	require.NoError(t, fakeClient.Get(context.Background(), k8s.ExtractNamespacedName(&resource), &res))
+	require.Equal(t, []string{"other"}, res.Finalizers)
+	require.Equal(t, 1, executed)
+
+	// finalizers already released are not executed again
+	require.NoError(t, handler.Release(&res, released))
+	require.Equal(t, 1, executed)
+}
@@ -26,6 +26,8 @@ type Resources struct {
 	InitContainer corev1.Container
 	// version of the secret provided by the user
 	Version string
+	// Settings are the secure settings aggregated from the secrets provided by the user, by key
+	Settings map[string][]byte
 }
 
 // HasKeystore interface represents an Elastic Stack application that offers a keystore which in ECK
@@ -47,7 +49,7 @@ func NewResources(
 	initContainerParams InitContainerParameters,
 ) (*Resources, error) {
 	// setup a volume from the user-provided secure settings secret
-	secretVolume, version, err := secureSettingsVolume(r, hasKeystore, labels, namer)
+	secretVolume, secret, err := secureSettingsVolume(r, hasKeystore, labels, namer)
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +71,9 @@ func NewResources(
 	return &Resources{
 		Volume:        secretVolume.Volume(),
 		InitContainer: initContainer,
-		Version:       version,
+		// resource version will be included in pod labels,
+		// to recreate pods on any secret change.
+		Version:  secret.GetResourceVersion(),
+		Settings: secret.Data,
 	}, nil
 }
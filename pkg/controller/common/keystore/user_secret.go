@@ -34,30 +34,30 @@ const secureSettingsSecretSuffix = "secure-settings"
 // The user provided secrets are then aggregated into a single secret.
 // This secret is mounted into the pods for secure settings to be injected into a keystore.
 // The user-provided secrets are watched to reconcile on any change.
-// The user secret resource version is returned along with the volume, so that
-// any change in the user secret leads to pod rotation.
+// The aggregated secret is returned along with the volume: its resource version is used
+// so that any change in the user secret leads to pod rotation.
 func secureSettingsVolume(
 	r driver.Interface,
 	hasKeystore HasKeystore,
 	labels map[string]string,
 	namer name.Namer,
-) (*volume.SecretVolume, string, error) {
+) (*volume.SecretVolume, *corev1.Secret, error) {
 	// setup (or remove) watches for the user-provided secret to reconcile on any change
 	err := watchSecureSettings(r.DynamicWatches(), hasKeystore.SecureSettings(), k8s.ExtractNamespacedName(hasKeystore))
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	secrets, err := retrieveUserSecrets(r.K8sClient(), r.Recorder(), hasKeystore)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 	secret, err := reconcileSecureSettings(r.K8sClient(), r.Scheme(), hasKeystore, secrets, namer, labels)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 	if secret == nil {
-		return nil, "", nil
+		return nil, nil, nil
 	}
 
 	// build a volume from that secret
@@ -67,11 +67,7 @@ func secureSettingsVolume(
 		SecureSettingsVolumeMountPath,
 	)
 
-	// resource version will be included in pod labels,
-	// to recreate pods on any secret change.
-	resourceVersion := secret.GetResourceVersion()
-
-	return &secureSettingsVolume, resourceVersion, nil
+	return &secureSettingsVolume, secret, nil
 }
 
 func reconcileSecureSettings(
@@ -105,8 +105,12 @@ func Test_secureSettingsVolume(t *testing.T) {
 				Watches:       tt.w,
 				FakeRecorder:  record.NewFakeRecorder(1000),
 			}
-			vol, version, err := secureSettingsVolume(testDriver, &tt.kb, nil, kbname.KBNamer)
+			vol, secret, err := secureSettingsVolume(testDriver, &tt.kb, nil, kbname.KBNamer)
 			require.NoError(t, err)
+			version := ""
+			if secret != nil {
+				version = secret.ResourceVersion
+			}
 
 			if !reflect.DeepEqual(vol, tt.wantVolume) {
 				t.Errorf("secureSettingsVolume() got = %v, want %v", vol, tt.wantVolume)
@@ -0,0 +1,614 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package namespaces
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("namespaces")
+
+// NewCacheFunc returns a function building the cache of the manager, restricted to the namespaces of the given config.
+// If checkPermissions is not nil, namespaces the operator is not allowed to manage are rejected: the cache cannot be
+// built if they are listed in the config, and they are ignored if they match the label selector.
+func NewCacheFunc(config Config, checkPermissions PermissionChecker) cache.NewCacheFunc {
+	return func(restConfig *rest.Config, opts cache.Options) (cache.Cache, error) {
+		if opts.Scheme == nil {
+			opts.Scheme = scheme.Scheme
+		}
+		if opts.Mapper == nil {
+			mapper, err := apiutil.NewDiscoveryRESTMapper(restConfig)
+			if err != nil {
+				return nil, err
+			}
+			opts.Mapper = mapper
+		}
+		for _, ns := range config.Namespaces {
+			if checkPermissions == nil {
+				break
+			}
+			if err := checkPermissions(ns); err != nil {
+				return nil, err
+			}
+		}
+		// cluster-scoped resources are cached across all namespaces
+		opts.Namespace = corev1.NamespaceAll
+		clusterCache, err := cache.New(restConfig, opts)
+		if err != nil {
+			return nil, err
+		}
+		return newCache(config, opts, clusterCache, checkPermissions, func(namespace string) (cache.Cache, error) {
+			nsOpts := opts
+			nsOpts.Namespace = namespace
+			return cache.New(restConfig, nsOpts)
+		})
+	}
+}
+
+// Cache is a cache of the resources of the namespaces managed by the operator. Each namespace is cached separately,
+// so that the operator does not need to access the resources of other namespaces.
+// Namespaces matching the label selector of the config are cached as soon as they are created or labeled,
+// and not cached anymore when they are deleted or do not match the selector anymore.
+// Permissions of the operator on the namespaces matching the selector are checked in the background, namespaces
+// the operator is not allowed to manage are checked again with an exponential backoff.
+type Cache struct {
+	config           Config
+	scheme           *runtime.Scheme
+	mapper           meta.RESTMapper
+	checkPermissions PermissionChecker
+	// newNamespaceCache returns a new cache of the resources of the given namespace.
+	newNamespaceCache func(namespace string) (cache.Cache, error)
+	// clusterCache caches the cluster-scoped resources.
+	clusterCache cache.Cache
+
+	lock sync.RWMutex
+	// stop is closed when the cache is stopped, it is nil until the cache is started.
+	stop         <-chan struct{}
+	namespaces   map[string]*namespaceCache
+	informers    map[schema.GroupVersionKind]*informer
+	fieldIndexes []fieldIndex
+	// selected are the namespaces matching the selector, cached once the permissions of the operator are checked.
+	selected map[string]struct{}
+	// failed are the namespaces matching the selector which could not be cached, retried with a backoff.
+	failed map[string]struct{}
+	// queue holds the namespaces matching the selector to add to the cache.
+	queue workqueue.RateLimitingInterface
+}
+
+var _ cache.Cache = &Cache{}
+
+// namespaceCache is the cache of a single namespace.
+type namespaceCache struct {
+	cache.Cache
+	informers map[schema.GroupVersionKind]cache.Informer
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+func (n *namespaceCache) close() {
+	n.stopOnce.Do(func() { close(n.stop) })
+}
+
+// fieldIndex is a field index added to the cache, to be added to the caches of the namespaces added later.
+type fieldIndex struct {
+	obj          runtime.Object
+	field        string
+	extractValue client.IndexerFunc
+}
+
+func newCache(
+	config Config,
+	opts cache.Options,
+	clusterCache cache.Cache,
+	checkPermissions PermissionChecker,
+	newNamespaceCache func(namespace string) (cache.Cache, error),
+) (*Cache, error) {
+	c := &Cache{
+		config:            config,
+		scheme:            opts.Scheme,
+		mapper:            opts.Mapper,
+		checkPermissions:  checkPermissions,
+		newNamespaceCache: newNamespaceCache,
+		clusterCache:      clusterCache,
+		namespaces:        map[string]*namespaceCache{},
+		informers:         map[schema.GroupVersionKind]*informer{},
+		selected:          map[string]struct{}{},
+		failed:            map[string]struct{}{},
+		queue:             workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "namespaces"),
+	}
+	for _, ns := range config.Namespaces {
+		nsCache, err := newNamespaceCache(ns)
+		if err != nil {
+			return nil, err
+		}
+		c.namespaces[ns] = &namespaceCache{
+			Cache:     nsCache,
+			informers: map[schema.GroupVersionKind]cache.Informer{},
+			stop:      make(chan struct{}),
+		}
+	}
+	if config.Selector != nil {
+		nsInformer, err := clusterCache.GetInformer(&corev1.Namespace{})
+		if err != nil {
+			return nil, err
+		}
+		nsInformer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc: c.onNamespace,
+			UpdateFunc: func(_, newObj interface{}) {
+				c.onNamespace(newObj)
+			},
+			DeleteFunc: c.onNamespaceDeletion,
+		})
+	}
+	return c, nil
+}
+
+// Namespaces returns the names of the namespaces currently cached.
+func (c *Cache) Namespaces() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	namespaces := make([]string, 0, len(c.namespaces))
+	for ns := range c.namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces
+}
+
+func (c *Cache) onNamespace(obj interface{}) {
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok || stringsutil.StringInSlice(ns.Name, c.config.Namespaces) {
+		return
+	}
+	if ns.DeletionTimestamp != nil || !c.config.Matches(*ns) {
+		c.removeNamespace(ns.Name)
+		return
+	}
+	c.lock.Lock()
+	c.selected[ns.Name] = struct{}{}
+	_, cached := c.namespaces[ns.Name]
+	_, failed := c.failed[ns.Name]
+	c.lock.Unlock()
+	// namespaces which could not be added are only retried with a backoff, not on each update
+	if !cached && !failed {
+		c.queue.Add(ns.Name)
+	}
+}
+
+func (c *Cache) onNamespaceDeletion(obj interface{}) {
+	name, err := toolscache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil || stringsutil.StringInSlice(name, c.config.Namespaces) {
+		return
+	}
+	c.removeNamespace(name)
+}
+
+// runWorker adds the namespaces of the queue to the cache until the queue is shut down.
+func (c *Cache) runWorker() {
+	for c.processNextNamespace() {
+	}
+}
+
+// processNextNamespace adds the next namespace of the queue to the cache, and retries it with a backoff on failure.
+// It returns false if the queue is shut down.
+func (c *Cache) processNextNamespace() bool {
+	item, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(item)
+	namespace := item.(string)
+	if err := c.addNamespace(namespace); err != nil {
+		log.Error(err, "Namespace matching the selector not managed", "namespace", namespace)
+		c.lock.Lock()
+		_, selected := c.selected[namespace]
+		if selected {
+			c.failed[namespace] = struct{}{}
+		}
+		c.lock.Unlock()
+		if selected {
+			c.queue.AddRateLimited(namespace)
+		}
+		return true
+	}
+	c.queue.Forget(namespace)
+	return true
+}
+
+// addNamespace starts caching the resources of the given namespace, with the informers, event handlers and indexes
+// of the other namespaces, if it still matches the selector and the operator is allowed to manage it.
+func (c *Cache) addNamespace(namespace string) error {
+	c.lock.RLock()
+	_, selected := c.selected[namespace]
+	_, exists := c.namespaces[namespace]
+	c.lock.RUnlock()
+	if !selected || exists {
+		return nil
+	}
+	if c.checkPermissions != nil {
+		if err := c.checkPermissions(namespace); err != nil {
+			return err
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, selected := c.selected[namespace]; !selected {
+		return nil
+	}
+	if _, exists := c.namespaces[namespace]; exists {
+		return nil
+	}
+	n, err := c.newNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	c.namespaces[namespace] = n
+	delete(c.failed, namespace)
+	if c.stop != nil {
+		c.start(namespace, n)
+	}
+	log.Info("Managing namespace matching the selector", "namespace", namespace)
+	return nil
+}
+
+// newNamespace returns a new cache of the given namespace, with the informers, event handlers and indexes of the other
+// namespaces. Its informers are not started: on failure, the cache is dropped along with the event handlers already
+// registered, which never receive any event.
+func (c *Cache) newNamespace(namespace string) (*namespaceCache, error) {
+	nsCache, err := c.newNamespaceCache(namespace)
+	if err != nil {
+		return nil, err
+	}
+	n := &namespaceCache{
+		Cache:     nsCache,
+		informers: map[schema.GroupVersionKind]cache.Informer{},
+		stop:      make(chan struct{}),
+	}
+	for gvk, i := range c.informers {
+		nsInformer, err := nsCache.GetInformerForKind(gvk)
+		if err != nil {
+			return nil, err
+		}
+		if err := i.register(nsInformer); err != nil {
+			return nil, err
+		}
+		n.informers[gvk] = nsInformer
+	}
+	for _, index := range c.fieldIndexes {
+		if err := nsCache.IndexField(index.obj, index.field, index.extractValue); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// removeNamespace stops caching the resources of the given namespace.
+func (c *Cache) removeNamespace(namespace string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.selected, namespace)
+	delete(c.failed, namespace)
+	c.queue.Forget(namespace)
+	n, exists := c.namespaces[namespace]
+	if !exists {
+		return
+	}
+	n.close()
+	delete(c.namespaces, namespace)
+	log.Info("Not managing namespace anymore", "namespace", namespace)
+}
+
+// start runs the informers of the given namespace cache until it is removed or the cache is stopped.
+func (c *Cache) start(namespace string, n *namespaceCache) {
+	stop := c.stop
+	go func() {
+		select {
+		case <-stop:
+			n.close()
+		case <-n.stop:
+		}
+	}()
+	go func() {
+		if err := n.Start(n.stop); err != nil {
+			log.Error(err, "Failed to start the cache of a namespace", "namespace", namespace)
+		}
+	}()
+}
+
+// Start runs the informers of all namespaces until the given channel is closed. It blocks.
+func (c *Cache) Start(stop <-chan struct{}) error {
+	c.lock.Lock()
+	c.stop = stop
+	for ns, n := range c.namespaces {
+		c.start(ns, n)
+	}
+	c.lock.Unlock()
+	go func() {
+		if err := c.clusterCache.Start(stop); err != nil {
+			log.Error(err, "Failed to start the cache of cluster-scoped resources")
+		}
+	}()
+	go wait.Until(c.runWorker, time.Second, stop)
+	<-stop
+	c.queue.ShutDown()
+	return nil
+}
+
+// WaitForCacheSync waits for the caches of all namespaces to sync.
+func (c *Cache) WaitForCacheSync(stop <-chan struct{}) bool {
+	// namespaces matching the selector are added once the cluster cache is synced
+	synced := c.clusterCache.WaitForCacheSync(stop)
+	c.lock.RLock()
+	caches := make([]cache.Cache, 0, len(c.namespaces))
+	for _, n := range c.namespaces {
+		caches = append(caches, n)
+	}
+	c.lock.RUnlock()
+	for _, nsCache := range caches {
+		if !nsCache.WaitForCacheSync(stop) {
+			synced = false
+		}
+	}
+	return synced
+}
+
+// isClusterScoped returns true if the given object or list of objects is cluster-scoped.
+func (c *Cache) isClusterScoped(obj runtime.Object) (bool, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return false, err
+	}
+	if meta.IsListType(obj) {
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+	return c.isClusterScopedKind(gvk)
+}
+
+func (c *Cache) isClusterScopedKind(gvk schema.GroupVersionKind) (bool, error) {
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
+}
+
+// GetInformer returns an informer of the kind of the given object across all namespaces.
+func (c *Cache) GetInformer(obj runtime.Object) (cache.Informer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetInformerForKind(gvk)
+}
+
+// GetInformerForKind returns an informer of the given kind across all namespaces.
+func (c *Cache) GetInformerForKind(gvk schema.GroupVersionKind) (cache.Informer, error) {
+	clusterScoped, err := c.isClusterScopedKind(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if clusterScoped {
+		return c.clusterCache.GetInformerForKind(gvk)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if i, exists := c.informers[gvk]; exists {
+		return i, nil
+	}
+	for _, n := range c.namespaces {
+		nsInformer, err := n.GetInformerForKind(gvk)
+		if err != nil {
+			return nil, err
+		}
+		n.informers[gvk] = nsInformer
+	}
+	i := &informer{cache: c, gvk: gvk}
+	c.informers[gvk] = i
+	return i, nil
+}
+
+// IndexField adds a field index to the caches of all namespaces, including the namespaces added later.
+func (c *Cache) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	clusterScoped, err := c.isClusterScoped(obj)
+	if err != nil {
+		return err
+	}
+	if clusterScoped {
+		return c.clusterCache.IndexField(obj, field, extractValue)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, n := range c.namespaces {
+		if err := n.IndexField(obj, field, extractValue); err != nil {
+			return err
+		}
+	}
+	c.fieldIndexes = append(c.fieldIndexes, fieldIndex{obj: obj, field: field, extractValue: extractValue})
+	return nil
+}
+
+// Get retrieves an object from the cache of its namespace.
+func (c *Cache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	clusterScoped, err := c.isClusterScoped(obj)
+	if err != nil {
+		return err
+	}
+	if clusterScoped {
+		return c.clusterCache.Get(ctx, key, obj)
+	}
+
+	c.lock.RLock()
+	n, exists := c.namespaces[key.Namespace]
+	c.lock.RUnlock()
+	if !exists {
+		return c.notManagedError(key, obj)
+	}
+	return n.Get(ctx, key, obj)
+}
+
+// notManagedError returns a NotFound error for the given object, in a namespace not managed by the operator.
+func (c *Cache) notManagedError(key client.ObjectKey, obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	}
+	notFound := apierrors.NewNotFound(mapping.Resource.GroupResource(), key.Name)
+	notFound.ErrStatus.Message = fmt.Sprintf("unable to get %v: namespace %s is not managed by the operator", key, key.Namespace)
+	return notFound
+}
+
+// List retrieves a list of objects from the cache of the requested namespace, or from the caches of all namespaces.
+func (c *Cache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	clusterScoped, err := c.isClusterScoped(list)
+	if err != nil {
+		return err
+	}
+	if clusterScoped {
+		return c.clusterCache.List(ctx, list, opts...)
+	}
+
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	c.lock.RLock()
+	caches := make([]cache.Cache, 0, len(c.namespaces))
+	for ns, n := range c.namespaces {
+		if listOpts.Namespace == corev1.NamespaceAll || listOpts.Namespace == ns {
+			caches = append(caches, n)
+		}
+	}
+	c.lock.RUnlock()
+	if listOpts.Namespace != corev1.NamespaceAll && len(caches) == 0 {
+		return fmt.Errorf("unable to list: namespace %s is not managed by the operator", listOpts.Namespace)
+	}
+
+	listAccessor, err := meta.ListAccessor(list)
+	if err != nil {
+		return err
+	}
+	var allItems []runtime.Object
+	var resourceVersion string
+	for _, nsCache := range caches {
+		nsList := list.DeepCopyObject()
+		if err := nsCache.List(ctx, nsList, opts...); err != nil {
+			return err
+		}
+		items, err := meta.ExtractList(nsList)
+		if err != nil {
+			return err
+		}
+		nsListAccessor, err := meta.ListAccessor(nsList)
+		if err != nil {
+			return err
+		}
+		allItems = append(allItems, items...)
+		resourceVersion = nsListAccessor.GetResourceVersion()
+	}
+	listAccessor.SetResourceVersion(resourceVersion)
+	return meta.SetList(list, allItems)
+}
+
+// informer is an informer of a kind of resource across the namespaces of the cache.
+type informer struct {
+	cache    *Cache
+	gvk      schema.GroupVersionKind
+	handlers []eventHandler
+	indexers []toolscache.Indexers
+}
+
+var _ cache.Informer = &informer{}
+
+type eventHandler struct {
+	handler      toolscache.ResourceEventHandler
+	resyncPeriod *time.Duration
+}
+
+// register adds the event handlers and indexers of the informer to the informer of a new namespace.
+func (i *informer) register(nsInformer cache.Informer) error {
+	for _, indexers := range i.indexers {
+		if err := nsInformer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	for _, h := range i.handlers {
+		if h.resyncPeriod == nil {
+			nsInformer.AddEventHandler(h.handler)
+		} else {
+			nsInformer.AddEventHandlerWithResyncPeriod(h.handler, *h.resyncPeriod)
+		}
+	}
+	return nil
+}
+
+// AddEventHandler adds the handler to the informers of all namespaces, including the namespaces added later.
+func (i *informer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	i.cache.lock.Lock()
+	defer i.cache.lock.Unlock()
+	for _, n := range i.cache.namespaces {
+		n.informers[i.gvk].AddEventHandler(handler)
+	}
+	i.handlers = append(i.handlers, eventHandler{handler: handler})
+}
+
+// AddEventHandlerWithResyncPeriod adds the handler with a resync period to the informers of all namespaces,
+// including the namespaces added later.
+func (i *informer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	i.cache.lock.Lock()
+	defer i.cache.lock.Unlock()
+	for _, n := range i.cache.namespaces {
+		n.informers[i.gvk].AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+	i.handlers = append(i.handlers, eventHandler{handler: handler, resyncPeriod: &resyncPeriod})
+}
+
+// AddIndexers adds the indexers to the informers of all namespaces, including the namespaces added later.
+func (i *informer) AddIndexers(indexers toolscache.Indexers) error {
+	i.cache.lock.Lock()
+	defer i.cache.lock.Unlock()
+	for _, n := range i.cache.namespaces {
+		if err := n.informers[i.gvk].AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	i.indexers = append(i.indexers, indexers)
+	return nil
+}
+
+// HasSynced returns true if the informers of all namespaces have synced.
+func (i *informer) HasSynced() bool {
+	i.cache.lock.RLock()
+	defer i.cache.lock.RUnlock()
+	for _, n := range i.cache.namespaces {
+		if !n.informers[i.gvk].HasSynced() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package namespaces
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+)
+
+var (
+	podGVK       = corev1.SchemeGroupVersion.WithKind("Pod")
+	namespaceGVK = corev1.SchemeGroupVersion.WithKind("Namespace")
+)
+
+type testCache struct {
+	*Cache
+	nsCaches map[string]*informertest.FakeInformers
+}
+
+func newTestCache(t *testing.T, config Config, checkPermissions PermissionChecker) testCache {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(podGVK, meta.RESTScopeNamespace)
+	mapper.Add(namespaceGVK, meta.RESTScopeRoot)
+	nsCaches := map[string]*informertest.FakeInformers{}
+	c, err := newCache(
+		config,
+		cache.Options{Scheme: scheme.Scheme, Mapper: mapper},
+		&informertest.FakeInformers{},
+		checkPermissions,
+		func(namespace string) (cache.Cache, error) {
+			nsCache := &informertest.FakeInformers{}
+			nsCaches[namespace] = nsCache
+			return nsCache, nil
+		},
+	)
+	require.NoError(t, err)
+	return testCache{Cache: c, nsCaches: nsCaches}
+}
+
+func (c testCache) sortedNamespaces() []string {
+	namespaces := c.Namespaces()
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// processQueue adds the namespaces of the queue to the cache, ignoring the namespaces retried with a backoff.
+func (c testCache) processQueue() {
+	for c.queue.Len() > 0 {
+		c.processNextNamespace()
+	}
+}
+
+func pod(namespace string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "pod"}}
+}
+
+func TestCache_DynamicNamespaces(t *testing.T) {
+	config, err := NewConfig([]string{"ns1"}, "team=a")
+	require.NoError(t, err)
+	checks := map[string]int{}
+	c := newTestCache(t, config, func(namespace string) error {
+		checks[namespace]++
+		if namespace == "forbidden" {
+			return errors.New("forbidden")
+		}
+		return nil
+	})
+	require.Equal(t, []string{"ns1"}, c.sortedNamespaces())
+
+	// register an event handler before any namespace matches the selector
+	var received []string
+	i, err := c.GetInformer(&corev1.Pod{})
+	require.NoError(t, err)
+	i.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			received = append(received, obj.(*corev1.Pod).Namespace)
+		},
+	})
+
+	// namespaces matching the selector are added
+	c.onNamespace(namespace("team-a", map[string]string{"team": "a"}))
+	c.onNamespace(namespace("team-b", map[string]string{"team": "b"}))
+	c.onNamespace(namespace("forbidden", map[string]string{"team": "a"}))
+	c.processQueue()
+	require.Equal(t, []string{"ns1", "team-a"}, c.sortedNamespaces())
+
+	// permissions are not checked again on updates, but retried with a backoff until granted
+	c.onNamespace(namespace("team-a", map[string]string{"team": "a"}))
+	c.onNamespace(namespace("forbidden", map[string]string{"team": "a"}))
+	c.processQueue()
+	require.Equal(t, map[string]int{"team-a": 1, "forbidden": 1}, checks)
+	require.Equal(t, 1, c.queue.NumRequeues("forbidden"))
+
+	// with the event handlers registered before
+	for _, ns := range []string{"ns1", "team-a"} {
+		fakeInformer, err := c.nsCaches[ns].FakeInformerForKind(podGVK)
+		require.NoError(t, err)
+		fakeInformer.Add(pod(ns))
+	}
+	require.Equal(t, []string{"ns1", "team-a"}, received)
+
+	// namespaces not matching the selector anymore are removed, listed namespaces are kept
+	removed := c.namespaces["team-a"]
+	c.onNamespace(namespace("team-a", map[string]string{"team": "b"}))
+	c.onNamespaceDeletion(namespace("ns1", nil))
+	c.onNamespace(namespace("forbidden", map[string]string{"team": "b"}))
+	require.Equal(t, []string{"ns1"}, c.sortedNamespaces())
+	require.Equal(t, 0, c.queue.NumRequeues("forbidden"))
+	select {
+	case <-removed.stop:
+	default:
+		t.Error("the cache of the removed namespace should be stopped")
+	}
+}
+
+func TestCache_Get(t *testing.T) {
+	config, err := NewConfig([]string{"ns1"}, "")
+	require.NoError(t, err)
+	c := newTestCache(t, config, nil)
+
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns1", Name: "pod"}, &corev1.Pod{}))
+	err = c.Get(context.Background(), types.NamespacedName{Namespace: "ns2", Name: "pod"}, &corev1.Pod{})
+	require.True(t, apierrors.IsNotFound(err))
+	// cluster-scoped resources are retrieved from the cluster cache
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "ns2"}, &corev1.Namespace{}))
+}
@@ -0,0 +1,74 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package namespaces restricts the operator to the resources of a set of namespaces, listed explicitly or
+// matching a label selector.
+package namespaces
+
+import (
+	"sort"
+
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Config defines the namespaces managed by the operator.
+type Config struct {
+	// Namespaces are the names of the namespaces always managed by the operator.
+	Namespaces []string
+	// Selector selects additional namespaces by label, if not nil.
+	Selector labels.Selector
+}
+
+// NewConfig returns the config of the given namespaces and label selector. An empty selector does not select
+// any namespace. All namespaces are managed if both are empty.
+func NewConfig(namespaces []string, selector string) (Config, error) {
+	config := Config{}
+	for _, ns := range namespaces {
+		if ns != "" && !stringsutil.StringInSlice(ns, config.Namespaces) {
+			config.Namespaces = append(config.Namespaces, ns)
+		}
+	}
+	sort.Strings(config.Namespaces)
+	if selector != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Selector = parsed
+	}
+	return config, nil
+}
+
+// AllNamespaces returns true if the operator manages the resources of all namespaces.
+func (c Config) AllNamespaces() bool {
+	return len(c.Namespaces) == 0 && c.Selector == nil
+}
+
+// Matches returns true if the given namespace is managed by the operator.
+func (c Config) Matches(ns corev1.Namespace) bool {
+	if c.AllNamespaces() || stringsutil.StringInSlice(ns.Name, c.Namespaces) {
+		return true
+	}
+	return c.Selector != nil && c.Selector.Matches(labels.Set(ns.Labels))
+}
+
+// IsManaged returns true if the namespace with the given name is managed by the operator. The namespace is only
+// retrieved if it must match the label selector.
+func (c Config) IsManaged(client k8s.Client, namespace string) (bool, error) {
+	if c.AllNamespaces() || stringsutil.StringInSlice(namespace, c.Namespaces) {
+		return true, nil
+	}
+	if c.Selector == nil {
+		return false, nil
+	}
+	var ns corev1.Namespace
+	if err := client.Get(types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return false, err
+	}
+	return c.Matches(ns), nil
+}
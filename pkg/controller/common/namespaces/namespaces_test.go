@@ -0,0 +1,141 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package namespaces
+
+import (
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func namespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestNewConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		namespaces     []string
+		selector       string
+		wantNamespaces []string
+		wantAll        bool
+		wantErr        bool
+	}{
+		{
+			name:    "all namespaces",
+			wantAll: true,
+		},
+		{
+			name:       "empty namespace",
+			namespaces: []string{""},
+			wantAll:    true,
+		},
+		{
+			name:           "sorted namespaces without duplicates",
+			namespaces:     []string{"ns2", "ns1", "", "ns2"},
+			wantNamespaces: []string{"ns1", "ns2"},
+		},
+		{
+			name:     "selector",
+			selector: "team=a",
+		},
+		{
+			name:     "invalid selector",
+			selector: "team in a",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := NewConfig(tt.namespaces, tt.selector)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantNamespaces, config.Namespaces)
+			require.Equal(t, tt.wantAll, config.AllNamespaces())
+		})
+	}
+}
+
+func TestConfig_IsManaged(t *testing.T) {
+	c := k8s.WrapClient(fake.NewFakeClient(
+		namespace("team-a", map[string]string{"team": "a"}),
+		namespace("team-b", map[string]string{"team": "b"}),
+	))
+	all, err := NewConfig(nil, "")
+	require.NoError(t, err)
+	listed, err := NewConfig([]string{"ns1", "ns2"}, "")
+	require.NoError(t, err)
+	selected, err := NewConfig([]string{"ns1"}, "team=a")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		config    Config
+		namespace string
+		want      bool
+		wantErr   bool
+	}{
+		{
+			name:      "all namespaces",
+			config:    all,
+			namespace: "ns3",
+			want:      true,
+		},
+		{
+			name:      "listed namespace",
+			config:    listed,
+			namespace: "ns2",
+			want:      true,
+		},
+		{
+			name:      "namespace not listed",
+			config:    listed,
+			namespace: "ns3",
+			want:      false,
+		},
+		{
+			name:      "listed namespace with a selector",
+			config:    selected,
+			namespace: "ns1",
+			want:      true,
+		},
+		{
+			name:      "namespace matching the selector",
+			config:    selected,
+			namespace: "team-a",
+			want:      true,
+		},
+		{
+			name:      "namespace not matching the selector",
+			config:    selected,
+			namespace: "team-b",
+			want:      false,
+		},
+		{
+			name:      "namespace not found",
+			config:    selected,
+			namespace: "team-c",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.IsManaged(c, tt.namespace)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
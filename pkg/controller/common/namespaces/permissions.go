@@ -0,0 +1,69 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package namespaces
+
+import (
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PermissionChecker returns an error if the operator is not allowed to manage the resources of the given namespace.
+type PermissionChecker func(namespace string) error
+
+// requiredVerbs are the verbs the operator must be allowed to use on the resources it reconciles.
+var requiredVerbs = []string{"get", "list", "watch", "create", "update", "delete"}
+
+// requiredResources are the resources reconciled by the operator in the namespaces it manages.
+var requiredResources = []schema.GroupResource{
+	{Resource: "configmaps"},
+	{Resource: "endpoints"},
+	{Resource: "persistentvolumeclaims"},
+	{Resource: "pods"},
+	{Resource: "secrets"},
+	{Resource: "services"},
+	{Group: "apps", Resource: "deployments"},
+	{Group: "apps", Resource: "statefulsets"},
+	{Group: "policy", Resource: "poddisruptionbudgets"},
+	{Group: "elasticsearch.k8s.elastic.co", Resource: "elasticsearches"},
+	{Group: "kibana.k8s.elastic.co", Resource: "kibanas"},
+	{Group: "apm.k8s.elastic.co", Resource: "apmservers"},
+	{Group: "associations.k8s.elastic.co", Resource: "apmserverelasticsearchassociations"},
+}
+
+// NewPermissionChecker returns a PermissionChecker reviewing the access of the operator to the resources it reconciles
+// through the SelfSubjectAccessReview API, which is allowed to every authenticated user.
+func NewPermissionChecker(clientset kubernetes.Interface) PermissionChecker {
+	return func(namespace string) error {
+		var missing []string
+		for _, resource := range requiredResources {
+			for _, verb := range requiredVerbs {
+				review, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(&authorizationv1.SelfSubjectAccessReview{
+					Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+						ResourceAttributes: &authorizationv1.ResourceAttributes{
+							Namespace: namespace,
+							Verb:      verb,
+							Group:     resource.Group,
+							Resource:  resource.Resource,
+						},
+					},
+				})
+				if err != nil {
+					return err
+				}
+				if !review.Status.Allowed {
+					missing = append(missing, fmt.Sprintf("%s %s", verb, resource.String()))
+				}
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		return fmt.Errorf("missing permissions in namespace %s: %s", namespace, strings.Join(missing, ", "))
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package namespaces
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// fakeClientset returns a clientset allowing the access to all resources but the secrets of the given namespace.
+func fakeClientset(namespaceWithoutSecrets string) *fake.Clientset {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = attributes.Namespace != namespaceWithoutSecrets || attributes.Resource != "secrets"
+		return true, review, nil
+	})
+	return clientset
+}
+
+func TestNewPermissionChecker(t *testing.T) {
+	check := NewPermissionChecker(fakeClientset("ns2"))
+	require.NoError(t, check("ns1"))
+	require.EqualError(t, check("ns2"),
+		"missing permissions in namespace ns2: get secrets, list secrets, watch secrets, create secrets, update secrets, delete secrets")
+}
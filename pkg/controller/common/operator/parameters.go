@@ -8,6 +8,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/about"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/net"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // Parameters contain parameters to create new operators.
@@ -22,4 +23,10 @@ type Parameters struct {
 	CACertRotation certificates.RotationParams
 	// CertRotation defines the rotation params for non-CA certificates.
 	CertRotation certificates.RotationParams
+	// KeyParams defines the algorithm and size of the generated private keys.
+	KeyParams certificates.KeyParams
+	// Name is the name of the operator instance, reported in the status of the resources it reconciles.
+	Name string
+	// ResourceSelector restricts the operator to the resources matching it, unless nil.
+	ResourceSelector labels.Selector
 }
@@ -0,0 +1,37 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package operator
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// MatchesResourceSelector returns true if resources with the given labels are reconciled by an operator restricted
+// to the given selector. All resources are reconciled if the selector is nil.
+func MatchesResourceSelector(selector labels.Selector, resourceLabels map[string]string) bool {
+	return selector == nil || selector.Matches(labels.Set(resourceLabels))
+}
+
+// ResourceSelectorPredicate filters out the events of the resources not reconciled by an operator restricted to the
+// given selector. Updates of resources which stop matching the selector are kept, so that the operator can release them.
+func ResourceSelectorPredicate(selector labels.Selector) predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return MatchesResourceSelector(selector, e.Meta.GetLabels())
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return MatchesResourceSelector(selector, e.MetaOld.GetLabels()) ||
+				MatchesResourceSelector(selector, e.MetaNew.GetLabels())
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return MatchesResourceSelector(selector, e.Meta.GetLabels())
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return MatchesResourceSelector(selector, e.Meta.GetLabels())
+		},
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestResourceSelectorPredicate(t *testing.T) {
+	canary, err := labels.Parse("operator=canary")
+	require.NoError(t, err)
+	stable := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "stable"}}
+	moved := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "moved", Labels: map[string]string{"operator": "canary"}}}
+
+	all := ResourceSelectorPredicate(nil)
+	require.True(t, all.Create(event.CreateEvent{Meta: stable, Object: stable}))
+	require.True(t, all.Update(event.UpdateEvent{MetaOld: stable, ObjectOld: stable, MetaNew: moved, ObjectNew: moved}))
+
+	p := ResourceSelectorPredicate(canary)
+	require.False(t, p.Create(event.CreateEvent{Meta: stable, Object: stable}))
+	require.True(t, p.Create(event.CreateEvent{Meta: moved, Object: moved}))
+	require.True(t, p.Update(event.UpdateEvent{MetaOld: stable, ObjectOld: stable, MetaNew: moved, ObjectNew: moved}))
+	// resources leaving the selector are released
+	require.True(t, p.Update(event.UpdateEvent{MetaOld: moved, ObjectOld: moved, MetaNew: stable, ObjectNew: stable}))
+	require.False(t, p.Update(event.UpdateEvent{MetaOld: stable, ObjectOld: stable, MetaNew: stable, ObjectNew: stable}))
+	require.False(t, p.Delete(event.DeleteEvent{Meta: stable, Object: stable}))
+	require.True(t, p.Generic(event.GenericEvent{Meta: moved, Object: moved}))
+}
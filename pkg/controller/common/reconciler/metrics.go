@@ -0,0 +1,34 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package reconciler
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var reconcileDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "elastic_reconcile_duration_seconds",
+		Help:    "Duration of the reconciliation steps, per kind of resource.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+	},
+	[]string{"kind", "step"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileDuration)
+}
+
+// ObserveDuration starts timing the given reconciliation step of a resource of the given kind, and returns a function
+// recording its duration once called, typically deferred at the beginning of the step.
+func ObserveDuration(kind, step string) func() {
+	start := time.Now()
+	return func() {
+		reconcileDuration.WithLabelValues(kind, step).Observe(time.Since(start).Seconds())
+	}
+}
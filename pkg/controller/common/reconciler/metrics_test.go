@@ -0,0 +1,23 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveDuration(t *testing.T) {
+	ObserveDuration("Kind", "step")()
+	ObserveDuration("Kind", "step")()
+	ObserveDuration("Kind", "other-step")()
+
+	var m dto.Metric
+	require.NoError(t, reconcileDuration.WithLabelValues("Kind", "step").(prometheus.Metric).Write(&m))
+	require.Equal(t, uint64(2), m.GetHistogram().GetSampleCount())
+}
@@ -57,6 +57,11 @@ func NewExternalUserFromSecret(secret v1.Secret) (ExternalUser, error) {
 	return user, nil
 }
 
+// NewExternalUser creates a user with the given password hash and roles.
+func NewExternalUser(name string, passwordHash []byte, roles []string) ExternalUser {
+	return ExternalUser{name: name, password: passwordHash, roles: roles}
+}
+
 // Id is the user id.
 func (u ExternalUser) Id() string {
 	return u.name
@@ -0,0 +1,42 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RotateCredentialsAnnotation can be set on an Elasticsearch resource to rotate the passwords of the elastic user,
+	// of the internal users and of the association users. Its value is an arbitrary token (eg. a date):
+	// passwords are rotated each time it changes.
+	RotateCredentialsAnnotation = "elasticsearch.k8s.elastic.co/rotate-credentials"
+	// CredentialsRotationAnnotation is set on the secrets holding generated passwords, with the token of the rotation
+	// the passwords were generated for.
+	CredentialsRotationAnnotation = "elasticsearch.k8s.elastic.co/credentials-rotation"
+)
+
+// RequestedRotation returns the token of the credentials rotation requested on the given Elasticsearch resource,
+// or an empty string if no rotation was ever requested.
+func RequestedRotation(es metav1.Object) string {
+	return es.GetAnnotations()[RotateCredentialsAnnotation]
+}
+
+// NeedsRotation returns true if the passwords in the given secret were not generated for the requested rotation.
+func NeedsRotation(rotation string, secret corev1.Secret) bool {
+	return rotation != "" && secret.Annotations[CredentialsRotationAnnotation] != rotation
+}
+
+// SetRotation marks the given secret as holding passwords generated for the given rotation.
+func SetRotation(rotation string, secret *corev1.Secret) {
+	if rotation == "" {
+		return
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[CredentialsRotationAnnotation] = rotation
+}
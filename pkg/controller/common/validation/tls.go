@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	netutil "github.com/elastic/cloud-on-k8s/pkg/utils/net"
+)
+
+const (
+	InvalidSanIPErrMsg  = "invalid SAN IP address"
+	InvalidKeyParamsMsg = "Invalid self-signed certificate key"
+)
+
+// ValidSanIP checks that the IP addresses in the subject alternative names of the self-signed certificate are valid.
+func ValidSanIP(tls commonv1beta1.TLSOptions) Result {
+	if tls.SelfSignedCertificate == nil {
+		return OK
+	}
+	for _, san := range tls.SelfSignedCertificate.SubjectAlternativeNames {
+		if san.IP != "" {
+			ip := netutil.MaybeIPTo4(net.ParseIP(san.IP))
+			if ip == nil {
+				msg := fmt.Sprintf("%s: %s", InvalidSanIPErrMsg, san.IP)
+				return Result{
+					Error:   errors.New(msg),
+					Reason:  msg,
+					Allowed: false,
+				}
+			}
+		}
+	}
+	return OK
+}
+
+// ValidKeyParams checks that the key size of the self-signed certificate is supported by its key algorithm.
+// The key size alone is not checked, since it depends on the key algorithm configured in the operator.
+func ValidKeyParams(tls commonv1beta1.TLSOptions) Result {
+	selfSignedCert := tls.SelfSignedCertificate
+	if selfSignedCert == nil || selfSignedCert.KeyAlgorithm == "" {
+		return OK
+	}
+	keyParams := certificates.KeyParams{}.Override(selfSignedCert.KeyAlgorithm, selfSignedCert.KeySize)
+	if err := keyParams.Validate(); err != nil {
+		return Result{Reason: fmt.Sprintf("%s: %s", InvalidKeyParamsMsg, err.Error())}
+	}
+	return OK
+}
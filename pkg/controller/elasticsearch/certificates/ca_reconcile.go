@@ -8,6 +8,7 @@ import (
 	"crypto/x509"
 	"time"
 
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates/http"
@@ -30,6 +31,12 @@ type CertificateResources struct {
 
 	// HTTPCACertProvided indicates whether ca.crt key is defined in the certificate secret.
 	HTTPCACertProvided bool
+
+	// InvalidTransportCAs explains why some of the additional transport CAs referenced in the spec are not trusted.
+	InvalidTransportCAs []string
+
+	// Certificates reports when the HTTP and transport certificates expire.
+	Certificates commonv1beta1.CertificatesStatus
 }
 
 // reconcileGenericResources reconciles the expected generic resources of a cluster.
@@ -39,10 +46,14 @@ func Reconcile(
 	services []corev1.Service,
 	caRotation certificates.RotationParams,
 	certRotation certificates.RotationParams,
+	keyParams certificates.KeyParams,
 ) (*CertificateResources, *reconciler.Results) {
+	defer reconciler.ObserveDuration(v1beta1.Kind, "certificates")()
 	results := &reconciler.Results{}
 
 	labels := label.NewLabels(k8s.ExtractNamespacedName(&es))
+	// the HTTP layer keys may be generated differently than the transport layer keys
+	httpKeyParams := http.SelfSignedKeyParams(es.Spec.HTTP.TLS, keyParams)
 
 	httpCA, err := certificates.ReconcileCAForOwner(
 		driver.K8sClient(),
@@ -52,6 +63,7 @@ func Reconcile(
 		labels,
 		certificates.HTTPCAType,
 		caRotation,
+		httpKeyParams,
 	)
 	if err != nil {
 		return nil, results.WithError(err)
@@ -72,6 +84,7 @@ func Reconcile(
 		labels,
 		services,
 		caRotation,
+		httpKeyParams,
 	)
 	if err != nil {
 		return nil, results.WithError(err)
@@ -82,35 +95,63 @@ func Reconcile(
 		return nil, results.WithError(err)
 	}
 
-	transportCA, err := certificates.ReconcileCAForOwner(
-		driver.K8sClient(),
-		driver.Scheme(),
-		name.ESNamer,
-		&es,
-		labels,
-		certificates.TransportCAType,
-		caRotation,
-	)
+	var customTransportCertificates *transport.CustomCertificates
+	if es.Spec.Transport.TLS.IssuerRef != nil {
+		customTransportCertificates, err = transport.ReconcileIssuedCertificates(driver.K8sClient(), driver.Scheme(), es, keyParams)
+	} else {
+		customTransportCertificates, err = transport.LoadCustomCertificates(driver.K8sClient(), es)
+	}
 	if err != nil {
 		return nil, results.WithError(err)
 	}
-	// make sure to requeue before the CA cert expires
-	results.WithResult(reconcile.Result{
-		RequeueAfter: certificates.ShouldRotateIn(time.Now(), transportCA.Cert.NotAfter, caRotation.RotateBefore),
-	})
+	var transportCA *certificates.CA
+	var nodeCertificates map[string][]byte
+	if customTransportCertificates != nil {
+		// user-provided or issued certificates are not rotated by the operator, their expiration is reported below
+		transportCA = customTransportCertificates.CA
+		if !customTransportCertificates.SignsNodeCertificates() {
+			nodeCertificates = customTransportCertificates.NodeCertificates
+		}
+	} else {
+		transportCA, err = certificates.ReconcileCAForOwner(
+			driver.K8sClient(),
+			driver.Scheme(),
+			name.ESNamer,
+			&es,
+			labels,
+			certificates.TransportCAType,
+			caRotation,
+			keyParams,
+		)
+		if err != nil {
+			return nil, results.WithError(err)
+		}
+		// make sure to requeue before the CA cert expires
+		results.WithResult(reconcile.Result{
+			RequeueAfter: certificates.ShouldRotateIn(time.Now(), transportCA.Cert.NotAfter, caRotation.RotateBefore),
+		})
+	}
 
 	// reconcile transport public certs secret:
 	if err := transport.ReconcileTransportCertsPublicSecret(driver.K8sClient(), driver.Scheme(), es, transportCA); err != nil {
 		return nil, results.WithError(err)
 	}
 
+	externalCAs, err := transport.LoadExternalCAs(driver.K8sClient(), es)
+	if err != nil {
+		return nil, results.WithError(err)
+	}
+
 	// reconcile transport certificates
-	result, err := transport.ReconcileTransportCertificatesSecrets(
+	transportExpiration, result, err := transport.ReconcileTransportCertificatesSecrets(
 		driver.K8sClient(),
 		driver.Scheme(),
 		transportCA,
 		es,
+		externalCAs.PEM,
+		nodeCertificates,
 		certRotation,
+		keyParams,
 	)
 	if results.WithResult(result).WithError(err).HasError() {
 		return nil, results
@@ -121,10 +162,31 @@ func Reconcile(
 		return nil, results.WithError(err)
 	}
 
+	httpExpiration, err := httpCertificates.Expiration()
+	if err != nil {
+		return nil, results.WithError(err)
+	}
+	// requeue to warn about certificates the operator does not rotate before they expire
+	transportRotateBefore := caRotation.RotateBefore
+	if certRotation.RotateBefore < transportRotateBefore {
+		// node certificates may be rotated closer to their expiration than the CA
+		transportRotateBefore = certRotation.RotateBefore
+	}
+	results.WithResult(reconcile.Result{
+		RequeueAfter: certificates.ReportExpiration(driver.Recorder(), &es, certificates.HTTPLayer, httpExpiration, caRotation.RotateBefore),
+	}).WithResult(reconcile.Result{
+		RequeueAfter: certificates.ReportExpiration(driver.Recorder(), &es, certificates.TransportLayer, transportExpiration, transportRotateBefore),
+	})
+
 	_, httpCACertProvided := httpCertificates.Data[certificates.CAFileName]
 	return &CertificateResources{
 		TrustedHTTPCertificates: trustedHTTPCertificates,
 		TransportCA:             transportCA,
 		HTTPCACertProvided:      httpCACertProvided,
+		InvalidTransportCAs:     externalCAs.Invalid,
+		Certificates: commonv1beta1.CertificatesStatus{
+			HTTP:      &httpExpiration,
+			Transport: &transportExpiration,
+		},
 	}, results
 }
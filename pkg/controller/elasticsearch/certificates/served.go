@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"strconv"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/network"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ServedHTTPCertificate reports the fingerprint of the HTTP certificate served by the ready Elasticsearch Pods, and
+// whether they all serve the expected certificate, ie. the first of the given certificates.
+// Elasticsearch reloads its certificate files when they change: each node serves the expected certificate once
+// the updated secret is propagated to its Pod.
+func ServedHTTPCertificate(
+	es v1beta1.Elasticsearch,
+	pods []corev1.Pod,
+	expected []*x509.Certificate,
+) (*commonv1beta1.HTTPStatus, bool, error) {
+	if !es.Spec.HTTP.TLS.Enabled() {
+		return nil, true, nil
+	}
+	var addresses []string
+	for _, pod := range pods {
+		if pod.Status.PodIP == "" || !k8s.IsPodReady(pod) {
+			// the certificate is checked once the Pod is ready
+			continue
+		}
+		addresses = append(addresses, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(network.HTTPPort)))
+	}
+	return servedHTTPCertificate(addresses, expected)
+}
+
+// servedHTTPCertificate checks the certificate served at each of the given addresses. The reported fingerprint is
+// the one of the first outdated certificate, if any.
+func servedHTTPCertificate(addresses []string, expected []*x509.Certificate) (*commonv1beta1.HTTPStatus, bool, error) {
+	var expectedFingerprint string
+	if len(expected) > 0 {
+		expectedFingerprint = certificates.Fingerprint(expected[0])
+	}
+	var status *commonv1beta1.HTTPStatus
+	for _, address := range addresses {
+		served, err := servedCertificate(address)
+		if err != nil {
+			return nil, false, err
+		}
+		fingerprint := certificates.Fingerprint(served)
+		if expectedFingerprint != "" && fingerprint != expectedFingerprint {
+			return &commonv1beta1.HTTPStatus{CertificateFingerprint: fingerprint}, false, nil
+		}
+		status = &commonv1beta1.HTTPStatus{CertificateFingerprint: fingerprint}
+	}
+	return status, true, nil
+}
+
+// servedCertificate returns the certificate presented by the node listening at the given address. A new connection
+// is opened for each check, since a pooled connection may have been established before the certificate was reloaded.
+// The connection is closed right after the handshake: no request is sent, so the certificate does not need to be
+// verified.
+func servedCertificate(address string) (*x509.Certificate, error) {
+	dialer := &net.Dialer{Timeout: esclient.DefaultReqTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
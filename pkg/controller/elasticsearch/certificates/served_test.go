@@ -0,0 +1,101 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCA(t *testing.T, cn string) *certificates.CA {
+	privateKey, err := rsa.GenerateKey(cryptorand.Reader, 1024)
+	require.NoError(t, err)
+	ca, err := certificates.NewSelfSignedCA(certificates.CABuilderOptions{Subject: pkix.Name{CommonName: cn}, PrivateKey: privateKey})
+	require.NoError(t, err)
+	return ca
+}
+
+// newTestServer starts a TLS server presenting the certificate of the given CA.
+func newTestServer(ca *certificates.CA) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{ca.Cert.Raw}, PrivateKey: ca.PrivateKey}}}
+	server.StartTLS()
+	return server
+}
+
+func Test_servedHTTPCertificate(t *testing.T) {
+	current := newTestCA(t, "current")
+	previous := newTestCA(t, "previous")
+	currentServer := newTestServer(current)
+	defer currentServer.Close()
+	previousServer := newTestServer(previous)
+	defer previousServer.Close()
+	currentAddress := strings.TrimPrefix(currentServer.URL, "https://")
+	previousAddress := strings.TrimPrefix(previousServer.URL, "https://")
+
+	tests := []struct {
+		name         string
+		addresses    []string
+		wantStatus   *commonv1beta1.HTTPStatus
+		wantUpToDate bool
+		wantErr      bool
+	}{
+		{
+			name:         "no ready Pod",
+			wantUpToDate: true,
+		},
+		{
+			name:         "expected certificate served by all Pods",
+			addresses:    []string{currentAddress, currentAddress},
+			wantStatus:   &commonv1beta1.HTTPStatus{CertificateFingerprint: certificates.Fingerprint(current.Cert)},
+			wantUpToDate: true,
+		},
+		{
+			name:         "previous certificate still served by a Pod",
+			addresses:    []string{currentAddress, previousAddress},
+			wantStatus:   &commonv1beta1.HTTPStatus{CertificateFingerprint: certificates.Fingerprint(previous.Cert)},
+			wantUpToDate: false,
+		},
+		{
+			name:      "Pod not reachable",
+			addresses: []string{"127.0.0.1:1"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, upToDate, err := servedHTTPCertificate(tt.addresses, []*x509.Certificate{current.Cert})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, status)
+			require.Equal(t, tt.wantUpToDate, upToDate)
+		})
+	}
+}
+
+func TestServedHTTPCertificate_TLSDisabled(t *testing.T) {
+	es := v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{HTTP: commonv1beta1.HTTPConfig{
+		TLS: commonv1beta1.TLSOptions{SelfSignedCertificate: &commonv1beta1.SelfSignedCertificate{Disabled: true}},
+	}}}
+	status, upToDate, err := ServedHTTPCertificate(es, nil, nil)
+	require.NoError(t, err)
+	require.Nil(t, status)
+	require.True(t, upToDate)
+}
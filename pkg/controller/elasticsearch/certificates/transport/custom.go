@@ -0,0 +1,154 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// CustomCertificates are the user-provided transport CA or node certificates.
+type CustomCertificates struct {
+	// SecretName is the name of the secret holding the certificates.
+	SecretName string
+	// CA is the user-provided CA. Its private key is nil when node certificates are provided.
+	CA *certificates.CA
+	// NodeCertificates holds the user-provided `<pod-name>.tls.crt` and `<pod-name>.tls.key` entries, if any.
+	NodeCertificates map[string][]byte
+}
+
+// SignsNodeCertificates returns true if the user-provided CA is used to issue the node certificates.
+func (c CustomCertificates) SignsNodeCertificates() bool {
+	return c.CA.PrivateKey != nil
+}
+
+// LoadCustomCertificates reads and validates the user-provided transport certificates referenced in the Elasticsearch
+// spec. It returns nil if there is no such certificates, in which case the operator manages a self-signed CA.
+func LoadCustomCertificates(c k8s.Client, es v1beta1.Elasticsearch) (*CustomCertificates, error) {
+	secretName := es.Spec.Transport.TLS.Certificate.SecretName
+	if secretName == "" {
+		return nil, nil
+	}
+	var secret corev1.Secret
+	if err := c.Get(types.NamespacedName{Namespace: es.Namespace, Name: secretName}, &secret); err != nil {
+		return nil, err
+	}
+	custom, err := parseCustomCertificates(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transport certificates in secret %s: %s", secretName, err.Error())
+	}
+	return custom, nil
+}
+
+func parseCustomCertificates(secret corev1.Secret) (*CustomCertificates, error) {
+	caCerts, err := certificates.ParsePEMCerts(secret.Data[certificates.CAFileName])
+	if err != nil {
+		return nil, err
+	}
+	if len(caCerts) == 0 {
+		return nil, fmt.Errorf("no PEM certificate in %s", certificates.CAFileName)
+	}
+	custom := CustomCertificates{
+		SecretName: secret.Name,
+		CA:         certificates.NewCA(nil, caCerts[0]),
+	}
+
+	if keyData, exists := secret.Data[certificates.CAKeyFileName]; exists {
+		// user-provided CA
+		if !caCerts[0].IsCA {
+			return nil, fmt.Errorf("certificate in %s is not a CA", certificates.CAFileName)
+		}
+		key, err := certificates.ParsePEMPrivateKey(keyData)
+		if err != nil {
+			return nil, err
+		}
+		if !certificates.PrivateMatchesPublicKey(caCerts[0].PublicKey, key) {
+			return nil, fmt.Errorf("private key in %s does not match the certificate", certificates.CAKeyFileName)
+		}
+		custom.CA.PrivateKey = key
+		return &custom, nil
+	}
+
+	// user-provided node certificates
+	custom.NodeCertificates = make(map[string][]byte)
+	for key, data := range secret.Data {
+		if !strings.HasSuffix(key, "."+certificates.CertFileName) && !strings.HasSuffix(key, "."+certificates.KeyFileName) {
+			continue
+		}
+		custom.NodeCertificates[key] = data
+		if !strings.HasSuffix(key, "."+certificates.CertFileName) {
+			continue
+		}
+		certs, err := certificates.ParsePEMCerts(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", key, err.Error())
+		}
+		if len(certs) == 0 {
+			return nil, fmt.Errorf("no PEM certificate in %s", key)
+		}
+		keyFileName := strings.TrimSuffix(key, certificates.CertFileName) + certificates.KeyFileName
+		if err := validateNodeCertificate(certs, secret.Data[keyFileName], caCerts); err != nil {
+			return nil, fmt.Errorf("%s: %s", key, err.Error())
+		}
+	}
+	if len(custom.NodeCertificates) == 0 {
+		return nil, errors.New("either a CA private key or node certificates are required")
+	}
+	return &custom, nil
+}
+
+// validateNodeCertificate checks that the given node certificate chain matches the given private key, and is issued
+// by one of the given CA certificates.
+func validateNodeCertificate(certs []*x509.Certificate, keyData []byte, caCerts []*x509.Certificate) error {
+	if keyData == nil {
+		return errors.New("missing private key")
+	}
+	key, err := certificates.ParsePEMPrivateKey(keyData)
+	if err != nil {
+		return err
+	}
+	if !certificates.PrivateMatchesPublicKey(certs[0].PublicKey, key) {
+		return errors.New("private key does not match the certificate")
+	}
+	roots := x509.NewCertPool()
+	for _, ca := range caCerts {
+		roots.AddCert(ca)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	verifyOpts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	if _, err := certs[0].Verify(verifyOpts); err != nil {
+		log.Info("Invalid custom transport certificate", "subject", certs[0].Subject, "issuer", certs[0].Issuer, "error", err.Error())
+		return fmt.Errorf("certificate not issued by the CA in %s", certificates.CAFileName)
+	}
+	return nil
+}
+
+// copyNodeCertificates copies the user-provided certificate and private key of the given pod in the transport
+// certificates secret. It returns false if there is no certificate for this pod.
+func copyNodeCertificates(secret *corev1.Secret, pod corev1.Pod, nodeCertificates map[string][]byte) bool {
+	cert, certExists := nodeCertificates[PodCertFileName(pod.Name)]
+	key, keyExists := nodeCertificates[PodKeyFileName(pod.Name)]
+	if !certExists || !keyExists {
+		return false
+	}
+	secret.Data[PodCertFileName(pod.Name)] = cert
+	secret.Data[PodKeyFileName(pod.Name)] = key
+	return true
+}
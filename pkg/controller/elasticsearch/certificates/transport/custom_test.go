@@ -0,0 +1,167 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_parseCustomCertificates(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(cryptorand.Reader, 1024)
+	require.NoError(t, err)
+	caCert := certificates.EncodePEMCert(testCA.Cert.Raw)
+	otherPEMKey, err := certificates.EncodePEMPrivateKey(otherKey)
+	require.NoError(t, err)
+	caKey := pemPrivateKey
+	otherCA, err := certificates.NewSelfSignedCA(certificates.CABuilderOptions{PrivateKey: otherKey})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name                      string
+		data                      map[string][]byte
+		wantSignsNodeCertificates bool
+		wantNodeCertificates      map[string][]byte
+		wantErr                   string
+	}{
+		{
+			name:    "missing CA",
+			data:    map[string][]byte{},
+			wantErr: "no PEM certificate in ca.crt",
+		},
+		{
+			name:                      "CA with its private key",
+			data:                      map[string][]byte{certificates.CAFileName: caCert, certificates.CAKeyFileName: caKey},
+			wantSignsNodeCertificates: true,
+		},
+		{
+			name:    "CA with a mismatching private key",
+			data:    map[string][]byte{certificates.CAFileName: caCert, certificates.CAKeyFileName: otherPEMKey},
+			wantErr: "private key in ca.key does not match the certificate",
+		},
+		{
+			name:    "private key for a certificate that is not a CA",
+			data:    map[string][]byte{certificates.CAFileName: certificates.EncodePEMCert(certData), certificates.CAKeyFileName: caKey},
+			wantErr: "certificate in ca.crt is not a CA",
+		},
+		{
+			name: "node certificates",
+			data: map[string][]byte{
+				certificates.CAFileName:       caCert,
+				PodCertFileName(testPod.Name): pemCert,
+				PodKeyFileName(testPod.Name):  caKey,
+				"unrelated":                   []byte("ignored"),
+			},
+			wantNodeCertificates: map[string][]byte{
+				PodCertFileName(testPod.Name): pemCert,
+				PodKeyFileName(testPod.Name):  caKey,
+			},
+		},
+		{
+			name: "invalid node certificate",
+			data: map[string][]byte{
+				certificates.CAFileName:       caCert,
+				PodCertFileName(testPod.Name): []byte("not a certificate"),
+				PodKeyFileName(testPod.Name):  caKey,
+			},
+			wantErr: "no PEM certificate in test-pod-name.tls.crt",
+		},
+		{
+			name: "node certificate without private key",
+			data: map[string][]byte{
+				certificates.CAFileName:       caCert,
+				PodCertFileName(testPod.Name): pemCert,
+			},
+			wantErr: "test-pod-name.tls.crt: missing private key",
+		},
+		{
+			name: "node certificate with a mismatching private key",
+			data: map[string][]byte{
+				certificates.CAFileName:       caCert,
+				PodCertFileName(testPod.Name): pemCert,
+				PodKeyFileName(testPod.Name):  otherPEMKey,
+			},
+			wantErr: "test-pod-name.tls.crt: private key does not match the certificate",
+		},
+		{
+			name: "node certificate issued by another CA",
+			data: map[string][]byte{
+				certificates.CAFileName:       certificates.EncodePEMCert(otherCA.Cert.Raw),
+				PodCertFileName(testPod.Name): certificates.EncodePEMCert(certData),
+				PodKeyFileName(testPod.Name):  caKey,
+			},
+			wantErr: "test-pod-name.tls.crt: certificate not issued by the CA in ca.crt",
+		},
+		{
+			name:    "neither CA private key nor node certificates",
+			data:    map[string][]byte{certificates.CAFileName: caCert},
+			wantErr: "either a CA private key or node certificates are required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			custom, err := parseCustomCertificates(corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-certs"},
+				Data:       tt.data,
+			})
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "my-certs", custom.SecretName)
+			require.Equal(t, testCA.Cert.Raw, custom.CA.Cert.Raw)
+			require.Equal(t, tt.wantSignsNodeCertificates, custom.SignsNodeCertificates())
+			require.Equal(t, tt.wantNodeCertificates, custom.NodeCertificates)
+		})
+	}
+}
+
+func TestLoadCustomCertificates(t *testing.T) {
+	es := v1beta1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	withSecret := es.DeepCopy()
+	withSecret.Spec.Transport.TLS.Certificate = commonv1beta1.SecretRef{SecretName: "my-certs"}
+	c := k8s.WrapClient(fake.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "my-certs"},
+		Data:       map[string][]byte{certificates.CAFileName: certificates.EncodePEMCert(testCA.Cert.Raw)},
+	}))
+
+	custom, err := LoadCustomCertificates(c, es)
+	require.NoError(t, err)
+	require.Nil(t, custom)
+
+	_, err = LoadCustomCertificates(c, *withSecret)
+	require.EqualError(t, err, "invalid transport certificates in secret my-certs: either a CA private key or node certificates are required")
+}
+
+func Test_copyNodeCertificates(t *testing.T) {
+	nodeCertificates := map[string][]byte{
+		PodCertFileName(testPod.Name): pemCert,
+		PodKeyFileName(testPod.Name):  []byte("key"),
+		PodCertFileName("other-pod"):  pemCert,
+	}
+
+	secret := corev1.Secret{Data: map[string][]byte{}}
+	require.True(t, copyNodeCertificates(&secret, testPod, nodeCertificates))
+	require.Equal(t, map[string][]byte{
+		PodCertFileName(testPod.Name): pemCert,
+		PodKeyFileName(testPod.Name):  []byte("key"),
+	}, secret.Data)
+
+	// the private key of other-pod is missing
+	otherPod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other-pod"}}
+	require.False(t, copyNodeCertificates(&secret, otherPod, nodeCertificates))
+	require.Len(t, secret.Data, 2)
+}
@@ -0,0 +1,112 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	"fmt"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/finalizer"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ExternalCAs are the additional CAs trusted on the transport layer, as referenced in the Elasticsearch spec.
+type ExternalCAs struct {
+	// PEM holds the certificates of the valid secrets.
+	PEM []byte
+	// Invalid explains why some of the referenced secrets are ignored.
+	Invalid []string
+}
+
+// LoadExternalCAs reads and validates the additional CAs referenced in the Elasticsearch spec.
+// Missing secrets and secrets without valid PEM certificates are ignored, and reported in the returned ExternalCAs:
+// they must not prevent the reconciliation of the transport certificates.
+func LoadExternalCAs(c k8s.Client, es v1beta1.Elasticsearch) (ExternalCAs, error) {
+	var cas ExternalCAs
+	for _, name := range es.Spec.Transport.TLS.CertificateAuthoritiesSecretNames() {
+		var secret corev1.Secret
+		if err := c.Get(types.NamespacedName{Namespace: es.Namespace, Name: name}, &secret); err != nil {
+			if errors.IsNotFound(err) {
+				cas.Invalid = append(cas.Invalid, fmt.Sprintf("secret %s not found", name))
+				continue
+			}
+			return ExternalCAs{}, err
+		}
+		pemData, err := parseExternalCA(secret)
+		if err != nil {
+			cas.Invalid = append(cas.Invalid, fmt.Sprintf("secret %s: %s", name, err.Error()))
+			continue
+		}
+		cas.PEM = append(cas.PEM, pemData...)
+	}
+	return cas, nil
+}
+
+// parseExternalCA returns the PEM encoded certificates held by the given secret.
+func parseExternalCA(secret corev1.Secret) ([]byte, error) {
+	data, exists := secret.Data[certificates.CAFileName]
+	if !exists {
+		return nil, fmt.Errorf("missing %s key", certificates.CAFileName)
+	}
+	certs, err := certificates.ParsePEMCerts(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate in %s: %s", certificates.CAFileName, err.Error())
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificate in %s", certificates.CAFileName)
+	}
+	blocks := make([][]byte, 0, len(certs))
+	for _, cert := range certs {
+		blocks = append(blocks, cert.Raw)
+	}
+	return certificates.EncodePEMCert(blocks...), nil
+}
+
+func transportSecretsWatchName(es types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-transport-secrets", es.Namespace, es.Name)
+}
+
+// WatchTransportSecrets registers a watch on the user-provided secrets referenced in the transport configuration
+// of the given cluster, and on the secret issued by cert-manager, or removes it if there is no such secret.
+func WatchTransportSecrets(watched watches.DynamicWatches, es v1beta1.Elasticsearch) error {
+	nsn := k8s.ExtractNamespacedName(&es)
+	watchName := transportSecretsWatchName(nsn)
+	var secrets []types.NamespacedName
+	if name := es.Spec.Transport.TLS.Certificate.SecretName; name != "" {
+		secrets = append(secrets, types.NamespacedName{Namespace: es.Namespace, Name: name})
+	}
+	if es.Spec.Transport.TLS.IssuerRef != nil {
+		secrets = append(secrets, types.NamespacedName{Namespace: es.Namespace, Name: name.TransportCertsIssuedSecret(es.Name)})
+	}
+	for _, name := range es.Spec.Transport.TLS.CertificateAuthoritiesSecretNames() {
+		secrets = append(secrets, types.NamespacedName{Namespace: es.Namespace, Name: name})
+	}
+	if len(secrets) == 0 {
+		watched.Secrets.RemoveHandlerForKey(watchName)
+		return nil
+	}
+	return watched.Secrets.AddHandler(watches.NamedWatch{
+		Name:    watchName,
+		Watched: secrets,
+		Watcher: nsn,
+	})
+}
+
+// TransportSecretsFinalizer removes the watch on the user-provided transport secrets of the given cluster.
+func TransportSecretsFinalizer(es types.NamespacedName, watched watches.DynamicWatches) finalizer.Finalizer {
+	return finalizer.Finalizer{
+		Name: "finalizer.elasticsearch.k8s.elastic.co/transport-secrets",
+		Execute: func() error {
+			watched.Secrets.RemoveHandlerForKey(transportSecretsWatchName(es))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	"strings"
+	"testing"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLoadExternalCAs(t *testing.T) {
+	caSecret := func(name string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}, Data: data}
+	}
+	validCA := certificates.EncodePEMCert(testCA.Cert.Raw)
+	c := k8s.WrapClient(fake.NewFakeClient(
+		caSecret("valid", map[string][]byte{certificates.CAFileName: validCA}),
+		caSecret("no-ca", map[string][]byte{"tls.crt": validCA}),
+		caSecret("not-pem", map[string][]byte{certificates.CAFileName: []byte("not a certificate")}),
+		caSecret("invalid-pem", map[string][]byte{certificates.CAFileName: []byte("-----BEGIN CERTIFICATE-----\naW52YWxpZA==\n-----END CERTIFICATE-----\n")}),
+	))
+
+	tests := []struct {
+		name        string
+		secrets     []string
+		wantPEM     []byte
+		wantInvalid []string
+	}{
+		{
+			name: "no additional CAs",
+		},
+		{
+			name:    "valid CA",
+			secrets: []string{"valid"},
+			wantPEM: validCA,
+		},
+		{
+			name:    "invalid secrets are reported and ignored",
+			secrets: []string{"missing", "no-ca", "not-pem", "invalid-pem", "valid"},
+			wantPEM: validCA,
+			wantInvalid: []string{
+				"secret missing not found",
+				"secret no-ca: missing ca.crt key",
+				"secret not-pem: no PEM certificate in ca.crt",
+				// followed by the x509 parsing error
+				"secret invalid-pem: invalid certificate in ca.crt: ",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := v1beta1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+			for _, name := range tt.secrets {
+				es.Spec.Transport.TLS.CertificateAuthorities = append(es.Spec.Transport.TLS.CertificateAuthorities, commonv1beta1.SecretRef{SecretName: name})
+			}
+			cas, err := LoadExternalCAs(c, es)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPEM, cas.PEM)
+			require.Len(t, cas.Invalid, len(tt.wantInvalid))
+			for i := range tt.wantInvalid {
+				require.True(t, strings.HasPrefix(cas.Invalid[i], tt.wantInvalid[i]), cas.Invalid[i])
+			}
+		})
+	}
+}
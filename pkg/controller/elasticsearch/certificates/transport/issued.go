@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	"fmt"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates/certmanager"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReconcileIssuedCertificates requests a transport certificate from the cert-manager issuer referenced in the
+// Elasticsearch spec. The certificate is shared by all the nodes: its wildcard name matches the name of each node,
+// and the transport layer does not verify hostnames.
+// It returns the issued certificate of each pod, or an error while the certificate is not issued yet.
+func ReconcileIssuedCertificates(
+	c k8s.Client,
+	scheme *runtime.Scheme,
+	es v1beta1.Elasticsearch,
+	keyParams certificates.KeyParams,
+) (*CustomCertificates, error) {
+	secretName := name.TransportCertsIssuedSecret(es.Name)
+	// same domain as the common name of the certificates issued by the operator
+	commonName := fmt.Sprintf("*.node.%s.%s.es.local", es.Name, es.Namespace)
+	expected, err := certmanager.NewCertificate(
+		es.Namespace,
+		secretName,
+		label.NewLabels(k8s.ExtractNamespacedName(&es)),
+		certmanager.NewCertificateSpec(*es.Spec.Transport.TLS.IssuerRef, secretName, commonName, []string{commonName}, nil, keyParams),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := certmanager.ReconcileCertificate(c, scheme, &es, expected)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("waiting for cert-manager to issue the transport certificate in secret %s", secretName)
+	}
+
+	var pods corev1.PodList
+	if err := c.List(&pods, label.NewLabelSelectorForElasticsearch(es), client.InNamespace(es.Namespace)); err != nil {
+		return nil, err
+	}
+	return parseIssuedCertificate(*secret, pods.Items)
+}
+
+// parseIssuedCertificate returns the CA and certificate issued by cert-manager in the given secret,
+// as the node certificate of each of the given pods.
+func parseIssuedCertificate(secret corev1.Secret, pods []corev1.Pod) (*CustomCertificates, error) {
+	caCerts, err := certificates.ParsePEMCerts(secret.Data[certificates.CAFileName])
+	if err != nil {
+		return nil, err
+	}
+	if len(caCerts) == 0 {
+		return nil, fmt.Errorf("no CA certificate in %s of secret %s issued by cert-manager", certificates.CAFileName, secret.Name)
+	}
+	certs, err := certificates.ParsePEMCerts(secret.Data[certificates.CertFileName])
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate in %s of secret %s issued by cert-manager", certificates.CertFileName, secret.Name)
+	}
+
+	nodeCertificates := make(map[string][]byte, 2*len(pods))
+	for _, pod := range pods {
+		nodeCertificates[PodCertFileName(pod.Name)] = secret.Data[certificates.CertFileName]
+		nodeCertificates[PodKeyFileName(pod.Name)] = secret.Data[certificates.KeyFileName]
+	}
+	return &CustomCertificates{
+		SecretName:       secret.Name,
+		CA:               certificates.NewCA(nil, caCerts[0]),
+		NodeCertificates: nodeCertificates,
+	}, nil
+}
@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	"testing"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates/certmanager"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileIssuedCertificates(t *testing.T) {
+	es := *testES.DeepCopy()
+	es.Spec.Transport.TLS.IssuerRef = &commonv1beta1.IssuerRef{Name: "ca-issuer", Kind: "ClusterIssuer"}
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: es.Namespace,
+		Name:      "test-es-name-es-default-0",
+		Labels:    map[string]string{label.ClusterNameLabelName: es.Name},
+	}}
+	c := k8s.WrapClient(fake.NewFakeClient(&pod))
+
+	// the Certificate is not issued yet
+	_, err := ReconcileIssuedCertificates(c, scheme.Scheme, es, certificates.DefaultKeyParams)
+	require.EqualError(t, err, "waiting for cert-manager to issue the transport certificate in secret test-es-name-es-transport-certs-issued")
+	var cert unstructured.Unstructured
+	cert.SetGroupVersionKind(certmanager.CertificateGVK)
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: es.Namespace, Name: "test-es-name-es-transport-certs-issued"}, &cert))
+	commonName, _, err := unstructured.NestedString(cert.Object, "spec", "commonName")
+	require.NoError(t, err)
+	require.Equal(t, "*.node.test-es-name.test-namespace.es.local", commonName)
+	issuerKind, _, err := unstructured.NestedString(cert.Object, "spec", "issuerRef", "kind")
+	require.NoError(t, err)
+	require.Equal(t, "ClusterIssuer", issuerKind)
+
+	// cert-manager issues the certificate
+	key := pemPrivateKey
+	require.NoError(t, c.Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: "test-es-name-es-transport-certs-issued"},
+		Data: map[string][]byte{
+			certificates.CAFileName:   certificates.EncodePEMCert(testCA.Cert.Raw),
+			certificates.CertFileName: pemCert,
+			certificates.KeyFileName:  key,
+		},
+	}))
+	issued, err := ReconcileIssuedCertificates(c, scheme.Scheme, es, certificates.DefaultKeyParams)
+	require.NoError(t, err)
+	require.Equal(t, testCA.Cert.Raw, issued.CA.Cert.Raw)
+	require.False(t, issued.SignsNodeCertificates())
+	require.Equal(t, map[string][]byte{
+		PodCertFileName(pod.Name): pemCert,
+		PodKeyFileName(pod.Name):  key,
+	}, issued.NodeCertificates)
+}
+
+func Test_parseIssuedCertificate(t *testing.T) {
+	_, err := parseIssuedCertificate(corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "issued"},
+		Data:       map[string][]byte{certificates.CertFileName: pemCert},
+	}, nil)
+	require.EqualError(t, err, "no CA certificate in ca.crt of secret issued issued by cert-manager")
+}
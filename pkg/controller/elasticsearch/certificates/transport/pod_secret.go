@@ -5,8 +5,8 @@
 package transport
 
 import (
+	"crypto"
 	cryptorand "crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"errors"
 	"fmt"
@@ -36,16 +36,21 @@ func ensureTransportCertificatesSecretContentsForPod(
 	pod corev1.Pod,
 	ca *certificates.CA,
 	rotationParams certificates.RotationParams,
+	keyParams certificates.KeyParams,
 ) error {
-	// verify that the secret contains a parsable private key, create if it does not exist
-	var privateKey *rsa.PrivateKey
+	// verify that the secret contains a parsable private key matching the key params, create if it does not exist
+	var privateKey crypto.Signer
 	needsNewPrivateKey := true
 	if privateKeyData, ok := secret.Data[PodKeyFileName(pod.Name)]; ok {
 		storedPrivateKey, err := certificates.ParsePEMPrivateKey(privateKeyData)
-		if err != nil {
+		switch {
+		case err != nil:
 			log.Error(err, "Unable to parse stored private key",
 				"namespace", pod.Namespace, "pod_name", pod.Name)
-		} else {
+		case !keyParams.Matches(storedPrivateKey):
+			log.Info("Stored private key does not match the key params",
+				"namespace", pod.Namespace, "pod_name", pod.Name)
+		default:
 			needsNewPrivateKey = false
 			privateKey = storedPrivateKey
 		}
@@ -53,13 +58,17 @@ func ensureTransportCertificatesSecretContentsForPod(
 
 	// if we need a new private key, generate it
 	if needsNewPrivateKey {
-		generatedPrivateKey, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+		generatedPrivateKey, err := keyParams.GenerateKey()
+		if err != nil {
+			return err
+		}
+		encodedPrivateKey, err := certificates.EncodePEMPrivateKey(generatedPrivateKey)
 		if err != nil {
 			return err
 		}
 
 		privateKey = generatedPrivateKey
-		secret.Data[PodKeyFileName(pod.Name)] = certificates.EncodePEMPrivateKey(*privateKey)
+		secret.Data[PodKeyFileName(pod.Name)] = encodedPrivateKey
 	}
 
 	if shouldIssueNewCertificate(es, *secret, pod, privateKey, ca, rotationParams.RotateBefore) {
@@ -110,7 +119,7 @@ func shouldIssueNewCertificate(
 	es v1beta1.Elasticsearch,
 	secret corev1.Secret,
 	pod corev1.Pod,
-	privateKey *rsa.PrivateKey,
+	privateKey crypto.Signer,
 	ca *certificates.CA,
 	certReconcileBefore time.Duration,
 ) bool {
@@ -128,8 +137,7 @@ func shouldIssueNewCertificate(
 		return true
 	}
 
-	publicKey, publicKeyOk := cert.PublicKey.(*rsa.PublicKey)
-	if !publicKeyOk || publicKey.N.Cmp(privateKey.PublicKey.N) != 0 || publicKey.E != privateKey.PublicKey.E {
+	if !certificates.PrivateMatchesPublicKey(cert.PublicKey, privateKey) {
 		log.Info(
 			"Certificate belongs do a different public key, should issue new",
 			"namespace", pod.Namespace,
@@ -5,6 +5,7 @@
 package transport
 
 import (
+	"crypto/ecdsa"
 	"testing"
 	"time"
 
@@ -109,6 +110,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 		name       string
 		secret     *corev1.Secret
 		pod        *corev1.Pod
+		keyParams  *certificates.KeyParams
 		assertions func(t *testing.T, before corev1.Secret, after corev1.Secret)
 		wantErr    func(t *testing.T, err error)
 	}{
@@ -131,7 +133,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 			name: "no cert in the secret",
 			secret: &corev1.Secret{
 				Data: map[string][]byte{
-					PodKeyFileName(testPod.Name): certificates.EncodePEMPrivateKey(*testRSAPrivateKey),
+					PodKeyFileName(testPod.Name): pemPrivateKey,
 				},
 			},
 			assertions: func(t *testing.T, before corev1.Secret, after corev1.Secret) {
@@ -146,7 +148,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 			name: "cert does not belong to the key in the secret",
 			secret: &corev1.Secret{
 				Data: map[string][]byte{
-					PodKeyFileName(testPod.Name):  certificates.EncodePEMPrivateKey(*testRSAPrivateKey),
+					PodKeyFileName(testPod.Name):  pemPrivateKey,
 					PodCertFileName(testPod.Name): certificates.EncodePEMCert(testCA.Cert.Raw),
 				},
 			},
@@ -163,7 +165,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 			name: "invalid cert in the secret",
 			secret: &corev1.Secret{
 				Data: map[string][]byte{
-					PodKeyFileName(testPod.Name):  certificates.EncodePEMPrivateKey(*testRSAPrivateKey),
+					PodKeyFileName(testPod.Name):  pemPrivateKey,
 					PodCertFileName(testPod.Name): []byte("invalid"),
 				},
 			},
@@ -176,11 +178,29 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 				assert.NotEqual(t, after.Data[PodCertFileName(testPod.Name)], before.Data[PodCertFileName(testPod.Name)])
 			},
 		},
+		{
+			name: "key does not match the key params",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					PodKeyFileName(testPod.Name):  pemPrivateKey,
+					PodCertFileName(testPod.Name): pemCert,
+				},
+			},
+			keyParams: &certificates.KeyParams{Algorithm: certificates.ECDSAKeyAlgorithm, Size: 256},
+			assertions: func(t *testing.T, before corev1.Secret, after corev1.Secret) {
+				// both the key and the cert should be re-generated
+				assert.NotEqual(t, before.Data[PodKeyFileName(testPod.Name)], after.Data[PodKeyFileName(testPod.Name)])
+				assert.NotEqual(t, before.Data[PodCertFileName(testPod.Name)], after.Data[PodCertFileName(testPod.Name)])
+				key, err := certificates.ParsePEMPrivateKey(after.Data[PodKeyFileName(testPod.Name)])
+				require.NoError(t, err)
+				assert.IsType(t, &ecdsa.PrivateKey{}, key)
+			},
+		},
 		{
 			name: "valid data should not require updating",
 			secret: &corev1.Secret{
 				Data: map[string][]byte{
-					PodKeyFileName(testPod.Name):  certificates.EncodePEMPrivateKey(*testRSAPrivateKey),
+					PodKeyFileName(testPod.Name):  pemPrivateKey,
 					PodCertFileName(testPod.Name): pemCert,
 				},
 			},
@@ -197,6 +217,9 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 			if tt.pod == nil {
 				tt.pod = testPod.DeepCopy()
 			}
+			if tt.keyParams == nil {
+				tt.keyParams = &certificates.DefaultKeyParams
+			}
 
 			beforeSecret := tt.secret.DeepCopy()
 
@@ -209,6 +232,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 					Validity:     certificates.DefaultCertValidity,
 					RotateBefore: certificates.DefaultRotateBefore,
 				},
+				*tt.keyParams,
 			)
 			if tt.wantErr != nil {
 				tt.wantErr(t, err)
@@ -6,9 +6,12 @@ package transport
 
 import (
 	"bytes"
+	"crypto/x509"
+	"fmt"
 	"reflect"
 	"strings"
 
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/annotation"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
@@ -27,38 +30,51 @@ import (
 var log = logf.Log.WithName("transport")
 
 // ReconcileTransportCertificatesSecrets reconciles the secret containing transport certificates for all nodes in the
-// cluster.
+// cluster, and the CAs they trust: the cluster CA, the CAs of its remote clusters and the given external CAs.
+// The certificates are issued by the given CA, unless user-provided node certificates are given.
+// It returns when the CA and the first node certificate to expire expire.
 func ReconcileTransportCertificatesSecrets(
 	c k8s.Client,
 	scheme *runtime.Scheme,
 	ca *certificates.CA,
 	es v1beta1.Elasticsearch,
+	externalCAs []byte,
+	nodeCertificates map[string][]byte,
 	rotationParams certificates.RotationParams,
-) (reconcile.Result, error) {
+	keyParams certificates.KeyParams,
+) (commonv1beta1.CertificateExpiration, reconcile.Result, error) {
 	var pods corev1.PodList
 	matchLabels := label.NewLabelSelectorForElasticsearch(es)
 	ns := client.InNamespace(es.Namespace)
 	if err := c.List(&pods, matchLabels, ns); err != nil {
-		return reconcile.Result{}, err
+		return commonv1beta1.CertificateExpiration{}, reconcile.Result{}, err
 	}
 
 	secret, err := ensureTransportCertificatesSecretExists(c, scheme, es)
 	if err != nil {
-		return reconcile.Result{}, err
+		return commonv1beta1.CertificateExpiration{}, reconcile.Result{}, err
 	}
 	// defensive copy of the current secret so we can check whether we need to update later on
 	currentTransportCertificatesSecret := secret.DeepCopy()
 
+	var missingNodeCertificates []string
 	for _, pod := range pods.Items {
+		if nodeCertificates != nil {
+			if !copyNodeCertificates(secret, pod, nodeCertificates) {
+				missingNodeCertificates = append(missingNodeCertificates, pod.Name)
+			}
+			continue
+		}
+
 		if pod.Status.PodIP == "" {
 			log.Info("Skipping pod because it has no IP yet", "namespace", pod.Namespace, "pod_name", pod.Name)
 			continue
 		}
 
 		if err := ensureTransportCertificatesSecretContentsForPod(
-			es, secret, pod, ca, rotationParams,
+			es, secret, pod, ca, rotationParams, keyParams,
 		); err != nil {
-			return reconcile.Result{}, err
+			return commonv1beta1.CertificateExpiration{}, reconcile.Result{}, err
 		}
 	}
 
@@ -87,7 +103,10 @@ func ReconcileTransportCertificatesSecrets(
 		}
 	}
 
-	caBytes := certificates.EncodePEMCert(ca.Cert.Raw)
+	caBytes, err := trustedCAs(c, k8s.ExtractNamespacedName(&es), ca, externalCAs)
+	if err != nil {
+		return commonv1beta1.CertificateExpiration{}, reconcile.Result{}, err
+	}
 
 	// compare with current trusted CA certs.
 	if !bytes.Equal(caBytes, secret.Data[certificates.CAFileName]) {
@@ -96,14 +115,41 @@ func ReconcileTransportCertificatesSecrets(
 
 	if !reflect.DeepEqual(secret, currentTransportCertificatesSecret) {
 		if err := c.Update(secret); err != nil {
-			return reconcile.Result{}, err
+			return commonv1beta1.CertificateExpiration{}, reconcile.Result{}, err
 		}
 		for _, pod := range pods.Items {
 			annotation.MarkPodAsUpdated(c, pod)
 		}
 	}
 
-	return reconcile.Result{}, nil
+	expiration, err := nodeCertificatesExpiration(ca, *secret, pods.Items)
+	if err != nil {
+		return commonv1beta1.CertificateExpiration{}, reconcile.Result{}, err
+	}
+	if len(missingNodeCertificates) > 0 {
+		return expiration, reconcile.Result{}, fmt.Errorf("missing transport certificates for pods %v", missingNodeCertificates)
+	}
+	return expiration, reconcile.Result{}, nil
+}
+
+// nodeCertificatesExpiration returns when the given CA and the first certificate of the given pods in the transport
+// certificates secret expire.
+func nodeCertificatesExpiration(
+	ca *certificates.CA,
+	secret corev1.Secret,
+	pods []corev1.Pod,
+) (commonv1beta1.CertificateExpiration, error) {
+	nodeCerts := make([]*x509.Certificate, 0, len(pods))
+	for _, pod := range pods {
+		certs, err := certificates.ParsePEMCerts(secret.Data[PodCertFileName(pod.Name)])
+		if err != nil {
+			return commonv1beta1.CertificateExpiration{}, err
+		}
+		if len(certs) > 0 {
+			nodeCerts = append(nodeCerts, certs[0])
+		}
+	}
+	return certificates.NewExpiration(ca.Cert, nodeCerts), nil
 }
 
 // ensureTransportCertificatesSecretExists ensures the existence and Labels of the Secret that at a later point
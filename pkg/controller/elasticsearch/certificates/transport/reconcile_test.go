@@ -10,9 +10,11 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
 
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -109,3 +111,22 @@ func Test_ensureTransportCertificateSecretExists(t *testing.T) {
 		})
 	}
 }
+
+func Test_nodeCertificatesExpiration(t *testing.T) {
+	otherPod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-without-certificate"}}
+	secret := corev1.Secret{Data: map[string][]byte{
+		certificates.CAFileName:       certificates.EncodePEMCert(testCA.Cert.Raw),
+		PodCertFileName(testPod.Name): pemCert,
+	}}
+	cert, err := certificates.ParsePEMCerts(pemCert)
+	require.NoError(t, err)
+
+	expiration, err := nodeCertificatesExpiration(testCA, secret, []corev1.Pod{testPod, otherPod})
+	require.NoError(t, err)
+	require.True(t, testCA.Cert.NotAfter.Equal(expiration.CANotAfter.Time))
+	require.True(t, cert[0].NotAfter.Equal(expiration.NotAfter.Time))
+
+	expiration, err = nodeCertificatesExpiration(testCA, secret, []corev1.Pod{otherPod})
+	require.NoError(t, err)
+	require.Nil(t, expiration.NotAfter)
+}
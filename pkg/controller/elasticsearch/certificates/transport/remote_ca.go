@@ -0,0 +1,82 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// RemoteCAType is the type label value of the secrets holding the transport CA of a remote cluster.
+	RemoteCAType = "remote-ca"
+	// RemoteClusterNameLabelName is the name of the remote cluster whose transport CA is held by a secret.
+	RemoteClusterNameLabelName = "elasticsearch.k8s.elastic.co/remote-cluster-name"
+	// RemoteClusterNamespaceLabelName is the namespace of the remote cluster whose transport CA is held by a secret.
+	RemoteClusterNamespaceLabelName = "elasticsearch.k8s.elastic.co/remote-cluster-namespace"
+
+	remoteCASecretSuffix = "remote-ca"
+)
+
+// RemoteCASecretName returns the name of the secret holding the transport CA of the given remote cluster,
+// in the namespace of the cluster es.
+func RemoteCASecretName(es string, remote types.NamespacedName) string {
+	return name.ESNamer.Suffix(es, remoteCASecretSuffix, hash.HashObject(remote))
+}
+
+// RemoteCALabels returns the labels of the secret holding the transport CA of the given remote cluster.
+func RemoteCALabels(es string, remote types.NamespacedName) map[string]string {
+	return map[string]string{
+		label.ClusterNameLabelName:      es,
+		common.TypeLabelName:            RemoteCAType,
+		RemoteClusterNameLabelName:      remote.Name,
+		RemoteClusterNamespaceLabelName: remote.Namespace,
+	}
+}
+
+// RemoteCASecrets returns the secrets holding the transport CAs of the remote clusters of the given cluster.
+func RemoteCASecrets(c k8s.Client, es types.NamespacedName) ([]corev1.Secret, error) {
+	var secrets corev1.SecretList
+	matchLabels := client.MatchingLabels(map[string]string{
+		label.ClusterNameLabelName: es.Name,
+		common.TypeLabelName:       RemoteCAType,
+	})
+	if err := c.List(&secrets, matchLabels, client.InNamespace(es.Namespace)); err != nil {
+		return nil, err
+	}
+	return secrets.Items, nil
+}
+
+// trustedCAs returns the PEM encoded CAs trusted by the nodes of the given cluster on the transport layer:
+// the cluster's own CA, followed by the CAs of its remote clusters and by the given external CAs.
+func trustedCAs(c k8s.Client, es types.NamespacedName, ca *certificates.CA, externalCAs []byte) ([]byte, error) {
+	remoteCAs, err := RemoteCASecrets(c, es)
+	if err != nil {
+		return nil, err
+	}
+	// sort to avoid unnecessary updates
+	sort.Slice(remoteCAs, func(i, j int) bool {
+		return remoteCAs[i].Name < remoteCAs[j].Name
+	})
+
+	caBytes := [][]byte{certificates.EncodePEMCert(ca.Cert.Raw)}
+	for _, secret := range remoteCAs {
+		if remoteCA := secret.Data[certificates.CAFileName]; len(remoteCA) > 0 {
+			caBytes = append(caBytes, remoteCA)
+		}
+	}
+	caBytes = append(caBytes, externalCAs)
+	return bytes.Join(caBytes, nil), nil
+}
@@ -0,0 +1,60 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_trustedCAs(t *testing.T) {
+	es := types.NamespacedName{Namespace: "ns", Name: "es"}
+	remoteCA := func(es string, remote types.NamespacedName, ca string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      RemoteCASecretName(es, remote),
+				Labels:    RemoteCALabels(es, remote),
+			},
+			Data: map[string][]byte{certificates.CAFileName: []byte(ca)},
+		}
+	}
+	remote1 := types.NamespacedName{Namespace: "other", Name: "remote1"}
+	remote2 := types.NamespacedName{Namespace: "ns", Name: "remote2"}
+	c := k8s.WrapClient(fake.NewFakeClient(
+		remoteCA("es", remote1, "remote1-ca\n"),
+		remoteCA("es", remote2, "remote2-ca\n"),
+		// CA of a remote cluster of another cluster
+		remoteCA("another-es", remote1, "remote1-ca\n"),
+	))
+
+	ownCA := certificates.EncodePEMCert(testCA.Cert.Raw)
+	cas, err := trustedCAs(c, es, testCA, []byte("external-ca\n"))
+	require.NoError(t, err)
+
+	secrets, err := RemoteCASecrets(c, es)
+	require.NoError(t, err)
+	require.Len(t, secrets, 2)
+	expected := string(ownCA)
+	if secrets[0].Name < secrets[1].Name {
+		expected += string(secrets[0].Data[certificates.CAFileName]) + string(secrets[1].Data[certificates.CAFileName])
+	} else {
+		expected += string(secrets[1].Data[certificates.CAFileName]) + string(secrets[0].Data[certificates.CAFileName])
+	}
+	expected += "external-ca\n"
+	require.Equal(t, expected, string(cas))
+
+	// no remote cluster nor external CA
+	cas, err = trustedCAs(k8s.WrapClient(fake.NewFakeClient()), es, testCA, nil)
+	require.NoError(t, err)
+	require.Equal(t, ownCA, cas)
+}
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
@@ -63,11 +64,16 @@ func (c *baseClient) doRequest(context context.Context, request *http.Request) (
 		withContext.SetBasicAuth(c.User.Name, c.User.Password)
 	}
 
+	api := apiOf(request.URL.Path)
+	start := time.Now()
 	response, err := c.HTTP.Do(withContext)
+	requestDuration.WithLabelValues(request.Method, api).Observe(time.Since(start).Seconds())
+	if err == nil {
+		err = checkError(response)
+	}
 	if err != nil {
-		return response, err
+		requestErrors.WithLabelValues(request.Method, api).Inc()
 	}
-	err = checkError(response)
 	return response, err
 }
 
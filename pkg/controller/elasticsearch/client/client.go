@@ -32,31 +32,45 @@ type UserAuth struct {
 	Password string
 }
 
-// Role represents an Elasticsearch role.
+// Role represents an Elasticsearch role, as defined in the roles.yml file.
 type Role struct {
-	Cluster []string `json:"cluster,omitempty"`
-	/*Indices []struct {
-		Names      []string `json:"names,omitempty"`
-		Privileges []string `json:",omitempty"`
-	} `json:"indices,omitempty"`
-	Applications []struct {
-		Application string   `json:"application"`
-		Privileges  []string `json:"privileges"`
-		Resources   []string `json:"resources,omitempty"`
-	} `json:"applications,omitempty"`
-	RunAs    []string `json:"run_as,omitempty"`
-	Metadata *struct {
-		Reserved bool `json:"_reserved"`
-	} `json:"metadata,omitempty"`
-	TransientMetadata *struct {
-		Enabled bool `json:"enabled"`
-	} `json:"transient_metadata,omitempty"`*/
+	Cluster      []string                `json:"cluster,omitempty"`
+	Indices      []IndicesPrivileges     `json:"indices,omitempty"`
+	Applications []ApplicationPrivileges `json:"applications,omitempty"`
+	RunAs        []string                `json:"run_as,omitempty"`
+	Metadata     map[string]interface{}  `json:"metadata,omitempty"`
+}
+
+// IndicesPrivileges are the privileges of a role on a set of indices.
+type IndicesPrivileges struct {
+	Names                  []string       `json:"names"`
+	Privileges             []string       `json:"privileges"`
+	FieldSecurity          *FieldSecurity `json:"field_security,omitempty"`
+	Query                  string         `json:"query,omitempty"`
+	AllowRestrictedIndices bool           `json:"allow_restricted_indices,omitempty"`
+}
+
+// FieldSecurity restricts the fields a role can read.
+type FieldSecurity struct {
+	Grant  []string `json:"grant,omitempty"`
+	Except []string `json:"except,omitempty"`
+}
+
+// ApplicationPrivileges are the privileges of a role on the resources of an application.
+type ApplicationPrivileges struct {
+	Application string   `json:"application"`
+	Privileges  []string `json:"privileges"`
+	Resources   []string `json:"resources"`
 }
 
 // Client captures the information needed to interact with an Elasticsearch cluster via HTTP
 type Client interface {
 	AllocationSetter
 	ShardLister
+	SnapshotClient
+	IndexLifecycleClient
+	SecurityClient
+	RemoteClusterClient
 	// Close idle connections in the underlying http client.
 	Close()
 	// Equal returns true if other can be considered as the same client.
@@ -74,11 +88,13 @@ type Client interface {
 	SyncedFlush(ctx context.Context) error
 	// GetClusterHealth calls the _cluster/health api.
 	GetClusterHealth(ctx context.Context) (Health, error)
+	// GetIndicesHealth calls the _cluster/health api for the given indices or index patterns.
+	GetIndicesHealth(ctx context.Context, indices []string) (Health, error)
 	// SetMinimumMasterNodes sets the transient and persistent setting of the same name in cluster settings.
 	SetMinimumMasterNodes(ctx context.Context, n int) error
 	// ReloadSecureSettings will decrypt and re-read the entire keystore, on every cluster node,
-	// but only the reloadable secure settings will be applied
-	ReloadSecureSettings(ctx context.Context) error
+	// but only the reloadable secure settings will be applied. The response reports the result on each node.
+	ReloadSecureSettings(ctx context.Context) (ReloadSecureSettingsResponse, error)
 	// GetNodes calls the _nodes api to return a map(nodeName -> Node)
 	GetNodes(ctx context.Context) (Nodes, error)
 	// GetNodesStats calls the _nodes/stats api to return a map(nodeName -> NodeStats)
@@ -169,6 +185,13 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("%s: %s", e.response.Status, reason)
 }
 
+// IsAPIError checks whether the error is a response from the Elasticsearch API, as opposed to a request that may not
+// have reached Elasticsearch.
+func IsAPIError(err error) bool {
+	_, ok := err.(*APIError)
+	return ok
+}
+
 // IsNotFound checks whether the error was an HTTP 404 error.
 func IsNotFound(err error) bool {
 	switch err := err.(type) {
@@ -179,6 +202,16 @@ func IsNotFound(err error) bool {
 	}
 }
 
+// IsUnauthorized checks whether the error was an HTTP 401 error.
+func IsUnauthorized(err error) bool {
+	switch err := err.(type) {
+	case *APIError:
+		return err.response.StatusCode == http.StatusUnauthorized
+	default:
+		return false
+	}
+}
+
 // IsConflict checks whether the error was an HTTP 409 error.
 func IsConflict(err error) bool {
 	switch err := err.(type) {
@@ -337,6 +337,26 @@ func TestClientGetNodesStats(t *testing.T) {
 	require.Equal(t, "3221225472", resp.Nodes["Rt-o5-ZBQaq-Nkhhy0p7JA"].OS.CGroup.Memory.LimitInBytes)
 }
 
+func TestClientReloadSecureSettings(t *testing.T) {
+	expectedPath := "/_nodes/reload_secure_settings"
+	testClient := NewMockClient(version.MustParse("6.8.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, expectedPath, req.URL.Path)
+		require.Equal(t, http.MethodPost, req.Method)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(fixtures.ReloadSecureSettingsSample)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	resp, err := testClient.ReloadSecureSettings(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(resp.Nodes))
+	require.Equal(t, "elasticsearch-sample-es-default-0", resp.Nodes["Rt-o5-ZBQaq-Nkhhy0p7JA"].Name)
+	require.Nil(t, resp.Nodes["Rt-o5-ZBQaq-Nkhhy0p7JA"].ReloadException)
+	require.Equal(t, "Keystore is missing", resp.Nodes["iXqjbgPYThO-6S7reL5_HA"].ReloadException.Reason)
+}
+
 func TestGetInfo(t *testing.T) {
 	expectedPath := "/"
 	testClient := NewMockClient(version.MustParse("6.4.1"), func(req *http.Request) *http.Response {
@@ -559,6 +579,23 @@ func TestClient_AddVotingConfigExclusions(t *testing.T) {
 	}
 }
 
+func TestClient_SnapshotPathEscaping(t *testing.T) {
+	var paths []string
+	client := NewMockClient(version.MustParse("7.4.0"), func(req *http.Request) *http.Response {
+		paths = append(paths, req.URL.EscapedPath())
+		return NewMockResponse(200, req, "{}")
+	})
+	ctx := context.Background()
+	require.NoError(t, client.CreateSnapshot(ctx, "my repo", "snap/1"))
+	require.NoError(t, client.DeleteSnapshot(ctx, "my repo", "snap/1"))
+	require.NoError(t, client.RestoreSnapshot(ctx, "my repo", "snap/1", RestoreRequest{}))
+	require.Equal(t, []string{
+		"/_snapshot/my%20repo/snap%2F1",
+		"/_snapshot/my%20repo/snap%2F1",
+		"/_snapshot/my%20repo/snap%2F1/_restore",
+	}, paths)
+}
+
 func TestClient_DeleteVotingConfigExclusions(t *testing.T) {
 	tests := []struct {
 		expectedPath string
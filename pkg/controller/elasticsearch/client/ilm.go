@@ -0,0 +1,40 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// IndexLifecycleClient captures Elasticsearch API calls around ILM policies and index templates.
+type IndexLifecycleClient interface {
+	// GetLifecyclePolicies returns all the ILM policies stored in the cluster.
+	GetLifecyclePolicies(ctx context.Context) (LifecyclePolicies, error)
+	// UpdateLifecyclePolicy creates or updates the ILM policy with the given name.
+	UpdateLifecyclePolicy(ctx context.Context, name string, policy LifecyclePolicy) error
+	// GetIndexTemplates returns all the index templates stored in the cluster.
+	GetIndexTemplates(ctx context.Context) (IndexTemplates, error)
+	// UpdateIndexTemplate creates or updates the index template with the given name.
+	UpdateIndexTemplate(ctx context.Context, name string, template IndexTemplate) error
+}
+
+func (c *clientV6) GetLifecyclePolicies(ctx context.Context) (LifecyclePolicies, error) {
+	var policies LifecyclePolicies
+	return policies, c.get(ctx, "/_ilm/policy", &policies)
+}
+
+func (c *clientV6) UpdateLifecyclePolicy(ctx context.Context, name string, policy LifecyclePolicy) error {
+	return c.put(ctx, fmt.Sprintf("/_ilm/policy/%s", name), LifecyclePolicyDefinition{Policy: policy}, nil)
+}
+
+func (c *clientV6) GetIndexTemplates(ctx context.Context) (IndexTemplates, error) {
+	var templates IndexTemplates
+	return templates, c.get(ctx, "/_template", &templates)
+}
+
+func (c *clientV6) UpdateIndexTemplate(ctx context.Context, name string, template IndexTemplate) error {
+	return c.put(ctx, fmt.Sprintf("/_template/%s", name), template, nil)
+}
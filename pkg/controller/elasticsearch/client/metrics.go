@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "elastic_elasticsearch_client_request_duration_seconds",
+			Help:    "Duration of the requests to the Elasticsearch API, per method and API.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+		},
+		[]string{"method", "api"},
+	)
+
+	requestErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "elastic_elasticsearch_client_request_errors_total",
+			Help: "Number of requests to the Elasticsearch API which failed or returned an error status code, per method and API.",
+		},
+		[]string{"method", "api"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestDuration, requestErrors)
+}
+
+// apiOf returns the Elasticsearch API targeted by the given request path, which is its first segment, so that the
+// names of the resources in the path do not end up in the metrics labels.
+func apiOf(path string) string {
+	api := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	if api == "" {
+		return "/"
+	}
+	return api
+}
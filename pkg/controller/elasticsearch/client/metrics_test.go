@@ -0,0 +1,50 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_apiOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "", want: "/"},
+		{path: "/", want: "/"},
+		{path: "/_license", want: "_license"},
+		{path: "/_cluster/health", want: "_cluster"},
+		{path: "/_snapshot/repository/snapshot/_restore", want: "_snapshot"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			require.Equal(t, tt.want, apiOf(tt.path))
+		})
+	}
+}
+
+func TestClient_RequestErrors(t *testing.T) {
+	errorsOf := func(method, api string) float64 {
+		return testutil.ToFloat64(requestErrors.WithLabelValues(method, api))
+	}
+	infoErrors := errorsOf(http.MethodGet, "/")
+	settingsErrors := errorsOf(http.MethodPut, "_cluster")
+
+	testClient := NewMockClient(version.MustParse("6.8.0"), errorResponses([]int{500}))
+	_, err := testClient.GetClusterInfo(context.Background())
+	require.Error(t, err)
+	require.Equal(t, infoErrors+1, errorsOf(http.MethodGet, "/"))
+
+	testClient = NewMockClient(version.MustParse("6.8.0"), errorResponses([]int{200}))
+	require.NoError(t, testClient.SetMinimumMasterNodes(context.Background(), 1))
+	require.Equal(t, settingsErrors, errorsOf(http.MethodPut, "_cluster"))
+}
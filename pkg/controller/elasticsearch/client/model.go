@@ -75,6 +75,24 @@ type Node struct {
 	} `json:"jvm"`
 }
 
+// ReloadSecureSettingsResponse partially models the response from a request to /_nodes/reload_secure_settings
+type ReloadSecureSettingsResponse struct {
+	Nodes map[string]NodeReloadResult `json:"nodes"`
+}
+
+// NodeReloadResult is the result of the secure settings reload on a single node.
+type NodeReloadResult struct {
+	Name string `json:"name"`
+	// ReloadException is set if the node failed to reload its secure settings.
+	ReloadException *ReloadException `json:"reload_exception,omitempty"`
+}
+
+// ReloadException describes why a node failed to reload its secure settings.
+type ReloadException struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
 // NodesStats partially models the response from a request to /_nodes/stats
 type NodesStats struct {
 	Nodes map[string]NodeStats `json:"nodes"`
@@ -306,6 +324,13 @@ type RemoteCluster struct {
 	Seeds []string `json:"seeds"`
 }
 
+// AutoFollowPattern is a cross-cluster replication auto-follow pattern.
+type AutoFollowPattern struct {
+	RemoteCluster       string   `json:"remote_cluster"`
+	LeaderIndexPatterns []string `json:"leader_index_patterns"`
+	FollowIndexPattern  string   `json:"follow_index_pattern,omitempty"`
+}
+
 // Hit represents a single search hit.
 type Hit struct {
 	Index  string                 `json:"_index"`
@@ -328,3 +353,109 @@ type SearchResults struct {
 	Shards json.RawMessage            // model when needed
 	Aggs   map[string]json.RawMessage // model when needed
 }
+
+// SnapshotRepository models a snapshot repository, as registered through /_snapshot/<repository>.
+type SnapshotRepository struct {
+	Type     string                 `json:"type"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+// SnapshotRepositories is the response from /_snapshot: a map(repositoryName -> SnapshotRepository).
+type SnapshotRepositories map[string]SnapshotRepository
+
+type SnapshotState string
+
+// These are possible snapshot states
+const (
+	SnapshotInProgress SnapshotState = "IN_PROGRESS"
+	SnapshotSuccess    SnapshotState = "SUCCESS"
+	SnapshotFailed     SnapshotState = "FAILED"
+	SnapshotPartial    SnapshotState = "PARTIAL"
+)
+
+// Snapshot partially models an Elasticsearch snapshot retrieved from /_snapshot/<repository>/_all
+type Snapshot struct {
+	Snapshot          string        `json:"snapshot"`
+	UUID              string        `json:"uuid"`
+	State             SnapshotState `json:"state"`
+	Reason            string        `json:"reason,omitempty"`
+	StartTimeInMillis int64         `json:"start_time_in_millis"`
+	EndTimeInMillis   int64         `json:"end_time_in_millis"`
+}
+
+// StartTime is the time at which the snapshot started.
+func (s Snapshot) StartTime() time.Time {
+	return time.Unix(0, s.StartTimeInMillis*int64(time.Millisecond))
+}
+
+// IsInProgress is true if the snapshot is still running.
+func (s Snapshot) IsInProgress() bool {
+	return s.State == SnapshotInProgress
+}
+
+// IsSuccess is true if all shards of the snapshot were stored successfully.
+func (s Snapshot) IsSuccess() bool {
+	return s.State == SnapshotSuccess
+}
+
+// Snapshots is the response from /_snapshot/<repository>/_all
+type Snapshots struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// RestoreRequest is the request to restore a snapshot.
+type RestoreRequest struct {
+	// Indices is a comma-separated list of indices to restore, all indices are restored if empty.
+	Indices            string `json:"indices,omitempty"`
+	IncludeGlobalState bool   `json:"include_global_state"`
+}
+
+// LifecyclePolicy models the phases of an ILM policy.
+type LifecyclePolicy struct {
+	Phases map[string]interface{} `json:"phases"`
+}
+
+// LifecyclePolicyDefinition wraps a LifecyclePolicy, as stored through /_ilm/policy/<policy>.
+type LifecyclePolicyDefinition struct {
+	Policy LifecyclePolicy `json:"policy"`
+}
+
+// LifecyclePolicies is the response from /_ilm/policy: a map(policyName -> LifecyclePolicyDefinition).
+type LifecyclePolicies map[string]LifecyclePolicyDefinition
+
+// IndexTemplate models an index template, as stored through /_template/<template>.
+type IndexTemplate struct {
+	IndexPatterns []string               `json:"index_patterns"`
+	Order         int32                  `json:"order"`
+	Settings      map[string]interface{} `json:"settings"`
+	Mappings      map[string]interface{} `json:"mappings"`
+	Aliases       map[string]interface{} `json:"aliases"`
+}
+
+// IndexTemplates is the response from /_template: a map(templateName -> IndexTemplate).
+type IndexTemplates map[string]IndexTemplate
+
+// RoleMapping models a role mapping, as stored through /_security/role_mapping/<name>.
+type RoleMapping struct {
+	Roles    []string               `json:"roles"`
+	Enabled  bool                   `json:"enabled"`
+	Rules    map[string]interface{} `json:"rules"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// RoleMappings is the response from /_security/role_mapping: a map(roleMappingName -> RoleMapping).
+type RoleMappings map[string]RoleMapping
+
+// User models a user of the native realm, as stored through /_security/user/<username>.
+// The password is only set in requests.
+type User struct {
+	Password string                 `json:"password,omitempty"`
+	Roles    []string               `json:"roles"`
+	FullName string                 `json:"full_name,omitempty"`
+	Email    string                 `json:"email,omitempty"`
+	Metadata map[string]interface{} `json:"metadata"`
+	Enabled  bool                   `json:"enabled"`
+}
+
+// Users is the response from /_security/user: a map(username -> User).
+type Users map[string]User
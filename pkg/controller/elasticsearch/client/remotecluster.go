@@ -0,0 +1,39 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// RemoteClusterClient captures Elasticsearch API calls around remote clusters and cross-cluster replication.
+type RemoteClusterClient interface {
+	// GetClusterSettings returns the persistent and transient cluster settings, including the remote clusters.
+	GetClusterSettings(ctx context.Context) (Settings, error)
+	// UpdateSettings updates the given persistent or transient cluster settings, eg. the remote clusters.
+	UpdateSettings(ctx context.Context, settings Settings) error
+	// UpdateAutoFollowPattern creates or updates the cross-cluster replication auto-follow pattern with the given name.
+	UpdateAutoFollowPattern(ctx context.Context, name string, pattern AutoFollowPattern) error
+	// DeleteAutoFollowPattern deletes the cross-cluster replication auto-follow pattern with the given name.
+	DeleteAutoFollowPattern(ctx context.Context, name string) error
+}
+
+func (c *clientV6) GetClusterSettings(ctx context.Context) (Settings, error) {
+	var settings Settings
+	return settings, c.get(ctx, "/_cluster/settings", &settings)
+}
+
+func (c *clientV6) UpdateSettings(ctx context.Context, settings Settings) error {
+	return c.put(ctx, "/_cluster/settings", &settings, nil)
+}
+
+func (c *clientV6) UpdateAutoFollowPattern(ctx context.Context, name string, pattern AutoFollowPattern) error {
+	return c.put(ctx, fmt.Sprintf("/_ccr/auto_follow/%s", name), pattern, nil)
+}
+
+func (c *clientV6) DeleteAutoFollowPattern(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("/_ccr/auto_follow/%s", name), nil, nil)
+}
@@ -0,0 +1,40 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// SecurityClient captures Elasticsearch API calls around role mappings and native realm users.
+type SecurityClient interface {
+	// GetRoleMappings returns all the role mappings stored in the cluster.
+	GetRoleMappings(ctx context.Context) (RoleMappings, error)
+	// UpdateRoleMapping creates or updates the role mapping with the given name.
+	UpdateRoleMapping(ctx context.Context, name string, mapping RoleMapping) error
+	// GetUsers returns all the users of the native realm, including reserved users.
+	GetUsers(ctx context.Context) (Users, error)
+	// UpdateUser creates or updates the native realm user with the given username.
+	UpdateUser(ctx context.Context, username string, user User) error
+}
+
+func (c *clientV6) GetRoleMappings(ctx context.Context) (RoleMappings, error) {
+	var mappings RoleMappings
+	return mappings, c.get(ctx, "/_security/role_mapping", &mappings)
+}
+
+func (c *clientV6) UpdateRoleMapping(ctx context.Context, name string, mapping RoleMapping) error {
+	return c.put(ctx, fmt.Sprintf("/_security/role_mapping/%s", name), mapping, nil)
+}
+
+func (c *clientV6) GetUsers(ctx context.Context) (Users, error) {
+	var users Users
+	return users, c.get(ctx, "/_security/user", &users)
+}
+
+func (c *clientV6) UpdateUser(ctx context.Context, username string, user User) error {
+	return c.put(ctx, fmt.Sprintf("/_security/user/%s", username), user, nil)
+}
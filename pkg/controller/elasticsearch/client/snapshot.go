@@ -0,0 +1,53 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// SnapshotClient captures Elasticsearch API calls around snapshot repositories and snapshots.
+type SnapshotClient interface {
+	// GetSnapshotRepositories returns all the snapshot repositories registered in the cluster.
+	GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error)
+	// UpdateSnapshotRepository registers or updates the snapshot repository with the given name.
+	UpdateSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository) error
+	// GetSnapshots returns all the snapshots stored in the given repository.
+	GetSnapshots(ctx context.Context, repository string) (Snapshots, error)
+	// CreateSnapshot starts a snapshot of all indices in the given repository, without waiting for its completion.
+	CreateSnapshot(ctx context.Context, repository string, snapshot string) error
+	// DeleteSnapshot deletes a snapshot from the given repository.
+	DeleteSnapshot(ctx context.Context, repository string, snapshot string) error
+	// RestoreSnapshot starts restoring a snapshot from the given repository, without waiting for its completion.
+	RestoreSnapshot(ctx context.Context, repository string, snapshot string, request RestoreRequest) error
+}
+
+func (c *clientV6) GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error) {
+	var repositories SnapshotRepositories
+	return repositories, c.get(ctx, "/_snapshot", &repositories)
+}
+
+func (c *clientV6) UpdateSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository) error {
+	return c.put(ctx, fmt.Sprintf("/_snapshot/%s", url.PathEscape(name)), repository, nil)
+}
+
+func (c *clientV6) GetSnapshots(ctx context.Context, repository string) (Snapshots, error) {
+	var snapshots Snapshots
+	return snapshots, c.get(ctx, fmt.Sprintf("/_snapshot/%s/_all", url.PathEscape(repository)), &snapshots)
+}
+
+func (c *clientV6) CreateSnapshot(ctx context.Context, repository string, snapshot string) error {
+	return c.put(ctx, fmt.Sprintf("/_snapshot/%s/%s?wait_for_completion=false", url.PathEscape(repository), url.PathEscape(snapshot)), nil, nil)
+}
+
+func (c *clientV6) DeleteSnapshot(ctx context.Context, repository string, snapshot string) error {
+	return c.delete(ctx, fmt.Sprintf("/_snapshot/%s/%s", url.PathEscape(repository), url.PathEscape(snapshot)), nil, nil)
+}
+
+func (c *clientV6) RestoreSnapshot(ctx context.Context, repository string, snapshot string, request RestoreRequest) error {
+	return c.post(ctx, fmt.Sprintf("/_snapshot/%s/%s/_restore", url.PathEscape(repository), url.PathEscape(snapshot)), request, nil)
+}
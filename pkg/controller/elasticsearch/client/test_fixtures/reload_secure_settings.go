@@ -0,0 +1,30 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fixtures
+
+const (
+	ReloadSecureSettingsSample = `
+{
+  "_nodes" : {
+    "total" : 2,
+    "successful" : 2,
+    "failed" : 0
+  },
+  "cluster_name" : "elasticsearch-sample",
+  "nodes" : {
+    "Rt-o5-ZBQaq-Nkhhy0p7JA" : {
+      "name" : "elasticsearch-sample-es-default-0"
+    },
+    "iXqjbgPYThO-6S7reL5_HA" : {
+      "name" : "elasticsearch-sample-es-default-1",
+      "reload_exception" : {
+        "type" : "illegal_state_exception",
+        "reason" : "Keystore is missing"
+      }
+    }
+  }
+}
+`
+)
@@ -8,6 +8,7 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
 	"github.com/pkg/errors"
@@ -60,6 +61,11 @@ func (c *clientV6) GetClusterHealth(ctx context.Context) (Health, error) {
 	return result, c.get(ctx, "/_cluster/health", &result)
 }
 
+func (c *clientV6) GetIndicesHealth(ctx context.Context, indices []string) (Health, error) {
+	var result Health
+	return result, c.get(ctx, "/_cluster/health/"+strings.Join(indices, ","), &result)
+}
+
 func (c *clientV6) SetMinimumMasterNodes(ctx context.Context, n int) error {
 	zenSettings := DiscoveryZenSettings{
 		Transient:  DiscoveryZen{MinimumMasterNodes: n},
@@ -68,8 +74,9 @@ func (c *clientV6) SetMinimumMasterNodes(ctx context.Context, n int) error {
 	return c.put(ctx, "/_cluster/settings", &zenSettings, nil)
 }
 
-func (c *clientV6) ReloadSecureSettings(ctx context.Context) error {
-	return c.post(ctx, "/_nodes/reload_secure_settings", nil, nil)
+func (c *clientV6) ReloadSecureSettings(ctx context.Context) (ReloadSecureSettingsResponse, error) {
+	var response ReloadSecureSettingsResponse
+	return response, c.post(ctx, "/_nodes/reload_secure_settings", nil, &response)
 }
 
 func (c *clientV6) GetNodes(ctx context.Context) (Nodes, error) {
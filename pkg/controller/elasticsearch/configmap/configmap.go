@@ -10,6 +10,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/securesettings"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,18 +31,23 @@ func NewConfigMapWithData(es types.NamespacedName, data map[string]string) corev
 }
 
 // ReconcileScriptsConfigMap reconciles a configmap containing scripts used by
-// init containers and readiness probe.
+// init containers, readiness probe and keystore updates.
 func ReconcileScriptsConfigMap(c k8s.Client, scheme *runtime.Scheme, es v1beta1.Elasticsearch) error {
 	fsScript, err := initcontainer.RenderPrepareFsScript()
 	if err != nil {
 		return err
 	}
+	updateKeystoreScript, err := securesettings.RenderUpdateKeystoreScript()
+	if err != nil {
+		return err
+	}
 
 	scriptsConfigMap := NewConfigMapWithData(
 		types.NamespacedName{Namespace: es.Namespace, Name: name.ScriptsConfigMap(es.Name)},
 		map[string]string{
-			nodespec.ReadinessProbeScriptConfigKey: nodespec.ReadinessProbeScript,
-			initcontainer.PrepareFsScriptConfigKey: fsScript,
+			nodespec.ReadinessProbeScriptConfigKey:       nodespec.ReadinessProbeScript,
+			initcontainer.PrepareFsScriptConfigKey:       fsScript,
+			securesettings.UpdateKeystoreScriptConfigKey: updateKeystoreScript,
 		},
 	)
 
@@ -0,0 +1,47 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DelayedRestarts keeps track of the predicate which delayed the restart of each Pod during the last rolling upgrade
+// attempt, so that an event is only emitted the first time a predicate delays the restart of a Pod, and not on
+// every reconciliation.
+// Note: DelayedRestarts is NOT thread-safe.
+type DelayedRestarts struct {
+	// Cluster -> Pod -> predicate
+	delayed map[types.NamespacedName]map[string]string
+}
+
+func NewDelayedRestarts() *DelayedRestarts {
+	return &DelayedRestarts{
+		delayed: make(map[types.NamespacedName]map[string]string),
+	}
+}
+
+// Update replaces the delayed restarts of the given cluster, and returns the ones which were not already delayed by
+// the same predicate during the previous rolling upgrade attempt. A nil DelayedRestarts returns all of them.
+func (d *DelayedRestarts) Update(cluster types.NamespacedName, delayed []delayedRestart) []delayedRestart {
+	if d == nil {
+		return delayed
+	}
+	previous := d.delayed[cluster]
+	current := make(map[string]string, len(delayed))
+	var added []delayedRestart
+	for _, restart := range delayed {
+		current[restart.pod] = restart.predicate
+		if previous[restart.pod] != restart.predicate {
+			added = append(added, restart)
+		}
+	}
+	if len(current) == 0 {
+		delete(d.delayed, cluster)
+	} else {
+		d.delayed[cluster] = current
+	}
+	return added
+}
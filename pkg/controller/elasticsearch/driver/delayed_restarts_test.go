@@ -0,0 +1,36 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDelayedRestarts_Update(t *testing.T) {
+	cluster := types.NamespacedName{Namespace: "ns", Name: "es"}
+	other := types.NamespacedName{Namespace: "ns", Name: "other"}
+	d := NewDelayedRestarts()
+
+	// first delays are all reported
+	delayed := []delayedRestart{{pod: "es-0", predicate: "one_master_at_a_time"}, {pod: "es-1", predicate: "zones"}}
+	require.Equal(t, delayed, d.Update(cluster, delayed))
+	// the same delays are not reported again
+	require.Empty(t, d.Update(cluster, delayed))
+	// delays of other clusters are tracked separately
+	require.Equal(t, delayed[:1], d.Update(other, delayed[:1]))
+	// a delay by another predicate is reported
+	changed := []delayedRestart{{pod: "es-0", predicate: "logs_green"}, {pod: "es-1", predicate: "zones"}}
+	require.Equal(t, changed[:1], d.Update(cluster, changed))
+	// a Pod delayed again after being restarted is reported again
+	require.Empty(t, d.Update(cluster, nil))
+	require.Equal(t, changed, d.Update(cluster, changed))
+
+	// without tracking, all delays are reported
+	var untracked *DelayedRestarts
+	require.Equal(t, delayed, untracked.Update(cluster, delayed))
+}
@@ -11,6 +11,7 @@ import (
 	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/migration"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodespec"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
@@ -29,6 +30,7 @@ func HandleDownscale(
 	expectedStatefulSets sset.StatefulSetList,
 	actualStatefulSets sset.StatefulSetList,
 ) *reconciler.Results {
+	defer reconciler.ObserveDuration(v1beta1.Kind, "downscale")()
 	results := &reconciler.Results{}
 
 	// make sure we only downscale nodes we're allowed to
@@ -87,6 +89,13 @@ func calculateDownscales(state downscaleState, expectedStatefulSets sset.Statefu
 				toDelete = 1 // Only one removal allowed for masters and if it's not already at 0
 			}
 			toDelete = state.getMaxNodesToRemove(toDelete)
+			if state.zones != nil && toDelete > 0 {
+				toDelete = state.getMaxZoneNodesToRemove(actualSset, toDelete)
+				if toDelete == 0 {
+					// nodes to remove are not in the zone being drained
+					continue
+				}
+			}
 			downscales = append(downscales, ssetDownscale{
 				statefulSet:     actualSset,
 				initialReplicas: actualReplicas,
@@ -167,12 +176,29 @@ func calculatePerformableDownscale(
 		targetReplicas:  downscale.initialReplicas, // target set to initial
 		finalReplicas:   downscale.finalReplicas,
 	}
+	// when draining a zone, nodes whose data is replicated in other zones can be removed without waiting for migration
+	var shards esclient.Shards
+	var zones map[string]string
+	if ctx.es.Spec.IsZoneAware() {
+		if podZones, known := nodeattr.Zones(ctx.resourcesState.CurrentPods); known {
+			var err error
+			if shards, err = ctx.shardLister.GetShards(); err != nil {
+				return performableDownscale, err
+			}
+			zones = podZones
+		}
+	}
 	// iterate on all leaving nodes (ordered by highest ordinal first)
 	for _, node := range downscale.leavingNodeNames() {
 		migrating, err := migration.IsMigratingData(ctx.shardLister, node, allLeavingNodes)
 		if err != nil {
 			return performableDownscale, err
 		}
+		if migrating && zones != nil && migration.IsReplicatedInOtherZones(shards, []string{node}, zones) {
+			ssetLogger(downscale.statefulSet).Info("Data replicated in other zones, starting node deletion", "node", node)
+			performableDownscale.targetReplicas--
+			continue
+		}
 		if migrating {
 			ssetLogger(downscale.statefulSet).V(1).Info("Data migration not over yet, skipping node deletion", "node", node)
 			ctx.reconcileState.UpdateElasticsearchMigrating(ctx.resourcesState, ctx.observedState)
@@ -7,10 +7,13 @@ package driver
 import (
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/set"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 const (
@@ -48,6 +51,11 @@ type downscaleState struct {
 	removalsAllowed *int32
 	// masterRemovalInProgress indicates whether a master node is in the process of being removed already.
 	masterRemovalInProgress bool
+	// zones indexes the zone of each node by node name, if nodes can be removed a zone at a time.
+	// In this case removals are bounded by maxUnavailable, and limited to the nodes of the drainingZone.
+	zones map[string]string
+	// drainingZone is the zone of the nodes being removed, if any.
+	drainingZone string
 }
 
 // newDownscaleState creates a new downscaleState.
@@ -60,14 +68,44 @@ func newDownscaleState(c k8s.Client, es v1beta1.Elasticsearch) (*downscaleState,
 	mastersReady := reconcile.AvailableElasticsearchNodes(label.FilterMasterNodePods(actualPods))
 	nodesReady := reconcile.AvailableElasticsearchNodes(actualPods)
 
-	return &downscaleState{
+	state := &downscaleState{
 		masterRemovalInProgress: false,
 		runningMasters:          len(mastersReady),
 		removalsAllowed: calculateRemovalsAllowed(
 			int32(len(nodesReady)),
 			es.Spec.NodeCount(),
 			es.Spec.UpdateStrategy.ChangeBudget.GetMaxUnavailableOrDefault()),
-	}, nil
+	}
+	if es.Spec.IsZoneAware() {
+		state.setZones(actualPods, nodesReady)
+	}
+	return state, nil
+}
+
+// setZones enables zone at a time removals if the zone of all the nodes is known, and unavailable nodes
+// are all in the same zone, which is then the only one that can be drained.
+func (s *downscaleState) setZones(actualPods []corev1.Pod, nodesReady []corev1.Pod) {
+	zones, known := nodeattr.Zones(actualPods)
+	if !known {
+		return
+	}
+	ready := set.StringSet{}
+	for _, pod := range nodesReady {
+		ready.Add(pod.Name)
+	}
+	unavailableZones := set.StringSet{}
+	for _, pod := range actualPods {
+		if !ready.Has(pod.Name) {
+			unavailableZones.Add(zones[pod.Name])
+		}
+	}
+	if unavailableZones.Count() > 1 {
+		return
+	}
+	if unavailableZones.Count() == 1 {
+		s.drainingZone = unavailableZones.AsSlice()[0]
+	}
+	s.zones = zones
 }
 
 func calculateRemovalsAllowed(nodesReady, desiredNodes int32, maxUnavailable *int32) *int32 {
@@ -95,6 +133,26 @@ func (s *downscaleState) getMaxNodesToRemove(noMoreThan int32) int32 {
 	return noMoreThan
 }
 
+// getMaxZoneNodesToRemove returns how many nodes of the given StatefulSet can be removed, no more than the given
+// number, while draining a single zone. Nodes are removed starting with the highest ordinal.
+func (s *downscaleState) getMaxZoneNodesToRemove(statefulSet appsv1.StatefulSet, noMoreThan int32) int32 {
+	replicas := sset.GetReplicas(statefulSet)
+	toRemove := int32(0)
+	for ; toRemove < noMoreThan; toRemove++ {
+		zone, known := s.zones[sset.PodName(statefulSet.Name, replicas-1-toRemove)]
+		if !known {
+			break
+		}
+		if s.drainingZone == "" {
+			s.drainingZone = zone
+		}
+		if zone != s.drainingZone {
+			break
+		}
+	}
+	return toRemove
+}
+
 // recordRemoval updates the state to consider n-replica downscale of the given statefulSet.
 func (s *downscaleState) recordRemoval(statefulSet appsv1.StatefulSet, accountedRemovals int32) {
 	if accountedRemovals == 0 {
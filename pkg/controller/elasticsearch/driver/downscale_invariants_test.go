@@ -18,6 +18,8 @@ import (
 
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
 
@@ -277,3 +279,107 @@ func Test_downscaleState_recordRemoval(t *testing.T) {
 		})
 	}
 }
+
+func Test_downscaleState_setZones(t *testing.T) {
+	podInZone := func(name, zone string) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}}}
+		if zone != "" {
+			pod.Annotations[nodeattr.Annotation(nodeattr.ZoneAttribute)] = zone
+		}
+		return pod
+	}
+	tests := []struct {
+		name             string
+		pods             []corev1.Pod
+		ready            []string
+		wantZones        bool
+		wantDrainingZone string
+	}{
+		{
+			name:      "all pods ready",
+			pods:      []corev1.Pod{podInZone("a", "zone-a"), podInZone("b", "zone-b")},
+			ready:     []string{"a", "b"},
+			wantZones: true,
+		},
+		{
+			name:             "unavailable pod in a single zone",
+			pods:             []corev1.Pod{podInZone("a", "zone-a"), podInZone("b", "zone-b")},
+			ready:            []string{"a"},
+			wantZones:        true,
+			wantDrainingZone: "zone-b",
+		},
+		{
+			name:      "unavailable pods in several zones",
+			pods:      []corev1.Pod{podInZone("a", "zone-a"), podInZone("b", "zone-b"), podInZone("c", "zone-c")},
+			ready:     []string{"a"},
+			wantZones: false,
+		},
+		{
+			name:      "unknown zone",
+			pods:      []corev1.Pod{podInZone("a", "zone-a"), podInZone("b", "")},
+			ready:     []string{"a", "b"},
+			wantZones: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ready []corev1.Pod
+			for _, pod := range tt.pods {
+				for _, name := range tt.ready {
+					if pod.Name == name {
+						ready = append(ready, pod)
+					}
+				}
+			}
+			state := &downscaleState{removalsAllowed: common.Int32(1)}
+			state.setZones(tt.pods, ready)
+			require.Equal(t, tt.wantZones, state.zones != nil)
+			require.Equal(t, tt.wantDrainingZone, state.drainingZone)
+			// maxUnavailable still applies when draining a zone
+			require.Equal(t, common.Int32(1), state.removalsAllowed)
+		})
+	}
+}
+
+func Test_downscaleState_getMaxZoneNodesToRemove(t *testing.T) {
+	zones := map[string]string{
+		sset.PodName(ssetData4Replicas.Name, 0): "zone-a",
+		sset.PodName(ssetData4Replicas.Name, 1): "zone-b",
+		sset.PodName(ssetData4Replicas.Name, 2): "zone-a",
+		sset.PodName(ssetData4Replicas.Name, 3): "zone-a",
+	}
+	tests := []struct {
+		name             string
+		drainingZone     string
+		noMoreThan       int32
+		want             int32
+		wantDrainingZone string
+	}{
+		{
+			name:             "remove nodes of the zone of the highest ordinal",
+			noMoreThan:       4,
+			want:             2,
+			wantDrainingZone: "zone-a",
+		},
+		{
+			name:             "no more than the requested removals",
+			noMoreThan:       1,
+			want:             1,
+			wantDrainingZone: "zone-a",
+		},
+		{
+			name:             "highest ordinal in another zone",
+			drainingZone:     "zone-b",
+			noMoreThan:       4,
+			want:             0,
+			wantDrainingZone: "zone-b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &downscaleState{zones: zones, drainingZone: tt.drainingZone}
+			require.Equal(t, tt.want, state.getMaxZoneNodesToRemove(ssetData4Replicas, tt.noMoreThan))
+			require.Equal(t, tt.wantDrainingZone, state.drainingZone)
+		})
+	}
+}
@@ -447,6 +447,40 @@ func Test_calculateDownscales(t *testing.T) {
 	}
 }
 
+func Test_calculateDownscales_zones(t *testing.T) {
+	zones := map[string]string{
+		sset.PodName(ssetData4Replicas.Name, 0): "zone-b",
+		sset.PodName(ssetData4Replicas.Name, 1): "zone-a",
+		sset.PodName(ssetData4Replicas.Name, 2): "zone-a",
+		sset.PodName(ssetData4Replicas.Name, 3): "zone-a",
+	}
+	expected := sset.StatefulSetList{*ssetData4Replicas.DeepCopy()}
+	expected[0].Spec.Replicas = common.Int32(0)
+	tests := []struct {
+		name            string
+		removalsAllowed *int32
+		wantTarget      int32
+	}{
+		{
+			name:       "remove the nodes of the zone",
+			wantTarget: 1,
+		},
+		{
+			name:            "remove the nodes of the zone within maxUnavailable",
+			removalsAllowed: common.Int32(2),
+			wantTarget:      2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := downscaleState{zones: zones, removalsAllowed: tt.removalsAllowed}
+			got := calculateDownscales(state, expected, sset.StatefulSetList{ssetData4Replicas})
+			require.Len(t, got, 1)
+			require.Equal(t, tt.wantTarget, got[0].targetReplicas)
+		})
+	}
+}
+
 func Test_calculatePerformableDownscale(t *testing.T) {
 	type args struct {
 		ctx             downscaleContext
@@ -5,8 +5,11 @@
 package driver
 
 import (
+	"context"
 	"crypto/x509"
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
@@ -20,17 +23,23 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates/transport"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/cleanup"
 	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/configmap"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/ilm"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/initcontainer"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/license"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/observer"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/remotecluster"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/securesettings"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/snapshot"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	esversion "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/version"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
@@ -44,6 +53,9 @@ var (
 	defaultRequeue = controller.Result{Requeue: true, RequeueAfter: 10 * time.Second}
 )
 
+// controllerUserCheckTimeout is the timeout of the request checking whether rotated credentials are accepted.
+const controllerUserCheckTimeout = 10 * time.Second
+
 // Driver orchestrates the reconciliation of an Elasticsearch resource.
 // Its lifecycle is bound to a single reconciliation attempt.
 type Driver interface {
@@ -72,6 +84,8 @@ type DefaultDriverParameters struct {
 	Client   k8s.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	// PodExecutor runs commands in the Elasticsearch Pods.
+	PodExecutor k8s.PodExecutor
 
 	// State holds the accumulated state during the reconcile loop
 	ReconcileState *reconcile.State
@@ -82,6 +96,8 @@ type DefaultDriverParameters struct {
 	// Expectations control some expectations set on resources in the cache, in order to
 	// avoid doing certain operations if the cache hasn't seen an up-to-date resource yet.
 	Expectations *expectations.Expectations
+	// DelayedRestarts keep track of the Pod restarts delayed by the rolling upgrade predicates.
+	DelayedRestarts *DelayedRestarts
 }
 
 // defaultDriver is the default Driver implementation
@@ -125,21 +141,38 @@ func (d *defaultDriver) Reconcile() *reconciler.Results {
 		return results.WithError(err)
 	}
 
+	if _, err := common.ReconcileService(d.Client, d.Scheme(), services.NewTransportService(d.ES), &d.ES); err != nil {
+		return results.WithError(err)
+	}
+
+	if err := transport.WatchTransportSecrets(d.DynamicWatches(), d.ES); err != nil {
+		return results.WithError(err)
+	}
 	certificateResources, res := certificates.Reconcile(
 		d,
 		d.ES,
 		[]corev1.Service{*externalService},
 		d.OperatorParameters.CACertRotation,
 		d.OperatorParameters.CertRotation,
+		d.OperatorParameters.KeyParams,
 	)
 	if results.WithResults(res).HasError() {
 		return results
 	}
+	d.reportInvalidTransportCAs(certificateResources.InvalidTransportCAs)
+	d.ReconcileState.UpdateCertificatesStatus(&certificateResources.Certificates)
 
-	internalUsers, err := user.ReconcileUsers(d.Client, d.Scheme(), d.ES)
+	if err := user.WatchUserSecrets(d.DynamicWatches(), d.ES); err != nil {
+		return results.WithError(err)
+	}
+	internalUsers, err := user.ReconcileUsers(d.Client, d.Scheme(), d.ES, d.ReconcileState.Recorder)
 	if err != nil {
 		return results.WithError(err)
 	}
+	if internalUsers.PreviousCredentialsRetained {
+		// come back to delete the previous credentials once the rotation grace period is over
+		results.WithResult(controller.Result{RequeueAfter: user.CredentialsRotationGracePeriod})
+	}
 
 	resourcesState, err := reconcile.NewResourcesStateFromAPI(d.Client, d.ES)
 	if err != nil {
@@ -155,11 +188,13 @@ func (d *defaultDriver) Reconcile() *reconciler.Results {
 
 	warnUnsupportedDistro(resourcesState.AllPods, d.ReconcileState.Recorder)
 
+	controllerUser := d.controllerUser(resourcesState, *internalUsers, *min, certificateResources.TrustedHTTPCertificates)
+
 	observedState := d.Observers.ObservedStateResolver(
 		k8s.ExtractNamespacedName(&d.ES),
 		d.newElasticsearchClient(
 			resourcesState,
-			internalUsers.ControllerUser,
+			controllerUser,
 			*min,
 			certificateResources.TrustedHTTPCertificates,
 		))
@@ -176,7 +211,7 @@ func (d *defaultDriver) Reconcile() *reconciler.Results {
 	// TODO: support user-supplied certificate (non-ca)
 	esClient := d.newElasticsearchClient(
 		resourcesState,
-		internalUsers.ControllerUser,
+		controllerUser,
 		*min,
 		certificateResources.TrustedHTTPCertificates,
 	)
@@ -208,11 +243,179 @@ func (d *defaultDriver) Reconcile() *reconciler.Results {
 		},
 	)
 
+	// the snapshot restore runs before any other change made through the Elasticsearch API, which waits for the
+	// restore to complete not to conflict with the restored content
+	restoreState, err := snapshot.ReconcileRestore(d.Client, esClient, &d.ES, AnnotatedForBootstrap(d.ES), esReachable)
+	if err != nil {
+		d.ReconcileState.AddEvent(
+			corev1.EventTypeWarning,
+			events.EventReasonUnexpected,
+			fmt.Sprintf("Could not restore snapshot: %s", err.Error()),
+		)
+		if !AnnotatedForBootstrap(d.ES) {
+			// do not create the nodes until the restore is recorded, it would be ignored once the cluster is bootstrapped
+			return results.WithError(err)
+		}
+		results.WithError(err)
+	}
+	switch {
+	case restoreState == snapshot.RestoreStarted:
+		d.ReconcileState.AddEvent(
+			corev1.EventTypeNormal,
+			events.EventReasonStateChange,
+			fmt.Sprintf("Restoring snapshot %s", d.ES.Spec.Restore.Snapshot),
+		)
+	case restoreState == snapshot.RestoreIgnored:
+		d.ReconcileState.AddEvent(
+			corev1.EventTypeWarning,
+			events.EventReasonValidation,
+			fmt.Sprintf("Ignoring the restore of snapshot %s: a snapshot can only be restored on cluster creation", d.ES.Spec.Restore.Snapshot),
+		)
+	}
+	if restoreState.IsPending() {
+		results.WithResult(defaultRequeue)
+	}
+
+	results.Apply(
+		"reconcile-snapshots",
+		func() (controller.Result, error) {
+			if !esReachable || restoreState.IsPending() {
+				// repositories and snapshots are managed through the Elasticsearch API
+				return controller.Result{}, nil
+			}
+			status, res, err := snapshot.Reconcile(esClient, d.ES, time.Now())
+			if err != nil {
+				d.ReconcileState.AddEvent(
+					corev1.EventTypeWarning,
+					events.EventReasonUnexpected,
+					fmt.Sprintf("Could not reconcile snapshots: %s", err.Error()),
+				)
+				return defaultRequeue, err
+			}
+			d.ReconcileState.UpdateSnapshotStatus(status)
+			return res, nil
+		},
+	)
+
+	results.Apply(
+		"reconcile-index-lifecycle",
+		func() (controller.Result, error) {
+			if !esReachable || restoreState.IsPending() {
+				// policies and templates are managed through the Elasticsearch API
+				return controller.Result{}, nil
+			}
+			status, err := ilm.Reconcile(esClient, d.ES, d.ReconcileState.Recorder)
+			if err != nil {
+				d.ReconcileState.AddEvent(
+					corev1.EventTypeWarning,
+					events.EventReasonUnexpected,
+					fmt.Sprintf("Could not reconcile ILM policies and index templates: %s", err.Error()),
+				)
+				return defaultRequeue, err
+			}
+			d.ReconcileState.UpdateIndexLifecycleStatus(status)
+			return controller.Result{}, nil
+		},
+	)
+
+	results.Apply(
+		"reconcile-security",
+		func() (controller.Result, error) {
+			if !esReachable || restoreState.IsPending() {
+				// role mappings and native users are managed through the Elasticsearch API
+				return controller.Result{}, nil
+			}
+			status, err := user.ReconcileSecurity(d.Client, esClient, d.ES)
+			if err != nil {
+				d.ReconcileState.AddEvent(
+					corev1.EventTypeWarning,
+					events.EventReasonUnexpected,
+					fmt.Sprintf("Could not reconcile role mappings and native users: %s", err.Error()),
+				)
+				return defaultRequeue, err
+			}
+			d.ReconcileState.UpdateSecurityStatus(status)
+			return controller.Result{}, nil
+		},
+	)
+
+	results.Apply(
+		"reconcile-remote-clusters",
+		func() (controller.Result, error) {
+			if !esReachable || restoreState.IsPending() {
+				// remote clusters are configured through the Elasticsearch API
+				return controller.Result{}, nil
+			}
+			status, err := remotecluster.Reconcile(esClient, d.ES)
+			if err != nil {
+				d.ReconcileState.AddEvent(
+					corev1.EventTypeWarning,
+					events.EventReasonUnexpected,
+					fmt.Sprintf("Could not reconcile remote clusters: %s", err.Error()),
+				)
+				return defaultRequeue, err
+			}
+			d.ReconcileState.UpdateRemoteClustersStatus(status)
+			return controller.Result{}, nil
+		},
+	)
+
+	results.Apply(
+		"check-http-certificate",
+		func() (controller.Result, error) {
+			if !esReachable {
+				return controller.Result{}, nil
+			}
+			status, upToDate, err := certificates.ServedHTTPCertificate(
+				d.ES, resourcesState.CurrentPods, certificateResources.TrustedHTTPCertificates,
+			)
+			if err != nil {
+				d.ReconcileState.AddEvent(
+					corev1.EventTypeWarning,
+					events.EventReasonUnexpected,
+					fmt.Sprintf("Could not check the served HTTP certificate: %s", err.Error()),
+				)
+				return defaultRequeue, err
+			}
+			d.ReconcileState.UpdateHTTPStatus(status)
+			if !upToDate {
+				// the nodes reload the certificate once the updated secret is propagated to the pods
+				log.Info("Waiting for the nodes to serve the updated HTTP certificate",
+					"namespace", d.ES.Namespace, "es_name", d.ES.Name)
+				return defaultRequeue, nil
+			}
+			return controller.Result{}, nil
+		},
+	)
+
 	// Compute seed hosts based on current masters with a podIP
 	if err := settings.UpdateSeedHostsConfigMap(d.Client, d.Scheme(), d.ES, resourcesState.AllPods); err != nil {
 		return results.WithError(err)
 	}
 
+	results.Apply(
+		"annotate-pods-node-attributes",
+		func() (controller.Result, error) {
+			// Pods are waiting for the node attributes annotations to start Elasticsearch
+			missingLabels, err := nodeattr.AnnotateClusterPods(d.Client, d.ES, resourcesState.AllPods)
+			if err != nil {
+				return defaultRequeue, err
+			}
+			for _, missing := range missingLabels {
+				d.ReconcileState.AddEvent(
+					corev1.EventTypeWarning,
+					events.EventReasonUnexpected,
+					fmt.Sprintf("Cannot set node attributes of pod %s: node %s has no label %s", missing.Pod, missing.Node, missing.Label),
+				)
+			}
+			if len(missingLabels) > 0 {
+				// node labels are not watched
+				return defaultRequeue, nil
+			}
+			return controller.Result{}, nil
+		},
+	)
+
 	// setup a keystore with secure settings in an init container, if specified by the user
 	keystoreResources, err := keystore.NewResources(
 		d,
@@ -238,6 +441,34 @@ func (d *defaultDriver) Reconcile() *reconciler.Results {
 
 	d.ReconcileState.UpdateElasticsearchState(*resourcesState, observedState)
 
+	results.Apply(
+		"reload-secure-settings",
+		func() (controller.Result, error) {
+			if !esReachable || keystoreResources == nil {
+				return controller.Result{}, nil
+			}
+			reloaded, err := securesettings.Reload(d.Client, d.PodExecutor, esClient, keystoreResources.Settings, resourcesState.CurrentPods)
+			if err != nil {
+				d.ReconcileState.AddEvent(
+					corev1.EventTypeWarning,
+					events.EventReasonUnexpected,
+					fmt.Sprintf("Could not reload secure settings: %s", err.Error()),
+				)
+				return defaultRequeue, err
+			}
+			if !reloaded {
+				// the updated secure settings are eventually propagated to the pods
+				return defaultRequeue, nil
+			}
+			return controller.Result{}, nil
+		},
+	)
+
+	if restoreState.IsInProgress() {
+		// set last not to be overridden by the phase set during the nodes reconciliation
+		d.ReconcileState.UpdateElasticsearchRestoringSnapshot()
+	}
+
 	return results
 }
 
@@ -252,6 +483,35 @@ func (d *defaultDriver) newElasticsearchClient(
 	return esclient.NewElasticsearchClient(d.OperatorParameters.Dialer, url, user.Auth(), v, caCerts)
 }
 
+// controllerUser returns the user the operator authenticates with. During the grace period following a credentials
+// rotation, the previous controller user is used until Elasticsearch accepts the new password, which happens once
+// the nodes reload the file realm. The new password is not checked anymore once accepted.
+func (d *defaultDriver) controllerUser(
+	state *reconcile.ResourcesState,
+	internalUsers user.InternalUsers,
+	v version.Version,
+	caCerts []*x509.Certificate,
+) user.User {
+	if internalUsers.PreviousControllerUser == nil {
+		return internalUsers.ControllerUser
+	}
+	esClient := d.newElasticsearchClient(state, internalUsers.ControllerUser, v, caCerts)
+	defer esClient.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), controllerUserCheckTimeout)
+	defer cancel()
+	_, err := esClient.GetClusterInfo(ctx)
+	switch {
+	case esclient.IsUnauthorized(err):
+		log.V(1).Info("Rotated password not accepted yet, using the previous one", "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		return *internalUsers.PreviousControllerUser
+	case err == nil:
+		if err := user.MarkNewPasswordAccepted(d.K8sClient(), d.ES); err != nil {
+			log.Error(err, "Failed to record the rotated password as accepted", "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		}
+	}
+	return internalUsers.ControllerUser
+}
+
 // warnUnsupportedDistro sends an event of type warning if the Elasticsearch Docker image is not a supported
 // distribution by looking at if the prepare fs init container terminated with the UnsupportedDistro exit code.
 func warnUnsupportedDistro(pods []corev1.Pod, recorder *events.Recorder) {
@@ -266,3 +526,24 @@ func warnUnsupportedDistro(pods []corev1.Pod, recorder *events.Recorder) {
 		}
 	}
 }
+
+// reportInvalidTransportCAs reports the additional transport CAs that are not trusted in the resource status,
+// and in an event if they changed since the last reconciliation.
+func (d *defaultDriver) reportInvalidTransportCAs(invalid []string) {
+	if len(invalid) == 0 {
+		d.ReconcileState.UpdateTransportStatus(nil)
+		return
+	}
+	var previous []string
+	if d.ES.Status.Transport != nil {
+		previous = d.ES.Status.Transport.InvalidCertificateAuthorities
+	}
+	if !reflect.DeepEqual(previous, invalid) {
+		d.ReconcileState.AddEvent(
+			corev1.EventTypeWarning,
+			events.EventReasonUnexpected,
+			fmt.Sprintf("Ignoring invalid transport certificate authorities: %s", strings.Join(invalid, ", ")),
+		)
+	}
+	d.ReconcileState.UpdateTransportStatus(&v1beta1.TransportStatus{InvalidCertificateAuthorities: invalid})
+}
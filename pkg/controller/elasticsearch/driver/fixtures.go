@@ -8,6 +8,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	corev1 "k8s.io/api/core/v1"
@@ -26,6 +27,7 @@ type testPod struct {
 	name                                                     string
 	version                                                  string
 	ssetName                                                 string
+	zone                                                     string
 	master, data, healthy, toUpgrade, inCluster, terminating bool
 	uid                                                      types.UID
 }
@@ -45,6 +47,7 @@ func (t testPod) needsUpgrade(v bool) testPod           { t.toUpgrade = v; retur
 func (t testPod) isTerminating(v bool) testPod          { t.terminating = v; return t }
 func (t testPod) withVersion(v string) testPod          { t.version = v; return t }
 func (t testPod) inStatefulset(ssetName string) testPod { t.ssetName = ssetName; return t } //nolint:unparam
+func (t testPod) inZone(zone string) testPod            { t.zone = zone; return t }
 
 // filter to simulate a Pod that has been removed while upgrading
 // unfortunately fake client does not support predicate
@@ -245,6 +248,9 @@ func (t testPod) toPod() corev1.Pod {
 	label.NodeTypesDataLabelName.Set(t.data, labels)
 	labels[label.StatefulSetNameLabelName] = t.ssetName
 	pod.Labels = labels
+	if t.zone != "" {
+		pod.Annotations = map[string]string{nodeattr.Annotation(nodeattr.ZoneAttribute): t.zone}
+	}
 	if t.healthy {
 		pod.Status = corev1.PodStatus{
 			Conditions: []corev1.PodCondition{
@@ -0,0 +1,51 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	podsPendingRestart = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "elastic_elasticsearch_pods_pending_restart",
+			Help: "Number of Pods of the Elasticsearch cluster to restart in order to apply the expected specification.",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	predicateRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "elastic_elasticsearch_upgrade_predicate_rejections_total",
+			Help: "Number of times an upgrade predicate prevented a Pod from being restarted.",
+		},
+		[]string{"predicate"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(podsPendingRestart, predicateRejections)
+}
+
+// updatePodsPendingRestart exposes the number of Pods of the given StatefulSets not running their expected revision yet.
+func updatePodsPendingRestart(c k8s.Client, es v1beta1.Elasticsearch, statefulSets sset.StatefulSetList) {
+	pods, err := podsToUpgrade(c, statefulSets)
+	if err != nil {
+		log.Error(err, "Failed to count the Pods pending restart", "namespace", es.Namespace, "es_name", es.Name)
+		return
+	}
+	podsPendingRestart.WithLabelValues(es.Namespace, es.Name).Set(float64(len(pods)))
+}
+
+// ForgetPodsPendingRestart stops exposing the number of Pods pending restart of the given cluster, once it is deleted.
+func ForgetPodsPendingRestart(es types.NamespacedName) {
+	podsPendingRestart.DeleteLabelValues(es.Namespace, es.Name)
+}
@@ -0,0 +1,40 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_updatePodsPendingRestart(t *testing.T) {
+	es := v1beta1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "es"}}
+	statefulSets := sset.StatefulSetList{
+		sset.TestSset{
+			Name: "masters", Replicas: 2, Master: true,
+			Status: appsv1.StatefulSetStatus{CurrentRevision: "rev-a", UpdateRevision: "rev-b", UpdatedReplicas: 1, Replicas: 2},
+		}.Build(),
+	}
+	defer ForgetPodsPendingRestart(types.NamespacedName{Namespace: es.Namespace, Name: es.Name})
+
+	// one Pod still runs the previous revision
+	client := k8s.WrapClient(fake.NewFakeClient(podWithRevision("masters-0", "rev-b"), podWithRevision("masters-1", "rev-a")))
+	updatePodsPendingRestart(client, es, statefulSets)
+	require.Equal(t, float64(1), testutil.ToFloat64(podsPendingRestart.WithLabelValues(es.Namespace, es.Name)))
+
+	// the gauge is reset once all the Pods are restarted
+	client = k8s.WrapClient(fake.NewFakeClient(podWithRevision("masters-0", "rev-b"), podWithRevision("masters-1", "rev-b")))
+	updatePodsPendingRestart(client, es, statefulSets)
+	require.Equal(t, float64(0), testutil.ToFloat64(podsPendingRestart.WithLabelValues(es.Namespace, es.Name)))
+}
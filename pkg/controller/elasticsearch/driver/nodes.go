@@ -7,6 +7,7 @@ package driver
 import (
 	"fmt"
 
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
@@ -32,12 +33,17 @@ func (d *defaultDriver) reconcileNodeSpecs(
 	keystoreResources *keystore.Resources,
 	certResources *certificates.CertificateResources,
 ) *reconciler.Results {
+	defer reconciler.ObserveDuration(v1beta1.Kind, "nodespecs")()
 	results := &reconciler.Results{}
 
 	actualStatefulSets, err := sset.RetrieveActualStatefulSets(d.Client, k8s.ExtractNamespacedName(&d.ES))
 	if err != nil {
 		return results.WithError(err)
 	}
+	// expose the Pods pending restart whatever the outcome of this reconciliation, including early returns
+	defer func() {
+		updatePodsPendingRestart(d.Client, d.ES, actualStatefulSets)
+	}()
 
 	// check if actual StatefulSets and corresponding pods match our expectations before applying any change
 	ok, err := d.expectationsMet(actualStatefulSets)
@@ -48,11 +54,35 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithResult(defaultRequeue)
 	}
 
-	expectedResources, err := nodespec.BuildExpectedResources(d.ES, keystoreResources, d.Scheme(), certResources)
+	expectedResources, err := nodespec.BuildExpectedResources(d.ES, keystoreResources, d.Scheme(), certResources, actualStatefulSets)
 	if err != nil {
 		return results.WithError(err)
 	}
 
+	// Phase 0: expand the volumes of existing StatefulSets, which are recreated to update their claim templates.
+	volumeExpansionCtx := volumeExpansionCtx{
+		k8sClient:      d.K8sClient(),
+		es:             &d.ES,
+		scheme:         d.Scheme(),
+		reconcileState: reconcileState,
+		expectations:   d.Expectations,
+	}
+	// StatefulSets whose volumes cannot be expanded keep their current claim templates in the expected resources.
+	recreating, unexpandable, err := handleVolumeExpansion(volumeExpansionCtx, expectedResources, actualStatefulSets)
+	if err != nil {
+		reconcileState.AddEvent(corev1.EventTypeWarning, events.EventReconciliationError, fmt.Sprintf("Failed to expand volumes: %v", err))
+		return results.WithError(err)
+	}
+	volumeExpansion, err := volumeExpansionStatus(d.K8sClient(), d.ES, actualStatefulSets, unexpandable)
+	if err != nil {
+		return results.WithError(err)
+	}
+	reconcileState.UpdateVolumeExpansionStatus(volumeExpansion)
+	if recreating {
+		// wait for the StatefulSets to be recreated before applying other changes
+		return results.WithResult(defaultRequeue)
+	}
+
 	if err := GarbageCollectPVCs(d.K8sClient(), d.ES, actualStatefulSets, expectedResources.StatefulSets()); err != nil {
 		return results.WithError(err)
 	}
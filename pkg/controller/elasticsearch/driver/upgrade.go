@@ -26,6 +26,7 @@ func (d *defaultDriver) handleRollingUpgrades(
 	statefulSets sset.StatefulSetList,
 	expectedMaster []string,
 ) *reconciler.Results {
+	defer reconciler.ObserveDuration(v1beta1.Kind, "upgrade")()
 	results := &reconciler.Results{}
 
 	// We need to check that all the expectations are met before continuing.
@@ -54,6 +55,10 @@ func (d *defaultDriver) handleRollingUpgrades(
 		return results.WithError(err)
 	}
 
+	if d.ES.Spec.UpdateStrategy.IsFullRestart() && len(podsToUpgrade) > 0 {
+		return d.handleFullRestart(esClient, esReachable, esState, statefulSets, expectedMaster, actualPods, podsToUpgrade)
+	}
+
 	if !esReachable {
 		// Cannot move on with rolling upgrades if ES cannot be reached.
 		return results.WithResult(defaultRequeue)
@@ -109,6 +114,7 @@ type rollingUpgradeCtx struct {
 	shardLister     esclient.ShardLister
 	esState         ESState
 	expectations    *expectations.Expectations
+	delayedRestarts *DelayedRestarts
 	reconcileState  *reconcile.State
 	expectedMasters []string
 	actualMasters   []corev1.Pod
@@ -133,6 +139,7 @@ func newRollingUpgrade(
 		esClient:        esClient,
 		esState:         esState,
 		expectations:    d.Expectations,
+		delayedRestarts: d.DelayedRestarts,
 		reconcileState:  d.ReconcileState,
 		expectedMasters: expectedMaster,
 		actualMasters:   actualMasters,
@@ -206,6 +213,15 @@ func podsToUpgrade(
 			alreadyUpgraded := podUpgradeDone(pod, statefulSet.Status.UpdateRevision)
 			if !alreadyUpgraded {
 				toUpgrade = append(toUpgrade, pod)
+				continue
+			}
+			// the file system of some expanded volumes may only be resized when the Pod restarts
+			resizePending, err := fileSystemResizePending(client, pod)
+			if err != nil {
+				return toUpgrade, err
+			}
+			if resizePending {
+				toUpgrade = append(toUpgrade, pod)
 			}
 		}
 	}
@@ -0,0 +1,169 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"context"
+	"sort"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// handleFullRestart performs a full restart of the Pods to upgrade, as an alternative to rolling upgrades.
+// Shards allocation is re-enabled by the rolling upgrade logic once there is no Pod left to upgrade.
+func (d *defaultDriver) handleFullRestart(
+	esClient esclient.Client,
+	esReachable bool,
+	esState ESState,
+	statefulSets sset.StatefulSetList,
+	expectedMaster []string,
+	actualPods []corev1.Pod,
+	podsToUpgrade []corev1.Pod,
+) *reconciler.Results {
+	results := &reconciler.Results{}
+	ctx := newRollingUpgrade(d, statefulSets, esClient, esState, expectedMaster, nil, podsToUpgrade, nil)
+	if _, err := ctx.fullRestart(esReachable, actualPods); err != nil {
+		return results.WithError(err)
+	}
+	// Pods are either being restarted or waiting to be restarted, check again later
+	return results.WithResult(defaultRequeue)
+}
+
+// fullRestart deletes all the Pods to upgrade at once, either in the whole cluster or in a single NodeSet
+// depending on the full restart scope, and returns the deleted Pods.
+// A new full restart is only started if Elasticsearch can be reached, in order to disable shards allocation and
+// request a synced flush first. Once started, the remaining Pods are deleted even if the cluster is unavailable.
+func (ctx rollingUpgradeCtx) fullRestart(esReachable bool, actualPods []corev1.Pod) ([]corev1.Pod, error) {
+	scope := ctx.ES.Spec.UpdateStrategy.FullRestartScope()
+	toRestart := podsToRestart(scope, ctx.statefulSets, actualPods, ctx.podsToUpgrade)
+	if len(toRestart) == 0 {
+		return nil, nil
+	}
+	inProgress := restartInProgress(ctx.statefulSets, actualPods, toRestart)
+
+	if !esReachable {
+		if !inProgress {
+			// Cannot start a full restart if ES cannot be reached.
+			return nil, nil
+		}
+	} else {
+		if !inProgress && scope == v1beta1.NodeSetFullRestartScope {
+			ready, err := ctx.readyForNextNodeSet(actualPods)
+			if err != nil || !ready {
+				return nil, err
+			}
+		}
+		if err := ctx.prepareClusterForNodeRestart(ctx.esClient, ctx.esState); err != nil {
+			return nil, err
+		}
+		for _, pod := range toRestart {
+			if err := ctx.handleMasterScaleChange(pod); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	log.Info("Performing a full restart",
+		"namespace", ctx.ES.Namespace, "es_name", ctx.ES.Name,
+		"scope", scope, "pod_count", len(toRestart),
+	)
+	deletedPods := make([]corev1.Pod, 0, len(toRestart))
+	for _, pod := range toRestart {
+		if err := deletePod(ctx.client, ctx.ES, pod, ctx.expectations); err != nil {
+			return deletedPods, err
+		}
+		deletedPods = append(deletedPods, pod)
+	}
+	return deletedPods, nil
+}
+
+// readyForNextNodeSet returns true if all the Pods are back in the cluster and the cluster health is green,
+// so that the next NodeSet can be restarted. Shards allocation, disabled for the previous NodeSet restart,
+// is re-enabled in order for the cluster to become green.
+func (ctx rollingUpgradeCtx) readyForNextNodeSet(actualPods []corev1.Pod) (bool, error) {
+	healthy, err := healthyPods(ctx.client, ctx.statefulSets, ctx.esState)
+	if err != nil {
+		return false, err
+	}
+	if len(healthy) < len(actualPods) {
+		log.V(1).Info("Waiting for all nodes to be back in the cluster before restarting the next NodeSet",
+			"namespace", ctx.ES.Namespace, "es_name", ctx.ES.Name)
+		return false, nil
+	}
+	allocationEnabled, err := ctx.esState.ShardAllocationsEnabled()
+	if err != nil {
+		return false, err
+	}
+	if !allocationEnabled {
+		log.Info("Enabling shards allocation", "namespace", ctx.ES.Namespace, "es_name", ctx.ES.Name)
+		reqCtx, cancel := context.WithTimeout(context.Background(), esclient.DefaultReqTimeout)
+		defer cancel()
+		return false, ctx.esClient.EnableShardAllocation(reqCtx)
+	}
+	green, err := ctx.esState.GreenHealth()
+	if err != nil {
+		return false, err
+	}
+	if !green {
+		log.V(1).Info("Waiting for the cluster to be green before restarting the next NodeSet",
+			"namespace", ctx.ES.Namespace, "es_name", ctx.ES.Name)
+	}
+	return green, nil
+}
+
+// podsToRestart returns the Pods to restart together for the given scope, ignoring Pods already terminating.
+// For the NodeSet scope, a NodeSet whose restart is in progress is completed before moving to the next one.
+func podsToRestart(
+	scope v1beta1.FullRestartScope,
+	statefulSets sset.StatefulSetList,
+	actualPods []corev1.Pod,
+	podsToUpgrade []corev1.Pod,
+) []corev1.Pod {
+	var candidates []corev1.Pod
+	for _, pod := range podsToUpgrade {
+		if pod.DeletionTimestamp.IsZero() {
+			candidates = append(candidates, pod)
+		}
+	}
+	if scope != v1beta1.NodeSetFullRestartScope || len(candidates) == 0 {
+		return candidates
+	}
+
+	bySset := podsByStatefulSetName(candidates)
+	ssetNames := make([]string, 0, len(bySset))
+	for name := range bySset {
+		ssetNames = append(ssetNames, name)
+	}
+	sort.Strings(ssetNames)
+	for _, name := range ssetNames {
+		if restartInProgress(statefulSets, actualPods, bySset[name]) {
+			return bySset[name]
+		}
+	}
+	return bySset[ssetNames[0]]
+}
+
+// restartInProgress returns true if some Pods of the StatefulSets the given Pods belong to
+// have already been restarted with the updated revision.
+func restartInProgress(statefulSets sset.StatefulSetList, actualPods []corev1.Pod, toRestart []corev1.Pod) bool {
+	for ssetName := range podsByStatefulSetName(toRestart) {
+		statefulSet, found := statefulSets.GetByName(ssetName)
+		if !found {
+			continue
+		}
+		for _, pod := range actualPods {
+			if pod.Labels[label.StatefulSetNameLabelName] == ssetName &&
+				podUpgradeDone(pod, statefulSet.Status.UpdateRevision) {
+				return true
+			}
+		}
+	}
+	return false
+}
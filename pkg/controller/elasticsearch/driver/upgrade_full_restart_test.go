@@ -0,0 +1,144 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func fullRestartTestPod(name string, ssetName string, revision string, master bool) corev1.Pod {
+	pod := newTestPod(name).inStatefulset(ssetName).isMaster(master).isData(!master).
+		isHealthy(true).isInCluster(true).toPod()
+	pod.Labels[appsv1.StatefulSetRevisionLabel] = revision
+	return pod
+}
+
+func Test_rollingUpgradeCtx_fullRestart(t *testing.T) {
+	statefulSets := sset.StatefulSetList{
+		sset.TestSset{Name: "data", Namespace: TestEsNamespace, Replicas: 2,
+			Status: appsv1.StatefulSetStatus{CurrentRevision: "rev-a", UpdateRevision: "rev-b"}}.Build(),
+		sset.TestSset{Name: "masters", Namespace: TestEsNamespace, Replicas: 2, Master: true,
+			Status: appsv1.StatefulSetStatus{CurrentRevision: "rev-a", UpdateRevision: "rev-b"}}.Build(),
+	}
+	notUpgraded := []corev1.Pod{
+		fullRestartTestPod("data-0", "data", "rev-a", false),
+		fullRestartTestPod("data-1", "data", "rev-a", false),
+		fullRestartTestPod("masters-0", "masters", "rev-a", true),
+		fullRestartTestPod("masters-1", "masters", "rev-a", true),
+	}
+	mastersInProgress := []corev1.Pod{
+		fullRestartTestPod("data-0", "data", "rev-a", false),
+		fullRestartTestPod("data-1", "data", "rev-a", false),
+		fullRestartTestPod("masters-0", "masters", "rev-b", true),
+		fullRestartTestPod("masters-1", "masters", "rev-a", true),
+	}
+
+	tests := []struct {
+		name                string
+		scope               v1beta1.FullRestartScope
+		pods                []corev1.Pod
+		esReachable         bool
+		green               bool
+		wantDeleted         []string
+		wantClusterPrepared bool
+	}{
+		{
+			name:                "cluster scope: restart all Pods at once",
+			scope:               v1beta1.ClusterFullRestartScope,
+			pods:                notUpgraded,
+			esReachable:         true,
+			wantDeleted:         []string{"data-0", "data-1", "masters-0", "masters-1"},
+			wantClusterPrepared: true,
+		},
+		{
+			name:        "cluster scope: ES unreachable, do not start the restart",
+			scope:       v1beta1.ClusterFullRestartScope,
+			pods:        notUpgraded,
+			esReachable: false,
+			wantDeleted: []string{},
+		},
+		{
+			name:        "cluster scope: ES unreachable, complete the restart in progress",
+			scope:       v1beta1.ClusterFullRestartScope,
+			pods:        mastersInProgress,
+			esReachable: false,
+			wantDeleted: []string{"data-0", "data-1", "masters-1"},
+		},
+		{
+			name:                "nodeSet scope: restart the first NodeSet",
+			scope:               v1beta1.NodeSetFullRestartScope,
+			pods:                notUpgraded,
+			esReachable:         true,
+			green:               true,
+			wantDeleted:         []string{"data-0", "data-1"},
+			wantClusterPrepared: true,
+		},
+		{
+			name:        "nodeSet scope: wait for the cluster to be green",
+			scope:       v1beta1.NodeSetFullRestartScope,
+			pods:        notUpgraded,
+			esReachable: true,
+			green:       false,
+			wantDeleted: []string{},
+		},
+		{
+			name:        "nodeSet scope: complete the NodeSet restart in progress",
+			scope:       v1beta1.NodeSetFullRestartScope,
+			pods:        mastersInProgress,
+			esReachable: false,
+			wantDeleted: []string{"masters-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []runtime.Object
+			var toUpgrade []corev1.Pod
+			var inCluster []string
+			for i := range tt.pods {
+				objs = append(objs, &tt.pods[i])
+				inCluster = append(inCluster, tt.pods[i].Name)
+				if sset.PodRevision(tt.pods[i]) != "rev-b" {
+					toUpgrade = append(toUpgrade, tt.pods[i])
+				}
+			}
+			esClient := &fakeESClient{}
+			es := v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{UpdateStrategy: v1beta1.UpdateStrategy{
+				Type:        v1beta1.FullRestartUpdateStrategyType,
+				FullRestart: &v1beta1.FullRestartStrategy{Scope: tt.scope},
+			}}}
+			es.Name = TestEsName
+			es.Namespace = TestEsNamespace
+			ctx := rollingUpgradeCtx{
+				client:          k8s.WrapClient(fake.NewFakeClient(objs...)),
+				ES:              es,
+				statefulSets:    statefulSets,
+				esClient:        esClient,
+				esState:         &testESState{inCluster: inCluster, green: tt.green},
+				expectations:    expectations.NewExpectations(),
+				reconcileState:  reconcile.NewState(es),
+				expectedMasters: []string{"masters-0", "masters-1"},
+				podsToUpgrade:   toUpgrade,
+			}
+
+			deleted, err := ctx.fullRestart(tt.esReachable, tt.pods)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.wantDeleted, names(deleted))
+			assert.Equal(t, tt.wantClusterPrepared, esClient.DisableReplicaShardsAllocationCalled)
+			assert.Equal(t, tt.wantClusterPrepared, esClient.SyncedFlushCalled)
+		})
+	}
+}
@@ -5,13 +5,18 @@
 package driver
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/migration"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/set"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,6 +26,8 @@ import (
 // Do not run this function unless driver expectations are met.
 func (ctx *rollingUpgradeCtx) Delete() ([]corev1.Pod, error) {
 	if len(ctx.podsToUpgrade) == 0 {
+		// forget the restarts delayed during the previous upgrade, if any
+		ctx.delayedRestarts.Update(k8s.ExtractNamespacedName(&ctx.ES), nil)
 		return nil, nil
 	}
 
@@ -32,23 +39,48 @@ func (ctx *rollingUpgradeCtx) Delete() ([]corev1.Pod, error) {
 	copy(candidates, ctx.podsToUpgrade)
 	sortCandidates(candidates)
 
+	actualPods, err := ctx.statefulSets.GetActualPods(ctx.client)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.ES.Spec.IsZoneAware() {
+		zoneCandidates, zoneAtATime, err := ctx.selectZoneCandidates(candidates, actualPods)
+		if err != nil {
+			return nil, err
+		}
+		if zoneAtATime {
+			// the Pods of the zone can be restarted together, within the limits of maxUnavailable
+			candidates = zoneCandidates
+		}
+	}
+
 	// Step 2: Apply predicates
 	predicateContext := NewPredicateContext(
 		ctx.esState,
+		ctx.esClient,
 		ctx.shardLister,
+		ctx.client,
 		ctx.healthyPods,
 		ctx.podsToUpgrade,
 		ctx.expectedMasters,
 		ctx.actualMasters,
+		actualPods,
 	)
+	strategy := ctx.ES.Spec.UpdateStrategy
 	log.V(1).Info("Applying predicates",
 		"maxUnavailableReached", maxUnavailableReached,
 		"allowedDeletions", allowedDeletions,
 	)
-	podsToDelete, err := applyPredicates(predicateContext, candidates, maxUnavailableReached, allowedDeletions)
+	podsToDelete, delayed, err := applyPredicates(
+		predicateContext, predicatesFor(strategy), candidates, maxUnavailableReached, allowedDeletions,
+	)
 	if err != nil {
 		return podsToDelete, err
 	}
+	for _, d := range ctx.delayedRestarts.Update(k8s.ExtractNamespacedName(&ctx.ES), delayed) {
+		ctx.reconcileState.AddEvent(corev1.EventTypeNormal, events.EventReasonDelayed,
+			fmt.Sprintf("Restart of pod %s delayed: predicate %s not satisfied", d.pod, d.predicate))
+	}
 
 	if len(podsToDelete) == 0 {
 		log.V(1).Info(
@@ -77,6 +109,69 @@ func (ctx *rollingUpgradeCtx) Delete() ([]corev1.Pod, error) {
 	return deletedPods, nil
 }
 
+// selectZoneCandidates restricts the candidates to the ones of a single zone, if the whole zone can be restarted
+// at once: shard allocation awareness must guarantee that all the shards of the zone have a started copy in
+// other zones. The zone with unavailable Pods, if any, is selected first. Otherwise zones are upgraded in order.
+// It returns false if the upgrade cannot be performed a zone at a time.
+func (ctx *rollingUpgradeCtx) selectZoneCandidates(candidates []corev1.Pod, actualPods []corev1.Pod) ([]corev1.Pod, bool, error) {
+	zones, known := nodeattr.Zones(actualPods)
+	if !known || len(candidates) == 0 {
+		return nil, false, nil
+	}
+	unavailableZones := set.StringSet{}
+	for _, pod := range actualPods {
+		if _, healthy := ctx.healthyPods[pod.Name]; !healthy {
+			unavailableZones.Add(zones[pod.Name])
+		}
+	}
+	var zone string
+	switch unavailableZones.Count() {
+	case 0:
+		candidateZones := set.StringSet{}
+		for _, candidate := range candidates {
+			candidateZones.Add(zones[candidate.Name])
+		}
+		sortedZones := candidateZones.AsSlice()
+		sortedZones.Sort()
+		zone = sortedZones[0]
+	case 1:
+		zone = unavailableZones.AsSlice()[0]
+	default:
+		// several zones are degraded, let the predicates restart Pods one at a time
+		return nil, false, nil
+	}
+
+	var zonePods []string
+	for _, pod := range actualPods {
+		if zones[pod.Name] == zone {
+			zonePods = append(zonePods, pod.Name)
+		}
+	}
+	shards, err := ctx.shardLister.GetShards()
+	if err != nil {
+		return nil, false, err
+	}
+	if !migration.IsReplicatedInOtherZones(shards, zonePods, zones) {
+		log.V(1).Info("Zone data not replicated in other zones, restarting nodes one at a time",
+			"namespace", ctx.ES.Namespace, "es_name", ctx.ES.Name, "zone", zone)
+		return nil, false, nil
+	}
+
+	var zoneCandidates []corev1.Pod
+	for _, candidate := range candidates {
+		if zones[candidate.Name] == zone {
+			zoneCandidates = append(zoneCandidates, candidate)
+		}
+	}
+	if len(zoneCandidates) == 0 {
+		// Pods are unavailable in a zone with nothing left to upgrade, rely on maxUnavailable
+		return nil, false, nil
+	}
+	log.V(1).Info("Restarting nodes a zone at a time",
+		"namespace", ctx.ES.Namespace, "es_name", ctx.ES.Name, "zone", zone, "candidates", len(zoneCandidates))
+	return zoneCandidates, true, nil
+}
+
 // getAllowedDeletions returns the number of deletions that can be done and if maxUnavailable has been reached.
 func (ctx *rollingUpgradeCtx) getAllowedDeletions() (int, bool) {
 	// Check if we are not over disruption budget
@@ -170,23 +265,27 @@ func deletePod(k8sClient k8s.Client, es v1beta1.Elasticsearch, pod corev1.Pod, e
 	return err
 }
 
+// runPredicates returns the name of the first predicate preventing the candidate from being deleted,
+// or an empty string if all predicates passed.
 func runPredicates(
 	ctx PredicateContext,
+	predicates []Predicate,
 	candidate corev1.Pod,
 	deletedPods []corev1.Pod,
 	maxUnavailableReached bool,
-) (bool, error) {
+) (string, error) {
 	for _, predicate := range predicates {
 		canDelete, err := predicate.fn(ctx, candidate, deletedPods, maxUnavailableReached)
 		if err != nil {
-			return false, err
+			return "", err
 		}
 		if !canDelete {
 			log.V(1).Info("Predicate failed", "pod_name", candidate.Name, "predicate_name", predicate.name)
+			predicateRejections.WithLabelValues(predicate.name).Inc()
 			// Skip this Pod, it can't be deleted for the moment
-			return false, nil
+			return predicate.name, nil
 		}
 	}
 	// All predicates passed!
-	return true, nil
+	return "", nil
 }
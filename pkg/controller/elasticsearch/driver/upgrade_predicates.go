@@ -5,8 +5,10 @@
 package driver
 
 import (
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -14,10 +16,13 @@ import (
 type PredicateContext struct {
 	masterNodesNames       []string
 	actualMasters          []corev1.Pod
+	actualPods             []corev1.Pod
 	healthyPods            map[string]corev1.Pod
 	toUpdate               []corev1.Pod
 	esState                ESState
+	esClient               client.Client
 	shardLister            client.ShardLister
+	k8sClient              k8s.Client
 	masterUpdateInProgress bool
 }
 
@@ -29,27 +34,47 @@ type Predicate struct {
 
 func NewPredicateContext(
 	state ESState,
+	esClient client.Client,
 	shardLister client.ShardLister,
+	k8sClient k8s.Client,
 	healthyPods map[string]corev1.Pod,
 	podsToUpgrade []corev1.Pod,
 	masterNodesNames []string,
 	actualMasters []corev1.Pod,
+	actualPods []corev1.Pod,
 ) PredicateContext {
 	return PredicateContext{
 		masterNodesNames: masterNodesNames,
 		actualMasters:    actualMasters,
+		actualPods:       actualPods,
 		healthyPods:      healthyPods,
 		toUpdate:         podsToUpgrade,
 		esState:          state,
+		esClient:         esClient,
 		shardLister:      shardLister,
+		k8sClient:        k8sClient,
 	}
 }
 
-func applyPredicates(ctx PredicateContext, candidates []corev1.Pod, maxUnavailableReached bool, allowedDeletions int) (deletedPods []corev1.Pod, err error) {
+// delayedRestart records the predicate which prevented a Pod from being restarted.
+type delayedRestart struct {
+	pod       string
+	predicate string
+}
+
+func applyPredicates(
+	ctx PredicateContext,
+	predicates []Predicate,
+	candidates []corev1.Pod,
+	maxUnavailableReached bool,
+	allowedDeletions int,
+) (deletedPods []corev1.Pod, delayed []delayedRestart, err error) {
 	for _, candidate := range candidates {
-		if ok, err := runPredicates(ctx, candidate, deletedPods, maxUnavailableReached); err != nil {
-			return deletedPods, err
-		} else if ok {
+		if failedPredicate, err := runPredicates(ctx, predicates, candidate, deletedPods, maxUnavailableReached); err != nil {
+			return deletedPods, delayed, err
+		} else if failedPredicate != "" {
+			delayed = append(delayed, delayedRestart{pod: candidate.Name, predicate: failedPredicate})
+		} else {
 			candidate := candidate
 			if label.IsMasterNode(candidate) || willBecomeMasterNode(candidate.Name, ctx.masterNodesNames) {
 				// It is a mutation on an already existing or future master.
@@ -65,7 +90,37 @@ func applyPredicates(ctx PredicateContext, candidates []corev1.Pod, maxUnavailab
 			}
 		}
 	}
-	return deletedPods, nil
+	return deletedPods, delayed, nil
+}
+
+// predicatesFor returns the built-in predicates which are not disabled in the given update strategy,
+// followed by the declarative predicates it specifies.
+func predicatesFor(strategy v1beta1.UpdateStrategy) []Predicate {
+	result := make([]Predicate, 0, len(predicates)+len(strategy.Predicates))
+	for _, predicate := range predicates {
+		if stringsutil.StringInSlice(predicate.name, strategy.DisabledPredicates) {
+			continue
+		}
+		result = append(result, predicate)
+	}
+	for _, spec := range strategy.Predicates {
+		switch {
+		case spec.IndicesGreen != nil:
+			result = append(result, indicesGreenPredicate(spec.Name, *spec.IndicesGreen))
+		case spec.OneNodePerZone != nil:
+			result = append(result, oneNodePerZonePredicate(spec.Name, *spec.OneNodePerZone))
+		}
+	}
+	return result
+}
+
+// PredicateNames returns the names of the built-in predicates, which can be disabled in the update strategy.
+func PredicateNames() []string {
+	names := make([]string, 0, len(predicates))
+	for _, predicate := range predicates {
+		names = append(names, predicate.name)
+	}
+	return names
 }
 
 var predicates = [...]Predicate{
@@ -0,0 +1,117 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"context"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Declarative predicates are built from the update strategy at each rolling upgrade attempt.
+// They may memoize information retrieved from Elasticsearch or Kubernetes for the duration of the attempt.
+
+// indicesGreenPredicate only allows healthy Pods to be restarted if the given indices are green.
+func indicesGreenPredicate(name string, spec v1beta1.IndicesGreenPredicate) Predicate {
+	var green *bool
+	return Predicate{
+		name: name,
+		fn: func(
+			predicateContext PredicateContext,
+			candidate corev1.Pod,
+			deletedPods []corev1.Pod,
+			maxUnavailableReached bool,
+		) (bool, error) {
+			if _, healthy := predicateContext.healthyPods[candidate.Name]; !healthy {
+				// give unhealthy Pods a chance to restart
+				return true, nil
+			}
+			if green == nil {
+				ctx, cancel := context.WithTimeout(context.Background(), esclient.DefaultReqTimeout)
+				defer cancel()
+				health, err := predicateContext.esClient.GetIndicesHealth(ctx, spec.Indices)
+				if err != nil {
+					return false, err
+				}
+				isGreen := health.Status == string(v1beta1.ElasticsearchGreenHealth)
+				green = &isGreen
+			}
+			return *green, nil
+		},
+	}
+}
+
+// oneNodePerZonePredicate only allows a Pod to be restarted if all the other Pods in the same zone are healthy.
+// The zone of a Pod is the value of the given label on the Kubernetes node it is scheduled on.
+func oneNodePerZonePredicate(name string, spec v1beta1.OneNodePerZonePredicate) Predicate {
+	var zones map[string]string
+	return Predicate{
+		name: name,
+		fn: func(
+			predicateContext PredicateContext,
+			candidate corev1.Pod,
+			deletedPods []corev1.Pod,
+			maxUnavailableReached bool,
+		) (bool, error) {
+			if zones == nil {
+				var err error
+				zones, err = podZones(predicateContext.k8sClient, predicateContext.actualPods, spec.ZoneLabelOrDefault())
+				if err != nil {
+					return false, err
+				}
+			}
+			zone, exists := zones[candidate.Name]
+			if !exists {
+				// the zone of the Pod is unknown, it cannot be checked
+				return true, nil
+			}
+			for _, pod := range deletedPods {
+				if zones[pod.Name] == zone {
+					return false, nil
+				}
+			}
+			for _, pod := range predicateContext.actualPods {
+				if pod.Name == candidate.Name || zones[pod.Name] != zone {
+					continue
+				}
+				if _, healthy := predicateContext.healthyPods[pod.Name]; !healthy {
+					return false, nil
+				}
+			}
+			return true, nil
+		},
+	}
+}
+
+// podZones returns the zone of the given Pods, indexed by Pod name.
+// Pods which are not scheduled yet, or scheduled on a node without the zone label, are not included.
+func podZones(c k8s.Client, pods []corev1.Pod, zoneLabel string) (map[string]string, error) {
+	nodeZones := make(map[string]string)
+	zones := make(map[string]string)
+	for _, pod := range pods {
+		nodeName := pod.Spec.NodeName
+		if nodeName == "" {
+			continue
+		}
+		zone, cached := nodeZones[nodeName]
+		if !cached {
+			var node corev1.Node
+			if err := c.Get(types.NamespacedName{Name: nodeName}, &node); err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			zone = node.Labels[zoneLabel]
+			nodeZones[nodeName] = zone
+		}
+		if zone != "" {
+			zones[pod.Name] = zone
+		}
+	}
+	return zones, nil
+}
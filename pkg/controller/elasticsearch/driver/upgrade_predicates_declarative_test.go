@@ -0,0 +1,169 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_indicesGreenPredicate(t *testing.T) {
+	healthy := newTestPod("healthy-0").isHealthy(true).toPod()
+	unhealthy := newTestPod("unhealthy-0").toPod()
+	tests := []struct {
+		name      string
+		status    string
+		candidate corev1.Pod
+		want      bool
+	}{
+		{
+			name:      "indices green",
+			status:    "green",
+			candidate: healthy,
+			want:      true,
+		},
+		{
+			name:      "indices yellow",
+			status:    "yellow",
+			candidate: healthy,
+			want:      false,
+		},
+		{
+			name:      "indices yellow, unhealthy candidate",
+			status:    "yellow",
+			candidate: unhealthy,
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := esclient.NewMockClient(version.MustParse("7.4.0"), func(req *http.Request) *http.Response {
+				require.Equal(t, "/_cluster/health/logs-*,metrics", req.URL.Path)
+				return esclient.NewMockResponse(200, req, `{"status":"`+tt.status+`"}`)
+			})
+			ctx := PredicateContext{
+				esClient:    esClient,
+				healthyPods: map[string]corev1.Pod{healthy.Name: healthy},
+			}
+			predicate := indicesGreenPredicate("test", v1beta1.IndicesGreenPredicate{Indices: []string{"logs-*", "metrics"}})
+			got, err := predicate.fn(ctx, tt.candidate, nil, false)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_oneNodePerZonePredicate(t *testing.T) {
+	podOnNode := func(name string, node string, healthy bool) corev1.Pod {
+		pod := newTestPod(name).isHealthy(healthy).toPod()
+		pod.Spec.NodeName = node
+		return pod
+	}
+	nodeInZone := func(name string, zone string) runtime.Object {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{v1beta1.DefaultZoneLabel: zone},
+		}}
+	}
+	tests := []struct {
+		name        string
+		pods        []corev1.Pod
+		candidate   string
+		deletedPods []string
+		want        bool
+	}{
+		{
+			name: "all Pods healthy",
+			pods: []corev1.Pod{
+				podOnNode("es-0", "node-a", true),
+				podOnNode("es-1", "node-a", true),
+				podOnNode("es-2", "node-b", true),
+			},
+			candidate: "es-0",
+			want:      true,
+		},
+		{
+			name: "another Pod in the same zone is unhealthy",
+			pods: []corev1.Pod{
+				podOnNode("es-0", "node-a", true),
+				podOnNode("es-1", "node-a", false),
+				podOnNode("es-2", "node-b", true),
+			},
+			candidate: "es-0",
+			want:      false,
+		},
+		{
+			name: "another Pod in the same zone is being deleted",
+			pods: []corev1.Pod{
+				podOnNode("es-0", "node-a", true),
+				podOnNode("es-1", "node-a2", true),
+				podOnNode("es-2", "node-b", true),
+			},
+			candidate:   "es-0",
+			deletedPods: []string{"es-1"},
+			want:        false,
+		},
+		{
+			name: "a Pod in another zone is unhealthy",
+			pods: []corev1.Pod{
+				podOnNode("es-0", "node-a", true),
+				podOnNode("es-2", "node-b", false),
+			},
+			candidate: "es-0",
+			want:      true,
+		},
+		{
+			name: "Pod not scheduled yet",
+			pods: []corev1.Pod{
+				podOnNode("es-0", "", false),
+				podOnNode("es-1", "node-a", false),
+			},
+			candidate: "es-0",
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := k8s.WrapClient(fake.NewFakeClient(
+				nodeInZone("node-a", "zone-a"), nodeInZone("node-a2", "zone-a"), nodeInZone("node-b", "zone-b"),
+			))
+			healthyPods := map[string]corev1.Pod{}
+			var candidate corev1.Pod
+			var deletedPods []corev1.Pod
+			for _, pod := range tt.pods {
+				if k8s.IsPodReady(pod) {
+					healthyPods[pod.Name] = pod
+				}
+				if pod.Name == tt.candidate {
+					candidate = pod
+				}
+				for _, deleted := range tt.deletedPods {
+					if pod.Name == deleted {
+						deletedPods = append(deletedPods, pod)
+					}
+				}
+			}
+			ctx := PredicateContext{
+				k8sClient:   k8sClient,
+				actualPods:  tt.pods,
+				healthyPods: healthyPods,
+			}
+			predicate := oneNodePerZonePredicate("test", v1beta1.OneNodePerZonePredicate{})
+			got, err := predicate.fn(ctx, candidate, deletedPods, false)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
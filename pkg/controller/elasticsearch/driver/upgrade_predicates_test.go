@@ -11,9 +11,13 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/migration"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -90,6 +94,7 @@ func TestUpgradePodsDeletion_WithNodeTypeMutations(t *testing.T) {
 			deleted:                      []string{},
 			wantErr:                      false,
 			wantShardsAllocationDisabled: false,
+			recordedEvents:               1, // restart delayed by one_master_at_a_time
 		},
 		{
 			name: "Two data nodes converted into master+data nodes, step 1: only 1 at a time is allowed",
@@ -106,6 +111,7 @@ func TestUpgradePodsDeletion_WithNodeTypeMutations(t *testing.T) {
 			deleted:                      []string{"data-to-masters-1"},
 			wantErr:                      false,
 			wantShardsAllocationDisabled: true,
+			recordedEvents:               1, // restart delayed by one_master_at_a_time
 		},
 		{
 			name: "Two data nodes converted into master+data nodes, step 2: upgrade the remaining one",
@@ -401,6 +407,7 @@ func TestUpgradePodsDeletion_Delete(t *testing.T) {
 			shardLister:     tt.fields.shardLister,
 			esState:         esState,
 			expectations:    expectations.NewExpectations(),
+			reconcileState:  reconcile.NewState(v1beta1.Elasticsearch{}),
 			expectedMasters: tt.fields.upgradeTestPods.toMasters(noMutation),
 			podsToUpgrade:   tt.fields.upgradeTestPods.toUpgrade(),
 			healthyPods:     tt.fields.upgradeTestPods.toHealthyPods(),
@@ -475,3 +482,185 @@ func TestDeletionStrategy_SortFunction(t *testing.T) {
 		})
 	}
 }
+
+func Test_predicatesFor(t *testing.T) {
+	strategy := v1beta1.UpdateStrategy{
+		DisabledPredicates: []string{"do_not_restart_healthy_node_if_not_green"},
+		Predicates: []v1beta1.UpgradePredicate{
+			{Name: "logs_green", IndicesGreen: &v1beta1.IndicesGreenPredicate{Indices: []string{"logs-*"}}},
+			{Name: "zones", OneNodePerZone: &v1beta1.OneNodePerZonePredicate{}},
+		},
+	}
+	var got []string
+	for _, predicate := range predicatesFor(strategy) {
+		got = append(got, predicate.name)
+	}
+	require.Equal(t, []string{
+		"do_not_restart_healthy_node_if_MaxUnavailable_reached",
+		"skip_already_terminating_pods",
+		"one_master_at_a_time",
+		"do_not_delete_last_master_if_data_nodes_are_not_upgraded",
+		"do_not_delete_pods_with_same_shards",
+		"logs_green",
+		"zones",
+	}, got)
+}
+
+func Test_selectZoneCandidates(t *testing.T) {
+	podInZone := func(name, zone string, healthy bool) corev1.Pod {
+		pod := newTestPod(name).isData(true).isHealthy(healthy).needsUpgrade(true).isInCluster(true).toPod()
+		pod.Annotations = map[string]string{nodeattr.Annotation(nodeattr.ZoneAttribute): zone}
+		return pod
+	}
+	replicatedShards := client.Shards{
+		{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "a-0"},
+		{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "b-0"},
+	}
+	tests := []struct {
+		name            string
+		pods            []corev1.Pod
+		shards          client.Shards
+		wantCandidates  []string
+		wantZoneAtATime bool
+	}{
+		{
+			name: "all healthy: select the first zone",
+			pods: []corev1.Pod{
+				podInZone("b-0", "zone-b", true), podInZone("a-0", "zone-a", true), podInZone("a-1", "zone-a", true),
+			},
+			shards:          replicatedShards,
+			wantCandidates:  []string{"a-0", "a-1"},
+			wantZoneAtATime: true,
+		},
+		{
+			name: "continue with the zone being upgraded",
+			pods: []corev1.Pod{
+				podInZone("a-0", "zone-a", true), podInZone("b-0", "zone-b", false), podInZone("b-1", "zone-b", true),
+			},
+			shards:          replicatedShards,
+			wantCandidates:  []string{"b-0", "b-1"},
+			wantZoneAtATime: true,
+		},
+		{
+			name: "unavailable Pods in several zones",
+			pods: []corev1.Pod{
+				podInZone("a-0", "zone-a", false), podInZone("b-0", "zone-b", false),
+			},
+			shards:          replicatedShards,
+			wantZoneAtATime: false,
+		},
+		{
+			name: "shards not replicated in another zone",
+			pods: []corev1.Pod{
+				podInZone("a-0", "zone-a", true), podInZone("a-1", "zone-a", true), podInZone("b-0", "zone-b", true),
+			},
+			shards: client.Shards{
+				{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "a-0"},
+				{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "a-1"},
+			},
+			wantZoneAtATime: false,
+		},
+		{
+			name: "unknown zone",
+			pods: []corev1.Pod{
+				podInZone("a-0", "zone-a", true), newTestPod("b-0").isData(true).isHealthy(true).toPod(),
+			},
+			shards:          replicatedShards,
+			wantZoneAtATime: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthyPods := make(map[string]corev1.Pod)
+			for _, pod := range tt.pods {
+				if k8s.IsPodReady(pod) {
+					healthyPods[pod.Name] = pod
+				}
+			}
+			ctx := &rollingUpgradeCtx{
+				healthyPods: healthyPods,
+				shardLister: migration.NewFakeShardLister(tt.shards),
+			}
+			candidates, zoneAtATime, err := ctx.selectZoneCandidates(tt.pods, tt.pods)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantZoneAtATime, zoneAtATime)
+			var names []string
+			for _, candidate := range candidates {
+				names = append(names, candidate.Name)
+			}
+			require.ElementsMatch(t, tt.wantCandidates, names)
+		})
+	}
+}
+
+func TestUpgradePodsDeletion_ZoneAtATime(t *testing.T) {
+	pods := newUpgradeTestPods(
+		newTestPod("data-0").isData(true).isHealthy(true).needsUpgrade(true).isInCluster(true).inStatefulset("data").inZone("zone-a"),
+		newTestPod("data-1").isData(true).isHealthy(true).needsUpgrade(true).isInCluster(true).inStatefulset("data").inZone("zone-a"),
+		newTestPod("data-2").isData(true).isHealthy(true).needsUpgrade(true).isInCluster(true).inStatefulset("data").inZone("zone-b"),
+		newTestPod("data-3").isData(true).isHealthy(true).needsUpgrade(true).isInCluster(true).inStatefulset("data").inZone("zone-b"),
+	)
+	shards := client.Shards{
+		{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "data-0"},
+		{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "data-2"},
+		{Index: "index-2", Shard: "0", State: client.STARTED, NodeName: "data-1"},
+		{Index: "index-2", Shard: "0", State: client.STARTED, NodeName: "data-3"},
+	}
+	tests := []struct {
+		name           string
+		maxUnavailable int
+		deleted        []string
+	}{
+		{
+			name:           "restart the nodes of the first zone within maxUnavailable",
+			maxUnavailable: 1,
+			deleted:        []string{"data-1"},
+		},
+		{
+			name:           "restart all the nodes of the first zone",
+			maxUnavailable: 3,
+			deleted:        []string{"data-0", "data-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := pods.toES(tt.maxUnavailable)
+			es.Spec.ZoneAwareness = &v1beta1.ZoneAwareness{}
+			ctx := rollingUpgradeCtx{
+				client:          k8s.WrapClient(fake.NewFakeClient(pods.toRuntimeObjects(tt.maxUnavailable, nothing)...)),
+				ES:              es,
+				statefulSets:    pods.toStatefulSetList(),
+				esClient:        &fakeESClient{},
+				shardLister:     migration.NewFakeShardLister(shards),
+				esState:         &testESState{inCluster: pods.podsInCluster(), green: true},
+				expectations:    expectations.NewExpectations(),
+				reconcileState:  reconcile.NewState(v1beta1.Elasticsearch{}),
+				expectedMasters: pods.toMasters(noMutation),
+				podsToUpgrade:   pods.toUpgrade(),
+				healthyPods:     pods.toHealthyPods(),
+			}
+			deleted, err := ctx.Delete()
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.deleted, names(deleted))
+		})
+	}
+}
+
+func TestRunPredicates_CountsRejections(t *testing.T) {
+	predicate := func(name string, canDelete bool) Predicate {
+		return Predicate{
+			name: name,
+			fn: func(_ PredicateContext, _ corev1.Pod, _ []corev1.Pod, _ bool) (bool, error) {
+				return canDelete, nil
+			},
+		}
+	}
+	predicates := []Predicate{predicate("passing", true), predicate("failing", false), predicate("skipped", false)}
+
+	failedPredicate, err := runPredicates(PredicateContext{}, predicates, newTestPod("a-0").toPod(), nil, false)
+	require.NoError(t, err)
+	require.Equal(t, "failing", failedPredicate)
+	require.Equal(t, float64(0), testutil.ToFloat64(predicateRejections.WithLabelValues("passing")))
+	require.Equal(t, float64(1), testutil.ToFloat64(predicateRejections.WithLabelValues("failing")))
+	require.Equal(t, float64(0), testutil.ToFloat64(predicateRejections.WithLabelValues("skipped")))
+}
@@ -0,0 +1,421 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RecreateStatefulSetsAnnotation records the StatefulSets deleted while orphaning their Pods to update their
+// volume claim templates, until they are recreated.
+const RecreateStatefulSetsAnnotation = "elasticsearch.k8s.elastic.co/recreate-statefulsets"
+
+// statefulSetRecreation identifies a deleted StatefulSet to recreate.
+type statefulSetRecreation struct {
+	// UID of the deleted StatefulSet, to distinguish it from the recreated one.
+	UID types.UID `json:"uid"`
+	// Replicas of the deleted StatefulSet, to recreate it without removing any of its orphaned Pods.
+	Replicas int32 `json:"replicas"`
+}
+
+type volumeExpansionCtx struct {
+	k8sClient      k8s.Client
+	es             *v1beta1.Elasticsearch
+	scheme         *runtime.Scheme
+	reconcileState *reconcile.State
+	expectations   *expectations.Expectations
+}
+
+// handleVolumeExpansion expands the persistent volume claims of the existing StatefulSets whose volume claim
+// templates request more storage. Since volume claim templates are immutable, these StatefulSets are then deleted
+// while orphaning their Pods, and recreated with the expected claim templates: their Pods are adopted without
+// restarting. StatefulSets whose claims cannot be expanded keep their current claim templates in the expected
+// resources, and are returned so they can be reported. It returns true while some StatefulSets are being recreated,
+// in which case no other change should be applied to the StatefulSets.
+func handleVolumeExpansion(
+	ctx volumeExpansionCtx,
+	expectedResources nodespec.ResourcesList,
+	actualStatefulSets sset.StatefulSetList,
+) (bool, []v1beta1.UnexpandableStatefulSet, error) {
+	recreations, err := getRecreations(*ctx.es)
+	if err != nil {
+		return false, nil, err
+	}
+	var unexpandable []v1beta1.UnexpandableStatefulSet
+	storageClasses := make(map[string]*storagev1.StorageClass)
+	for i := range expectedResources {
+		expected := &expectedResources[i].StatefulSet
+		if _, recreating := recreations[expected.Name]; recreating {
+			continue
+		}
+		actual, exists := actualStatefulSets.GetByName(expected.Name)
+		if !exists {
+			continue
+		}
+		claims := volume.ClaimsToExpand(actual.Spec.VolumeClaimTemplates, expected.Spec.VolumeClaimTemplates)
+		if len(claims) == 0 {
+			continue
+		}
+		reason, err := unexpandableReason(ctx.k8sClient, actual, claims, storageClasses)
+		if err != nil {
+			return false, nil, err
+		}
+		if reason != "" {
+			// keep the current claim templates, so that other changes can still be applied to the StatefulSet
+			expected.Spec.VolumeClaimTemplates = actual.Spec.VolumeClaimTemplates
+			unexpandable = append(unexpandable, newUnexpandableStatefulSet(actual, reason))
+			if !wasUnexpandable(*ctx.es, actual.Name, reason) {
+				ctx.reconcileState.AddEvent(
+					corev1.EventTypeWarning,
+					events.EventReasonUnexpected,
+					fmt.Sprintf("Cannot expand the volumes of StatefulSet %s: %s", actual.Name, reason),
+				)
+			}
+			continue
+		}
+		if err := expandClaims(ctx.k8sClient, actual, claims); err != nil {
+			return false, nil, err
+		}
+		ctx.reconcileState.AddEvent(
+			corev1.EventTypeNormal,
+			events.EventReasonStateChange,
+			fmt.Sprintf("Expanding the volumes of StatefulSet %s", actual.Name),
+		)
+		recreations[actual.Name] = statefulSetRecreation{UID: actual.UID, Replicas: sset.GetReplicas(actual)}
+	}
+	// record the StatefulSets to recreate before deleting them
+	if err := setRecreations(ctx.k8sClient, ctx.es, recreations); err != nil {
+		return false, nil, err
+	}
+	if err := recreateStatefulSets(ctx, expectedResources.StatefulSets(), actualStatefulSets, recreations); err != nil {
+		return false, nil, err
+	}
+	if err := setRecreations(ctx.k8sClient, ctx.es, recreations); err != nil {
+		return false, nil, err
+	}
+	return len(recreations) > 0, unexpandable, nil
+}
+
+// newUnexpandableStatefulSet reports the given StatefulSet whose volumes cannot be expanded, with the storage
+// of its current volume claim templates.
+func newUnexpandableStatefulSet(statefulSet appsv1.StatefulSet, reason string) v1beta1.UnexpandableStatefulSet {
+	storage := make(map[string]resource.Quantity, len(statefulSet.Spec.VolumeClaimTemplates))
+	for _, claim := range statefulSet.Spec.VolumeClaimTemplates {
+		storage[claim.Name] = volume.StorageRequest(claim)
+	}
+	return v1beta1.UnexpandableStatefulSet{Name: statefulSet.Name, Reason: reason, Storage: storage}
+}
+
+// wasUnexpandable returns true if the given StatefulSet was already reported as unexpandable for the same reason
+// in the status of the given Elasticsearch.
+func wasUnexpandable(es v1beta1.Elasticsearch, name string, reason string) bool {
+	if es.Status.VolumeExpansion == nil {
+		return false
+	}
+	for _, unexpandable := range es.Status.VolumeExpansion.UnexpandableStatefulSets {
+		if unexpandable.Name == name && unexpandable.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// recreateStatefulSets deletes the StatefulSets to recreate while orphaning their Pods, then creates them again
+// from the expected StatefulSets once the deletion is observed. Recreated StatefulSets are removed from the given
+// recreations.
+func recreateStatefulSets(
+	ctx volumeExpansionCtx,
+	expectedStatefulSets sset.StatefulSetList,
+	actualStatefulSets sset.StatefulSetList,
+	recreations map[string]statefulSetRecreation,
+) error {
+	for name, recreation := range recreations {
+		actual, exists := actualStatefulSets.GetByName(name)
+		switch {
+		case exists && actual.UID == recreation.UID:
+			log.Info("Deleting StatefulSet to update its volume claim templates",
+				"namespace", actual.Namespace, "statefulset_name", actual.Name)
+			uid := actual.UID
+			err := ctx.k8sClient.Delete(
+				&actual,
+				client.PropagationPolicy(metav1.DeletePropagationOrphan),
+				client.Preconditions{UID: &uid},
+			)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		case exists:
+			// the StatefulSet has been recreated
+			delete(recreations, name)
+		default:
+			expected, found := expectedStatefulSets.GetByName(name)
+			if !found {
+				// the NodeSet has been removed from the specification meanwhile
+				delete(recreations, name)
+				continue
+			}
+			log.Info("Recreating StatefulSet with expanded volume claim templates",
+				"namespace", expected.Namespace, "statefulset_name", expected.Name)
+			// replicas are adjusted later on, once the orphaned Pods are adopted
+			replicas := recreation.Replicas
+			nodespec.UpdateReplicas(&expected, &replicas)
+			_, err := sset.ReconcileStatefulSet(ctx.k8sClient, ctx.scheme, *ctx.es, expected, ctx.expectations)
+			if err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// expandClaims updates the storage request of the existing persistent volume claims of the given StatefulSet
+// to match the given volume claim templates. The storage classes of the claims must allow volume expansion.
+func expandClaims(c k8s.Client, statefulSet appsv1.StatefulSet, claims []corev1.PersistentVolumeClaim) error {
+	for _, claim := range claims {
+		storage := volume.StorageRequest(claim)
+		pvcs, err := claimsToExpand(c, statefulSet, claim)
+		if err != nil {
+			return err
+		}
+		for _, pvc := range pvcs {
+			log.Info("Expanding persistent volume claim",
+				"namespace", pvc.Namespace, "pvc_name", pvc.Name, "storage", storage.String())
+			if pvc.Spec.Resources.Requests == nil {
+				pvc.Spec.Resources.Requests = corev1.ResourceList{}
+			}
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = storage
+			if err := c.Update(&pvc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// claimsToExpand returns the existing persistent volume claims of the given StatefulSet, created from the given
+// volume claim template, that request less storage than the template.
+func claimsToExpand(c k8s.Client, statefulSet appsv1.StatefulSet, claim corev1.PersistentVolumeClaim) ([]corev1.PersistentVolumeClaim, error) {
+	storage := volume.StorageRequest(claim)
+	var pvcs []corev1.PersistentVolumeClaim
+	for _, podName := range sset.PodNames(statefulSet) {
+		var pvc corev1.PersistentVolumeClaim
+		pvcName := types.NamespacedName{Namespace: statefulSet.Namespace, Name: fmt.Sprintf("%s-%s", claim.Name, podName)}
+		if err := c.Get(pvcName, &pvc); err != nil {
+			if apierrors.IsNotFound(err) {
+				// will be created from the expected volume claim template
+				continue
+			}
+			return nil, err
+		}
+		if actualStorage := volume.StorageRequest(pvc); actualStorage.Cmp(storage) >= 0 {
+			continue
+		}
+		pvcs = append(pvcs, pvc)
+	}
+	return pvcs, nil
+}
+
+// unexpandableReason returns why the existing persistent volume claims of the given StatefulSet cannot be expanded
+// to match the given volume claim templates, or an empty string if their storage classes allow volume expansion.
+// Storage classes are cached in the given map, indexed by storage class name.
+func unexpandableReason(
+	c k8s.Client,
+	statefulSet appsv1.StatefulSet,
+	claims []corev1.PersistentVolumeClaim,
+	storageClasses map[string]*storagev1.StorageClass,
+) (string, error) {
+	for _, claim := range claims {
+		pvcs, err := claimsToExpand(c, statefulSet, claim)
+		if err != nil {
+			return "", err
+		}
+		for _, pvc := range pvcs {
+			var name string
+			if pvc.Spec.StorageClassName != nil {
+				name = *pvc.Spec.StorageClassName
+			}
+			sc, cached := storageClasses[name]
+			if !cached {
+				sc, err = getStorageClass(c, pvc.Spec.StorageClassName)
+				if err != nil {
+					return "", err
+				}
+				storageClasses[name] = sc
+			}
+			switch {
+			case sc == nil:
+				return fmt.Sprintf("no storage class found for persistent volume claim %s", pvc.Name), nil
+			case !volume.AllowsExpansion(*sc):
+				return fmt.Sprintf("storage class %s of persistent volume claim %s does not allow volume expansion", sc.Name, pvc.Name), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// getStorageClass returns the storage class with the given name, or the default storage class if not specified.
+// It returns nil if there is no such storage class.
+func getStorageClass(c k8s.Client, name *string) (*storagev1.StorageClass, error) {
+	if name != nil {
+		if *name == "" {
+			// claims explicitly without storage class are bound to pre-provisioned volumes
+			return nil, nil
+		}
+		var sc storagev1.StorageClass
+		if err := c.Get(types.NamespacedName{Name: *name}, &sc); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return &sc, nil
+	}
+	var storageClasses storagev1.StorageClassList
+	if err := c.List(&storageClasses); err != nil {
+		return nil, err
+	}
+	for i := range storageClasses.Items {
+		if volume.IsDefaultStorageClass(storageClasses.Items[i]) {
+			return &storageClasses.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// getRecreations returns the StatefulSets to recreate recorded in the annotations of the given Elasticsearch.
+func getRecreations(es v1beta1.Elasticsearch) (map[string]statefulSetRecreation, error) {
+	recreations := make(map[string]statefulSetRecreation)
+	serialized, exists := es.Annotations[RecreateStatefulSetsAnnotation]
+	if !exists {
+		return recreations, nil
+	}
+	if err := json.Unmarshal([]byte(serialized), &recreations); err != nil {
+		return nil, err
+	}
+	return recreations, nil
+}
+
+// setRecreations records the given StatefulSets to recreate in the annotations of the given Elasticsearch,
+// and updates it if they changed.
+func setRecreations(c k8s.Client, es *v1beta1.Elasticsearch, recreations map[string]statefulSetRecreation) error {
+	current, err := getRecreations(*es)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(current, recreations) {
+		return nil
+	}
+	if len(recreations) == 0 {
+		delete(es.Annotations, RecreateStatefulSetsAnnotation)
+		return c.Update(es)
+	}
+	serialized, err := json.Marshal(recreations)
+	if err != nil {
+		return err
+	}
+	if es.Annotations == nil {
+		es.Annotations = make(map[string]string, 1)
+	}
+	es.Annotations[RecreateStatefulSetsAnnotation] = string(serialized)
+	return c.Update(es)
+}
+
+// volumeExpansionStatus reports the StatefulSets being recreated, the given StatefulSets whose volumes cannot be
+// expanded, and the bound persistent volume claims of the actual StatefulSets whose capacity does not match
+// the storage request yet.
+func volumeExpansionStatus(
+	c k8s.Client,
+	es v1beta1.Elasticsearch,
+	actualStatefulSets sset.StatefulSetList,
+	unexpandable []v1beta1.UnexpandableStatefulSet,
+) (*v1beta1.VolumeExpansionStatus, error) {
+	status := v1beta1.VolumeExpansionStatus{UnexpandableStatefulSets: unexpandable}
+	recreations, err := getRecreations(es)
+	if err != nil {
+		return nil, err
+	}
+	for name := range recreations {
+		status.RecreatingStatefulSets = append(status.RecreatingStatefulSets, name)
+	}
+	sort.Strings(status.RecreatingStatefulSets)
+
+	var pvcs corev1.PersistentVolumeClaimList
+	ns := client.InNamespace(es.Namespace)
+	matchLabels := label.NewLabelSelectorForElasticsearch(es)
+	if err := c.List(&pvcs, ns, matchLabels); err != nil {
+		return nil, err
+	}
+	claimNames := stringsutil.SliceToMap(actualStatefulSets.PVCNames())
+	for _, pvc := range pvcs.Items {
+		if _, exists := claimNames[pvc.Name]; !exists || pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+		requested := volume.StorageRequest(pvc)
+		capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+		if capacity.Cmp(requested) >= 0 {
+			continue
+		}
+		status.PersistentVolumeClaims = append(status.PersistentVolumeClaims, v1beta1.PersistentVolumeClaimExpansion{
+			Name:                    pvc.Name,
+			Requested:               requested,
+			Capacity:                capacity,
+			FileSystemResizePending: volume.FileSystemResizePending(pvc),
+		})
+	}
+	sort.Slice(status.PersistentVolumeClaims, func(i, j int) bool {
+		return status.PersistentVolumeClaims[i].Name < status.PersistentVolumeClaims[j].Name
+	})
+
+	if len(status.RecreatingStatefulSets) == 0 && len(status.PersistentVolumeClaims) == 0 &&
+		len(status.UnexpandableStatefulSets) == 0 {
+		return nil, nil
+	}
+	return &status, nil
+}
+
+// fileSystemResizePending returns true if the file system of one of the expanded volumes of the given Pod
+// is resized once the Pod restarts.
+func fileSystemResizePending(c k8s.Client, pod corev1.Pod) (bool, error) {
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim == nil {
+			continue
+		}
+		var pvc corev1.PersistentVolumeClaim
+		err := c.Get(types.NamespacedName{Namespace: pod.Namespace, Name: v.PersistentVolumeClaim.ClaimName}, &pvc)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if volume.FileSystemResizePending(pvc) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,224 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func volumeExpansionTestES() v1beta1.Elasticsearch {
+	return v1beta1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: TestEsNamespace, Name: "es"}}
+}
+
+func volumeExpansionTestSset(storage string) appsv1.StatefulSet {
+	statefulSet := sset.TestSset{Namespace: TestEsNamespace, Name: "es-data", ClusterName: "es", Replicas: 2}.Build()
+	statefulSet.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{*volumeExpansionTestClaim("elasticsearch-data", storage)}
+	return statefulSet
+}
+
+func volumeExpansionTestClaim(name string, storage string) *corev1.PersistentVolumeClaim {
+	storageClassName := "standard"
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: TestEsNamespace,
+			Name:      name,
+			Labels:    map[string]string{label.ClusterNameLabelName: "es"},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+			},
+		},
+	}
+}
+
+func volumeExpansionTestStorageClass(allowExpansion bool) *storagev1.StorageClass {
+	return &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "standard"},
+		AllowVolumeExpansion: &allowExpansion,
+	}
+}
+
+func Test_handleVolumeExpansion(t *testing.T) {
+	require.NoError(t, v1beta1.AddToScheme(scheme.Scheme))
+
+	es := volumeExpansionTestES()
+	actual := volumeExpansionTestSset("1Gi")
+	actual.UID = "old-uid"
+	expected := volumeExpansionTestSset("2Gi")
+	c := k8s.WrapClient(fake.NewFakeClient(
+		&es,
+		&actual,
+		volumeExpansionTestStorageClass(true),
+		volumeExpansionTestClaim("elasticsearch-data-es-data-0", "1Gi"),
+		volumeExpansionTestClaim("elasticsearch-data-es-data-1", "1Gi"),
+	))
+	ctx := volumeExpansionCtx{
+		k8sClient:      c,
+		es:             &es,
+		scheme:         scheme.Scheme,
+		reconcileState: reconcile.NewState(es),
+	}
+
+	// the claims are expanded, and the StatefulSet is deleted
+	recreating, unexpandable, err := handleVolumeExpansion(ctx, nodespec.ResourcesList{{StatefulSet: expected}}, sset.StatefulSetList{actual})
+	require.NoError(t, err)
+	require.True(t, recreating)
+	require.Empty(t, unexpandable)
+	for _, name := range []string{"elasticsearch-data-es-data-0", "elasticsearch-data-es-data-1"} {
+		var pvc corev1.PersistentVolumeClaim
+		require.NoError(t, c.Get(types.NamespacedName{Namespace: TestEsNamespace, Name: name}, &pvc))
+		require.Equal(t, resource.MustParse("2Gi"), pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+	}
+	err = c.Get(k8s.ExtractNamespacedName(&actual), &appsv1.StatefulSet{})
+	require.True(t, apierrors.IsNotFound(err))
+	recreations, err := getRecreations(es)
+	require.NoError(t, err)
+	require.Equal(t, map[string]statefulSetRecreation{"es-data": {UID: "old-uid", Replicas: 2}}, recreations)
+
+	// the StatefulSet is recreated with the expanded claims, and the same replicas
+	scaledDown := *expected.DeepCopy()
+	replicas := int32(1)
+	scaledDown.Spec.Replicas = &replicas
+	recreating, _, err = handleVolumeExpansion(ctx, nodespec.ResourcesList{{StatefulSet: scaledDown}}, nil)
+	require.NoError(t, err)
+	require.True(t, recreating)
+	var recreated appsv1.StatefulSet
+	require.NoError(t, c.Get(k8s.ExtractNamespacedName(&actual), &recreated))
+	require.Equal(t, int32(2), sset.GetReplicas(recreated))
+	require.Equal(t, resource.MustParse("2Gi"), recreated.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage])
+
+	// the recreation is over once the new StatefulSet is observed
+	recreating, _, err = handleVolumeExpansion(ctx, nodespec.ResourcesList{{StatefulSet: scaledDown}}, sset.StatefulSetList{recreated})
+	require.NoError(t, err)
+	require.False(t, recreating)
+	var updatedES v1beta1.Elasticsearch
+	require.NoError(t, c.Get(k8s.ExtractNamespacedName(&es), &updatedES))
+	require.NotContains(t, updatedES.Annotations, RecreateStatefulSetsAnnotation)
+}
+
+func Test_handleVolumeExpansion_notExpandable(t *testing.T) {
+	require.NoError(t, v1beta1.AddToScheme(scheme.Scheme))
+
+	es := volumeExpansionTestES()
+	actual := volumeExpansionTestSset("1Gi")
+	c := k8s.WrapClient(fake.NewFakeClient(
+		&es,
+		&actual,
+		volumeExpansionTestStorageClass(false),
+		volumeExpansionTestClaim("elasticsearch-data-es-data-0", "1Gi"),
+	))
+	reconcileState := reconcile.NewState(es)
+	ctx := volumeExpansionCtx{k8sClient: c, es: &es, scheme: scheme.Scheme, reconcileState: reconcileState}
+
+	expected := nodespec.ResourcesList{{StatefulSet: volumeExpansionTestSset("2Gi")}}
+	recreating, unexpandable, err := handleVolumeExpansion(ctx, expected, sset.StatefulSetList{actual})
+	require.NoError(t, err)
+	require.False(t, recreating)
+	// the StatefulSet is reported, and keeps its current claim templates
+	require.Equal(t, []v1beta1.UnexpandableStatefulSet{{
+		Name:    "es-data",
+		Reason:  "storage class standard of persistent volume claim elasticsearch-data-es-data-0 does not allow volume expansion",
+		Storage: map[string]resource.Quantity{"elasticsearch-data": resource.MustParse("1Gi")},
+	}}, unexpandable)
+	require.Equal(t, actual.Spec.VolumeClaimTemplates, expected[0].StatefulSet.Spec.VolumeClaimTemplates)
+	// the StatefulSet is not deleted
+	require.NoError(t, c.Get(k8s.ExtractNamespacedName(&actual), &appsv1.StatefulSet{}))
+	require.NotContains(t, es.Annotations, RecreateStatefulSetsAnnotation)
+	evts, _ := reconcileState.Apply()
+	require.Len(t, evts, 1)
+
+	// no event is emitted once the StatefulSet is reported in the status
+	es.Status.VolumeExpansion = &v1beta1.VolumeExpansionStatus{UnexpandableStatefulSets: unexpandable}
+	reconcileState = reconcile.NewState(es)
+	ctx.reconcileState = reconcileState
+	expected = nodespec.ResourcesList{{StatefulSet: volumeExpansionTestSset("2Gi")}}
+	_, unexpandable, err = handleVolumeExpansion(ctx, expected, sset.StatefulSetList{actual})
+	require.NoError(t, err)
+	require.Len(t, unexpandable, 1)
+	evts, _ = reconcileState.Apply()
+	require.Empty(t, evts)
+}
+
+func Test_volumeExpansionStatus(t *testing.T) {
+	bound := func(pvc *corev1.PersistentVolumeClaim, capacity string, resizePending bool) *corev1.PersistentVolumeClaim {
+		pvc.Status.Phase = corev1.ClaimBound
+		pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+		if resizePending {
+			pvc.Status.Conditions = []corev1.PersistentVolumeClaimCondition{
+				{Type: corev1.PersistentVolumeClaimFileSystemResizePending, Status: corev1.ConditionTrue},
+			}
+		}
+		return pvc
+	}
+	es := volumeExpansionTestES()
+	actual := volumeExpansionTestSset("2Gi")
+
+	tests := []struct {
+		name string
+		pvcs []runtime.Object
+		want *v1beta1.VolumeExpansionStatus
+	}{
+		{
+			name: "expanded claims",
+			pvcs: []runtime.Object{
+				bound(volumeExpansionTestClaim("elasticsearch-data-es-data-0", "2Gi"), "2Gi", false),
+				bound(volumeExpansionTestClaim("elasticsearch-data-es-data-1", "2Gi"), "2Gi", false),
+			},
+			want: nil,
+		},
+		{
+			name: "claims being expanded",
+			pvcs: []runtime.Object{
+				bound(volumeExpansionTestClaim("elasticsearch-data-es-data-0", "2Gi"), "1Gi", true),
+				bound(volumeExpansionTestClaim("elasticsearch-data-es-data-1", "2Gi"), "1Gi", false),
+				// not bound yet
+				volumeExpansionTestClaim("elasticsearch-data-es-data-2", "2Gi"),
+			},
+			want: &v1beta1.VolumeExpansionStatus{
+				PersistentVolumeClaims: []v1beta1.PersistentVolumeClaimExpansion{
+					{
+						Name:                    "elasticsearch-data-es-data-0",
+						Requested:               resource.MustParse("2Gi"),
+						Capacity:                resource.MustParse("1Gi"),
+						FileSystemResizePending: true,
+					},
+					{
+						Name:      "elasticsearch-data-es-data-1",
+						Requested: resource.MustParse("2Gi"),
+						Capacity:  resource.MustParse("1Gi"),
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.WrapClient(fake.NewFakeClient(tt.pvcs...))
+			got, err := volumeExpansionStatus(c, es, sset.StatefulSetList{actual}, nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	elasticsearchv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates/http"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/expectations"
@@ -20,11 +21,13 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	commonversion "github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates/transport"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/driver"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	esname "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/observer"
 	esreconcile "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/validation"
 	esversion "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/version"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
@@ -51,7 +54,14 @@ var log = logf.Log.WithName(name)
 // on the Controller and Start it when the Manager is Started.
 // this is also called by cmd/main.go
 func Add(mgr manager.Manager, params operator.Parameters) error {
-	reconciler := newReconciler(mgr, params)
+	reconciler, err := newReconciler(mgr, params)
+	if err != nil {
+		return err
+	}
+	// stop observing clusters when the manager stops
+	if err := mgr.Add(reconciler.esObservers); err != nil {
+		return err
+	}
 	c, err := add(mgr, reconciler)
 	if err != nil {
 		return err
@@ -60,21 +70,27 @@ func Add(mgr manager.Manager, params operator.Parameters) error {
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileElasticsearch {
+func newReconciler(mgr manager.Manager, params operator.Parameters) (*ReconcileElasticsearch, error) {
 	client := k8s.WrapClient(mgr.GetClient())
+	podExecutor, err := k8s.NewPodExecutor(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
 	return &ReconcileElasticsearch{
-		Client:   client,
-		scheme:   mgr.GetScheme(),
-		recorder: mgr.GetEventRecorderFor(name),
+		Client:      client,
+		scheme:      mgr.GetScheme(),
+		recorder:    mgr.GetEventRecorderFor(name),
+		podExecutor: podExecutor,
 
 		esObservers: observer.NewManager(observer.DefaultSettings),
 
-		finalizers:     finalizer.NewHandler(client),
-		dynamicWatches: watches.NewDynamicWatches(),
-		expectations:   expectations.NewExpectations(),
+		finalizers:      finalizer.NewHandler(client),
+		dynamicWatches:  watches.NewDynamicWatches(),
+		expectations:    expectations.NewExpectations(),
+		delayedRestarts: driver.NewDelayedRestarts(),
 
 		Parameters: params,
-	}
+	}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -87,6 +103,7 @@ func addWatches(c controller.Controller, r *ReconcileElasticsearch) error {
 	// Watch for changes to Elasticsearch
 	if err := c.Watch(
 		&source.Kind{Type: &elasticsearchv1beta1.Elasticsearch{}}, &handler.EnqueueRequestForObject{},
+		operator.ResourceSelectorPredicate(r.ResourceSelector),
 	); err != nil {
 		return err
 	}
@@ -162,6 +179,9 @@ type ReconcileElasticsearch struct {
 	scheme   *runtime.Scheme
 	recorder record.EventRecorder
 
+	// podExecutor runs commands in the Elasticsearch Pods
+	podExecutor k8s.PodExecutor
+
 	esObservers *observer.Manager
 
 	finalizers finalizer.Handler
@@ -172,6 +192,9 @@ type ReconcileElasticsearch struct {
 	// by marking resources updates as expected, and skipping some operations if the cache is not up-to-date.
 	expectations *expectations.Expectations
 
+	// delayedRestarts keep track of the Pod restarts delayed during rolling upgrades, to report them only once.
+	delayedRestarts *driver.DelayedRestarts
+
 	// iteration is the number of times this controller has run its Reconcile method
 	iteration uint64
 }
@@ -180,6 +203,7 @@ type ReconcileElasticsearch struct {
 // what is in the Elasticsearch.Spec
 func (r *ReconcileElasticsearch) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	defer common.LogReconciliationRun(log, request, &r.iteration)()
+	defer reconciler.ObserveDuration(elasticsearchv1beta1.Kind, "reconcile")()
 
 	// Fetch the Elasticsearch instance
 	es := elasticsearchv1beta1.Elasticsearch{}
@@ -194,13 +218,21 @@ func (r *ReconcileElasticsearch) Reconcile(request reconcile.Request) (reconcile
 		return reconcile.Result{}, err
 	}
 
+	if !operator.MatchesResourceSelector(r.ResourceSelector, es.Labels) {
+		// the resource is reconciled by another operator: stop observing it
+		log.Info("Releasing resource not matching the resource selector", "namespace", es.Namespace, "es_name", es.Name)
+		certificates.ForgetExpiration(es.Kind, k8s.ExtractNamespacedName(&es))
+		driver.ForgetPodsPendingRestart(k8s.ExtractNamespacedName(&es))
+		return reconcile.Result{}, r.finalizers.Release(&es, r.finalizersFor(es)...)
+	}
+
 	if common.IsPaused(es.ObjectMeta) {
 		log.Info("Object is paused. Skipping reconciliation", "namespace", es.Namespace, "es_name", es.Name)
 		return common.PauseRequeue, nil
 	}
 
 	selector := map[string]string{label.ClusterNameLabelName: es.Name}
-	compat, err := annotation.ReconcileCompatibility(r.Client, &es, selector, r.OperatorInfo.BuildInfo.Version)
+	compat, err := annotation.ReconcileCompatibility(r.Client, &es, selector, r.OperatorInfo.BuildInfo.Version, r.ResourceSelector)
 	if err != nil {
 		k8s.EmitErrorEvent(r.recorder, err, &es, events.EventCompatCheckError, "Error during compatibility check: %v", err)
 		return reconcile.Result{}, err
@@ -215,7 +247,7 @@ func (r *ReconcileElasticsearch) Reconcile(request reconcile.Request) (reconcile
 		return reconcile.Result{}, err
 	}
 
-	state := esreconcile.NewState(es)
+	state := esreconcile.NewState(es).UpdateOperator(r.Parameters.Name)
 	results := r.internalReconcile(es, state)
 	err = r.updateStatus(es, state)
 	if err != nil {
@@ -241,6 +273,8 @@ func (r *ReconcileElasticsearch) internalReconcile(
 	if es.IsMarkedForDeletion() {
 		// resource will be deleted, nothing to reconcile
 		// pre-delete operations are handled by finalizers
+		certificates.ForgetExpiration(es.Kind, k8s.ExtractNamespacedName(&es))
+		driver.ForgetPodsPendingRestart(k8s.ExtractNamespacedName(&es))
 		return results
 	}
 
@@ -276,8 +310,10 @@ func (r *ReconcileElasticsearch) internalReconcile(
 		Client:             r.Client,
 		Scheme:             r.scheme,
 		Recorder:           r.recorder,
+		PodExecutor:        r.podExecutor,
 		Version:            *ver,
 		Expectations:       r.expectations,
+		DelayedRestarts:    r.delayedRestarts,
 		Observers:          r.esObservers,
 		DynamicWatches:     r.dynamicWatches,
 		SupportedVersions:  *supported,
@@ -309,5 +345,7 @@ func (r *ReconcileElasticsearch) finalizersFor(
 		r.esObservers.Finalizer(clusterName),
 		keystore.Finalizer(k8s.ExtractNamespacedName(&es), r.dynamicWatches, es.Kind),
 		http.DynamicWatchesFinalizer(r.dynamicWatches, es.Kind, es.Name, esname.ESNamer),
+		user.UserSecretsFinalizer(clusterName, r.dynamicWatches),
+		transport.TransportSecretsFinalizer(clusterName, r.dynamicWatches),
 	}
 }
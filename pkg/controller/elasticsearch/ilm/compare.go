@@ -0,0 +1,142 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package ilm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/maps"
+)
+
+const (
+	minAgeField      = "min_age"
+	actionsField     = "actions"
+	defaultMinAge    = "0ms"
+	indexSettingsKey = "index."
+)
+
+func expectedPolicy(p v1beta1.LifecyclePolicy) esclient.LifecyclePolicy {
+	return esclient.LifecyclePolicy{Phases: configData(p.Phases)}
+}
+
+func expectedTemplate(t v1beta1.IndexTemplate) esclient.IndexTemplate {
+	return esclient.IndexTemplate{
+		IndexPatterns: t.IndexPatterns,
+		Order:         t.Order,
+		Settings:      configData(t.Settings),
+		Mappings:      configData(t.Mappings),
+		Aliases:       configData(t.Aliases),
+	}
+}
+
+func configData(cfg *commonv1beta1.Config) map[string]interface{} {
+	if cfg == nil || cfg.Data == nil {
+		return map[string]interface{}{}
+	}
+	return cfg.Data
+}
+
+// policyEqual compares two policies. Elasticsearch sets defaults in the stored policies, such as the min_age of phases
+// which do not specify one, or options of some actions (for example delete_searchable_snapshot of the delete action
+// since 7.8), hence only the options specified in the expected policy are compared, along with the phases and the
+// actions they are made of.
+func policyEqual(expected, actual esclient.LifecyclePolicy) bool {
+	if len(expected.Phases) != len(actual.Phases) {
+		return false
+	}
+	for name, expectedPhase := range expected.Phases {
+		actualPhase, exists := actual.Phases[name]
+		if !exists {
+			return false
+		}
+		e, isMap := expectedPhase.(map[string]interface{})
+		a, isActualMap := actualPhase.(map[string]interface{})
+		if !isMap || !isActualMap {
+			if !reflect.DeepEqual(expectedPhase, actualPhase) {
+				return false
+			}
+			continue
+		}
+		if _, exists := e[minAgeField]; !exists {
+			e = withField(e, minAgeField, defaultMinAge)
+		}
+		if !sameKeys(e[actionsField], a[actionsField]) || !contains(e, a) {
+			return false
+		}
+	}
+	return true
+}
+
+func withField(m map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m)+1)
+	for k, v := range m {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
+
+// sameKeys returns true if both values have the same keys, values which are not maps having none.
+func sameKeys(expected, actual interface{}) bool {
+	e, _ := expected.(map[string]interface{})
+	a, _ := actual.(map[string]interface{})
+	if len(e) != len(a) {
+		return false
+	}
+	for k := range e {
+		if _, exists := a[k]; !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// contains returns true if all the options of the expected value are set to the same value in the actual one.
+// Values are compared based on their string representation.
+func contains(expected, actual interface{}) bool {
+	e, isMap := expected.(map[string]interface{})
+	if !isMap {
+		return fmt.Sprint(expected) == fmt.Sprint(actual)
+	}
+	a, isMap := actual.(map[string]interface{})
+	if !isMap {
+		return false
+	}
+	for k, v := range e {
+		actualValue, exists := a[k]
+		if !exists || !contains(v, actualValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// templateEqual compares two index templates. Elasticsearch returns all setting values as strings, prefixed
+// with `index.`, hence settings, as well as mappings and aliases, are compared based on their flattened
+// string representation.
+func templateEqual(expected, actual esclient.IndexTemplate) bool {
+	return reflect.DeepEqual(expected.IndexPatterns, actual.IndexPatterns) &&
+		expected.Order == actual.Order &&
+		reflect.DeepEqual(indexSettings(expected.Settings), indexSettings(actual.Settings)) &&
+		reflect.DeepEqual(maps.Flatten(expected.Mappings), maps.Flatten(actual.Mappings)) &&
+		reflect.DeepEqual(maps.Flatten(expected.Aliases), maps.Flatten(actual.Aliases))
+}
+
+func indexSettings(settings map[string]interface{}) map[string]string {
+	flat := maps.Flatten(settings)
+	out := make(map[string]string, len(flat))
+	for k, v := range flat {
+		if !strings.HasPrefix(k, indexSettingsKey) {
+			k = indexSettingsKey + k
+		}
+		out[k] = v
+	}
+	return out
+}
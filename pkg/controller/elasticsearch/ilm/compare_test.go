@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package ilm
+
+import (
+	"encoding/json"
+	"testing"
+
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/stretchr/testify/require"
+)
+
+// storedPolicy is the response of GET /_ilm/policy/logs in Elasticsearch 7.8, for the policy defined in Test_policyEqual
+const storedPolicy = `{
+	"logs": {
+		"version": 1,
+		"modified_date": "2020-06-24T09:12:54.216Z",
+		"policy": {
+			"phases": {
+				"hot": {
+					"min_age": "0ms",
+					"actions": {
+						"rollover": {"max_size": "50gb", "max_age": "30d"},
+						"set_priority": {"priority": 100}
+					}
+				},
+				"delete": {
+					"min_age": "90d",
+					"actions": {
+						"delete": {"delete_searchable_snapshot": true}
+					}
+				}
+			}
+		}
+	}
+}`
+
+func Test_policyEqual(t *testing.T) {
+	expected := esclient.LifecyclePolicy{Phases: map[string]interface{}{
+		"hot": map[string]interface{}{
+			"actions": map[string]interface{}{
+				"rollover":     map[string]interface{}{"max_size": "50gb", "max_age": "30d"},
+				"set_priority": map[string]interface{}{"priority": 100},
+			},
+		},
+		"delete": map[string]interface{}{
+			"min_age": "90d",
+			"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+		},
+	}}
+	stored := func(t *testing.T, modify func(phases map[string]interface{})) esclient.LifecyclePolicy {
+		var policies esclient.LifecyclePolicies
+		require.NoError(t, json.Unmarshal([]byte(storedPolicy), &policies))
+		policy := policies["logs"].Policy
+		if modify != nil {
+			modify(policy.Phases)
+		}
+		return policy
+	}
+	phase := func(phases map[string]interface{}, name string) map[string]interface{} {
+		return phases[name].(map[string]interface{})
+	}
+	actions := func(phases map[string]interface{}, name string) map[string]interface{} {
+		return phase(phases, name)["actions"].(map[string]interface{})
+	}
+
+	tests := []struct {
+		name   string
+		modify func(phases map[string]interface{})
+		want   bool
+	}{
+		{
+			name: "policy with default values set by Elasticsearch",
+			want: true,
+		},
+		{
+			name: "option modified",
+			modify: func(phases map[string]interface{}) {
+				actions(phases, "hot")["rollover"] = map[string]interface{}{"max_size": "10gb", "max_age": "30d"}
+			},
+			want: false,
+		},
+		{
+			name: "default min_age modified",
+			modify: func(phases map[string]interface{}) {
+				phase(phases, "hot")["min_age"] = "1d"
+			},
+			want: false,
+		},
+		{
+			name: "action added",
+			modify: func(phases map[string]interface{}) {
+				actions(phases, "delete")["wait_for_snapshot"] = map[string]interface{}{"policy": "nightly"}
+			},
+			want: false,
+		},
+		{
+			name: "action removed",
+			modify: func(phases map[string]interface{}) {
+				delete(actions(phases, "hot"), "set_priority")
+			},
+			want: false,
+		},
+		{
+			name: "phase added",
+			modify: func(phases map[string]interface{}) {
+				phases["warm"] = map[string]interface{}{"min_age": "7d", "actions": map[string]interface{}{}}
+			},
+			want: false,
+		},
+		{
+			name: "phase removed",
+			modify: func(phases map[string]interface{}) {
+				delete(phases, "delete")
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, policyEqual(expected, stored(t, tt.modify)))
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package ilm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("ilm")
+
+const (
+	policyKind   = "ILM policy"
+	templateKind = "index template"
+)
+
+// Reconcile creates or updates the ILM policies and index templates specified in the Elasticsearch spec.
+// Policies and templates modified outside of the operator since they were last applied are reverted or reported,
+// according to the drift policy. It returns the status to report in the Elasticsearch status, which is nil
+// if no index lifecycle is specified.
+// Policies and templates created outside of the operator are left untouched.
+func Reconcile(c esclient.Client, es v1beta1.Elasticsearch, recorder *events.Recorder) (*v1beta1.IndexLifecycleStatus, error) {
+	spec := es.Spec.IndexLifecycle
+	if spec == nil {
+		return nil, nil
+	}
+	previous := v1beta1.IndexLifecycleStatus{}
+	if es.Status.IndexLifecycle != nil {
+		previous = *es.Status.IndexLifecycle
+	}
+	r := reconciler{
+		recorder:    recorder,
+		driftPolicy: spec.DriftPolicyOrDefault(),
+		previous:    previous,
+		status: v1beta1.IndexLifecycleStatus{
+			AppliedPolicies:  map[string]string{},
+			AppliedTemplates: map[string]string{},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), esclient.DefaultReqTimeout)
+	defer cancel()
+
+	// policies first, since templates may reference them
+	if err := r.reconcilePolicies(ctx, c, spec.Policies); err != nil {
+		return nil, err
+	}
+	if err := r.reconcileTemplates(ctx, c, spec.Templates); err != nil {
+		return nil, err
+	}
+	return &r.status, nil
+}
+
+type reconciler struct {
+	recorder    *events.Recorder
+	driftPolicy v1beta1.DriftPolicy
+	previous    v1beta1.IndexLifecycleStatus
+	status      v1beta1.IndexLifecycleStatus
+}
+
+func (r *reconciler) reconcilePolicies(ctx context.Context, c esclient.Client, policies []v1beta1.LifecyclePolicy) error {
+	if len(policies) == 0 {
+		return nil
+	}
+	current, err := c.GetLifecyclePolicies(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range policies {
+		expected := expectedPolicy(p)
+		actual, exists := current[p.Name]
+		specHash := hash.HashObject(p)
+		drifted, err := r.reconcileOne(
+			policyKind, p.Name, specHash, r.previous.AppliedPolicies[p.Name],
+			exists && policyEqual(expected, actual.Policy),
+			func() error { return c.UpdateLifecyclePolicy(ctx, p.Name, expected) },
+		)
+		if err != nil {
+			return err
+		}
+		r.status.AppliedPolicies[p.Name] = specHash
+		if drifted {
+			r.status.DriftedPolicies = append(r.status.DriftedPolicies, p.Name)
+		}
+	}
+	sort.Strings(r.status.DriftedPolicies)
+	return nil
+}
+
+func (r *reconciler) reconcileTemplates(ctx context.Context, c esclient.Client, templates []v1beta1.IndexTemplate) error {
+	if len(templates) == 0 {
+		return nil
+	}
+	current, err := c.GetIndexTemplates(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range templates {
+		expected := expectedTemplate(t)
+		actual, exists := current[t.Name]
+		specHash := hash.HashObject(t)
+		drifted, err := r.reconcileOne(
+			templateKind, t.Name, specHash, r.previous.AppliedTemplates[t.Name],
+			exists && templateEqual(expected, actual),
+			func() error { return c.UpdateIndexTemplate(ctx, t.Name, expected) },
+		)
+		if err != nil {
+			return err
+		}
+		r.status.AppliedTemplates[t.Name] = specHash
+		if drifted {
+			r.status.DriftedTemplates = append(r.status.DriftedTemplates, t.Name)
+		}
+	}
+	sort.Strings(r.status.DriftedTemplates)
+	return nil
+}
+
+// reconcileOne updates a policy or template that is not in sync with its spec. A policy or template that is not in
+// sync although its spec did not change since it was last applied has been modified outside of the operator:
+// it is only updated if the drift policy allows it. It returns true if the drift is left in place.
+func (r *reconciler) reconcileOne(kind, name, specHash, appliedHash string, inSync bool, update func() error) (bool, error) {
+	if inSync {
+		return false, nil
+	}
+	if specHash == appliedHash {
+		if r.driftPolicy == v1beta1.ReportDriftPolicy {
+			if !r.wasDrifted(kind, name) {
+				r.recorder.AddEvent(
+					corev1.EventTypeWarning,
+					events.EventReasonUnexpected,
+					fmt.Sprintf("%s %s was modified outside of the operator", kind, name),
+				)
+			}
+			return true, nil
+		}
+		r.recorder.AddEvent(
+			corev1.EventTypeWarning,
+			events.EventReasonUnexpected,
+			fmt.Sprintf("Reverting %s %s modified outside of the operator", kind, name),
+		)
+	}
+	log.Info("Updating "+kind, "name", name)
+	return false, update()
+}
+
+func (r *reconciler) wasDrifted(kind, name string) bool {
+	drifted := r.previous.DriftedPolicies
+	if kind == templateKind {
+		drifted = r.previous.DriftedTemplates
+	}
+	for _, n := range drifted {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
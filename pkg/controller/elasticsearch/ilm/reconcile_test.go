@@ -0,0 +1,151 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package ilm
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	phases := commonv1beta1.NewConfig(map[string]interface{}{
+		"hot":    map[string]interface{}{"actions": map[string]interface{}{"rollover": map[string]interface{}{"max_size": "50gb"}}},
+		"delete": map[string]interface{}{"min_age": "30d", "actions": map[string]interface{}{"delete": map[string]interface{}{}}},
+	})
+	policy := v1beta1.LifecyclePolicy{Name: "logs", Phases: &phases}
+	settings := commonv1beta1.NewConfig(map[string]interface{}{"number_of_shards": 1, "index.lifecycle.name": "logs"})
+	template := v1beta1.IndexTemplate{Name: "logs", IndexPatterns: []string{"logs-*"}, Settings: &settings}
+	// Elasticsearch sets the default min_age, and returns all settings as strings
+	stored := map[string]string{
+		"/_ilm/policy": `{"logs":{"version":1,"policy":{"phases":{` +
+			`"hot":{"min_age":"0ms","actions":{"rollover":{"max_size":"50gb"}}},` +
+			`"delete":{"min_age":"30d","actions":{"delete":{}}}}}}}`,
+		"/_template": `{"logs":{"order":0,"index_patterns":["logs-*"],` +
+			`"settings":{"index":{"lifecycle":{"name":"logs"},"number_of_shards":"1"}},"mappings":{},"aliases":{}}}`,
+	}
+	modified := map[string]string{
+		"/_ilm/policy": `{"logs":{"version":2,"policy":{"phases":{"hot":{"min_age":"0ms","actions":{}}}}}}`,
+		"/_template":   `{"logs":{"order":0,"index_patterns":["other-*"],"settings":{},"mappings":{},"aliases":{}}}`,
+	}
+	wantPolicyUpdate := `PUT /_ilm/policy/logs {"policy":{"phases":{"delete":{"actions":{"delete":{}},"min_age":"30d"},"hot":{"actions":{"rollover":{"max_size":"50gb"}}}}}}`
+	wantTemplateUpdate := `PUT /_template/logs {"index_patterns":["logs-*"],"order":0,"settings":{"index.lifecycle.name":"logs","number_of_shards":1},"mappings":{},"aliases":{}}`
+	applied := &v1beta1.IndexLifecycleStatus{
+		AppliedPolicies:  map[string]string{"logs": hash.HashObject(policy)},
+		AppliedTemplates: map[string]string{"logs": hash.HashObject(template)},
+	}
+	appliedStatus := func(driftedPolicies, driftedTemplates []string) *v1beta1.IndexLifecycleStatus {
+		return &v1beta1.IndexLifecycleStatus{
+			AppliedPolicies:  applied.AppliedPolicies,
+			AppliedTemplates: applied.AppliedTemplates,
+			DriftedPolicies:  driftedPolicies,
+			DriftedTemplates: driftedTemplates,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		spec         *v1beta1.IndexLifecycleSpec
+		status       *v1beta1.IndexLifecycleStatus
+		current      map[string]string
+		wantRequests []string
+		wantStatus   *v1beta1.IndexLifecycleStatus
+		wantEvents   int
+	}{
+		{
+			name:       "no index lifecycle",
+			spec:       nil,
+			current:    stored,
+			wantStatus: nil,
+		},
+		{
+			name:         "new policy and template",
+			spec:         &v1beta1.IndexLifecycleSpec{Policies: []v1beta1.LifecyclePolicy{policy}, Templates: []v1beta1.IndexTemplate{template}},
+			current:      map[string]string{"/_ilm/policy": `{}`, "/_template": `{}`},
+			wantRequests: []string{wantPolicyUpdate, wantTemplateUpdate},
+			wantStatus:   appliedStatus(nil, nil),
+		},
+		{
+			name:       "policy and template in sync",
+			spec:       &v1beta1.IndexLifecycleSpec{Policies: []v1beta1.LifecyclePolicy{policy}, Templates: []v1beta1.IndexTemplate{template}},
+			status:     applied,
+			current:    stored,
+			wantStatus: appliedStatus(nil, nil),
+		},
+		{
+			name:         "policy and template updated in the spec",
+			spec:         &v1beta1.IndexLifecycleSpec{Policies: []v1beta1.LifecyclePolicy{policy}, Templates: []v1beta1.IndexTemplate{template}},
+			status:       &v1beta1.IndexLifecycleStatus{AppliedPolicies: map[string]string{"logs": "previous"}},
+			current:      modified,
+			wantRequests: []string{wantPolicyUpdate, wantTemplateUpdate},
+			wantStatus:   appliedStatus(nil, nil),
+		},
+		{
+			name:         "policy and template modified outside of the operator: revert",
+			spec:         &v1beta1.IndexLifecycleSpec{Policies: []v1beta1.LifecyclePolicy{policy}, Templates: []v1beta1.IndexTemplate{template}},
+			status:       applied,
+			current:      modified,
+			wantRequests: []string{wantPolicyUpdate, wantTemplateUpdate},
+			wantStatus:   appliedStatus(nil, nil),
+			wantEvents:   2,
+		},
+		{
+			name: "policy and template modified outside of the operator: report",
+			spec: &v1beta1.IndexLifecycleSpec{
+				Policies:    []v1beta1.LifecyclePolicy{policy},
+				Templates:   []v1beta1.IndexTemplate{template},
+				DriftPolicy: v1beta1.ReportDriftPolicy,
+			},
+			status:     applied,
+			current:    modified,
+			wantStatus: appliedStatus([]string{"logs"}, []string{"logs"}),
+			wantEvents: 2,
+		},
+		{
+			name: "drift already reported",
+			spec: &v1beta1.IndexLifecycleSpec{
+				Policies:    []v1beta1.LifecyclePolicy{policy},
+				Templates:   []v1beta1.IndexTemplate{template},
+				DriftPolicy: v1beta1.ReportDriftPolicy,
+			},
+			status:     appliedStatus([]string{"logs"}, []string{"logs"}),
+			current:    modified,
+			wantStatus: appliedStatus([]string{"logs"}, []string{"logs"}),
+			wantEvents: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			c := esclient.NewMockClient(version.MustParse("7.4.0"), func(req *http.Request) *http.Response {
+				if req.Method == http.MethodGet {
+					return esclient.NewMockResponse(200, req, tt.current[req.URL.Path])
+				}
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+				requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+				return esclient.NewMockResponse(200, req, "{}")
+			})
+			es := v1beta1.Elasticsearch{
+				Spec:   v1beta1.ElasticsearchSpec{IndexLifecycle: tt.spec},
+				Status: v1beta1.ElasticsearchStatus{IndexLifecycle: tt.status},
+			}
+			recorder := events.NewRecorder()
+			status, err := Reconcile(c, es, recorder)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRequests, requests)
+			require.Equal(t, tt.wantStatus, status)
+			require.Len(t, recorder.Events(), tt.wantEvents)
+		})
+	}
+}
@@ -7,6 +7,7 @@ package initcontainer
 import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -18,10 +19,11 @@ func NewInitContainers(
 	elasticsearchImage string,
 	transportCertificatesVolume volume.SecretVolume,
 	clusterName string,
+	nodeAttributes nodeattr.Attributes,
 	keystoreResources *keystore.Resources,
 ) ([]corev1.Container, error) {
 	var containers []corev1.Container
-	prepareFsContainer, err := NewPrepareFSInitContainer(elasticsearchImage, transportCertificatesVolume, clusterName)
+	prepareFsContainer, err := NewPrepareFSInitContainer(elasticsearchImage, transportCertificatesVolume, clusterName, nodeAttributes)
 	if err != nil {
 		return nil, err
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,6 +40,7 @@ func TestNewInitContainers(t *testing.T) {
 				tt.args.elasticsearchImage,
 				volume.SecretVolume{},
 				"clustername",
+				nil,
 				tt.args.keystoreResources,
 			)
 			assert.NoError(t, err)
@@ -46,3 +48,15 @@ func TestNewInitContainers(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPrepareFSInitContainer_NodeAttributes(t *testing.T) {
+	container, err := NewPrepareFSInitContainer("es-image", volume.SecretVolume{}, "clustername", nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, container.VolumeMounts, nodeattr.AnnotationsVolumeMount)
+
+	attributes := nodeattr.Attributes{nodeattr.ZoneAttribute: "zone-label"}
+	container, err = NewPrepareFSInitContainer("es-image", volume.SecretVolume{}, "clustername", attributes)
+	assert.NoError(t, err)
+	assert.Contains(t, container.VolumeMounts, nodeattr.AnnotationsVolumeMount)
+	assert.Contains(t, container.Env, attributes.RequiredAnnotationsEnvVar())
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	esvolume "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/volume"
@@ -87,6 +88,7 @@ func NewPrepareFSInitContainer(
 	imageName string,
 	transportCertificatesVolume volume.SecretVolume,
 	clusterName string,
+	nodeAttributes nodeattr.Attributes,
 ) (corev1.Container, error) {
 	// we mount the certificates to a location outside of the default config directory because the prepare-fs script
 	// will attempt to move all the files under the configuration directory to a different volume, and it should not
@@ -119,6 +121,12 @@ func NewPrepareFSInitContainer(
 		),
 	}
 
+	if len(nodeAttributes) > 0 {
+		// wait for the operator to annotate the Pod with the node attributes
+		container.Env = append(container.Env, nodeAttributes.RequiredAnnotationsEnvVar())
+		container.VolumeMounts = append(container.VolumeMounts, nodeattr.AnnotationsVolumeMount)
+	}
+
 	return container, nil
 }
 
@@ -142,5 +150,6 @@ func RenderPrepareFsScript() (string, error) {
 			esvolume.NodeTransportCertificateCertFile,
 		),
 		TransportCertificatesSecretVolumeMountPath: esvolume.TransportCertificatesSecretVolumeMountPath,
+		PodAnnotationsFilePath:                     nodeattr.AnnotationsFilePath,
 	})
 }
@@ -8,6 +8,8 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 )
 
 // TemplateParams are the parameters manipulated in the scriptTemplate
@@ -33,6 +35,10 @@ type TemplateParams struct {
 	// TransportCertificatesSecretVolumeMountPath is the path to the volume in the es container that contains the
 	// transport certificates.
 	TransportCertificatesSecretVolumeMountPath string
+
+	// PodAnnotationsFilePath is the path to the file exposing the Pod annotations in the init container, used to
+	// wait for the node attributes annotations.
+	PodAnnotationsFilePath string
 }
 
 // RenderScriptTemplate renders scriptTemplate using the given TemplateParams
@@ -163,6 +169,21 @@ var scriptTemplate = template.Must(template.New("").Parse(
 
 	echo "Certs linking duration: $(duration $ln_start) sec."
 
+	######################
+	#  Node attributes   #
+	######################
+
+	# wait for the operator to annotate the pod with the attributes of its Kubernetes node
+	attrs_start=$(date +%s)
+	for annotation in ${` + nodeattr.RequiredAnnotationsEnvVar + `:-}; do
+		echo "waiting for the pod annotation ${annotation}"
+		while ! grep -qs "^${annotation}=" {{ .PodAnnotationsFilePath }}
+		do
+			sleep 1
+		done
+	done
+	echo "Node attributes wait duration: $(duration $attrs_start) sec."
+
 	######################
 	#         End        #
 	######################
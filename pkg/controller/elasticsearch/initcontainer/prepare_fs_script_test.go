@@ -34,6 +34,16 @@ func TestRenderScriptTemplate(t *testing.T) {
 				"ln -sf /secrets/users /usr/share/elasticsearch/users",
 			},
 		},
+		{
+			name: "Wait for node attributes annotations",
+			params: TemplateParams{
+				PodAnnotationsFilePath: "/mnt/elastic-internal/pod-annotations/annotations",
+			},
+			wantSubstr: []string{
+				"for annotation in ${REQUIRED_POD_ANNOTATIONS:-}; do",
+				`grep -qs "^${annotation}=" /mnt/elastic-internal/pod-annotations/annotations`,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -32,7 +32,7 @@ const (
 
 	// ConfigHashLabelName is a label used to store a hash of the Elasticsearch configuration.
 	ConfigHashLabelName = "elasticsearch.k8s.elastic.co/config-hash"
-	// SecureSettingsHashLabelName is a label used to store a hash of the Elasticsearch secure settings secret.
+	// SecureSettingsHashLabelName is a label used to store a hash of the Elasticsearch secure settings that cannot be reloaded.
 	SecureSettingsHashLabelName = "elasticsearch.k8s.elastic.co/secure-settings-hash"
 
 	// NodeTypesMasterLabelName is a label set to true on nodes with the master role
@@ -84,3 +84,44 @@ func MigrateData(allocationSetter esclient.AllocationSetter, leavingNodes []stri
 	// update allocation exclusions
 	return allocationSetter.ExcludeFromShardAllocation(exclusions)
 }
+
+// IsReplicatedInOtherZones returns true if every shard held by the given nodes has a started copy on a node of
+// another zone, in which case the nodes can be removed without data loss. Zones are indexed by node name.
+// This is expected when shard allocation awareness is enabled on the zone attribute.
+func IsReplicatedInOtherZones(shards client.Shards, nodeNames []string, zones map[string]string) bool {
+	startedByShard := make(map[string][]client.Shard)
+	for _, shard := range shards {
+		if shard.IsStarted() {
+			startedByShard[shard.Key()] = append(startedByShard[shard.Key()], shard)
+		}
+	}
+	for _, nodeName := range nodeNames {
+		zone, known := zones[nodeName]
+		if !known {
+			return false
+		}
+		for _, shard := range shards {
+			if shard.NodeName != nodeName {
+				continue
+			}
+			if !shard.IsStarted() {
+				// relocating or initializing shard copy
+				return false
+			}
+			if !hasCopyInOtherZone(startedByShard[shard.Key()], zone, zones) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func hasCopyInOtherZone(copies []client.Shard, zone string, zones map[string]string) bool {
+	for _, other := range copies {
+		otherZone, known := zones[other.NodeName]
+		if known && otherZone != zone {
+			return true
+		}
+	}
+	return false
+}
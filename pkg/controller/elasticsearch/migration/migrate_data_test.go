@@ -166,3 +166,78 @@ func TestMigrateData(t *testing.T) {
 		})
 	}
 }
+
+func TestIsReplicatedInOtherZones(t *testing.T) {
+	zones := map[string]string{"A": "zone-a", "B": "zone-a", "C": "zone-b", "D": "zone-c"}
+	tests := []struct {
+		name      string
+		shards    client.Shards
+		nodeNames []string
+		zones     map[string]string
+		want      bool
+	}{
+		{
+			name:      "no shards",
+			nodeNames: []string{"A", "B"},
+			zones:     zones,
+			want:      true,
+		},
+		{
+			name: "all shards have a started copy in another zone",
+			shards: client.Shards{
+				{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "A"},
+				{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "C"},
+				{Index: "index-1", Shard: "1", State: client.STARTED, NodeName: "B"},
+				{Index: "index-1", Shard: "1", State: client.STARTED, NodeName: "D"},
+			},
+			nodeNames: []string{"A", "B"},
+			zones:     zones,
+			want:      true,
+		},
+		{
+			name: "only copy in another node of the same zone",
+			shards: client.Shards{
+				{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "A"},
+				{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "B"},
+			},
+			nodeNames: []string{"A"},
+			zones:     zones,
+			want:      false,
+		},
+		{
+			name: "copy in another zone is initializing",
+			shards: client.Shards{
+				{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "A"},
+				{Index: "index-1", Shard: "0", State: client.INITIALIZING, NodeName: "C"},
+			},
+			nodeNames: []string{"A"},
+			zones:     zones,
+			want:      false,
+		},
+		{
+			name: "shard relocating away from the node",
+			shards: client.Shards{
+				{Index: "index-1", Shard: "0", State: client.RELOCATING, NodeName: "A"},
+				{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "C"},
+			},
+			nodeNames: []string{"A"},
+			zones:     zones,
+			want:      false,
+		},
+		{
+			name: "unknown zone",
+			shards: client.Shards{
+				{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "A"},
+				{Index: "index-1", Shard: "0", State: client.STARTED, NodeName: "C"},
+			},
+			nodeNames: []string{"A"},
+			zones:     map[string]string{"C": "zone-b"},
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsReplicatedInOtherZones(tt.shards, tt.nodeNames, tt.zones))
+		})
+	}
+}
@@ -21,14 +21,18 @@ const (
 	configSecretSuffix                = "config"
 	secureSettingsSecretSuffix        = "secure-settings"
 	httpServiceSuffix                 = "http"
+	transportServiceSuffix            = "transport"
 	elasticUserSecretSuffix           = "elastic-user"
 	xpackFileRealmSecretSuffix        = "xpack-file-realm"
 	internalUsersSecretSuffix         = "internal-users"
+	previousElasticUserSecretSuffix   = "elastic-user-previous"
+	previousInternalUsersSuffix       = "internal-users-previous"
 	unicastHostsConfigMapSuffix       = "unicast-hosts"
 	licenseSecretSuffix               = "license"
 	defaultPodDisruptionBudget        = "default"
 	scriptsConfigMapSuffix            = "scripts"
 	transportCertificatesSecretSuffix = "transport-certificates"
+	transportCertsIssuedSecretSuffix  = "transport-certs-issued"
 
 	controllerRevisionHashLen = 10
 )
@@ -41,14 +45,18 @@ var (
 		configSecretSuffix,
 		secureSettingsSecretSuffix,
 		httpServiceSuffix,
+		transportServiceSuffix,
 		elasticUserSecretSuffix,
 		xpackFileRealmSecretSuffix,
 		internalUsersSecretSuffix,
+		previousElasticUserSecretSuffix,
+		previousInternalUsersSuffix,
 		unicastHostsConfigMapSuffix,
 		licenseSecretSuffix,
 		defaultPodDisruptionBudget,
 		scriptsConfigMapSuffix,
 		transportCertificatesSecretSuffix,
+		transportCertsIssuedSecretSuffix,
 	}
 )
 
@@ -107,10 +115,21 @@ func TransportCertificatesSecret(esName string) string {
 	return ESNamer.Suffix(esName, transportCertificatesSecretSuffix)
 }
 
+// TransportCertsIssuedSecret returns the name of the secret in which cert-manager issues the transport certificate
+// shared by all the nodes.
+func TransportCertsIssuedSecret(esName string) string {
+	return ESNamer.Suffix(esName, transportCertsIssuedSecretSuffix)
+}
+
 func HTTPService(esName string) string {
 	return ESNamer.Suffix(esName, httpServiceSuffix)
 }
 
+// TransportService returns the name of the headless service targeting the transport port of all the nodes.
+func TransportService(esName string) string {
+	return ESNamer.Suffix(esName, transportServiceSuffix)
+}
+
 func ElasticUserSecret(esName string) string {
 	return ESNamer.Suffix(esName, elasticUserSecretSuffix)
 }
@@ -123,6 +142,16 @@ func InternalUsersSecret(esName string) string {
 	return ESNamer.Suffix(esName, internalUsersSecretSuffix)
 }
 
+// PreviousElasticUserSecret returns the name of the secret retaining the elastic user password replaced by a rotation.
+func PreviousElasticUserSecret(esName string) string {
+	return ESNamer.Suffix(esName, previousElasticUserSecretSuffix)
+}
+
+// PreviousInternalUsersSecret returns the name of the secret retaining the internal users passwords replaced by a rotation.
+func PreviousInternalUsersSecret(esName string) string {
+	return ESNamer.Suffix(esName, previousInternalUsersSuffix)
+}
+
 // UnicastHostsConfigMap returns the name of the ConfigMap that holds the list of seed nodes for a given cluster.
 func UnicastHostsConfigMap(esName string) string {
 	return ESNamer.Suffix(esName, unicastHostsConfigMapSuffix)
@@ -0,0 +1,110 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package nodeattr
+
+import (
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("nodeattr")
+
+// MissingLabel describes a Kubernetes node which does not have a label required to set a node attribute.
+type MissingLabel struct {
+	Pod   string
+	Node  string
+	Label string
+}
+
+// AnnotateClusterPods annotates the Pods of each NodeSet of the given cluster with their node attributes.
+func AnnotateClusterPods(c k8s.Client, es v1beta1.Elasticsearch, pods []corev1.Pod) ([]MissingLabel, error) {
+	var missingLabels []MissingLabel
+	for _, nodeSet := range es.Spec.NodeSets {
+		attributes := For(es, nodeSet)
+		if len(attributes) == 0 {
+			continue
+		}
+		statefulSetName := name.StatefulSet(es.Name, nodeSet.Name)
+		var nodeSetPods []corev1.Pod
+		for _, pod := range pods {
+			if pod.Labels[label.StatefulSetNameLabelName] == statefulSetName {
+				nodeSetPods = append(nodeSetPods, pod)
+			}
+		}
+		missing, err := AnnotatePods(c, nodeSetPods, attributes)
+		missingLabels = append(missingLabels, missing...)
+		if err != nil {
+			return missingLabels, err
+		}
+	}
+	return missingLabels, nil
+}
+
+// AnnotatePods annotates the scheduled Pods with the value of the given attributes, read from the labels
+// of the Kubernetes node they are scheduled on. Annotations are only set once, since a Pod never moves to another node.
+// Pods scheduled on nodes missing some labels are not annotated, and are returned.
+func AnnotatePods(c k8s.Client, pods []corev1.Pod, attributes Attributes) ([]MissingLabel, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	var missingLabels []MissingLabel
+	nodes := make(map[string]corev1.Node)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil || isAnnotated(pod, attributes) {
+			continue
+		}
+		node, cached := nodes[pod.Spec.NodeName]
+		if !cached {
+			if err := c.Get(types.NamespacedName{Name: pod.Spec.NodeName}, &node); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			nodes[pod.Spec.NodeName] = node
+		}
+
+		annotations := make(map[string]string, len(attributes))
+		for _, name := range attributes.Names() {
+			value, exists := node.Labels[attributes[name]]
+			if !exists {
+				missingLabels = append(missingLabels, MissingLabel{Pod: pod.Name, Node: node.Name, Label: attributes[name]})
+				break
+			}
+			annotations[Annotation(name)] = value
+		}
+		if len(annotations) != len(attributes) {
+			continue
+		}
+
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string, len(annotations))
+		}
+		for k, v := range annotations {
+			pod.Annotations[k] = v
+		}
+		log.V(1).Info("Annotating pod with node attributes", "namespace", pod.Namespace, "pod_name", pod.Name, "annotations", annotations)
+		pod := pod
+		if err := c.Update(&pod); err != nil {
+			return missingLabels, err
+		}
+	}
+	return missingLabels, nil
+}
+
+func isAnnotated(pod corev1.Pod, attributes Attributes) bool {
+	for name := range attributes {
+		if _, exists := Value(pod, name); !exists {
+			return false
+		}
+	}
+	return true
+}
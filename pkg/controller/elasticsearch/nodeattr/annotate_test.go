@@ -0,0 +1,128 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package nodeattr
+
+import (
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAnnotatePods(t *testing.T) {
+	attributes := Attributes{ZoneAttribute: "zone-label"}
+	node := func(name string, labels map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	pod := func(name, nodeName string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Annotations: annotations},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+	tests := []struct {
+		name            string
+		objects         []runtime.Object
+		pods            []*corev1.Pod
+		wantAnnotations map[string]map[string]string
+		wantMissing     []MissingLabel
+	}{
+		{
+			name: "annotate scheduled pods",
+			objects: []runtime.Object{
+				node("node-1", map[string]string{"zone-label": "zone-a"}),
+				node("node-2", map[string]string{"zone-label": "zone-b"}),
+			},
+			pods: []*corev1.Pod{pod("a", "node-1", nil), pod("b", "node-2", nil), pod("c", "", nil)},
+			wantAnnotations: map[string]map[string]string{
+				"a": {Annotation(ZoneAttribute): "zone-a"},
+				"b": {Annotation(ZoneAttribute): "zone-b"},
+				"c": nil,
+			},
+		},
+		{
+			name:    "do not update already annotated pods",
+			objects: []runtime.Object{node("node-1", map[string]string{"zone-label": "zone-b"})},
+			pods:    []*corev1.Pod{pod("a", "node-1", map[string]string{Annotation(ZoneAttribute): "zone-a"})},
+			wantAnnotations: map[string]map[string]string{
+				"a": {Annotation(ZoneAttribute): "zone-a"},
+			},
+		},
+		{
+			name:            "node without the label",
+			objects:         []runtime.Object{node("node-1", nil)},
+			pods:            []*corev1.Pod{pod("a", "node-1", nil)},
+			wantAnnotations: map[string]map[string]string{"a": nil},
+			wantMissing:     []MissingLabel{{Pod: "a", Node: "node-1", Label: "zone-label"}},
+		},
+		{
+			name:            "node not found",
+			pods:            []*corev1.Pod{pod("a", "node-1", nil)},
+			wantAnnotations: map[string]map[string]string{"a": nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := tt.objects
+			pods := make([]corev1.Pod, 0, len(tt.pods))
+			for _, p := range tt.pods {
+				objects = append(objects, p)
+				pods = append(pods, *p)
+			}
+			c := k8s.WrapClient(fake.NewFakeClient(objects...))
+			missing, err := AnnotatePods(c, pods, attributes)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantMissing, missing)
+			for name, want := range tt.wantAnnotations {
+				var actual corev1.Pod
+				require.NoError(t, c.Get(types.NamespacedName{Namespace: "ns", Name: name}, &actual))
+				require.Equal(t, want, actual.Annotations)
+			}
+		})
+	}
+}
+
+func TestAnnotateClusterPods(t *testing.T) {
+	es := v1beta1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: v1beta1.ElasticsearchSpec{NodeSets: []v1beta1.NodeSet{
+			{Name: "hot", NodeAttributes: []v1beta1.NodeAttribute{{Name: "rack", NodeLabel: "rack-label"}}},
+			{Name: "warm"},
+		}},
+	}
+	podInStatefulSet := func(podName, statefulSet string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      podName,
+				Labels:    map[string]string{label.StatefulSetNameLabelName: statefulSet},
+			},
+			Spec: corev1.PodSpec{NodeName: "node-1"},
+		}
+	}
+	hot := podInStatefulSet("hot-0", name.StatefulSet(es.Name, "hot"))
+	warm := podInStatefulSet("warm-0", name.StatefulSet(es.Name, "warm"))
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"rack-label": "rack-1"}}}
+	c := k8s.WrapClient(fake.NewFakeClient(node, hot, warm))
+
+	missing, err := AnnotateClusterPods(c, es, []corev1.Pod{*hot, *warm})
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	var actual corev1.Pod
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: "ns", Name: "hot-0"}, &actual))
+	require.Equal(t, map[string]string{Annotation("rack"): "rack-1"}, actual.Annotations)
+	var actualWarm corev1.Pod
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: "ns", Name: "warm-0"}, &actualWarm))
+	require.Empty(t, actualWarm.Annotations)
+}
@@ -0,0 +1,175 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package nodeattr
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	esvolume "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/volume"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Elasticsearch node attributes can be derived from the labels of the Kubernetes node a Pod is scheduled on.
+// Since the node is not known before scheduling, the operator copies the labels values into Pod annotations,
+// which are exposed to the Elasticsearch container through environment variables.
+// The prepare-fs init container waits for the annotations to be set before Elasticsearch starts.
+
+const (
+	// AnnotationPrefix is the prefix of the Pod annotations holding the value of node attributes.
+	AnnotationPrefix = "node-attr.elasticsearch.k8s.elastic.co/"
+	// ZoneAttribute is the node attribute holding the zone of the node, when zone awareness is enabled.
+	ZoneAttribute = "zone"
+	// RequiredAnnotationsEnvVar lists the Pod annotations the prepare-fs init container waits for.
+	RequiredAnnotationsEnvVar = "REQUIRED_POD_ANNOTATIONS"
+
+	envVarPrefix = "NODE_ATTR_"
+)
+
+// Attributes maps Elasticsearch node attribute names to the Kubernetes node label holding their value.
+type Attributes map[string]string
+
+// For returns the node attributes derived from Kubernetes node labels for the given NodeSet.
+func For(es v1beta1.Elasticsearch, nodeSet v1beta1.NodeSet) Attributes {
+	if !es.Spec.IsZoneAware() && len(nodeSet.NodeAttributes) == 0 {
+		return nil
+	}
+	attributes := make(Attributes, len(nodeSet.NodeAttributes)+1)
+	for _, attribute := range nodeSet.NodeAttributes {
+		attributes[attribute.Name] = attribute.NodeLabel
+	}
+	if es.Spec.IsZoneAware() {
+		attributes[ZoneAttribute] = es.Spec.ZoneAwareness.TopologyKeyOrDefault()
+	}
+	return attributes
+}
+
+// Awareness returns the sorted node attributes used for shard allocation awareness by the cluster, across all its
+// NodeSets. Elasticsearch expects the same awareness attributes on all the nodes.
+func Awareness(es v1beta1.Elasticsearch) []string {
+	set := make(map[string]struct{})
+	for _, nodeSet := range es.Spec.NodeSets {
+		for _, attribute := range nodeSet.NodeAttributes {
+			if attribute.Awareness {
+				set[attribute.Name] = struct{}{}
+			}
+		}
+	}
+	if es.Spec.IsZoneAware() {
+		set[ZoneAttribute] = struct{}{}
+	}
+	var awareness []string
+	for name := range set {
+		awareness = append(awareness, name)
+	}
+	sort.Strings(awareness)
+	return awareness
+}
+
+// Names returns the sorted names of the attributes.
+func (a Attributes) Names() []string {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Annotation returns the name of the Pod annotation holding the value of the given attribute.
+func Annotation(attribute string) string {
+	return AnnotationPrefix + attribute
+}
+
+// EnvVarName returns the name of the environment variable holding the value of the given attribute.
+// Different attribute names can map to the same environment variable, for example `foo-bar` and `foo_bar`:
+// such attributes are rejected by the validation.
+func EnvVarName(attribute string) string {
+	return envVarPrefix + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, attribute)
+}
+
+// EnvVars returns the environment variables of the Elasticsearch container, populated from the Pod annotations.
+func (a Attributes) EnvVars() []corev1.EnvVar {
+	vars := make([]corev1.EnvVar, 0, len(a))
+	for _, name := range a.Names() {
+		vars = append(vars, corev1.EnvVar{
+			Name: EnvVarName(name),
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  fmt.Sprintf("metadata.annotations['%s']", Annotation(name)),
+				},
+			},
+		})
+	}
+	return vars
+}
+
+// RequiredAnnotationsEnvVar returns the environment variable listing the annotations the prepare-fs init container
+// must wait for.
+func (a Attributes) RequiredAnnotationsEnvVar() corev1.EnvVar {
+	annotations := make([]string, 0, len(a))
+	for _, name := range a.Names() {
+		annotations = append(annotations, Annotation(name))
+	}
+	return corev1.EnvVar{Name: RequiredAnnotationsEnvVar, Value: strings.Join(annotations, " ")}
+}
+
+// AnnotationsVolume is a downward API volume exposing the Pod annotations as a file, refreshed by the kubelet
+// when annotations are updated.
+var AnnotationsVolume = corev1.Volume{
+	Name: esvolume.PodAnnotationsVolumeName,
+	VolumeSource: corev1.VolumeSource{
+		DownwardAPI: &corev1.DownwardAPIVolumeSource{
+			Items: []corev1.DownwardAPIVolumeFile{
+				{
+					Path:     esvolume.PodAnnotationsFile,
+					FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "metadata.annotations"},
+				},
+			},
+		},
+	},
+}
+
+// AnnotationsVolumeMount mounts the AnnotationsVolume.
+var AnnotationsVolumeMount = corev1.VolumeMount{
+	Name:      esvolume.PodAnnotationsVolumeName,
+	MountPath: esvolume.PodAnnotationsVolumeMountPath,
+	ReadOnly:  true,
+}
+
+// AnnotationsFilePath is the path to the file holding the Pod annotations, in the AnnotationsVolume.
+var AnnotationsFilePath = path.Join(esvolume.PodAnnotationsVolumeMountPath, esvolume.PodAnnotationsFile)
+
+// Value returns the value of the given attribute for the given Pod, if it has been annotated.
+func Value(pod corev1.Pod, attribute string) (string, bool) {
+	value, exists := pod.Annotations[Annotation(attribute)]
+	return value, exists
+}
+
+// Zones returns the zone of the given Pods, indexed by Pod name.
+// It returns false if the zone of any of the Pods is not known yet.
+func Zones(pods []corev1.Pod) (map[string]string, bool) {
+	zones := make(map[string]string, len(pods))
+	for _, pod := range pods {
+		zone, exists := Value(pod, ZoneAttribute)
+		if !exists {
+			return nil, false
+		}
+		zones[pod.Name] = zone
+	}
+	return zones, true
+}
@@ -0,0 +1,122 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package nodeattr
+
+import (
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFor(t *testing.T) {
+	nodeSet := v1beta1.NodeSet{NodeAttributes: []v1beta1.NodeAttribute{
+		{Name: "instance_type", NodeLabel: "node.kubernetes.io/instance-type", Awareness: true},
+		{Name: "rack", NodeLabel: "rack-label"},
+	}}
+	tests := []struct {
+		name          string
+		es            v1beta1.Elasticsearch
+		nodeSet       v1beta1.NodeSet
+		want          Attributes
+		wantAwareness []string
+	}{
+		{
+			name: "zone awareness disabled",
+			es:   v1beta1.Elasticsearch{},
+			want: nil,
+		},
+		{
+			name:          "default topology key",
+			es:            v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{ZoneAwareness: &v1beta1.ZoneAwareness{}}},
+			want:          Attributes{ZoneAttribute: v1beta1.DefaultZoneLabel},
+			wantAwareness: []string{ZoneAttribute},
+		},
+		{
+			name: "custom topology key",
+			es: v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{
+				ZoneAwareness: &v1beta1.ZoneAwareness{TopologyKey: "topology.kubernetes.io/zone"},
+			}},
+			want:          Attributes{ZoneAttribute: "topology.kubernetes.io/zone"},
+			wantAwareness: []string{ZoneAttribute},
+		},
+		{
+			name:          "NodeSet attributes",
+			es:            v1beta1.Elasticsearch{},
+			nodeSet:       nodeSet,
+			want:          Attributes{"instance_type": "node.kubernetes.io/instance-type", "rack": "rack-label"},
+			wantAwareness: []string{"instance_type"},
+		},
+		{
+			name:    "NodeSet attributes with zone awareness",
+			es:      v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{ZoneAwareness: &v1beta1.ZoneAwareness{}}},
+			nodeSet: nodeSet,
+			want: Attributes{
+				"instance_type": "node.kubernetes.io/instance-type",
+				"rack":          "rack-label",
+				ZoneAttribute:   v1beta1.DefaultZoneLabel,
+			},
+			wantAwareness: []string{"instance_type", ZoneAttribute},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, For(tt.es, tt.nodeSet))
+			tt.es.Spec.NodeSets = []v1beta1.NodeSet{tt.nodeSet}
+			require.Equal(t, tt.wantAwareness, Awareness(tt.es))
+		})
+	}
+}
+
+func TestAwareness(t *testing.T) {
+	es := v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{
+		ZoneAwareness: &v1beta1.ZoneAwareness{},
+		NodeSets: []v1beta1.NodeSet{
+			{Name: "hot", NodeAttributes: []v1beta1.NodeAttribute{{Name: "rack", NodeLabel: "rack-label", Awareness: true}}},
+			{Name: "warm", NodeAttributes: []v1beta1.NodeAttribute{
+				{Name: "rack", NodeLabel: "rack-label", Awareness: true},
+				{Name: "instance_type", NodeLabel: "node.kubernetes.io/instance-type", Awareness: true},
+			}},
+			{Name: "cold"},
+		},
+	}}
+	// the awareness attributes are the union of the attributes of all the NodeSets
+	require.Equal(t, []string{"instance_type", "rack", ZoneAttribute}, Awareness(es))
+}
+
+func TestEnvVarName(t *testing.T) {
+	require.Equal(t, "NODE_ATTR_ZONE", EnvVarName("zone"))
+	require.Equal(t, "NODE_ATTR_INSTANCE_TYPE2", EnvVarName("instance-type2"))
+}
+
+func TestAttributes_EnvVars(t *testing.T) {
+	attrs := Attributes{"zone": "zone-label", "rack": "rack-label"}
+	require.Equal(t, []string{"rack", "zone"}, attrs.Names())
+	vars := attrs.EnvVars()
+	require.Len(t, vars, 2)
+	require.Equal(t, "NODE_ATTR_RACK", vars[0].Name)
+	require.Equal(t, "metadata.annotations['node-attr.elasticsearch.k8s.elastic.co/rack']", vars[0].ValueFrom.FieldRef.FieldPath)
+	require.Equal(t,
+		corev1.EnvVar{
+			Name:  RequiredAnnotationsEnvVar,
+			Value: "node-attr.elasticsearch.k8s.elastic.co/rack node-attr.elasticsearch.k8s.elastic.co/zone",
+		},
+		attrs.RequiredAnnotationsEnvVar(),
+	)
+}
+
+func TestZones(t *testing.T) {
+	podInZone := func(name, zone string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{Annotation(ZoneAttribute): zone}}}
+	}
+	zones, known := Zones([]corev1.Pod{podInZone("a", "zone-a"), podInZone("b", "zone-b")})
+	require.True(t, known)
+	require.Equal(t, map[string]string{"a": "zone-a", "b": "zone-b"}, zones)
+
+	_, known = Zones([]corev1.Pod{podInZone("a", "zone-a"), {ObjectMeta: metav1.ObjectMeta{Name: "b"}}})
+	require.False(t, known)
+}
@@ -5,9 +5,6 @@
 package nodespec
 
 import (
-	"crypto/sha256"
-	"fmt"
-
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
@@ -19,6 +16,8 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/initcontainer"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/securesettings"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
 	esvolume "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/volume"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
@@ -32,7 +31,11 @@ func BuildPodTemplateSpec(
 	cfg settings.CanonicalConfig,
 	keystoreResources *keystore.Resources,
 ) (corev1.PodTemplateSpec, error) {
+	nodeAttributes := nodeattr.For(es, nodeSet)
 	volumes, volumeMounts := buildVolumes(es.Name, nodeSet, keystoreResources)
+	if len(nodeAttributes) > 0 {
+		volumes = append(volumes, nodeattr.AnnotationsVolume)
+	}
 	labels, err := buildLabels(es, cfg, nodeSet, keystoreResources)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
@@ -45,6 +48,7 @@ func BuildPodTemplateSpec(
 		builder.Container.Image,
 		transportCertificatesVolume(es.Name),
 		es.Name,
+		nodeAttributes,
 		keystoreResources,
 	)
 	if err != nil {
@@ -58,6 +62,7 @@ func BuildPodTemplateSpec(
 		WithReadinessProbe(*NewReadinessProbe()).
 		WithAffinity(DefaultAffinity(es.Name)).
 		WithEnv(DefaultEnvVars(es.Spec.HTTP)...).
+		WithEnv(nodeAttributes.EnvVars()...).
 		WithVolumes(volumes...).
 		WithVolumeMounts(volumeMounts...).
 		WithLabels(labels).
@@ -105,11 +110,9 @@ func buildLabels(
 	}
 
 	if keystoreResources != nil {
-		// label with a checksum of the secure settings to rotate the pod on secure settings change
-		// TODO: use hash.HashObject instead && fix the config checksum label name?
-		configChecksum := sha256.New224()
-		_, _ = configChecksum.Write([]byte(keystoreResources.Version))
-		podLabels[label.SecureSettingsHashLabelName] = fmt.Sprintf("%x", configChecksum.Sum(nil))
+		// label with a checksum of the secure settings to rotate the pod on secure settings change,
+		// reloadable secure settings are applied to the running nodes instead
+		podLabels[label.SecureSettingsHashLabelName] = securesettings.RestartChecksum(keystoreResources.Settings)
 	}
 
 	return podLabels, nil
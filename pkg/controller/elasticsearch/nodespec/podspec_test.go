@@ -11,9 +11,11 @@ import (
 	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/initcontainer"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
 	"github.com/go-test/deep"
 
@@ -92,7 +94,7 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 	nodeSet := sampleES.Spec.NodeSets[0]
 	ver, err := version.Parse(sampleES.Spec.Version)
 	require.NoError(t, err)
-	cfg, err := settings.NewMergedESConfig(sampleES.Name, *ver, sampleES.Spec.HTTP, *nodeSet.Config, &certResources)
+	cfg, err := settings.NewMergedESConfig(sampleES.Name, *ver, sampleES.Spec.HTTP, *nodeSet.Config, &certResources, nil, nil)
 	require.NoError(t, err)
 
 	actual, err := BuildPodTemplateSpec(sampleES, sampleES.Spec.NodeSets[0], cfg, nil)
@@ -113,6 +115,7 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 		transportCertificatesVolume(sampleES.Name),
 		sampleES.Name,
 		nil,
+		nil,
 	)
 	require.NoError(t, err)
 	// should be patched with volume and env
@@ -194,3 +197,52 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 	deep.MaxDepth = 25
 	require.Nil(t, deep.Equal(expected, actual))
 }
+
+func TestBuildPodTemplateSpec_SecureSettings(t *testing.T) {
+	certResources := certificates.CertificateResources{HTTPCACertProvided: true}
+	nodeSet := sampleES.Spec.NodeSets[0]
+	ver, err := version.Parse(sampleES.Spec.Version)
+	require.NoError(t, err)
+	cfg, err := settings.NewMergedESConfig(sampleES.Name, *ver, sampleES.Spec.HTTP, *nodeSet.Config, &certResources, nil, nil)
+	require.NoError(t, err)
+
+	keystoreResources := func(settings map[string][]byte) *keystore.Resources {
+		return &keystore.Resources{
+			Volume:   corev1.Volume{Name: keystore.SecureSettingsVolumeName},
+			Settings: settings,
+		}
+	}
+	build := func(settings map[string][]byte) corev1.PodTemplateSpec {
+		podTemplate, err := BuildPodTemplateSpec(sampleES, nodeSet, cfg, keystoreResources(settings))
+		require.NoError(t, err)
+		return podTemplate
+	}
+
+	initial := build(map[string][]byte{
+		"s3.client.default.access_key":                            []byte("access"),
+		"xpack.security.authc.realms.oidc.oidc1.rp.client_secret": []byte("secret"),
+	})
+	// the secure settings are mounted in the Elasticsearch container to be reloaded
+	require.Contains(t, initial.Spec.Containers[1].VolumeMounts, corev1.VolumeMount{
+		Name:      keystore.SecureSettingsVolumeName,
+		MountPath: keystore.SecureSettingsVolumeMountPath,
+		ReadOnly:  true,
+	})
+
+	// updating a reloadable setting does not change the pod template
+	reloadable := build(map[string][]byte{
+		"s3.client.default.access_key":                            []byte("updated"),
+		"xpack.security.authc.realms.oidc.oidc1.rp.client_secret": []byte("secret"),
+	})
+	require.Nil(t, deep.Equal(initial, reloadable))
+
+	// updating a setting that cannot be reloaded changes the pod labels
+	notReloadable := build(map[string][]byte{
+		"s3.client.default.access_key":                            []byte("access"),
+		"xpack.security.authc.realms.oidc.oidc1.rp.client_secret": []byte("updated"),
+	})
+	require.NotEqual(t,
+		initial.Labels[label.SecureSettingsHashLabelName],
+		notReloadable.Labels[label.SecureSettingsHashLabelName],
+	)
+}
@@ -7,6 +7,7 @@ package nodespec
 import (
 	"path"
 
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/volume"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -27,22 +28,32 @@ func NewReadinessProbe() *corev1.Probe {
 }
 
 const ReadinessProbeScriptConfigKey = "readiness-probe-script.sh"
+
+// ReadinessProbeScript falls back to the user holding the probe password replaced by a rotation when the probe user
+// is rejected, since the probe password file and the file realm are not updated at the same time.
 const ReadinessProbeScript string = `
 #!/usr/bin/env bash
 # Consider a node to be healthy if it responds to a simple GET on "/_cat/nodes?local"
 CURL_TIMEOUT=3
 
+# request Elasticsearch
+ENDPOINT="${READINESS_PROBE_PROTOCOL:-https}://127.0.0.1:9200/_cat/nodes?local"
+
 # setup basic auth if credentials are available
 if [ -n "${PROBE_USERNAME}" ] && [ -f "${PROBE_PASSWORD_FILE}" ]; then
   PROBE_PASSWORD=$(<$PROBE_PASSWORD_FILE)
   BASIC_AUTH="-u ${PROBE_USERNAME}:${PROBE_PASSWORD}"
+  # the password may have been rotated in the file realm but not in the password file yet
+  PREVIOUS_BASIC_AUTH="-u ${PROBE_USERNAME}` + user.PreviousProbeUserNameSuffix + `:${PROBE_PASSWORD}"
 else
   BASIC_AUTH=''
+  PREVIOUS_BASIC_AUTH=''
 fi
 
-# request Elasticsearch
-ENDPOINT="${READINESS_PROBE_PROTOCOL:-https}://127.0.0.1:9200/_cat/nodes?local"
 status=$(curl -o /dev/null -w "%{http_code}" --max-time $CURL_TIMEOUT -XGET -s -k ${BASIC_AUTH} $ENDPOINT)
+if [[ $status == "401" ]] && [ -n "${PREVIOUS_BASIC_AUTH}" ]; then
+  status=$(curl -o /dev/null -w "%{http_code}" --max-time $CURL_TIMEOUT -XGET -s -k ${PREVIOUS_BASIC_AUTH} $ENDPOINT)
+fi
 
 # ready if status code 200
 if [[ $status == "200" ]]; then
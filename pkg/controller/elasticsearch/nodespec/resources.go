@@ -11,10 +11,13 @@ import (
 
 	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/securesettings"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
@@ -42,6 +45,7 @@ func BuildExpectedResources(
 	keystoreResources *keystore.Resources,
 	scheme *runtime.Scheme,
 	certResources *certificates.CertificateResources,
+	actualStatefulSets sset.StatefulSetList,
 ) (ResourcesList, error) {
 	nodesResources := make(ResourcesList, 0, len(es.Spec.NodeSets))
 
@@ -50,13 +54,16 @@ func BuildExpectedResources(
 		return nil, err
 	}
 
+	// shard allocation awareness is a cluster-wide setting, identical on all the nodes
+	awareness := nodeattr.Awareness(es)
+
 	for _, nodeSpec := range es.Spec.NodeSets {
 		// build es config
 		userCfg := commonv1beta1.Config{}
 		if nodeSpec.Config != nil {
 			userCfg = *nodeSpec.Config
 		}
-		cfg, err := settings.NewMergedESConfig(es.Name, *ver, es.Spec.HTTP, userCfg, certResources)
+		cfg, err := settings.NewMergedESConfig(es.Name, *ver, es.Spec.HTTP, userCfg, certResources, nodeattr.For(es, nodeSpec), awareness)
 		if err != nil {
 			return nil, err
 		}
@@ -66,6 +73,9 @@ func BuildExpectedResources(
 		if err != nil {
 			return nil, err
 		}
+		if actual, exists := actualStatefulSets.GetByName(statefulSet.Name); exists {
+			keepLegacySecureSettings(&statefulSet, actual, keystoreResources)
+		}
 		headlessSvc := HeadlessService(k8s.ExtractNamespacedName(&es), statefulSet.Name)
 
 		nodesResources = append(nodesResources, Resources{
@@ -78,6 +88,32 @@ func BuildExpectedResources(
 	return nodesResources, nil
 }
 
+// keepLegacySecureSettings keeps the Pod template of a StatefulSet created by a previous version of the operator,
+// which labelled the Pods with a checksum of the version of the secure settings secret and did not mount the secure
+// settings in the Elasticsearch container, as long as the secure settings do not change. This prevents a rolling
+// restart of all the clusters with secure settings when the operator is upgraded.
+func keepLegacySecureSettings(statefulSet *appsv1.StatefulSet, actual appsv1.StatefulSet, keystoreResources *keystore.Resources) {
+	if keystoreResources == nil ||
+		actual.Spec.Template.Labels[label.SecureSettingsHashLabelName] != securesettings.LegacyChecksum(keystoreResources.Version) {
+		return
+	}
+	template := &statefulSet.Spec.Template
+	template.Labels[label.SecureSettingsHashLabelName] = actual.Spec.Template.Labels[label.SecureSettingsHashLabelName]
+	for i, container := range template.Spec.Containers {
+		if container.Name != v1beta1.ElasticsearchContainerName {
+			continue
+		}
+		mounts := make([]corev1.VolumeMount, 0, len(container.VolumeMounts))
+		for _, mount := range container.VolumeMounts {
+			if mount.MountPath != keystore.SecureSettingsVolumeMountPath {
+				mounts = append(mounts, mount)
+			}
+		}
+		template.Spec.Containers[i].VolumeMounts = mounts
+	}
+	statefulSet.Labels = hash.SetTemplateHashLabel(statefulSet.Labels, statefulSet.Spec)
+}
+
 // MasterNodesNames returns the names of the master nodes for this ResourcesList.
 func (l ResourcesList) MasterNodesNames() []string {
 	var masters []string
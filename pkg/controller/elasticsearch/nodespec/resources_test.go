@@ -9,9 +9,15 @@ import (
 	"testing"
 
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/securesettings"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -115,3 +121,52 @@ func TestSetVolumeClaimsControllerReference(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildExpectedResources_LegacySecureSettings(t *testing.T) {
+	require.NoError(t, v1beta1.AddToScheme(scheme.Scheme))
+	certResources := &certificates.CertificateResources{HTTPCACertProvided: true}
+	keystoreResources := func(version string) *keystore.Resources {
+		return &keystore.Resources{
+			Volume:   corev1.Volume{Name: keystore.SecureSettingsVolumeName},
+			Version:  version,
+			Settings: map[string][]byte{"s3.client.default.access_key": []byte("access")},
+		}
+	}
+	secureSettingsMounted := func(statefulSet appsv1.StatefulSet) bool {
+		for _, container := range statefulSet.Spec.Template.Spec.Containers {
+			for _, mount := range container.VolumeMounts {
+				if mount.MountPath == keystore.SecureSettingsVolumeMountPath {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	resources, err := BuildExpectedResources(sampleES, keystoreResources("1"), scheme.Scheme, certResources, nil)
+	require.NoError(t, err)
+	expected := resources[0].StatefulSet
+	restartChecksum := securesettings.RestartChecksum(keystoreResources("1").Settings)
+	require.Equal(t, restartChecksum, expected.Spec.Template.Labels[label.SecureSettingsHashLabelName])
+	require.True(t, secureSettingsMounted(expected))
+
+	// StatefulSet created by a previous version of the operator, labelled with a checksum of the secret version
+	legacy := *expected.DeepCopy()
+	legacy.Spec.Template.Labels[label.SecureSettingsHashLabelName] = securesettings.LegacyChecksum("1")
+
+	// the legacy Pod template is kept as long as the secure settings secret does not change
+	resources, err = BuildExpectedResources(sampleES, keystoreResources("1"), scheme.Scheme, certResources, sset.StatefulSetList{legacy})
+	require.NoError(t, err)
+	upgraded := resources[0].StatefulSet
+	require.Equal(t, securesettings.LegacyChecksum("1"), upgraded.Spec.Template.Labels[label.SecureSettingsHashLabelName])
+	require.False(t, secureSettingsMounted(upgraded))
+	require.NotEqual(t, expected.Labels[hash.TemplateHashLabelName], upgraded.Labels[hash.TemplateHashLabelName])
+
+	// the Pods are restarted with the new label once the secret changes
+	resources, err = BuildExpectedResources(sampleES, keystoreResources("2"), scheme.Scheme, certResources, sset.StatefulSetList{legacy})
+	require.NoError(t, err)
+	updated := resources[0].StatefulSet
+	require.Equal(t, restartChecksum, updated.Spec.Template.Labels[label.SecureSettingsHashLabelName])
+	require.True(t, secureSettingsMounted(updated))
+	require.Equal(t, expected.Labels[hash.TemplateHashLabelName], updated.Labels[hash.TemplateHashLabelName])
+}
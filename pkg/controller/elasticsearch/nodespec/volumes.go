@@ -88,6 +88,14 @@ func buildVolumes(esName string, nodeSpec v1beta1.NodeSet, keystoreResources *ke
 		scriptsVolume.VolumeMount(),
 		configVolume.VolumeMount(),
 	)
+	if keystoreResources != nil {
+		// updated secure settings are read from the volume to update the keystore of the running node
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      keystoreResources.Volume.Name,
+			MountPath: keystore.SecureSettingsVolumeMountPath,
+			ReadOnly:  true,
+		})
+	}
 
 	return volumes, volumeMounts
 }
@@ -28,6 +28,21 @@ func NewManager(settings Settings) *Manager {
 	}
 }
 
+// Start implements manager.Runnable. Observers are created by the reconciliations of the Elasticsearch controller,
+// which only run on the leader operator replica. They are all stopped once the given channel is closed, when the
+// manager stops, including when the leader election lease is lost.
+func (m *Manager) Start(stop <-chan struct{}) error {
+	<-stop
+	log.Info("Stopping all observers")
+	m.StopAll()
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: observers are only needed by the leader.
+func (m *Manager) NeedLeaderElection() bool {
+	return true
+}
+
 // ObservedStateResolver returns the last known state of the given cluster,
 // as expected by the main reconciliation driver
 func (m *Manager) ObservedStateResolver(cluster types.NamespacedName, esClient client.Client) State {
@@ -77,6 +92,14 @@ func (m *Manager) StopObserving(cluster types.NamespacedName) {
 	m.lock.Lock()
 	delete(m.observers, cluster)
 	m.lock.Unlock()
+	forgetHealth(cluster)
+}
+
+// StopAll stops and deletes all observers
+func (m *Manager) StopAll() {
+	for _, cluster := range m.List() {
+		m.StopObserving(cluster)
+	}
 }
 
 // List returns the names of clusters currently observed
@@ -165,6 +165,28 @@ func TestManager_StopObserving(t *testing.T) {
 	}
 }
 
+func TestManager_Start(t *testing.T) {
+	esClient := fakeEsClient200(client.UserAuth{})
+	m := NewManager(DefaultSettings)
+	m.Observe(cluster("cluster1"), esClient)
+	m.Observe(cluster("cluster2"), esClient)
+	require.Len(t, m.List(), 2)
+
+	stop := make(chan struct{})
+	stopped := make(chan error)
+	go func() {
+		stopped <- m.Start(stop)
+	}()
+	close(stop)
+	select {
+	case err := <-stopped:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("observers manager did not stop")
+	}
+	require.Empty(t, m.List())
+}
+
 func TestManager_AddObservationListener(t *testing.T) {
 	m := NewManager(Settings{
 		ObservationInterval: 1 * time.Microsecond,
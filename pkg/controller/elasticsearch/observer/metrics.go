@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package observer
+
+import (
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	clusterHealth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "elastic_elasticsearch_health",
+			Help: "Health of the Elasticsearch cluster as last observed by the operator: 1 for the current health, 0 for the others.",
+		},
+		[]string{"namespace", "name", "health"},
+	)
+
+	healths = []v1beta1.ElasticsearchHealth{
+		v1beta1.ElasticsearchGreenHealth,
+		v1beta1.ElasticsearchYellowHealth,
+		v1beta1.ElasticsearchRedHealth,
+		v1beta1.ElasticsearchUnknownHealth,
+	}
+)
+
+func init() {
+	metrics.Registry.MustRegister(clusterHealth)
+}
+
+// reportHealth exposes the observed health of the cluster, which is unknown if it could not be retrieved.
+func reportHealth(cluster types.NamespacedName, health *esclient.Health) {
+	observed := v1beta1.ElasticsearchUnknownHealth
+	if health != nil {
+		observed = v1beta1.ElasticsearchHealth(health.Status)
+	}
+	for _, h := range healths {
+		value := 0.0
+		if h == observed {
+			value = 1
+		}
+		clusterHealth.WithLabelValues(cluster.Namespace, cluster.Name, string(h)).Set(value)
+	}
+}
+
+// forgetHealth stops exposing the health of the cluster, once it is not observed anymore.
+func forgetHealth(cluster types.NamespacedName) {
+	for _, h := range healths {
+		clusterHealth.DeleteLabelValues(cluster.Namespace, cluster.Name, string(h))
+	}
+}
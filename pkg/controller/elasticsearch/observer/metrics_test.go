@@ -0,0 +1,39 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package observer
+
+import (
+	"testing"
+
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportHealth(t *testing.T) {
+	// other tests run observers reporting the health of their clusters concurrently
+	healthOf := func(name, health string) float64 {
+		return testutil.ToFloat64(clusterHealth.WithLabelValues("ns", name, health))
+	}
+
+	reportHealth(cluster("yellow-cluster"), &esclient.Health{Status: "yellow"})
+	reportHealth(cluster("unreachable-cluster"), nil)
+	for _, tt := range []struct {
+		cluster string
+		want    map[string]float64
+	}{
+		{cluster: "yellow-cluster", want: map[string]float64{"green": 0, "yellow": 1, "red": 0, "unknown": 0}},
+		{cluster: "unreachable-cluster", want: map[string]float64{"green": 0, "yellow": 0, "red": 0, "unknown": 1}},
+	} {
+		for health, want := range tt.want {
+			require.Equal(t, want, healthOf(tt.cluster, health), "%s %s", tt.cluster, health)
+		}
+	}
+
+	forgetHealth(cluster("yellow-cluster"))
+	for _, health := range healths {
+		require.False(t, clusterHealth.DeleteLabelValues("ns", "yellow-cluster", string(health)))
+	}
+}
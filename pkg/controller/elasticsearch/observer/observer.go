@@ -132,6 +132,7 @@ func (o *Observer) retrieveState(ctx context.Context) {
 	defer cancel()
 
 	newState := RetrieveState(timeoutCtx, o.cluster, o.esClient)
+	reportHealth(o.cluster, newState.ClusterHealth)
 
 	if o.onObservation != nil {
 		o.onObservation(o.cluster, o.LastState(), newState)
@@ -7,6 +7,7 @@ package reconcile
 import (
 	"reflect"
 
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/validation"
@@ -28,6 +29,12 @@ func NewState(c v1beta1.Elasticsearch) *State {
 	return &State{Recorder: events.NewRecorder(), cluster: c, status: *c.Status.DeepCopy()}
 }
 
+// UpdateOperator reports the name of the operator reconciling the cluster in the resource status.
+func (s *State) UpdateOperator(name string) *State {
+	s.status.Operator = name
+	return s
+}
+
 // AvailableElasticsearchNodes filters a slice of pods for the ones that are ready.
 func AvailableElasticsearchNodes(pods []corev1.Pod) []corev1.Pod {
 	var nodesAvailable []corev1.Pod
@@ -96,6 +103,60 @@ func (s *State) UpdateElasticsearchMigrating(
 	return s.updateWithPhase(v1beta1.ElasticsearchMigratingDataPhase, resourcesState, observedState)
 }
 
+// UpdateElasticsearchRestoringSnapshot marks Elasticsearch as being in the snapshot restore phase in the resource status.
+func (s *State) UpdateElasticsearchRestoringSnapshot() *State {
+	s.status.Phase = v1beta1.ElasticsearchRestoringSnapshotPhase
+	return s
+}
+
+// UpdateSnapshotStatus reports the outcome of the latest scheduled snapshots in the resource status.
+func (s *State) UpdateSnapshotStatus(status *v1beta1.SnapshotStatus) *State {
+	s.status.Snapshot = status
+	return s
+}
+
+// UpdateIndexLifecycleStatus reports the state of the ILM policies and index templates in the resource status.
+func (s *State) UpdateIndexLifecycleStatus(status *v1beta1.IndexLifecycleStatus) *State {
+	s.status.IndexLifecycle = status
+	return s
+}
+
+// UpdateSecurityStatus reports the state of the native users in the resource status.
+func (s *State) UpdateSecurityStatus(status *v1beta1.SecurityStatus) *State {
+	s.status.Security = status
+	return s
+}
+
+// UpdateRemoteClustersStatus reports the remote clusters and auto-follow patterns applied in the resource status.
+func (s *State) UpdateRemoteClustersStatus(status *v1beta1.RemoteClustersStatus) *State {
+	s.status.RemoteClusters = status
+	return s
+}
+
+// UpdateTransportStatus reports the state of the transport layer configuration in the resource status.
+func (s *State) UpdateTransportStatus(status *v1beta1.TransportStatus) *State {
+	s.status.Transport = status
+	return s
+}
+
+// UpdateHTTPStatus reports the fingerprint of the HTTP certificate served by the nodes in the resource status.
+func (s *State) UpdateHTTPStatus(status *commonv1beta1.HTTPStatus) *State {
+	s.status.HTTP = status
+	return s
+}
+
+// UpdateCertificatesStatus reports when the HTTP and transport certificates expire in the resource status.
+func (s *State) UpdateCertificatesStatus(status *commonv1beta1.CertificatesStatus) *State {
+	s.status.Certificates = status
+	return s
+}
+
+// UpdateVolumeExpansionStatus reports the progress of the expansion of the persistent volume claims in the resource status.
+func (s *State) UpdateVolumeExpansionStatus(status *v1beta1.VolumeExpansionStatus) *State {
+	s.status.VolumeExpansion = status
+	return s
+}
+
 // Apply takes the current Elasticsearch status, compares it to the previous status, and updates the status accordingly.
 // It returns the events to emit and an updated version of the Elasticsearch cluster resource with
 // the current status applied to its status sub-resource.
@@ -0,0 +1,153 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package remotecluster
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/services"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("remotecluster")
+
+// Reconcile configures the remote clusters specified in the Elasticsearch spec through the cluster settings API,
+// using the transport service of the remote cluster as seed, and creates their auto-follow patterns.
+// Remote clusters and auto-follow patterns previously applied by the operator but removed from the spec are removed
+// from the cluster. It returns the status to report in the Elasticsearch status, which is nil if there is nothing
+// to reconcile.
+// The transport CAs of the remote clusters are exchanged by the global operator.
+func Reconcile(c esclient.Client, es v1beta1.Elasticsearch) (*v1beta1.RemoteClustersStatus, error) {
+	previous := v1beta1.RemoteClustersStatus{}
+	if es.Status.RemoteClusters != nil {
+		previous = *es.Status.RemoteClusters
+	}
+	if len(es.Spec.RemoteClusters) == 0 && len(previous.AppliedRemoteClusters) == 0 && len(previous.AppliedAutoFollowPatterns) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), esclient.DefaultReqTimeout)
+	defer cancel()
+
+	status := v1beta1.RemoteClustersStatus{}
+	if err := reconcileRemoteClusters(ctx, c, es, previous, &status); err != nil {
+		return nil, err
+	}
+	if err := reconcileAutoFollowPatterns(ctx, c, es, previous, &status); err != nil {
+		return nil, err
+	}
+	if len(status.AppliedRemoteClusters) == 0 && len(status.AppliedAutoFollowPatterns) == 0 {
+		return nil, nil
+	}
+	return &status, nil
+}
+
+// expectedRemoteClusters returns the remote clusters settings expected for the given cluster, with removed remote
+// clusters set to nil seeds.
+func expectedRemoteClusters(es v1beta1.Elasticsearch, previous v1beta1.RemoteClustersStatus) map[string]esclient.RemoteCluster {
+	expected := make(map[string]esclient.RemoteCluster, len(es.Spec.RemoteClusters))
+	for _, name := range previous.AppliedRemoteClusters {
+		expected[name] = esclient.RemoteCluster{Seeds: nil}
+	}
+	for _, rc := range es.Spec.RemoteClusters {
+		expected[rc.Name] = esclient.RemoteCluster{
+			Seeds: []string{services.TransportServiceHost(rc.RemoteClusterRef(es.Namespace))},
+		}
+	}
+	return expected
+}
+
+func reconcileRemoteClusters(
+	ctx context.Context,
+	c esclient.Client,
+	es v1beta1.Elasticsearch,
+	previous v1beta1.RemoteClustersStatus,
+	status *v1beta1.RemoteClustersStatus,
+) error {
+	settings, err := c.GetClusterSettings(ctx)
+	if err != nil {
+		return err
+	}
+	var current map[string]esclient.RemoteCluster
+	if settings.PersistentSettings != nil {
+		current = settings.PersistentSettings.Cluster.RemoteClusters
+	}
+
+	changes := map[string]esclient.RemoteCluster{}
+	for name, expected := range expectedRemoteClusters(es, previous) {
+		actual, exists := current[name]
+		if expected.Seeds == nil && !exists {
+			// already removed
+			continue
+		}
+		if exists && reflect.DeepEqual(expected.Seeds, actual.Seeds) {
+			continue
+		}
+		changes[name] = expected
+	}
+	if len(changes) > 0 {
+		log.Info("Updating remote clusters", "namespace", es.Namespace, "es_name", es.Name, "remote_clusters", changes)
+		if err := c.UpdateSettings(ctx, esclient.Settings{
+			PersistentSettings: &esclient.SettingsGroup{
+				Cluster: esclient.Cluster{RemoteClusters: changes},
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, rc := range es.Spec.RemoteClusters {
+		status.AppliedRemoteClusters = append(status.AppliedRemoteClusters, rc.Name)
+	}
+	sort.Strings(status.AppliedRemoteClusters)
+	return nil
+}
+
+func reconcileAutoFollowPatterns(
+	ctx context.Context,
+	c esclient.Client,
+	es v1beta1.Elasticsearch,
+	previous v1beta1.RemoteClustersStatus,
+	status *v1beta1.RemoteClustersStatus,
+) error {
+	applied := map[string]string{}
+	for _, rc := range es.Spec.RemoteClusters {
+		for _, p := range rc.AutoFollowPatterns {
+			expected := esclient.AutoFollowPattern{
+				RemoteCluster:       rc.Name,
+				LeaderIndexPatterns: p.LeaderIndexPatterns,
+				FollowIndexPattern:  p.FollowIndexPattern,
+			}
+			specHash := hash.HashObject(expected)
+			if previous.AppliedAutoFollowPatterns[p.Name] != specHash {
+				log.Info("Updating auto-follow pattern", "namespace", es.Namespace, "es_name", es.Name, "name", p.Name)
+				if err := c.UpdateAutoFollowPattern(ctx, p.Name, expected); err != nil {
+					return err
+				}
+			}
+			applied[p.Name] = specHash
+		}
+	}
+
+	for name := range previous.AppliedAutoFollowPatterns {
+		if _, exists := applied[name]; exists {
+			continue
+		}
+		log.Info("Deleting auto-follow pattern", "namespace", es.Namespace, "es_name", es.Name, "name", name)
+		if err := c.DeleteAutoFollowPattern(ctx, name); err != nil && !esclient.IsNotFound(err) {
+			return err
+		}
+	}
+
+	if len(applied) > 0 {
+		status.AppliedAutoFollowPatterns = applied
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package remotecluster
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcile(t *testing.T) {
+	leader := v1beta1.RemoteCluster{
+		Name:             "leader",
+		ElasticsearchRef: commonv1beta1.ObjectSelector{Name: "leader-es", Namespace: "other"},
+		AutoFollowPatterns: []v1beta1.AutoFollowPattern{
+			{Name: "logs", LeaderIndexPatterns: []string{"logs-*"}, FollowIndexPattern: "{{leader_index}}-copy"},
+		},
+	}
+	logsHash := hash.HashObject(esclient.AutoFollowPattern{
+		RemoteCluster:       "leader",
+		LeaderIndexPatterns: []string{"logs-*"},
+		FollowIndexPattern:  "{{leader_index}}-copy",
+	})
+	configured := `{"persistent":{"cluster":{"remote":{"leader":{"seeds":["leader-es-es-transport.other.svc:9300"]}}}},"transient":{}}`
+	wantSeedsUpdate := `PUT /_cluster/settings {"persistent":{"cluster":{"remote":{"leader":{"seeds":["leader-es-es-transport.other.svc:9300"]}}}}}`
+	wantPatternUpdate := `PUT /_ccr/auto_follow/logs {"remote_cluster":"leader","leader_index_patterns":["logs-*"],"follow_index_pattern":"{{leader_index}}-copy"}`
+	applied := &v1beta1.RemoteClustersStatus{
+		AppliedRemoteClusters:     []string{"leader"},
+		AppliedAutoFollowPatterns: map[string]string{"logs": logsHash},
+	}
+
+	tests := []struct {
+		name         string
+		spec         []v1beta1.RemoteCluster
+		status       *v1beta1.RemoteClustersStatus
+		settings     string
+		wantRequests []string
+		wantStatus   *v1beta1.RemoteClustersStatus
+	}{
+		{
+			name:       "no remote clusters",
+			wantStatus: nil,
+		},
+		{
+			name:         "new remote cluster",
+			spec:         []v1beta1.RemoteCluster{leader},
+			settings:     `{"persistent":{},"transient":{}}`,
+			wantRequests: []string{wantSeedsUpdate, wantPatternUpdate},
+			wantStatus:   applied,
+		},
+		{
+			name:       "remote cluster in sync",
+			spec:       []v1beta1.RemoteCluster{leader},
+			status:     applied,
+			settings:   configured,
+			wantStatus: applied,
+		},
+		{
+			name:         "remote cluster seeds modified",
+			spec:         []v1beta1.RemoteCluster{leader},
+			status:       applied,
+			settings:     `{"persistent":{"cluster":{"remote":{"leader":{"seeds":["10.0.0.1:9300"]}}}}}`,
+			wantRequests: []string{wantSeedsUpdate},
+			wantStatus:   applied,
+		},
+		{
+			name:         "auto-follow pattern updated in the spec",
+			spec:         []v1beta1.RemoteCluster{leader},
+			status:       &v1beta1.RemoteClustersStatus{AppliedRemoteClusters: []string{"leader"}, AppliedAutoFollowPatterns: map[string]string{"logs": "previous"}},
+			settings:     configured,
+			wantRequests: []string{wantPatternUpdate},
+			wantStatus:   applied,
+		},
+		{
+			name:     "remote cluster removed from the spec",
+			status:   applied,
+			settings: configured,
+			wantRequests: []string{
+				`PUT /_cluster/settings {"persistent":{"cluster":{"remote":{"leader":{"seeds":null}}}}}`,
+				`DELETE /_ccr/auto_follow/logs `,
+			},
+			wantStatus: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			c := esclient.NewMockClient(version.MustParse("7.4.0"), func(req *http.Request) *http.Response {
+				if req.Method == http.MethodGet {
+					return esclient.NewMockResponse(200, req, tt.settings)
+				}
+				var body []byte
+				if req.Body != nil {
+					var err error
+					body, err = ioutil.ReadAll(req.Body)
+					require.NoError(t, err)
+				}
+				requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+				return esclient.NewMockResponse(200, req, "{}")
+			})
+			es := v1beta1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "follower"},
+				Spec:       v1beta1.ElasticsearchSpec{RemoteClusters: tt.spec},
+				Status:     v1beta1.ElasticsearchStatus{RemoteClusters: tt.status},
+			}
+			status, err := Reconcile(c, es)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRequests, requests)
+			require.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
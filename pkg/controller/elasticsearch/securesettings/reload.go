@@ -0,0 +1,104 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package securesettings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ChecksumAnnotation is set on the Pods whose secure settings were reloaded, with the checksum of these settings.
+const ChecksumAnnotation = "elasticsearch.k8s.elastic.co/secure-settings-checksum"
+
+var log = logf.Log.WithName("secure-settings")
+
+// Reload applies the given secure settings to the running nodes without restarting them.
+//
+// The keystore of each outdated node is updated from the secure settings mounted in its Pod, then the nodes
+// reload their secure settings. Pods expecting a different value of the settings that cannot be reloaded are
+// skipped, since they are going to be restarted anyway.
+// It returns false if some nodes do not run with the given secure settings yet.
+func Reload(
+	c k8s.Client,
+	executor k8s.PodExecutor,
+	esClient esclient.Client,
+	settings map[string][]byte,
+	pods []corev1.Pod,
+) (bool, error) {
+	checksum := Checksum(settings)
+	restartChecksum := RestartChecksum(settings)
+
+	done := true
+	var toReload []corev1.Pod
+	for _, pod := range pods {
+		if pod.Labels[label.SecureSettingsHashLabelName] != restartChecksum ||
+			pod.Annotations[ChecksumAnnotation] == checksum {
+			continue
+		}
+		if !k8s.IsPodReady(pod) {
+			done = false
+			continue
+		}
+		_, stderr, err := executor.Exec(k8s.ExtractNamespacedName(&pod), v1beta1.ElasticsearchContainerName, updateKeystoreCommand(checksum))
+		if code, ok := k8s.ExitCode(err); ok && code == NotPropagatedExitCode {
+			log.V(1).Info("Waiting for the secure settings to be updated in the Pod", "namespace", pod.Namespace, "pod_name", pod.Name)
+			done = false
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("while updating the keystore of pod %s: %s: %s", pod.Name, err.Error(), stderr)
+		}
+		toReload = append(toReload, pod)
+	}
+	if len(toReload) == 0 {
+		return done, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), esclient.DefaultReqTimeout)
+	defer cancel()
+	response, err := esClient.ReloadSecureSettings(ctx)
+	if err != nil {
+		return false, err
+	}
+	results := make(map[string]esclient.NodeReloadResult, len(response.Nodes))
+	for _, result := range response.Nodes {
+		results[result.Name] = result
+	}
+
+	var failures []string
+	for _, pod := range toReload {
+		result, exists := results[pod.Name]
+		switch {
+		case !exists:
+			failures = append(failures, fmt.Sprintf("%s: no response", pod.Name))
+		case result.ReloadException != nil:
+			failures = append(failures, fmt.Sprintf("%s: %s", pod.Name, result.ReloadException.Reason))
+		default:
+			log.Info("Reloaded secure settings", "namespace", pod.Namespace, "pod_name", pod.Name)
+			updated := pod.DeepCopy()
+			if updated.Annotations == nil {
+				updated.Annotations = map[string]string{}
+			}
+			updated.Annotations[ChecksumAnnotation] = checksum
+			if err := c.Update(updated); err != nil {
+				return false, err
+			}
+		}
+	}
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return false, fmt.Errorf("failed to reload secure settings on nodes %s", strings.Join(failures, ", "))
+	}
+	return done, nil
+}
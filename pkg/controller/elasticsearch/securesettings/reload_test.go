@@ -0,0 +1,198 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package securesettings
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeExecutor struct {
+	// errors returned by pod name
+	errors   map[string]error
+	executed []string
+}
+
+func (e *fakeExecutor) Exec(pod types.NamespacedName, _ string, _ []string) (string, string, error) {
+	e.executed = append(e.executed, pod.Name)
+	return "", "", e.errors[pod.Name]
+}
+
+type fakeESClient struct {
+	esclient.Client
+	response esclient.ReloadSecureSettingsResponse
+	called   bool
+}
+
+func (c *fakeESClient) ReloadSecureSettings(_ context.Context) (esclient.ReloadSecureSettingsResponse, error) {
+	c.called = true
+	return c.response, nil
+}
+
+func reloadTestPod(name string, restartChecksum string, checksum string, ready bool) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      name,
+			Labels:    map[string]string{label.SecureSettingsHashLabelName: restartChecksum},
+		},
+	}
+	if checksum != "" {
+		pod.Annotations = map[string]string{ChecksumAnnotation: checksum}
+	}
+	if ready {
+		pod.Status.Conditions = []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+		}
+	}
+	return pod
+}
+
+func reloadResponse(nodes ...esclient.NodeReloadResult) esclient.ReloadSecureSettingsResponse {
+	response := esclient.ReloadSecureSettingsResponse{Nodes: map[string]esclient.NodeReloadResult{}}
+	for _, node := range nodes {
+		response.Nodes[node.Name+"-id"] = node
+	}
+	return response
+}
+
+func TestReload(t *testing.T) {
+	settings := map[string][]byte{
+		"s3.client.default.access_key":                            []byte("access"),
+		"xpack.security.authc.realms.oidc.oidc1.rp.client_secret": []byte("secret"),
+	}
+	checksum := Checksum(settings)
+	restartChecksum := RestartChecksum(settings)
+	notPropagated := exec.CodeExitError{Err: errors.New("command terminated with exit code 3"), Code: NotPropagatedExitCode}
+
+	tests := []struct {
+		name         string
+		pods         []corev1.Pod
+		execErrors   map[string]error
+		response     esclient.ReloadSecureSettingsResponse
+		wantExecuted []string
+		wantReload   bool
+		wantUpdated  []string
+		wantDone     bool
+		wantErr      bool
+	}{
+		{
+			name: "up-to-date pods",
+			pods: []corev1.Pod{
+				reloadTestPod("pod-0", restartChecksum, checksum, true),
+				reloadTestPod("pod-1", restartChecksum, checksum, true),
+			},
+			wantDone: true,
+		},
+		{
+			name: "pods to restart are skipped",
+			pods: []corev1.Pod{
+				reloadTestPod("pod-0", "outdated", "", true),
+			},
+			wantDone: true,
+		},
+		{
+			name: "outdated pods are updated and reloaded",
+			pods: []corev1.Pod{
+				reloadTestPod("pod-0", restartChecksum, "outdated", true),
+				reloadTestPod("pod-1", restartChecksum, "", true),
+				reloadTestPod("pod-2", restartChecksum, checksum, true),
+			},
+			response:     reloadResponse(esclient.NodeReloadResult{Name: "pod-0"}, esclient.NodeReloadResult{Name: "pod-1"}, esclient.NodeReloadResult{Name: "pod-2"}),
+			wantExecuted: []string{"pod-0", "pod-1"},
+			wantReload:   true,
+			wantUpdated:  []string{"pod-0", "pod-1"},
+			wantDone:     true,
+		},
+		{
+			name: "wait for pods not ready",
+			pods: []corev1.Pod{
+				reloadTestPod("pod-0", restartChecksum, "", false),
+			},
+			wantDone: false,
+		},
+		{
+			name: "wait for the settings to be propagated",
+			pods: []corev1.Pod{
+				reloadTestPod("pod-0", restartChecksum, "", true),
+				reloadTestPod("pod-1", restartChecksum, "", true),
+			},
+			execErrors:   map[string]error{"pod-1": notPropagated},
+			response:     reloadResponse(esclient.NodeReloadResult{Name: "pod-0"}, esclient.NodeReloadResult{Name: "pod-1"}),
+			wantExecuted: []string{"pod-0", "pod-1"},
+			wantReload:   true,
+			wantUpdated:  []string{"pod-0"},
+			wantDone:     false,
+		},
+		{
+			name: "keystore update failure",
+			pods: []corev1.Pod{
+				reloadTestPod("pod-0", restartChecksum, "", true),
+			},
+			execErrors:   map[string]error{"pod-0": exec.CodeExitError{Err: errors.New("command terminated with exit code 1"), Code: 1}},
+			wantExecuted: []string{"pod-0"},
+			wantErr:      true,
+		},
+		{
+			name: "reload failure",
+			pods: []corev1.Pod{
+				reloadTestPod("pod-0", restartChecksum, "", true),
+				reloadTestPod("pod-1", restartChecksum, "", true),
+			},
+			response: reloadResponse(
+				esclient.NodeReloadResult{Name: "pod-0"},
+				esclient.NodeReloadResult{Name: "pod-1", ReloadException: &esclient.ReloadException{Reason: "boom"}},
+			),
+			wantExecuted: []string{"pod-0", "pod-1"},
+			wantReload:   true,
+			wantUpdated:  []string{"pod-0"},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := make([]runtime.Object, 0, len(tt.pods))
+			for i := range tt.pods {
+				objs = append(objs, &tt.pods[i])
+			}
+			c := k8s.WrapClient(fake.NewFakeClient(objs...))
+			executor := &fakeExecutor{errors: tt.execErrors}
+			esClient := &fakeESClient{response: tt.response}
+
+			done, err := Reload(c, executor, esClient, settings, tt.pods)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantDone, done)
+			}
+			require.Equal(t, tt.wantExecuted, executor.executed)
+			require.Equal(t, tt.wantReload, esClient.called)
+
+			var updated []string
+			for _, pod := range tt.pods {
+				var actual corev1.Pod
+				require.NoError(t, c.Get(k8s.ExtractNamespacedName(&pod), &actual))
+				if actual.Annotations[ChecksumAnnotation] == checksum && pod.Annotations[ChecksumAnnotation] != checksum {
+					updated = append(updated, pod.Name)
+				}
+			}
+			require.Equal(t, tt.wantUpdated, updated)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package securesettings
+
+import (
+	"bytes"
+	"path"
+	"text/template"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/initcontainer"
+	esvolume "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/volume"
+)
+
+const (
+	// UpdateKeystoreScriptConfigKey is the key of the keystore update script in the scripts config map.
+	UpdateKeystoreScriptConfigKey = "update-keystore.sh"
+	// NotPropagatedExitCode is the exit code of the keystore update script when the secure settings
+	// mounted in the Pod do not match the expected checksum yet.
+	NotPropagatedExitCode = 3
+)
+
+// updateKeystoreScriptParams are the parameters of the keystore update script template.
+type updateKeystoreScriptParams struct {
+	SecureSettingsPath    string
+	ConfigPath            string
+	KeystoreBinPath       string
+	NotPropagatedExitCode int
+}
+
+// updateKeystoreScript recreates the keystore of a running node from the secure settings mounted in the Pod,
+// once they match the checksum given as argument.
+const updateKeystoreScript = `#!/usr/bin/env bash
+
+set -eu
+
+# match the ordering of the keys in the checksum computed by the operator
+export LC_ALL=C
+
+expected_checksum="$1"
+
+# secure settings are eventually updated in the Pod by the kubelet
+checksum=$(
+	for filename in {{ .SecureSettingsPath }}/*; do
+		[[ -e "$filename" ]] || continue # glob does not match
+		echo "$(basename "$filename") $(sha224sum "$filename" | cut -d ' ' -f 1)"
+	done | sha224sum | cut -d ' ' -f 1
+)
+if [[ "$checksum" != "$expected_checksum" ]]; then
+	echo "Secure settings are not up-to-date yet."
+	exit {{ .NotPropagatedExitCode }}
+fi
+
+# create the keystore next to the current one, to replace it atomically
+tmp_dir=$(mktemp -d -p {{ .ConfigPath }})
+trap 'rm -rf "$tmp_dir"' EXIT
+
+ES_PATH_CONF="$tmp_dir" {{ .KeystoreBinPath }} create
+for filename in {{ .SecureSettingsPath }}/*; do
+	[[ -e "$filename" ]] || continue # glob does not match
+	key=$(basename "$filename")
+	echo "Adding "$key" to the keystore."
+	ES_PATH_CONF="$tmp_dir" {{ .KeystoreBinPath }} add-file "$key" "$filename"
+done
+mv -f "$tmp_dir/elasticsearch.keystore" {{ .ConfigPath }}/elasticsearch.keystore
+
+echo "Keystore update successful."
+`
+
+var updateKeystoreScriptTemplate = template.Must(template.New("").Parse(updateKeystoreScript))
+
+// RenderUpdateKeystoreScript renders the keystore update script, stored in the scripts config map.
+func RenderUpdateKeystoreScript() (string, error) {
+	tplBuffer := bytes.Buffer{}
+	if err := updateKeystoreScriptTemplate.Execute(&tplBuffer, updateKeystoreScriptParams{
+		SecureSettingsPath:    keystore.SecureSettingsVolumeMountPath,
+		ConfigPath:            esvolume.ConfigVolumeMountPath,
+		KeystoreBinPath:       initcontainer.KeystoreBinPath,
+		NotPropagatedExitCode: NotPropagatedExitCode,
+	}); err != nil {
+		return "", err
+	}
+	return tplBuffer.String(), nil
+}
+
+// updateKeystoreCommand returns the command updating the keystore of a node with the secure settings
+// matching the given checksum.
+func updateKeystoreCommand(checksum string) []string {
+	return []string{"bash", path.Join(esvolume.ScriptsVolumeMountPath, UpdateKeystoreScriptConfigKey), checksum}
+}
@@ -0,0 +1,65 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package securesettings
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// reloadablePrefixes are the prefixes of the secure settings Elasticsearch applies when reloading its keystore
+// through the _nodes/reload_secure_settings API. Any other secure setting requires a restart of the node.
+var reloadablePrefixes = []string{
+	"azure.client.",
+	"discovery.ec2.",
+	"gcs.client.",
+	"s3.client.",
+}
+
+// IsReloadable returns true if the given secure setting can be applied without restarting the node.
+func IsReloadable(key string) bool {
+	for _, prefix := range reloadablePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checksum returns a checksum of the given secure settings. It matches the checksum computed by the keystore
+// update script from the secure settings mounted in the Pods: a line with each key and the checksum of its value,
+// in lexicographic order of the keys.
+func Checksum(settings map[string][]byte) string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var lines strings.Builder
+	for _, key := range keys {
+		lines.WriteString(fmt.Sprintf("%s %x\n", key, sha256.Sum224(settings[key])))
+	}
+	return fmt.Sprintf("%x", sha256.Sum224([]byte(lines.String())))
+}
+
+// RestartChecksum returns a checksum of the secure settings that cannot be reloaded. Nodes must be restarted
+// when it changes.
+func RestartChecksum(settings map[string][]byte) string {
+	notReloadable := make(map[string][]byte, len(settings))
+	for key, value := range settings {
+		if !IsReloadable(key) {
+			notReloadable[key] = value
+		}
+	}
+	return Checksum(notReloadable)
+}
+
+// LegacyChecksum returns the value of the secure settings hash label set on the Pods by previous versions of the
+// operator: a checksum of the resource version of the secure settings secret.
+func LegacyChecksum(secretVersion string) string {
+	return fmt.Sprintf("%x", sha256.Sum224([]byte(secretVersion)))
+}
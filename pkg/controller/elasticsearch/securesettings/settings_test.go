@@ -0,0 +1,49 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package securesettings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsReloadable(t *testing.T) {
+	require.True(t, IsReloadable("s3.client.default.access_key"))
+	require.True(t, IsReloadable("gcs.client.default.credentials_file"))
+	require.True(t, IsReloadable("azure.client.secondary.key"))
+	require.True(t, IsReloadable("discovery.ec2.secret_key"))
+	require.False(t, IsReloadable("xpack.security.authc.realms.oidc.oidc1.rp.client_secret"))
+	require.False(t, IsReloadable("s3"))
+}
+
+func TestChecksum(t *testing.T) {
+	settings := map[string][]byte{
+		"s3.client.default.access_key": []byte("secret1"),
+		"a.b":                          []byte("x\ny"),
+		"Z":                            []byte("zz"),
+	}
+	// computed with the keystore update script
+	require.Equal(t, "74d2e976e92a2512cef0a8e626193ee98cd9d0acc9b533d6676d8cd8", Checksum(settings))
+	require.Equal(t, Checksum(nil), Checksum(map[string][]byte{}))
+}
+
+func TestRestartChecksum(t *testing.T) {
+	settings := map[string][]byte{
+		"s3.client.default.access_key":                            []byte("access"),
+		"xpack.security.authc.realms.oidc.oidc1.rp.client_secret": []byte("secret"),
+	}
+	restartChecksum := RestartChecksum(settings)
+
+	// updating a reloadable setting does not change the checksum
+	settings["s3.client.default.access_key"] = []byte("updated")
+	require.Equal(t, restartChecksum, RestartChecksum(settings))
+	// neither does adding one
+	settings["gcs.client.default.credentials_file"] = []byte("credentials")
+	require.Equal(t, restartChecksum, RestartChecksum(settings))
+	// updating a setting that cannot be reloaded does
+	settings["xpack.security.authc.realms.oidc.oidc1.rp.client_secret"] = []byte("updated")
+	require.NotEqual(t, restartChecksum, RestartChecksum(settings))
+}
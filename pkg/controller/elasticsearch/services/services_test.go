@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestExternalServiceURL(t *testing.T) {
@@ -52,6 +53,11 @@ func TestExternalServiceURL(t *testing.T) {
 	}
 }
 
+func TestTransportServiceHost(t *testing.T) {
+	host := TransportServiceHost(types.NamespacedName{Namespace: "default", Name: "an-es-name"})
+	assert.Equal(t, "an-es-name-es-transport.default.svc:9300", host)
+}
+
 func TestElasticsearchURL(t *testing.T) {
 	type args struct {
 		es   v1beta1.Elasticsearch
@@ -7,6 +7,8 @@ package settings
 const (
 	ClusterName = "cluster.name"
 
+	ClusterRoutingAllocationAwarenessAttributes = "cluster.routing.allocation.awareness.attributes"
+
 	DiscoveryZenMinimumMasterNodes = "discovery.zen.minimum_master_nodes"
 	ClusterInitialMasterNodes      = "cluster.initial_master_nodes"
 	DiscoveryZenHostsProvider      = "discovery.zen.hosts_provider"
@@ -14,7 +16,8 @@ const (
 	NetworkHost        = "network.host"
 	NetworkPublishHost = "network.publish_host"
 
-	NodeName = "node.name"
+	NodeName       = "node.name"
+	NodeAttrPrefix = "node.attr."
 
 	PathData = "path.data"
 	PathLogs = "path.logs"
@@ -6,12 +6,14 @@ package settings
 
 import (
 	"path"
+	"strings"
 
 	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	common "github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	escerts "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/volume"
 )
 
@@ -23,6 +25,8 @@ func NewMergedESConfig(
 	httpConfig v1beta1.HTTPConfig,
 	userConfig v1beta1.Config,
 	certResources *escerts.CertificateResources,
+	nodeAttributes nodeattr.Attributes,
+	awareness []string,
 ) (CanonicalConfig, error) {
 	config, err := common.NewCanonicalConfigFrom(userConfig.Data)
 	if err != nil {
@@ -31,6 +35,7 @@ func NewMergedESConfig(
 	err = config.MergeWith(
 		baseConfig(clusterName).CanonicalConfig,
 		xpackConfig(ver, httpConfig, certResources).CanonicalConfig,
+		nodeAttributesConfig(nodeAttributes, awareness).CanonicalConfig,
 	)
 	if err != nil {
 		return CanonicalConfig{}, err
@@ -104,3 +109,17 @@ func xpackConfig(ver version.Version, httpCfg v1beta1.HTTPConfig, certResources
 
 	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
 }
+
+// nodeAttributesConfig returns the configuration of the node attributes derived from Kubernetes node labels,
+// and enables shard allocation awareness on the given attributes. Validation prevents users from also setting
+// the awareness attributes in the configuration.
+func nodeAttributesConfig(attributes nodeattr.Attributes, awareness []string) *CanonicalConfig {
+	cfg := make(map[string]interface{}, len(attributes)+1)
+	for _, name := range attributes.Names() {
+		cfg[NodeAttrPrefix+name] = "${" + nodeattr.EnvVarName(name) + "}"
+	}
+	if len(awareness) > 0 {
+		cfg[ClusterRoutingAllocationAwarenessAttributes] = strings.Join(awareness, ",")
+	}
+	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	"github.com/stretchr/testify/require"
 )
 
@@ -107,9 +108,31 @@ func TestNewMergedESConfig(t *testing.T) {
 				v1beta1.HTTPConfig{},
 				v1beta1.Config{Data: tt.cfgData},
 				&certificates.CertificateResources{},
+				nil,
+				nil,
 			)
 			require.NoError(t, err)
 			tt.assert(cfg)
 		})
 	}
 }
+
+func TestNewMergedESConfig_NodeAttributes(t *testing.T) {
+	ver, err := version.Parse("7.3.0")
+	require.NoError(t, err)
+	cfg, err := NewMergedESConfig(
+		"clusterName",
+		*ver,
+		v1beta1.HTTPConfig{},
+		v1beta1.Config{},
+		&certificates.CertificateResources{},
+		nodeattr.Attributes{nodeattr.ZoneAttribute: "zone-label", "instance_type": "instance-type-label"},
+		[]string{"instance_type", nodeattr.ZoneAttribute},
+	)
+	require.NoError(t, err)
+	rendered, err := cfg.Render()
+	require.NoError(t, err)
+	require.Contains(t, string(rendered), "zone: ${NODE_ATTR_ZONE}")
+	require.Contains(t, string(rendered), "instance_type: ${NODE_ATTR_INSTANCE_TYPE}")
+	require.Contains(t, string(rendered), "awareness:\n        attributes: instance_type,zone")
+}
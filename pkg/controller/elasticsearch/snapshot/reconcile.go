@@ -0,0 +1,38 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package snapshot
+
+import (
+	"context"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var log = logf.Log.WithName("snapshot")
+
+// Reconcile registers the snapshot repositories specified in the Elasticsearch spec, then takes and prunes
+// scheduled snapshots. It returns the snapshot status to report in the Elasticsearch status, which is nil
+// if no schedule is specified, along with a result requeuing the next scheduled snapshot.
+func Reconcile(c esclient.Client, es v1beta1.Elasticsearch, now time.Time) (*v1beta1.SnapshotStatus, reconcile.Result, error) {
+	if es.Spec.Snapshot == nil {
+		return nil, reconcile.Result{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), esclient.DefaultReqTimeout)
+	defer cancel()
+
+	if err := reconcileRepositories(ctx, c, es.Spec.Snapshot.Repositories); err != nil {
+		return nil, reconcile.Result{}, err
+	}
+
+	if es.Spec.Snapshot.Schedule == nil {
+		return nil, reconcile.Result{}, nil
+	}
+	return reconcileSchedule(ctx, c, es, *es.Spec.Snapshot.Schedule, now)
+}
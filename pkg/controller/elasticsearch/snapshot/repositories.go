@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package snapshot
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/maps"
+)
+
+// reconcileRepositories registers the repositories specified in the spec, or updates them if their
+// type or settings differ from the ones currently registered.
+// Repositories registered outside of the operator are left untouched.
+func reconcileRepositories(ctx context.Context, c esclient.Client, repositories []v1beta1.SnapshotRepository) error {
+	if len(repositories) == 0 {
+		return nil
+	}
+	current, err := c.GetSnapshotRepositories(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range repositories {
+		expected := expectedRepository(r)
+		if actual, exists := current[r.Name]; exists && repositoryEqual(expected, actual) {
+			continue
+		}
+		log.Info("Updating snapshot repository", "repository", r.Name, "type", r.Type)
+		if err := c.UpdateSnapshotRepository(ctx, r.Name, expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func expectedRepository(r v1beta1.SnapshotRepository) esclient.SnapshotRepository {
+	settings := map[string]interface{}{}
+	if r.Settings != nil && r.Settings.Data != nil {
+		settings = r.Settings.Data
+	}
+	return esclient.SnapshotRepository{
+		Type:     string(r.Type),
+		Settings: settings,
+	}
+}
+
+// repositoryEqual compares two repositories. Elasticsearch returns all setting values as strings,
+// hence settings are compared based on their flattened string representation.
+func repositoryEqual(expected, actual esclient.SnapshotRepository) bool {
+	return expected.Type == actual.Type &&
+		reflect.DeepEqual(maps.Flatten(expected.Settings), maps.Flatten(actual.Settings))
+}
@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package snapshot
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_reconcileRepositories(t *testing.T) {
+	s3Settings := commonv1beta1.NewConfig(map[string]interface{}{"bucket": "backups", "compress": true})
+	// Elasticsearch returns all settings as strings
+	registered := `{"s3-backups":{"type":"s3","settings":{"bucket":"backups","compress":"true"}}}`
+
+	tests := []struct {
+		name         string
+		repositories []v1beta1.SnapshotRepository
+		wantRequests []string
+	}{
+		{
+			name:         "no repository",
+			repositories: nil,
+			wantRequests: nil,
+		},
+		{
+			name: "repository already registered",
+			repositories: []v1beta1.SnapshotRepository{
+				{Name: "s3-backups", Type: v1beta1.S3RepositoryType, Settings: &s3Settings},
+			},
+			wantRequests: nil,
+		},
+		{
+			name: "new and updated repositories",
+			repositories: []v1beta1.SnapshotRepository{
+				{Name: "s3-backups", Type: v1beta1.S3RepositoryType},
+				{Name: "fs-backups", Type: v1beta1.FsRepositoryType},
+			},
+			wantRequests: []string{
+				`PUT /_snapshot/s3-backups {"type":"s3","settings":{}}`,
+				`PUT /_snapshot/fs-backups {"type":"fs","settings":{}}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			c := esclient.NewMockClient(version.MustParse("7.4.0"), func(req *http.Request) *http.Response {
+				if req.Method == http.MethodGet {
+					return esclient.NewMockResponse(200, req, registered)
+				}
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+				requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+				return esclient.NewMockResponse(200, req, "{}")
+			})
+			require.NoError(t, reconcileRepositories(context.Background(), c, tt.repositories))
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package snapshot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	// RestoreRequestedAnnotationName is used to store the name of the snapshot to restore as an annotation, while the
+	// cluster is not bootstrapped yet. A restore added to the spec of a running cluster is ignored.
+	RestoreRequestedAnnotationName = "elasticsearch.k8s.elastic.co/restore-requested"
+	// RestoredSnapshotAnnotationName is used to store the name of the snapshot restored in a new cluster
+	// as an annotation, right before the restore is triggered.
+	RestoredSnapshotAnnotationName = "elasticsearch.k8s.elastic.co/restored-snapshot"
+	// RestoreCompletedAnnotationName is used to store the name of the restored snapshot as an annotation,
+	// once all the restored indices are available.
+	RestoreCompletedAnnotationName = "elasticsearch.k8s.elastic.co/restore-completed"
+)
+
+// RestoreState is the state of the restore specified in the Elasticsearch spec.
+type RestoreState string
+
+const (
+	// RestoreNotApplicable is returned if there is no snapshot to restore, or if it is already restored.
+	RestoreNotApplicable RestoreState = ""
+	// RestoreIgnored is returned if the restore was specified once the cluster was already bootstrapped.
+	RestoreIgnored RestoreState = "Ignored"
+	// RestoreWaiting is returned until the cluster is bootstrapped and reachable.
+	RestoreWaiting RestoreState = "Waiting"
+	// RestoreStarted is returned when the restore was just triggered.
+	RestoreStarted RestoreState = "Started"
+	// RestoreInProgress is returned while primary shards of the restored indices are being restored.
+	RestoreInProgress RestoreState = "InProgress"
+)
+
+// IsInProgress returns true if the restore is triggered but not complete yet.
+func (s RestoreState) IsInProgress() bool {
+	return s == RestoreStarted || s == RestoreInProgress
+}
+
+// IsPending returns true if the restore is not complete yet, including before it is triggered. Other changes made
+// through the Elasticsearch API must wait for the restore to complete, not to conflict with the restored content.
+func (s RestoreState) IsPending() bool {
+	return s == RestoreWaiting || s.IsInProgress()
+}
+
+// ReconcileRestore restores the snapshot specified in the spec, once the cluster is bootstrapped.
+// The restore is only triggered once, and only if it was specified before the cluster was bootstrapped: the cluster
+// is annotated with the snapshot name before the restore is triggered, and again once the restore is complete.
+func ReconcileRestore(
+	c k8s.Client,
+	esClient esclient.Client,
+	es *v1beta1.Elasticsearch,
+	bootstrapped bool,
+	esReachable bool,
+) (RestoreState, error) {
+	restore := es.Spec.Restore
+	if restore == nil {
+		return RestoreNotApplicable, nil
+	}
+	if _, completed := es.Annotations[RestoreCompletedAnnotationName]; completed {
+		return RestoreNotApplicable, nil
+	}
+
+	restored, started := es.Annotations[RestoredSnapshotAnnotationName]
+	_, requested := es.Annotations[RestoreRequestedAnnotationName]
+	switch {
+	case !started && !requested && bootstrapped:
+		// the restore was added to the spec of a running cluster
+		return RestoreIgnored, nil
+	case !started && !requested:
+		if es.Annotations == nil {
+			es.Annotations = make(map[string]string)
+		}
+		es.Annotations[RestoreRequestedAnnotationName] = restore.Snapshot
+		return RestoreWaiting, c.Update(es)
+	case !bootstrapped || !esReachable:
+		if started {
+			return RestoreInProgress, nil
+		}
+		return RestoreWaiting, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), esclient.DefaultReqTimeout)
+	defer cancel()
+
+	if !started {
+		// persist the restore before triggering it, so that it is never triggered twice
+		es.Annotations[RestoredSnapshotAnnotationName] = restore.Snapshot
+		if err := c.Update(es); err != nil {
+			return RestoreWaiting, err
+		}
+		log.Info("Restoring snapshot", "namespace", es.Namespace, "es_name", es.Name,
+			"repository", restore.Repository, "snapshot", restore.Snapshot)
+		request := esclient.RestoreRequest{
+			Indices:            strings.Join(restore.Indices, ","),
+			IncludeGlobalState: restore.IncludeGlobalState,
+		}
+		if err := esClient.RestoreSnapshot(ctx, restore.Repository, restore.Snapshot, request); err != nil {
+			if !esclient.IsAPIError(err) {
+				// the request may have been accepted by Elasticsearch, its outcome is reflected by the indices health
+				return RestoreStarted, err
+			}
+			// the restore was rejected by Elasticsearch, it can be triggered again
+			delete(es.Annotations, RestoredSnapshotAnnotationName)
+			if updateErr := c.Update(es); updateErr != nil {
+				log.Error(updateErr, "Failed to remove the restored snapshot annotation", "namespace", es.Namespace, "es_name", es.Name)
+			}
+			return RestoreWaiting, err
+		}
+		return RestoreStarted, nil
+	}
+
+	// the restored indices are created with unassigned primary shards as soon as the restore is accepted,
+	// their health stays red until all of their primary shards are restored from the snapshot
+	indices := restore.Indices
+	if len(indices) == 0 {
+		indices = []string{"_all"}
+	}
+	health, err := esClient.GetIndicesHealth(ctx, indices)
+	if err != nil {
+		return RestoreInProgress, err
+	}
+	if v1beta1.ElasticsearchHealth(health.Status) == v1beta1.ElasticsearchRedHealth {
+		log.V(1).Info("Snapshot restore in progress", "namespace", es.Namespace, "es_name", es.Name,
+			"snapshot", restored, "active_primary_shards", health.ActivePrimaryShards,
+			"unassigned_shards", health.UnassignedShards)
+		return RestoreInProgress, nil
+	}
+
+	log.Info("Snapshot restore completed", "namespace", es.Namespace, "es_name", es.Name, "snapshot", restored)
+	es.Annotations[RestoreCompletedAnnotationName] = restored
+	if err := c.Update(es); err != nil {
+		return RestoreInProgress, err
+	}
+	return RestoreNotApplicable, nil
+}
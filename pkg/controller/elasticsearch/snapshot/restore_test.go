@@ -0,0 +1,198 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package snapshot
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileRestore(t *testing.T) {
+	require.NoError(t, v1beta1.AddToScheme(scheme.Scheme))
+
+	esWithRestore := func(annotations map[string]string) *v1beta1.Elasticsearch {
+		var copied map[string]string
+		for k, v := range annotations {
+			if copied == nil {
+				copied = make(map[string]string, len(annotations))
+			}
+			copied[k] = v
+		}
+		return &v1beta1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: copied},
+			Spec: v1beta1.ElasticsearchSpec{
+				Restore: &v1beta1.SnapshotRestore{Repository: "repo", Snapshot: "snap", Indices: []string{"index-1", "index-2"}},
+			},
+		}
+	}
+	requested := map[string]string{RestoreRequestedAnnotationName: "snap"}
+	started := map[string]string{RestoreRequestedAnnotationName: "snap", RestoredSnapshotAnnotationName: "snap"}
+	tests := []struct {
+		name            string
+		es              *v1beta1.Elasticsearch
+		bootstrapped    bool
+		unreachable     bool
+		health          string
+		restoreResponse *http.Response
+		wantState       RestoreState
+		wantErr         bool
+		wantRequests    []string
+		wantAnnotations []string
+	}{
+		{
+			name:         "no restore specified",
+			es:           &v1beta1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}},
+			bootstrapped: true,
+			wantState:    RestoreNotApplicable,
+		},
+		{
+			name:            "new cluster: record the restore to trigger once bootstrapped",
+			es:              esWithRestore(nil),
+			bootstrapped:    false,
+			wantState:       RestoreWaiting,
+			wantAnnotations: []string{RestoreRequestedAnnotationName},
+		},
+		{
+			name:            "cluster not bootstrapped yet",
+			es:              esWithRestore(requested),
+			bootstrapped:    false,
+			wantState:       RestoreWaiting,
+			wantAnnotations: []string{RestoreRequestedAnnotationName},
+		},
+		{
+			name:         "restore added to a running cluster",
+			es:           esWithRestore(nil),
+			bootstrapped: true,
+			wantState:    RestoreIgnored,
+		},
+		{
+			name:            "cluster not reachable yet",
+			es:              esWithRestore(requested),
+			bootstrapped:    true,
+			unreachable:     true,
+			wantState:       RestoreWaiting,
+			wantAnnotations: []string{RestoreRequestedAnnotationName},
+		},
+		{
+			name:         "trigger the restore",
+			es:           esWithRestore(requested),
+			bootstrapped: true,
+			wantState:    RestoreStarted,
+			wantRequests: []string{
+				`POST /_snapshot/repo/snap/_restore {"indices":"index-1,index-2","include_global_state":false}`,
+			},
+			wantAnnotations: []string{RestoreRequestedAnnotationName, RestoredSnapshotAnnotationName},
+		},
+		{
+			name:            "restore rejected by Elasticsearch: triggered again later",
+			es:              esWithRestore(requested),
+			bootstrapped:    true,
+			restoreResponse: &http.Response{StatusCode: 503, Body: ioutil.NopCloser(strings.NewReader(`{}`))},
+			wantState:       RestoreWaiting,
+			wantErr:         true,
+			wantRequests: []string{
+				`POST /_snapshot/repo/snap/_restore {"indices":"index-1,index-2","include_global_state":false}`,
+			},
+			wantAnnotations: []string{RestoreRequestedAnnotationName},
+		},
+		{
+			name:            "restore just accepted, no shard recovery started yet",
+			es:              esWithRestore(started),
+			bootstrapped:    true,
+			health:          `{"status":"red","active_primary_shards":0,"unassigned_shards":4}`,
+			wantState:       RestoreInProgress,
+			wantAnnotations: []string{RestoreRequestedAnnotationName, RestoredSnapshotAnnotationName},
+		},
+		{
+			name:            "restore in progress",
+			es:              esWithRestore(started),
+			bootstrapped:    true,
+			health:          `{"status":"red","active_primary_shards":1,"unassigned_shards":3}`,
+			wantState:       RestoreInProgress,
+			wantAnnotations: []string{RestoreRequestedAnnotationName, RestoredSnapshotAnnotationName},
+		},
+		{
+			name:         "restore complete, replicas not assigned yet",
+			es:           esWithRestore(started),
+			bootstrapped: true,
+			health:       `{"status":"yellow","active_primary_shards":2,"unassigned_shards":2}`,
+			wantState:    RestoreNotApplicable,
+			wantAnnotations: []string{
+				RestoreRequestedAnnotationName, RestoredSnapshotAnnotationName, RestoreCompletedAnnotationName,
+			},
+		},
+		{
+			name: "restore already completed",
+			es: esWithRestore(map[string]string{
+				RestoredSnapshotAnnotationName: "snap", RestoreCompletedAnnotationName: "snap",
+			}),
+			bootstrapped:    true,
+			health:          `{"status":"red"}`,
+			wantState:       RestoreNotApplicable,
+			wantAnnotations: []string{RestoredSnapshotAnnotationName, RestoreCompletedAnnotationName},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			esClient := esclient.NewMockClient(version.MustParse("7.4.0"), func(req *http.Request) *http.Response {
+				if req.Method == http.MethodGet {
+					require.Equal(t, "/_cluster/health/index-1,index-2", req.URL.Path)
+					return esclient.NewMockResponse(200, req, tt.health)
+				}
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+				requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+				if tt.restoreResponse != nil {
+					tt.restoreResponse.Request = req
+					return tt.restoreResponse
+				}
+				return esclient.NewMockResponse(200, req, `{"accepted":true}`)
+			})
+			k8sClient := k8s.WrapClient(fake.NewFakeClient(tt.es))
+
+			state, err := ReconcileRestore(k8sClient, esClient, tt.es, tt.bootstrapped, !tt.unreachable)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantState, state)
+			require.Equal(t, tt.wantRequests, requests)
+
+			var retrieved v1beta1.Elasticsearch
+			require.NoError(t, k8sClient.Get(k8s.ExtractNamespacedName(tt.es), &retrieved))
+			var annotations []string
+			for name := range retrieved.Annotations {
+				annotations = append(annotations, name)
+			}
+			require.ElementsMatch(t, tt.wantAnnotations, annotations)
+		})
+	}
+}
+
+func TestReconcileRestore_AnnotatesBeforeRestoring(t *testing.T) {
+	require.NoError(t, v1beta1.AddToScheme(scheme.Scheme))
+	es := &v1beta1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: map[string]string{RestoreRequestedAnnotationName: "snap"}},
+		Spec:       v1beta1.ElasticsearchSpec{Restore: &v1beta1.SnapshotRestore{Repository: "repo", Snapshot: "snap"}},
+	}
+	// the cluster cannot be updated: the restore must not be triggered
+	k8sClient := k8s.WrapClient(fake.NewFakeClient())
+	esClient := esclient.NewMockClient(version.MustParse("7.4.0"), func(req *http.Request) *http.Response {
+		t.Fatalf("unexpected request %s %s", req.Method, req.URL.Path)
+		return nil
+	})
+	state, err := ReconcileRestore(k8sClient, esClient, es, true, true)
+	require.Error(t, err)
+	require.Equal(t, RestoreWaiting, state)
+}
@@ -0,0 +1,163 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// scheduledSnapshotSuffix is appended to the cluster name to prefix the names of the snapshots taken by the operator.
+	scheduledSnapshotSuffix = "-scheduled-"
+	// snapshotTimeFormat is the format of the time appended to the names of the snapshots taken by the operator.
+	snapshotTimeFormat = "20060102-150405"
+)
+
+// inProgressRequeue is used to refresh the status while a snapshot is running.
+var inProgressRequeue = reconcile.Result{RequeueAfter: 1 * time.Minute}
+
+// scheduledSnapshotPrefix returns the name prefix of the snapshots taken by the operator for this cluster.
+// It includes the namespace, since clusters with the same name in different namespaces can share a repository.
+// Underscores are not allowed in Kubernetes names, which prevents the namespace of a cluster from matching another one.
+// The prefix of a cluster can still match the snapshots of another cluster whose name starts with the same prefix,
+// see isScheduledSnapshot.
+func scheduledSnapshotPrefix(es v1beta1.Elasticsearch) string {
+	return es.Namespace + "_" + es.Name + scheduledSnapshotSuffix
+}
+
+// scheduledSnapshotName returns the name of a snapshot taken by the operator at the given time.
+func scheduledSnapshotName(es v1beta1.Elasticsearch, now time.Time) string {
+	return scheduledSnapshotPrefix(es) + now.UTC().Format(snapshotTimeFormat)
+}
+
+// isScheduledSnapshot returns true if the given snapshot was taken by the operator for this cluster: its name must be
+// the prefix of the cluster followed by a time. This excludes the snapshots of a cluster named, for example,
+// `<name>-scheduled-other`, which start with the same prefix.
+func isScheduledSnapshot(es v1beta1.Elasticsearch, snapshot string) bool {
+	prefix := scheduledSnapshotPrefix(es)
+	if !strings.HasPrefix(snapshot, prefix) {
+		return false
+	}
+	_, err := time.Parse(snapshotTimeFormat, strings.TrimPrefix(snapshot, prefix))
+	return err == nil
+}
+
+// reconcileSchedule takes a new snapshot if the interval since the last one has elapsed,
+// and deletes the snapshots that don't match the retention policy anymore.
+func reconcileSchedule(
+	ctx context.Context,
+	c esclient.Client,
+	es v1beta1.Elasticsearch,
+	schedule v1beta1.SnapshotSchedule,
+	now time.Time,
+) (*v1beta1.SnapshotStatus, reconcile.Result, error) {
+	snapshots, err := scheduledSnapshots(ctx, c, es, schedule.Repository)
+	if err != nil {
+		return nil, reconcile.Result{}, err
+	}
+	status := snapshotStatus(snapshots)
+
+	for _, s := range snapshots {
+		if s.IsInProgress() {
+			// wait for the running snapshot to complete before taking or deleting any other one
+			return status, inProgressRequeue, nil
+		}
+	}
+
+	for _, s := range toDelete(snapshots, schedule.Retention, now) {
+		log.Info("Deleting snapshot", "namespace", es.Namespace, "es_name", es.Name, "snapshot", s.Snapshot)
+		if err := c.DeleteSnapshot(ctx, schedule.Repository, s.Snapshot); err != nil && !esclient.IsNotFound(err) {
+			return status, reconcile.Result{}, err
+		}
+	}
+
+	if len(snapshots) > 0 {
+		next := snapshots[len(snapshots)-1].StartTime().Add(schedule.Interval.Duration)
+		if now.Before(next) {
+			return status, reconcile.Result{RequeueAfter: next.Sub(now)}, nil
+		}
+	}
+
+	name := scheduledSnapshotName(es, now)
+	log.Info("Creating snapshot", "namespace", es.Namespace, "es_name", es.Name, "snapshot", name)
+	if err := c.CreateSnapshot(ctx, schedule.Repository, name); err != nil {
+		return status, reconcile.Result{}, err
+	}
+	return status, inProgressRequeue, nil
+}
+
+// scheduledSnapshots returns the snapshots taken by the operator in the given repository, sorted by start time.
+func scheduledSnapshots(ctx context.Context, c esclient.Client, es v1beta1.Elasticsearch, repository string) ([]esclient.Snapshot, error) {
+	all, err := c.GetSnapshots(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []esclient.Snapshot
+	for _, s := range all.Snapshots {
+		if isScheduledSnapshot(es, s.Snapshot) {
+			snapshots = append(snapshots, s)
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].StartTimeInMillis < snapshots[j].StartTimeInMillis
+	})
+	return snapshots, nil
+}
+
+// snapshotStatus returns the status reporting the latest successful and failed snapshots among the given sorted ones.
+func snapshotStatus(snapshots []esclient.Snapshot) *v1beta1.SnapshotStatus {
+	status := v1beta1.SnapshotStatus{}
+	for _, s := range snapshots {
+		// truncate to the second to match the precision of the serialized status
+		startTime := metav1.NewTime(s.StartTime().Truncate(time.Second))
+		switch {
+		case s.IsInProgress():
+			continue
+		case s.IsSuccess():
+			status.LastSuccessSnapshot = s.Snapshot
+			status.LastSuccessTime = &startTime
+		default:
+			status.LastFailureSnapshot = s.Snapshot
+			status.LastFailureTime = &startTime
+			status.LastFailureReason = s.Reason
+			if status.LastFailureReason == "" {
+				status.LastFailureReason = fmt.Sprintf("snapshot state is %s", s.State)
+			}
+		}
+	}
+	return &status
+}
+
+// toDelete returns the snapshots from the given sorted ones that are beyond the maximum count or expired,
+// except for the most recent successful one.
+func toDelete(snapshots []esclient.Snapshot, retention v1beta1.SnapshotRetention, now time.Time) []esclient.Snapshot {
+	lastSuccess := -1
+	for i, s := range snapshots {
+		if s.IsSuccess() {
+			lastSuccess = i
+		}
+	}
+	var deletions []esclient.Snapshot
+	for i, s := range snapshots {
+		if i == lastSuccess {
+			continue
+		}
+		beyondMaxCount := retention.MaxCount > 0 && len(snapshots)-i > int(retention.MaxCount)
+		expired := retention.ExpireAfter != nil && s.StartTime().Add(retention.ExpireAfter.Duration).Before(now)
+		if beyondMaxCount || expired {
+			deletions = append(deletions, s)
+		}
+	}
+	return deletions
+}
@@ -0,0 +1,208 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	testES  = v1beta1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	testNow = time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func snapshotAt(name string, state esclient.SnapshotState, startTime time.Time) esclient.Snapshot {
+	return esclient.Snapshot{
+		Snapshot:          name,
+		State:             state,
+		StartTimeInMillis: startTime.UnixNano() / int64(time.Millisecond),
+	}
+}
+
+// fakeSnapshotAPI returns a RoundTripFunc serving the given snapshots and recording mutating requests.
+func fakeSnapshotAPI(t *testing.T, snapshots []esclient.Snapshot, requests *[]string) esclient.RoundTripFunc {
+	return func(req *http.Request) *http.Response {
+		if req.Method == http.MethodGet {
+			body, err := json.Marshal(esclient.Snapshots{Snapshots: snapshots})
+			require.NoError(t, err)
+			return esclient.NewMockResponse(200, req, string(body))
+		}
+		*requests = append(*requests, req.Method+" "+req.URL.Path)
+		return esclient.NewMockResponse(200, req, "{}")
+	}
+}
+
+func Test_reconcileSchedule(t *testing.T) {
+	schedule := v1beta1.SnapshotSchedule{
+		Repository: "repo",
+		Interval:   metav1.Duration{Duration: time.Hour},
+		Retention:  v1beta1.SnapshotRetention{MaxCount: 2},
+	}
+	tests := []struct {
+		name         string
+		snapshots    []esclient.Snapshot
+		wantRequests []string
+		wantResult   reconcile.Result
+		wantStatus   v1beta1.SnapshotStatus
+	}{
+		{
+			name:         "no snapshot yet: take one",
+			wantRequests: []string{"PUT /_snapshot/repo/ns_es-scheduled-20191001-120000"},
+			wantResult:   inProgressRequeue,
+		},
+		{
+			name: "recent snapshot: requeue for the next one",
+			snapshots: []esclient.Snapshot{
+				snapshotAt("ns_es-scheduled-20191001-114000", esclient.SnapshotSuccess, testNow.Add(-20*time.Minute)),
+				// not taken by the operator for this cluster
+				snapshotAt("manual", esclient.SnapshotSuccess, testNow.Add(-2*time.Hour)),
+			},
+			wantResult: reconcile.Result{RequeueAfter: 40 * time.Minute},
+			wantStatus: v1beta1.SnapshotStatus{
+				LastSuccessSnapshot: "ns_es-scheduled-20191001-114000",
+				LastSuccessTime:     &metav1.Time{Time: testNow.Add(-20 * time.Minute).Local()},
+			},
+		},
+		{
+			name: "snapshot in progress: wait for completion",
+			snapshots: []esclient.Snapshot{
+				snapshotAt("ns_es-scheduled-20191001-090000", esclient.SnapshotFailed, testNow.Add(-3*time.Hour)),
+				snapshotAt("ns_es-scheduled-20191001-100000", esclient.SnapshotSuccess, testNow.Add(-2*time.Hour)),
+				snapshotAt("ns_es-scheduled-20191001-115900", esclient.SnapshotInProgress, testNow.Add(-1*time.Minute)),
+			},
+			wantResult: inProgressRequeue,
+			wantStatus: v1beta1.SnapshotStatus{
+				LastSuccessSnapshot: "ns_es-scheduled-20191001-100000",
+				LastSuccessTime:     &metav1.Time{Time: testNow.Add(-2 * time.Hour).Local()},
+				LastFailureSnapshot: "ns_es-scheduled-20191001-090000",
+				LastFailureTime:     &metav1.Time{Time: testNow.Add(-3 * time.Hour).Local()},
+				LastFailureReason:   "snapshot state is FAILED",
+			},
+		},
+		{
+			name: "interval elapsed: prune old snapshots and take a new one",
+			snapshots: []esclient.Snapshot{
+				snapshotAt("ns_es-scheduled-20191001-080000", esclient.SnapshotSuccess, testNow.Add(-4*time.Hour)),
+				snapshotAt("ns_es-scheduled-20191001-090000", esclient.SnapshotSuccess, testNow.Add(-3*time.Hour)),
+				snapshotAt("ns_es-scheduled-20191001-100000", esclient.SnapshotSuccess, testNow.Add(-2*time.Hour)),
+			},
+			wantRequests: []string{
+				"DELETE /_snapshot/repo/ns_es-scheduled-20191001-080000",
+				"PUT /_snapshot/repo/ns_es-scheduled-20191001-120000",
+			},
+			wantResult: inProgressRequeue,
+			wantStatus: v1beta1.SnapshotStatus{
+				LastSuccessSnapshot: "ns_es-scheduled-20191001-100000",
+				LastSuccessTime:     &metav1.Time{Time: testNow.Add(-2 * time.Hour).Local()},
+			},
+		},
+		{
+			name: "snapshots of a cluster with the same name in another namespace are ignored",
+			snapshots: []esclient.Snapshot{
+				snapshotAt("other_es-scheduled-20191001-080000", esclient.SnapshotSuccess, testNow.Add(-4*time.Hour)),
+				snapshotAt("other_es-scheduled-20191001-090000", esclient.SnapshotSuccess, testNow.Add(-3*time.Hour)),
+				snapshotAt("other_es-scheduled-20191001-114000", esclient.SnapshotSuccess, testNow.Add(-20*time.Minute)),
+			},
+			wantRequests: []string{"PUT /_snapshot/repo/ns_es-scheduled-20191001-120000"},
+			wantResult:   inProgressRequeue,
+		},
+		{
+			name: "snapshots of a cluster whose name starts with the prefix of this cluster are ignored",
+			snapshots: []esclient.Snapshot{
+				snapshotAt("ns_es-scheduled-x-scheduled-20191001-080000", esclient.SnapshotSuccess, testNow.Add(-4*time.Hour)),
+				snapshotAt("ns_es-scheduled-x-scheduled-20191001-090000", esclient.SnapshotSuccess, testNow.Add(-3*time.Hour)),
+				snapshotAt("ns_es-scheduled-x-scheduled-20191001-114000", esclient.SnapshotSuccess, testNow.Add(-20*time.Minute)),
+			},
+			wantRequests: []string{"PUT /_snapshot/repo/ns_es-scheduled-20191001-120000"},
+			wantResult:   inProgressRequeue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			c := esclient.NewMockClient(version.MustParse("7.4.0"), fakeSnapshotAPI(t, tt.snapshots, &requests))
+			status, result, err := reconcileSchedule(context.Background(), c, testES, schedule, testNow)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRequests, requests)
+			require.Equal(t, tt.wantResult, result)
+			require.Equal(t, tt.wantStatus, *status)
+		})
+	}
+}
+
+func Test_isScheduledSnapshot(t *testing.T) {
+	es := testES
+	other := v1beta1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-scheduled-x"}}
+	tests := []struct {
+		snapshot  string
+		wantES    bool
+		wantOther bool
+	}{
+		{snapshot: scheduledSnapshotName(es, testNow), wantES: true},
+		{snapshot: scheduledSnapshotName(other, testNow), wantOther: true},
+		{snapshot: "ns_es-scheduled-"},
+		{snapshot: "ns_es-scheduled-20191001-120000-manual"},
+		{snapshot: "manual"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.snapshot, func(t *testing.T) {
+			require.Equal(t, tt.wantES, isScheduledSnapshot(es, tt.snapshot))
+			require.Equal(t, tt.wantOther, isScheduledSnapshot(other, tt.snapshot))
+		})
+	}
+}
+
+func Test_toDelete(t *testing.T) {
+	old := snapshotAt("old", esclient.SnapshotSuccess, testNow.Add(-72*time.Hour))
+	failed := snapshotAt("failed", esclient.SnapshotFailed, testNow.Add(-48*time.Hour))
+	recent := snapshotAt("recent", esclient.SnapshotSuccess, testNow.Add(-1*time.Hour))
+	failedRecent := snapshotAt("failed-recent", esclient.SnapshotPartial, testNow.Add(-1*time.Minute))
+	tests := []struct {
+		name      string
+		snapshots []esclient.Snapshot
+		retention v1beta1.SnapshotRetention
+		want      []esclient.Snapshot
+	}{
+		{
+			name:      "no retention policy",
+			snapshots: []esclient.Snapshot{old, failed, recent},
+			want:      nil,
+		},
+		{
+			name:      "max count",
+			snapshots: []esclient.Snapshot{old, failed, recent},
+			retention: v1beta1.SnapshotRetention{MaxCount: 1},
+			want:      []esclient.Snapshot{old, failed},
+		},
+		{
+			name:      "expiry",
+			snapshots: []esclient.Snapshot{old, failed, recent},
+			retention: v1beta1.SnapshotRetention{ExpireAfter: &metav1.Duration{Duration: 50 * time.Hour}},
+			want:      []esclient.Snapshot{old},
+		},
+		{
+			name:      "the latest successful snapshot is always kept",
+			snapshots: []esclient.Snapshot{old, failed, failedRecent},
+			retention: v1beta1.SnapshotRetention{MaxCount: 1, ExpireAfter: &metav1.Duration{Duration: time.Hour}},
+			want:      []esclient.Snapshot{failed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, toDelete(tt.snapshots, tt.retention, testNow))
+		})
+	}
+}
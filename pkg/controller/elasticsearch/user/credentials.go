@@ -53,8 +53,9 @@ type UserCredentials interface {
 
 // ClearTextCredentials store a secret with clear text passwords.
 type ClearTextCredentials struct {
-	users  []User
-	secret corev1.Secret
+	users    []User
+	secret   corev1.Secret
+	rotation string
 }
 
 func keysEqual(v1, v2 map[string][]byte) bool {
@@ -78,8 +79,20 @@ func (c *ClearTextCredentials) Reset(secret corev1.Secret) {
 	}
 }
 
-// NeedsUpdate is true for clear text credentials if the secret contains the same keys as the reference secret.
+// WithRotation sets the token of the requested credentials rotation: passwords generated for another rotation
+// are replaced.
+func (c *ClearTextCredentials) WithRotation(rotation string) *ClearTextCredentials {
+	c.rotation = rotation
+	common.SetRotation(rotation, &c.secret)
+	return c
+}
+
+// NeedsUpdate is true for clear text credentials if the secret does not contain the same keys as the reference secret,
+// or if its passwords were not generated for the requested rotation.
 func (c *ClearTextCredentials) NeedsUpdate(other corev1.Secret) bool {
+	if common.NeedsRotation(c.rotation, other) {
+		return true
+	}
 	// for generated secrets as long as the key exists we can work with it. Rotate secrets by deleting them (?)
 	for _, user := range c.users {
 		if _, ok := other.Data[user.Id()]; !ok {
@@ -110,8 +123,26 @@ func (hc *HashedCredentials) Reset(secret corev1.Secret) {
 	hc.secret = secret
 }
 
+// WithIgnoredFileRealmUsers records the user-provided file realm users ignored by the operator in an annotation
+// of the secret, so that they are only reported when they are first ignored.
+func (hc *HashedCredentials) WithIgnoredFileRealmUsers(ignored []string) *HashedCredentials {
+	if len(ignored) == 0 {
+		return hc
+	}
+	sort.Strings(ignored)
+	if hc.secret.Annotations == nil {
+		hc.secret.Annotations = map[string]string{}
+	}
+	hc.secret.Annotations[IgnoredFileRealmUsersAnnotation] = strings.Join(ignored, ",")
+	return hc
+}
+
 // NeedsUpdate checks whether the secret data in other matches the user information in these credentials.
 func (hc *HashedCredentials) NeedsUpdate(other corev1.Secret) bool {
+	if hc.secret.Annotations[IgnoredFileRealmUsersAnnotation] != other.Annotations[IgnoredFileRealmUsersAnnotation] {
+		return true
+	}
+
 	if !keysEqual(hc.secret.Data, other.Data) {
 		return true
 	}
@@ -90,6 +90,9 @@ func newTestCredentials(t *testing.T, users []user.User) UserCredentials {
 
 func TestNeedsUpdate(t *testing.T) {
 	otherUser := New("baz", Password("yolo"))
+	withIgnoredUsers, err := NewElasticUsersCredentialsAndRoles(testES, testUser, testRole)
+	assert.NoError(t, err)
+	withIgnoredUsers.WithIgnoredFileRealmUsers([]string{"secret/foo"})
 
 	tests := []struct {
 		desc        string
@@ -139,6 +142,12 @@ func TestNeedsUpdate(t *testing.T) {
 			subject2:    newTestCredentials(t, []user.User{otherUser, testUser[0]}),
 			needsUpdate: false,
 		},
+		{
+			desc:        "hashed creds: newly ignored file realm users warrant an update of the secret",
+			subject1:    withIgnoredUsers,
+			subject2:    newTestCredentials(t, testUser),
+			needsUpdate: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,194 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/user"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Keys of the secrets holding user-provided file realm users.
+const (
+	// FileRealmUsersKey holds users in the format of the file realm `users` file.
+	FileRealmUsersKey = ElasticUsersFile
+	// FileRealmUsersRolesKey holds roles assignments in the format of the file realm `users_roles` file.
+	FileRealmUsersRolesKey = ElasticUsersRolesFile
+	// FileRealmNameKey holds the name of a single user.
+	FileRealmNameKey = "name"
+	// FileRealmPasswordHashKey holds the bcrypt hash of the password of a single user.
+	FileRealmPasswordHashKey = "passwordHash"
+	// FileRealmPasswordKey holds the clear text password of a single user.
+	FileRealmPasswordKey = "password"
+	// FileRealmRolesKey holds the comma-separated roles of a single user.
+	FileRealmRolesKey = "roles"
+)
+
+// IgnoredFileRealmUsersAnnotation is set on the aggregated file realm secret to the comma-separated list of
+// the user-provided file realm secrets and users ignored by the operator, as <secret> or <secret>/<user>.
+const IgnoredFileRealmUsersAnnotation = "elasticsearch.k8s.elastic.co/ignored-file-realm-users"
+
+// providedUser is a file realm user read from a user-provided secret.
+type providedUser struct {
+	secret string
+	user   user.User
+}
+
+// mergeFileRealmUsers adds the users from the file realm secrets referenced in the security spec to the given users.
+// User-provided users with the same name as one of the given users, or an already provided user, are ignored, as well
+// as invalid secrets. They are returned, and reported in a warning event unless they were already reported.
+func mergeFileRealmUsers(
+	c k8s.Client,
+	es v1beta1.Elasticsearch,
+	users []user.User,
+	reported []string,
+	recorder *events.Recorder,
+) ([]user.User, []string, error) {
+	names := make(map[string]bool, len(users))
+	for _, u := range users {
+		names[u.Id()] = true
+	}
+	var ignored []string
+	ignore := func(key string, msg string) {
+		ignored = append(ignored, key)
+		if !stringsutil.StringInSlice(key, reported) {
+			recorder.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, msg)
+		}
+	}
+	for _, secretName := range es.Spec.Security.FileRealmSecretNames() {
+		var secret corev1.Secret
+		if err := c.Get(types.NamespacedName{Namespace: es.Namespace, Name: secretName}, &secret); err != nil {
+			return nil, nil, err
+		}
+		provided, err := parseFileRealmSecret(secret)
+		if err != nil {
+			ignore(secretName, fmt.Sprintf("Ignoring file realm secret %s: %s", secretName, err.Error()))
+			continue
+		}
+		for _, p := range provided {
+			if names[p.user.Id()] {
+				ignore(
+					p.secret+"/"+p.user.Id(),
+					fmt.Sprintf("Ignoring user %s from file realm secret %s: the name is reserved or already used", p.user.Id(), p.secret),
+				)
+				continue
+			}
+			names[p.user.Id()] = true
+			users = append(users, p.user)
+		}
+	}
+	return users, ignored, nil
+}
+
+// ignoredFileRealmUsers returns the user-provided file realm secrets and users already reported as ignored.
+func ignoredFileRealmUsers(c k8s.Client, es v1beta1.Elasticsearch) ([]string, error) {
+	var secret corev1.Secret
+	err := c.Get(types.NamespacedName{Namespace: es.Namespace, Name: XPackFileRealmSecretName(es.Name)}, &secret)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ignored := secret.Annotations[IgnoredFileRealmUsersAnnotation]
+	if ignored == "" {
+		return nil, nil
+	}
+	return strings.Split(ignored, ","), nil
+}
+
+// parseFileRealmSecret returns the users held in the given secret, either in the file realm files format,
+// or as a single user.
+func parseFileRealmSecret(secret corev1.Secret) ([]providedUser, error) {
+	if usersFile, exists := secret.Data[FileRealmUsersKey]; exists {
+		return parseFileRealmFiles(secret.Name, usersFile, secret.Data[FileRealmUsersRolesKey])
+	}
+	name := string(secret.Data[FileRealmNameKey])
+	if name == "" {
+		return nil, fmt.Errorf("neither %s nor %s keys found", FileRealmUsersKey, FileRealmNameKey)
+	}
+	var roles []string
+	for _, r := range strings.Split(string(secret.Data[FileRealmRolesKey]), ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			roles = append(roles, r)
+		}
+	}
+	if hash, exists := secret.Data[FileRealmPasswordHashKey]; exists {
+		if err := validatePasswordHash(name, hash); err != nil {
+			return nil, err
+		}
+		u := user.NewExternalUser(name, hash, roles)
+		return []providedUser{{secret: secret.Name, user: &u}}, nil
+	}
+	if password, exists := secret.Data[FileRealmPasswordKey]; exists && len(password) > 0 {
+		return []providedUser{{secret: secret.Name, user: New(name, Password(string(password)), Roles(roles...))}}, nil
+	}
+	return nil, fmt.Errorf("neither %s nor %s keys found for user %s", FileRealmPasswordHashKey, FileRealmPasswordKey, name)
+}
+
+// parseFileRealmFiles parses the content of the file realm `users` and `users_roles` files.
+func parseFileRealmFiles(secretName string, usersFile []byte, usersRolesFile []byte) ([]providedUser, error) {
+	userRoles := map[string][]string{}
+	for _, line := range fileLines(usersRolesFile) {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid line in %s: %s", FileRealmUsersRolesKey, line)
+		}
+		role := strings.TrimSpace(parts[0])
+		if role == "" {
+			return nil, fmt.Errorf("invalid line in %s: %s", FileRealmUsersRolesKey, line)
+		}
+		for _, u := range strings.Split(parts[1], ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				userRoles[u] = append(userRoles[u], role)
+			}
+		}
+	}
+
+	var users []providedUser
+	for _, line := range fileLines(usersFile) {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid line in %s", FileRealmUsersKey)
+		}
+		hash := []byte(parts[1])
+		if err := validatePasswordHash(parts[0], hash); err != nil {
+			return nil, err
+		}
+		u := user.NewExternalUser(parts[0], hash, userRoles[parts[0]])
+		users = append(users, providedUser{secret: secretName, user: &u})
+	}
+	return users, nil
+}
+
+// fileLines returns the non-empty lines of the given file, ignoring comments.
+func fileLines(file []byte) []string {
+	var lines []string
+	for _, line := range strings.Split(string(file), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// validatePasswordHash checks that the given password hash is a valid bcrypt hash.
+func validatePasswordHash(name string, hash []byte) error {
+	if _, err := bcrypt.Cost(hash); err != nil {
+		return fmt.Errorf("invalid bcrypt password hash for user %s: %s", name, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"testing"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/user"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testHash = "$2a$10$D6q/zdYfGJsJxipsZ4Jioul8tWIcL.o.Mhx/as1nlNdOX6EgqRRRS"
+
+func Test_parseFileRealmSecret(t *testing.T) {
+	type wantUser struct {
+		name  string
+		roles []string
+	}
+	tests := []struct {
+		name    string
+		data    map[string][]byte
+		want    []wantUser
+		wantErr bool
+	}{
+		{
+			name: "users and users_roles files",
+			data: map[string][]byte{
+				FileRealmUsersKey:      []byte("# comment\njane:" + testHash + "\n\njohn:" + testHash + "\n"),
+				FileRealmUsersRolesKey: []byte("viewer:jane,john\nadmin:jane\n"),
+			},
+			want: []wantUser{{name: "jane", roles: []string{"viewer", "admin"}}, {name: "john", roles: []string{"viewer"}}},
+		},
+		{
+			name:    "invalid hash in users file",
+			data:    map[string][]byte{FileRealmUsersKey: []byte("jane:changeme")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid users_roles file",
+			data:    map[string][]byte{FileRealmUsersKey: []byte("jane:" + testHash), FileRealmUsersRolesKey: []byte("viewer")},
+			wantErr: true,
+		},
+		{
+			name: "single user with a password hash",
+			data: map[string][]byte{
+				FileRealmNameKey:         []byte("jane"),
+				FileRealmPasswordHashKey: []byte(testHash),
+				FileRealmRolesKey:        []byte("viewer, admin,"),
+			},
+			want: []wantUser{{name: "jane", roles: []string{"viewer", "admin"}}},
+		},
+		{
+			name: "single user with a clear text password",
+			data: map[string][]byte{FileRealmNameKey: []byte("jane"), FileRealmPasswordKey: []byte("changeme")},
+			want: []wantUser{{name: "jane"}},
+		},
+		{
+			name:    "single user with an invalid password hash",
+			data:    map[string][]byte{FileRealmNameKey: []byte("jane"), FileRealmPasswordHashKey: []byte("changeme")},
+			wantErr: true,
+		},
+		{
+			name:    "single user without password",
+			data:    map[string][]byte{FileRealmNameKey: []byte("jane")},
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			data:    map[string][]byte{"foo": []byte("bar")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, err := parseFileRealmSecret(corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "users"}, Data: tt.data})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			got := make([]wantUser, 0, len(users))
+			for _, u := range users {
+				require.Equal(t, "users", u.secret)
+				got = append(got, wantUser{name: u.user.Id(), roles: u.user.Roles()})
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_mergeFileRealmUsers(t *testing.T) {
+	es := v1beta1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: v1beta1.ElasticsearchSpec{Security: &v1beta1.SecuritySpec{
+			FileRealm: []commonv1beta1.SecretRef{{SecretName: "team-a"}, {SecretName: "team-b"}, {SecretName: "invalid"}},
+		}},
+	}
+	c := k8s.WrapClient(fake.NewFakeClient(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "team-a"},
+			Data: map[string][]byte{
+				FileRealmUsersKey: []byte("jane:" + testHash + "\n" + InternalControllerUserName + ":" + testHash),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "team-b"},
+			Data:       map[string][]byte{FileRealmNameKey: []byte("jane"), FileRealmPasswordKey: []byte("changeme")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "invalid"},
+			Data:       map[string][]byte{FileRealmNameKey: []byte("john")},
+		},
+	))
+	recorder := events.NewRecorder()
+	existing := []user.User{New(InternalControllerUserName), New(ExternalUserName)}
+	users, ignored, err := mergeFileRealmUsers(c, es, existing, nil, recorder)
+	require.NoError(t, err)
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		names = append(names, u.Id())
+	}
+	require.Equal(t, []string{InternalControllerUserName, ExternalUserName, "jane"}, names)
+	// reserved name, duplicate user, invalid secret
+	require.Equal(t, []string{"team-a/" + InternalControllerUserName, "team-b/jane", "invalid"}, ignored)
+	require.Len(t, recorder.Events(), 3)
+
+	// already reported
+	recorder = events.NewRecorder()
+	_, _, err = mergeFileRealmUsers(c, es, existing, []string{"team-b/jane", "invalid"}, recorder)
+	require.NoError(t, err)
+	require.Len(t, recorder.Events(), 1)
+
+	// missing secret
+	es.Spec.Security.FileRealm = append(es.Spec.Security.FileRealm, commonv1beta1.SecretRef{SecretName: "missing"})
+	_, _, err = mergeFileRealmUsers(c, es, existing, nil, recorder)
+	require.Error(t, err)
+}
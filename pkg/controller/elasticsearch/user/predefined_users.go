@@ -13,6 +13,11 @@ const (
 	InternalControllerUserName = "elastic-internal"
 	// InternalProbeUserName is a user to be used from the liveness/readiness probes when interacting with ES.
 	InternalProbeUserName = "elastic-internal-probe"
+	// PreviousProbeUserNameSuffix is appended to the probe user name to form the name of the user holding the probe
+	// password replaced by a rotation, which the readiness probe falls back to during the rotation grace period.
+	PreviousProbeUserNameSuffix = "-previous"
+	// InternalPreviousProbeUserName is the user holding the probe password replaced by a rotation.
+	InternalPreviousProbeUserName = InternalProbeUserName + PreviousProbeUserNameSuffix
 	// InternalKeystoreUserName is a user to be used for reloading ES secure settings from the keystore.
 	InternalKeystoreUserName = "elastic-internal-keystore"
 
@@ -59,6 +64,12 @@ type InternalUsers struct {
 	ControllerUser User
 	ProbeUser      User
 	KeystoreUser   User
+	// PreviousControllerUser holds the controller user password replaced by a rotation, during the rotation grace period
+	// and until Elasticsearch accepts the new password.
+	PreviousControllerUser *User
+	// PreviousCredentialsRetained is true during the grace period following a rotation, including the grace period of
+	// the association users.
+	PreviousCredentialsRetained bool
 }
 
 // NewInternalUsersFrom constructs a new struct with internal users from the given credentials of those users.
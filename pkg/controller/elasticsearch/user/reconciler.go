@@ -5,9 +5,13 @@
 package user
 
 import (
+	"time"
+
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/user"
+	esname "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -33,7 +37,19 @@ func ReconcileUserCredentialsSecret(
 			return creds.NeedsUpdate(*reconciled)
 		},
 		UpdateReconciled: func() {
-			reconciled.Data = expected.Data // only update data, keep the rest
+			// only update data, the rotation and the ignored users annotations, keep the rest
+			reconciled.Data = expected.Data
+			if rotation, exists := expected.Annotations[user.CredentialsRotationAnnotation]; exists {
+				user.SetRotation(rotation, reconciled)
+			}
+			if ignored, exists := expected.Annotations[IgnoredFileRealmUsersAnnotation]; exists {
+				if reconciled.Annotations == nil {
+					reconciled.Annotations = map[string]string{}
+				}
+				reconciled.Annotations[IgnoredFileRealmUsersAnnotation] = ignored
+			} else {
+				delete(reconciled.Annotations, IgnoredFileRealmUsersAnnotation)
+			}
 		},
 	})
 	if err == nil {
@@ -71,19 +87,42 @@ func aggregateAllUsers(customUsers corev1.SecretList, defaultUsers ...ClearTextC
 // into the Elasticsearch config directory which the file realm of ES security can directly understand.
 // A second file called 'users_roles' is contained in this third secret as well which describes
 // role assignments for the users specified in the first file.
+// Users from the file realm secrets referenced in the security spec are added to the aggregated secret,
+// unless their name conflicts with another user. Ignored users are reported once, when they are first ignored.
+// Passwords of the 'internal-users' and 'elastic-user' secrets are regenerated when a rotation is requested through
+// the rotate credentials annotation. The replaced passwords are retained in separate secrets during a grace period,
+// and the previous controller user is returned to be used until Elasticsearch accepts the new passwords. The previous
+// probe password remains valid in the file realm during the grace period, for the readiness probe to use until the
+// Pods see the new one, as well as the association users replaced by a rotation.
 func ReconcileUsers(
 	c k8s.Client,
 	scheme *runtime.Scheme,
 	es v1beta1.Elasticsearch,
+	recorder *events.Recorder,
 ) (*InternalUsers, error) {
+	defer reconciler.ObserveDuration(v1beta1.Kind, "users")()
 
 	nsn := k8s.ExtractNamespacedName(&es)
-	internalSecrets := NewInternalUserCredentials(nsn)
+	rotation := user.RequestedRotation(&es)
+	now := time.Now()
+
+	previousInternalSecret, err := retainPreviousCredentials(
+		c, scheme, es, ElasticInternalUsersSecretName(es.Name), esname.PreviousInternalUsersSecret(es.Name), rotation, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	internalSecrets := NewInternalUserCredentials(nsn).WithRotation(rotation)
 	if err := ReconcileUserCredentialsSecret(c, scheme, es, internalSecrets); err != nil {
 		return nil, err
 	}
 
-	externalSecrets := NewExternalUserCredentials(nsn)
+	if _, err := retainPreviousCredentials(
+		c, scheme, es, ElasticExternalUsersSecretName(es.Name), esname.PreviousElasticUserSecret(es.Name), rotation, now,
+	); err != nil {
+		return nil, err
+	}
+	externalSecrets := NewExternalUserCredentials(nsn).WithRotation(rotation)
 	if err := ReconcileUserCredentialsSecret(c, scheme, es, externalSecrets); err != nil {
 		return nil, err
 	}
@@ -94,18 +133,59 @@ func ReconcileUsers(
 	if err := c.List(&customUsers, ns, matchLabels); err != nil {
 		return nil, err
 	}
+	customUsers, retainedUsers, err := expireRetainedUsers(c, customUsers, now)
+	if err != nil {
+		return nil, err
+	}
 
 	allUsers, err := aggregateAllUsers(customUsers, *internalSecrets, *externalSecrets)
 	if err != nil {
 		return nil, err
 	}
-	elasticUsersRolesSecret, err := NewElasticUsersCredentialsAndRoles(nsn, allUsers, PredefinedRoles)
+	if previousProbeUser := previousProbeUser(previousInternalSecret, *internalSecrets); previousProbeUser != nil {
+		allUsers = append(allUsers, previousProbeUser)
+	}
+	reported, err := ignoredFileRealmUsers(c, es)
 	if err != nil {
 		return nil, err
 	}
+	allUsers, ignored, err := mergeFileRealmUsers(c, es, allUsers, reported, recorder)
+	if err != nil {
+		return nil, err
+	}
+	elasticUsersRolesSecret, err := NewElasticUsersCredentialsAndRoles(nsn, allUsers, RolesFor(es))
+	if err != nil {
+		return nil, err
+	}
+	elasticUsersRolesSecret.WithIgnoredFileRealmUsers(ignored)
 	if err := ReconcileUserCredentialsSecret(c, scheme, es, elasticUsersRolesSecret); err != nil {
 		return nil, err
 	}
 
-	return NewInternalUsersFrom(*internalSecrets), nil
+	internalUsers := NewInternalUsersFrom(*internalSecrets)
+	internalUsers.PreviousCredentialsRetained = retainedUsers
+	if previousInternalSecret != nil {
+		internalUsers.PreviousCredentialsRetained = true
+		password, exists := previousInternalSecret.Data[InternalControllerUserName]
+		accepted := previousInternalSecret.Annotations[NewPasswordAcceptedAnnotation] == "true"
+		if exists && !accepted && string(password) != internalUsers.ControllerUser.Password() {
+			previous := New(InternalControllerUserName, Password(string(password)), Roles(SuperUserBuiltinRole))
+			internalUsers.PreviousControllerUser = &previous
+		}
+	}
+	return internalUsers, nil
+}
+
+// previousProbeUser returns the user holding the probe password replaced by a rotation, during the rotation grace
+// period. The readiness probe reads the probe password from a secret volume updated independently of the file realm:
+// it falls back to that user while the Pods still see the previous password.
+func previousProbeUser(previousInternalSecret *corev1.Secret, internalSecrets ClearTextCredentials) user.User {
+	if previousInternalSecret == nil {
+		return nil
+	}
+	password, exists := previousInternalSecret.Data[InternalProbeUserName]
+	if !exists || string(password) == string(internalSecrets.Secret().Data[InternalProbeUserName]) {
+		return nil
+	}
+	return New(InternalPreviousProbeUserName, Password(string(password)), Roles(ProbeUserRole))
 }
@@ -0,0 +1,57 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+)
+
+// RolesFor returns the roles to write to the roles.yml file: the predefined roles along with the roles
+// declared in the security spec.
+func RolesFor(es v1beta1.Elasticsearch) map[string]client.Role {
+	if es.Spec.Security == nil || len(es.Spec.Security.Roles) == 0 {
+		return PredefinedRoles
+	}
+	roles := make(map[string]client.Role, len(PredefinedRoles)+len(es.Spec.Security.Roles))
+	for _, r := range es.Spec.Security.Roles {
+		roles[r.Name] = toRole(r)
+	}
+	// predefined roles cannot be overridden
+	for name, r := range PredefinedRoles {
+		roles[name] = r
+	}
+	return roles
+}
+
+func toRole(r v1beta1.Role) client.Role {
+	role := client.Role{
+		Cluster: r.Cluster,
+		RunAs:   r.RunAs,
+	}
+	for _, i := range r.Indices {
+		privileges := client.IndicesPrivileges{
+			Names:                  i.Names,
+			Privileges:             i.Privileges,
+			Query:                  i.Query,
+			AllowRestrictedIndices: i.AllowRestrictedIndices,
+		}
+		if i.FieldSecurity != nil {
+			privileges.FieldSecurity = &client.FieldSecurity{Grant: i.FieldSecurity.Grant, Except: i.FieldSecurity.Except}
+		}
+		role.Indices = append(role.Indices, privileges)
+	}
+	for _, a := range r.Applications {
+		role.Applications = append(role.Applications, client.ApplicationPrivileges{
+			Application: a.Application,
+			Privileges:  a.Privileges,
+			Resources:   a.Resources,
+		})
+	}
+	if r.Metadata != nil {
+		role.Metadata = r.Metadata.Data
+	}
+	return role
+}
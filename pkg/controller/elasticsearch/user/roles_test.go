@@ -0,0 +1,71 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRolesFor(t *testing.T) {
+	es := v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{Security: &v1beta1.SecuritySpec{Roles: []v1beta1.Role{
+		{
+			Name:    "logs_reader",
+			Cluster: []string{"monitor"},
+			Indices: []v1beta1.IndicesPrivileges{{
+				Names:         []string{"logs-*"},
+				Privileges:    []string{"read"},
+				FieldSecurity: &v1beta1.FieldSecurity{Grant: []string{"*"}, Except: []string{"secret"}},
+			}},
+			Applications: []v1beta1.ApplicationPrivileges{{
+				Application: "kibana-.kibana",
+				Privileges:  []string{"read"},
+				Resources:   []string{"*"},
+			}},
+			RunAs: []string{"jane"},
+		},
+		// predefined roles cannot be overridden
+		{Name: ProbeUserRole, Cluster: []string{"all"}},
+	}}}}
+
+	roles := RolesFor(es)
+	require.Len(t, roles, 3)
+	require.Equal(t, PredefinedRoles[ProbeUserRole], roles[ProbeUserRole])
+
+	bytes, err := getRolesFileBytes(roles)
+	require.NoError(t, err)
+	require.Equal(t, `elastic_internal_keystore_user:
+  cluster:
+  - all
+elastic_internal_probe_user:
+  cluster:
+  - monitor
+logs_reader:
+  applications:
+  - application: kibana-.kibana
+    privileges:
+    - read
+    resources:
+    - '*'
+  cluster:
+  - monitor
+  indices:
+  - field_security:
+      except:
+      - secret
+      grant:
+      - '*'
+    names:
+    - logs-*
+    privileges:
+    - read
+  run_as:
+  - jane
+`, string(bytes))
+
+	require.Equal(t, PredefinedRoles, RolesFor(v1beta1.Elasticsearch{}))
+}
@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/user"
+	esname "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// CredentialsRotationGracePeriod is the duration during which passwords replaced by a rotation are retained.
+	CredentialsRotationGracePeriod = time.Hour
+	// ExpiresAtAnnotation is set on the secrets retaining passwords replaced by a rotation, with the time after which
+	// they are deleted.
+	ExpiresAtAnnotation = "elasticsearch.k8s.elastic.co/expires-at"
+	// NewPasswordAcceptedAnnotation is set on the secret retaining the internal users passwords replaced by a rotation,
+	// once Elasticsearch accepts the new password of the controller user.
+	NewPasswordAcceptedAnnotation = "elasticsearch.k8s.elastic.co/new-password-accepted"
+)
+
+// MarkNewPasswordAccepted records that Elasticsearch accepts the rotated password of the controller user, which is then
+// used without checking it again during the rest of the grace period.
+func MarkNewPasswordAccepted(c k8s.Client, es v1beta1.Elasticsearch) error {
+	var previous corev1.Secret
+	err := c.Get(types.NamespacedName{Namespace: es.Namespace, Name: esname.PreviousInternalUsersSecret(es.Name)}, &previous)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if previous.Annotations == nil {
+		previous.Annotations = make(map[string]string, 1)
+	}
+	previous.Annotations[NewPasswordAcceptedAnnotation] = "true"
+	return c.Update(&previous)
+}
+
+// retainPreviousCredentials copies the given credentials secret to the previous secret before its passwords are
+// rotated. It returns the previous secret during the grace period following the rotation, and deletes it once expired.
+func retainPreviousCredentials(
+	c k8s.Client,
+	scheme *runtime.Scheme,
+	es v1beta1.Elasticsearch,
+	secretName string,
+	previousSecretName string,
+	rotation string,
+	now time.Time,
+) (*corev1.Secret, error) {
+	var current corev1.Secret
+	err := c.Get(types.NamespacedName{Namespace: es.Namespace, Name: secretName}, &current)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil && user.NeedsRotation(rotation, current) {
+		previous := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: es.Namespace,
+				Name:      previousSecretName,
+				Labels:    current.Labels,
+				Annotations: map[string]string{
+					ExpiresAtAnnotation: now.Add(CredentialsRotationGracePeriod).UTC().Format(time.RFC3339),
+				},
+			},
+			Data: current.Data,
+		}
+		if err := controllerutil.SetControllerReference(&es, &previous, scheme); err != nil {
+			return nil, err
+		}
+		log.Info("Retaining credentials before rotation", "namespace", es.Namespace, "secret_name", previousSecretName)
+		if err := c.Create(&previous); err != nil {
+			if !errors.IsAlreadyExists(err) {
+				return nil, err
+			}
+			// replace the credentials retained from an earlier rotation
+			if err := c.Update(&previous); err != nil {
+				return nil, err
+			}
+		}
+		return &previous, nil
+	}
+
+	var previous corev1.Secret
+	if err := c.Get(types.NamespacedName{Namespace: es.Namespace, Name: previousSecretName}, &previous); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	expiresAt, err := time.Parse(time.RFC3339, previous.Annotations[ExpiresAtAnnotation])
+	if err == nil && now.Before(expiresAt) {
+		return &previous, nil
+	}
+	log.Info("Deleting credentials retained after rotation", "namespace", es.Namespace, "secret_name", previousSecretName)
+	if err := c.Delete(&previous); err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// expireRetainedUsers deletes the user secrets retained after a rotation once the grace period is over, and returns
+// the other ones. It also returns whether some of them are still retained.
+func expireRetainedUsers(c k8s.Client, users corev1.SecretList, now time.Time) (corev1.SecretList, bool, error) {
+	var remaining []corev1.Secret
+	retained := false
+	for _, u := range users.Items {
+		value, exists := u.Annotations[ExpiresAtAnnotation]
+		if !exists {
+			remaining = append(remaining, u)
+			continue
+		}
+		expiresAt, err := time.Parse(time.RFC3339, value)
+		if err == nil && now.Before(expiresAt) {
+			retained = true
+			remaining = append(remaining, u)
+			continue
+		}
+		log.Info("Deleting user retained after rotation", "namespace", u.Namespace, "secret_name", u.Name)
+		if err := c.Delete(&u); err != nil && !errors.IsNotFound(err) {
+			return corev1.SecretList{}, false, err
+		}
+	}
+	users.Items = remaining
+	return users, retained, nil
+}
@@ -0,0 +1,176 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/user"
+	esname "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileUsers_rotation(t *testing.T) {
+	require.NoError(t, v1beta1.AddToScheme(scheme.Scheme))
+	es := v1beta1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	c := k8s.WrapClient(fake.NewFakeClient())
+	getPassword := func(secretName, username string) string {
+		var secret corev1.Secret
+		require.NoError(t, c.Get(types.NamespacedName{Namespace: "ns", Name: secretName}, &secret))
+		return string(secret.Data[username])
+	}
+
+	internalUsers, err := ReconcileUsers(c, scheme.Scheme, es, events.NewRecorder())
+	require.NoError(t, err)
+	require.Nil(t, internalUsers.PreviousControllerUser)
+	initialControllerPassword := internalUsers.ControllerUser.Password()
+	initialProbePassword := internalUsers.ProbeUser.Password()
+	initialElasticPassword := getPassword(ElasticExternalUsersSecretName("es"), ExternalUserName)
+
+	// passwords are stable
+	internalUsers, err = ReconcileUsers(c, scheme.Scheme, es, events.NewRecorder())
+	require.NoError(t, err)
+	require.Equal(t, initialControllerPassword, internalUsers.ControllerUser.Password())
+
+	// request a rotation
+	es.Annotations = map[string]string{user.RotateCredentialsAnnotation: "2019-11-01"}
+	internalUsers, err = ReconcileUsers(c, scheme.Scheme, es, events.NewRecorder())
+	require.NoError(t, err)
+	require.NotEqual(t, initialControllerPassword, internalUsers.ControllerUser.Password())
+	require.Equal(t, initialControllerPassword, internalUsers.PreviousControllerUser.Password())
+	require.True(t, internalUsers.PreviousCredentialsRetained)
+	require.NotEqual(t, initialProbePassword, internalUsers.ProbeUser.Password())
+	require.Equal(t, initialProbePassword, getPassword(esname.PreviousInternalUsersSecret("es"), InternalProbeUserName))
+	rotatedElasticPassword := getPassword(ElasticExternalUsersSecretName("es"), ExternalUserName)
+	require.NotEqual(t, initialElasticPassword, rotatedElasticPassword)
+	require.Equal(t, initialElasticPassword, getPassword(esname.PreviousElasticUserSecret("es"), ExternalUserName))
+	require.Equal(t, initialControllerPassword, getPassword(esname.PreviousInternalUsersSecret("es"), InternalControllerUserName))
+
+	// the file realm is updated with the new passwords, and keeps the previous probe password
+	var fileRealm corev1.Secret
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: "ns", Name: XPackFileRealmSecretName("es")}, &fileRealm))
+	previousProbeUser := New(InternalPreviousProbeUserName, Password(initialProbePassword), Roles(ProbeUserRole))
+	require.False(t, expectedFileRealm(t, internalUsers, rotatedElasticPassword, previousProbeUser).NeedsUpdate(fileRealm))
+
+	// passwords are not rotated again for the same rotation
+	rotatedControllerPassword := internalUsers.ControllerUser.Password()
+	internalUsers, err = ReconcileUsers(c, scheme.Scheme, es, events.NewRecorder())
+	require.NoError(t, err)
+	require.Equal(t, rotatedControllerPassword, internalUsers.ControllerUser.Password())
+	require.Equal(t, initialControllerPassword, internalUsers.PreviousControllerUser.Password())
+	require.Equal(t, rotatedElasticPassword, getPassword(ElasticExternalUsersSecretName("es"), ExternalUserName))
+
+	// the previous controller user is not returned anymore once the new password is accepted
+	require.NoError(t, MarkNewPasswordAccepted(c, es))
+	internalUsers, err = ReconcileUsers(c, scheme.Scheme, es, events.NewRecorder())
+	require.NoError(t, err)
+	require.Nil(t, internalUsers.PreviousControllerUser)
+	require.True(t, internalUsers.PreviousCredentialsRetained)
+
+	// nor when the annotation is removed
+	es.Annotations = nil
+	internalUsers, err = ReconcileUsers(c, scheme.Scheme, es, events.NewRecorder())
+	require.NoError(t, err)
+	require.Equal(t, rotatedControllerPassword, internalUsers.ControllerUser.Password())
+
+	// the previous probe password is removed from the file realm once the grace period is over
+	var previous corev1.Secret
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: "ns", Name: esname.PreviousInternalUsersSecret("es")}, &previous))
+	previous.Annotations[ExpiresAtAnnotation] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	require.NoError(t, c.Update(&previous))
+	internalUsers, err = ReconcileUsers(c, scheme.Scheme, es, events.NewRecorder())
+	require.NoError(t, err)
+	require.False(t, internalUsers.PreviousCredentialsRetained)
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: "ns", Name: XPackFileRealmSecretName("es")}, &fileRealm))
+	require.False(t, expectedFileRealm(t, internalUsers, rotatedElasticPassword).NeedsUpdate(fileRealm))
+}
+
+// expectedFileRealm returns the file realm credentials expected for the given internal users,
+// elastic user password and additional users.
+func expectedFileRealm(t *testing.T, internalUsers *InternalUsers, elasticPassword string, others ...user.User) *HashedCredentials {
+	creds, err := NewElasticUsersCredentialsAndRoles(
+		types.NamespacedName{Namespace: "ns", Name: "es"},
+		append([]user.User{
+			internalUsers.ControllerUser, internalUsers.ProbeUser, internalUsers.KeystoreUser,
+			New(ExternalUserName, Password(elasticPassword), Roles(SuperUserBuiltinRole)),
+		}, others...),
+		PredefinedRoles,
+	)
+	require.NoError(t, err)
+	return creds
+}
+
+func Test_retainPreviousCredentials(t *testing.T) {
+	require.NoError(t, v1beta1.AddToScheme(scheme.Scheme))
+	es := v1beta1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	now := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	current := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "current"},
+		Data:       map[string][]byte{"user": []byte("password")},
+	}
+	c := k8s.WrapClient(fake.NewFakeClient(current))
+
+	// no rotation requested
+	previous, err := retainPreviousCredentials(c, scheme.Scheme, es, "current", "previous", "", now)
+	require.NoError(t, err)
+	require.Nil(t, previous)
+
+	// rotation requested
+	previous, err = retainPreviousCredentials(c, scheme.Scheme, es, "current", "previous", "1", now)
+	require.NoError(t, err)
+	require.Equal(t, current.Data, previous.Data)
+	require.Equal(t, "2019-11-01T01:00:00Z", previous.Annotations[ExpiresAtAnnotation])
+
+	// rotation done, during the grace period
+	user.SetRotation("1", current)
+	require.NoError(t, c.Update(current))
+	previous, err = retainPreviousCredentials(c, scheme.Scheme, es, "current", "previous", "1", now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, current.Data, previous.Data)
+
+	// grace period over
+	previous, err = retainPreviousCredentials(c, scheme.Scheme, es, "current", "previous", "1", now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Nil(t, previous)
+	err = c.Get(types.NamespacedName{Namespace: "ns", Name: "previous"}, &corev1.Secret{})
+	require.True(t, errors.IsNotFound(err))
+}
+
+func Test_expireRetainedUsers(t *testing.T) {
+	now := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	userSecret := func(name string, expiresAt string) corev1.Secret {
+		secret := corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}
+		if expiresAt != "" {
+			secret.Annotations = map[string]string{ExpiresAtAnnotation: expiresAt}
+		}
+		return secret
+	}
+	current := userSecret("current", "")
+	retained := userSecret("retained", "2019-11-01T01:00:00Z")
+	expired := userSecret("expired", "2019-10-31T23:00:00Z")
+	c := k8s.WrapClient(fake.NewFakeClient(&current, &retained, &expired))
+
+	users, isRetained, err := expireRetainedUsers(c, corev1.SecretList{Items: []corev1.Secret{current, retained, expired}}, now)
+	require.NoError(t, err)
+	require.True(t, isRetained)
+	require.Equal(t, []corev1.Secret{current, retained}, users.Items)
+	err = c.Get(types.NamespacedName{Namespace: "ns", Name: "expired"}, &corev1.Secret{})
+	require.True(t, errors.IsNotFound(err))
+
+	users, isRetained, err = expireRetainedUsers(c, corev1.SecretList{Items: []corev1.Secret{current}}, now)
+	require.NoError(t, err)
+	require.False(t, isRetained)
+	require.Equal(t, []corev1.Secret{current}, users.Items)
+}
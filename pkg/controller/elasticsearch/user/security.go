@@ -0,0 +1,171 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/finalizer"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("user")
+
+// ReconcileSecurity creates or updates the role mappings and native users declared in the security spec, through
+// the security API. It returns the security status to report in the Elasticsearch status, which is nil if no
+// native user is declared.
+// Role mappings and native users created outside of the operator are left untouched.
+func ReconcileSecurity(c k8s.Client, esClient esclient.Client, es v1beta1.Elasticsearch) (*v1beta1.SecurityStatus, error) {
+	spec := es.Spec.Security
+	if spec == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), esclient.DefaultReqTimeout)
+	defer cancel()
+
+	if err := reconcileRoleMappings(ctx, esClient, spec.RoleMappings); err != nil {
+		return nil, err
+	}
+	return reconcileNativeUsers(ctx, c, esClient, es)
+}
+
+func reconcileRoleMappings(ctx context.Context, esClient esclient.Client, mappings []v1beta1.RoleMapping) error {
+	if len(mappings) == 0 {
+		return nil
+	}
+	current, err := esClient.GetRoleMappings(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range mappings {
+		expected := esclient.RoleMapping{
+			Roles:    m.Roles,
+			Enabled:  m.IsEnabled(),
+			Rules:    configData(m.Rules),
+			Metadata: configData(m.Metadata),
+		}
+		if actual, exists := current[m.Name]; exists && reflect.DeepEqual(expected, actual) {
+			continue
+		}
+		log.Info("Updating role mapping", "name", m.Name)
+		if err := esClient.UpdateRoleMapping(ctx, m.Name, expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileNativeUsers creates or updates the native users declared in the spec. Since passwords cannot be read
+// from Elasticsearch, a user is also updated if its spec or its password secret changed since it was last applied.
+func reconcileNativeUsers(ctx context.Context, c k8s.Client, esClient esclient.Client, es v1beta1.Elasticsearch) (*v1beta1.SecurityStatus, error) {
+	users := es.Spec.Security.NativeUsers
+	if len(users) == 0 {
+		return nil, nil
+	}
+	var previous map[string]string
+	if es.Status.Security != nil {
+		previous = es.Status.Security.AppliedNativeUsers
+	}
+	current, err := esClient.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := v1beta1.SecurityStatus{AppliedNativeUsers: make(map[string]string, len(users))}
+	for _, u := range users {
+		var secret corev1.Secret
+		if err := c.Get(types.NamespacedName{Namespace: es.Namespace, Name: u.PasswordSecret.Name}, &secret); err != nil {
+			return nil, err
+		}
+		password, exists := secret.Data[u.PasswordSecret.Key]
+		if !exists {
+			return nil, fmt.Errorf("key %s not found in secret %s for native user %s", u.PasswordSecret.Key, u.PasswordSecret.Name, u.Username)
+		}
+		expected := esclient.User{
+			Roles:    u.Roles,
+			FullName: u.FullName,
+			Email:    u.Email,
+			Metadata: configData(u.Metadata),
+			Enabled:  true,
+		}
+		appliedHash := hash.HashObject([]interface{}{u, secret.ResourceVersion})
+		status.AppliedNativeUsers[u.Username] = appliedHash
+		if actual, exists := current[u.Username]; exists && userEqual(expected, actual) && previous[u.Username] == appliedHash {
+			continue
+		}
+		log.Info("Updating native user", "username", u.Username)
+		expected.Password = string(password)
+		if err := esClient.UpdateUser(ctx, u.Username, expected); err != nil {
+			return nil, err
+		}
+	}
+	return &status, nil
+}
+
+func userEqual(expected, actual esclient.User) bool {
+	return (len(expected.Roles) == 0 && len(actual.Roles) == 0 || reflect.DeepEqual(expected.Roles, actual.Roles)) &&
+		expected.FullName == actual.FullName &&
+		expected.Email == actual.Email &&
+		reflect.DeepEqual(expected.Metadata, actual.Metadata) &&
+		expected.Enabled == actual.Enabled
+}
+
+func configData(cfg *commonv1beta1.Config) map[string]interface{} {
+	if cfg == nil || cfg.Data == nil {
+		return map[string]interface{}{}
+	}
+	return cfg.Data
+}
+
+// userSecretsWatchName returns the name of the watch on the user-provided secrets of the given cluster.
+func userSecretsWatchName(es types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-user-secrets", es.Namespace, es.Name)
+}
+
+// WatchUserSecrets registers a watch on the password secrets of the native users and on the file realm secrets
+// of the given cluster, or removes it if there is no such secret.
+func WatchUserSecrets(watched watches.DynamicWatches, es v1beta1.Elasticsearch) error {
+	nsn := k8s.ExtractNamespacedName(&es)
+	watchName := userSecretsWatchName(nsn)
+	var secrets []types.NamespacedName
+	if es.Spec.Security != nil {
+		for _, u := range es.Spec.Security.NativeUsers {
+			secrets = append(secrets, types.NamespacedName{Namespace: es.Namespace, Name: u.PasswordSecret.Name})
+		}
+		for _, name := range es.Spec.Security.FileRealmSecretNames() {
+			secrets = append(secrets, types.NamespacedName{Namespace: es.Namespace, Name: name})
+		}
+	}
+	if len(secrets) == 0 {
+		watched.Secrets.RemoveHandlerForKey(watchName)
+		return nil
+	}
+	return watched.Secrets.AddHandler(watches.NamedWatch{
+		Name:    watchName,
+		Watched: secrets,
+		Watcher: nsn,
+	})
+}
+
+// UserSecretsFinalizer removes the watch on the user-provided secrets of the given cluster.
+func UserSecretsFinalizer(es types.NamespacedName, watched watches.DynamicWatches) finalizer.Finalizer {
+	return finalizer.Finalizer{
+		Name: "finalizer.elasticsearch.k8s.elastic.co/user-secrets",
+		Execute: func() error {
+			watched.Secrets.RemoveHandlerForKey(userSecretsWatchName(es))
+			return nil
+		},
+	}
+}
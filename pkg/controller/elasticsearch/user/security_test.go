@@ -0,0 +1,99 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileSecurity(t *testing.T) {
+	rules := commonv1beta1.NewConfig(map[string]interface{}{"field": map[string]interface{}{"realm.name": "saml1"}})
+	es := v1beta1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: v1beta1.ElasticsearchSpec{Security: &v1beta1.SecuritySpec{
+			RoleMappings: []v1beta1.RoleMapping{{Name: "saml", Roles: []string{"viewer"}, Rules: &rules}},
+			NativeUsers: []v1beta1.NativeUser{{
+				Username:       "jane",
+				Roles:          []string{"viewer"},
+				FullName:       "Jane Doe",
+				PasswordSecret: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "jane"}, Key: "password"},
+			}},
+		}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "jane"},
+		Data:       map[string][]byte{"password": []byte("changeme")},
+	}
+	c := k8s.WrapClient(fake.NewFakeClient(secret))
+
+	stored := map[string]string{
+		"/_security/role_mapping": `{}`,
+		"/_security/user":         `{"elastic":{"username":"elastic","roles":["superuser"],"metadata":{"_reserved":true},"enabled":true}}`,
+	}
+	var requests []string
+	esClient := esclient.NewMockClient(version.MustParse("7.4.0"), func(req *http.Request) *http.Response {
+		if req.Method == http.MethodGet {
+			return esclient.NewMockResponse(200, req, stored[req.URL.Path])
+		}
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+		return esclient.NewMockResponse(200, req, "{}")
+	})
+
+	// role mapping and user created
+	status, err := ReconcileSecurity(c, esClient, es)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`PUT /_security/role_mapping/saml {"roles":["viewer"],"enabled":true,"rules":{"field":{"realm.name":"saml1"}},"metadata":{}}`,
+		`PUT /_security/user/jane {"password":"changeme","roles":["viewer"],"full_name":"Jane Doe","metadata":{},"enabled":true}`,
+	}, requests)
+	require.Len(t, status.AppliedNativeUsers, 1)
+
+	// in sync
+	stored["/_security/role_mapping"] = `{"saml":{"enabled":true,"roles":["viewer"],"rules":{"field":{"realm.name":"saml1"}},"metadata":{}}}`
+	stored["/_security/user"] = `{"jane":{"username":"jane","roles":["viewer"],"full_name":"Jane Doe","email":null,"metadata":{},"enabled":true}}`
+	es.Status.Security = status
+	requests = nil
+	status, err = ReconcileSecurity(c, esClient, es)
+	require.NoError(t, err)
+	require.Empty(t, requests)
+	require.Equal(t, es.Status.Security, status)
+
+	// password updated
+	secret.Data["password"] = []byte("new-password")
+	secret.ResourceVersion = "2" // set by the API server
+	require.NoError(t, c.Update(secret))
+	status, err = ReconcileSecurity(c, esClient, es)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`PUT /_security/user/jane {"password":"new-password","roles":["viewer"],"full_name":"Jane Doe","metadata":{},"enabled":true}`,
+	}, requests)
+	require.NotEqual(t, es.Status.Security, status)
+
+	// missing password key
+	es.Spec.Security.NativeUsers[0].PasswordSecret.Key = "missing"
+	_, err = ReconcileSecurity(c, esClient, es)
+	require.Error(t, err)
+
+	// nothing to reconcile
+	requests = nil
+	status, err = ReconcileSecurity(c, esClient, v1beta1.Elasticsearch{})
+	require.NoError(t, err)
+	require.Nil(t, status)
+	require.Empty(t, requests)
+}
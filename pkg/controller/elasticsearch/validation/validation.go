@@ -17,9 +17,15 @@ const (
 	masterRequiredMsg        = "Elasticsearch needs to have at least one master node"
 	parseVersionErrMsg       = "Cannot parse Elasticsearch version"
 	parseStoredVersionErrMsg = "Cannot parse current Elasticsearch version"
-	invalidSanIPErrMsg       = "invalid SAN IP address"
-	pvcImmutableMsg          = "Volume claim templates cannot be modified"
+	pvcImmutableMsg          = "Volume claim templates can only be modified to increase their storage request"
 	invalidNamesErrMsg       = "Elasticsearch configuration would generate resources with invalid names"
+	invalidSnapshotMsg       = "Invalid snapshot configuration"
+	invalidPredicateMsg      = "Invalid upgrade predicate"
+	invalidNodeAttributeMsg  = "Invalid node attribute"
+	invalidIndexLifecycleMsg = "Invalid index lifecycle configuration"
+	invalidSecurityMsg       = "Invalid security configuration"
+	invalidRemoteClustersMsg = "Invalid remote clusters"
+	invalidTransportMsg      = "Invalid transport configuration"
 )
 
 // Validation is a function from a currently stored Elasticsearch spec and proposed new spec
@@ -83,13 +89,28 @@ func Validate(es estype.Elasticsearch) ([]validation.Result, error) {
 			Version:       *v,
 		},
 	}
+	return vCtx.validate(), nil
+}
+
+// ValidateUpdate runs validation logic against the Elasticsearch currently stored in the API server,
+// which is nil on creation. Unlike Validate, it includes the validations of the changes between both specs.
+func ValidateUpdate(current *estype.Elasticsearch, proposed estype.Elasticsearch) ([]validation.Result, error) {
+	vCtx, err := NewValidationContext(current, proposed)
+	if err != nil {
+		return nil, err
+	}
+	return vCtx.validate(), nil
+}
+
+// validate returns the results of the failed validations.
+func (v Context) validate() []validation.Result {
 	var errs []validation.Result
-	for _, v := range Validations {
-		r := v(vCtx)
+	for _, validate := range Validations {
+		r := validate(v)
 		if r.Allowed {
 			continue
 		}
 		errs = append(errs, r)
 	}
-	return errs, nil
+	return errs
 }
@@ -15,6 +15,7 @@ import (
 
 	common "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	estype "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/validation"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
 )
@@ -202,7 +203,7 @@ func TestValidate(t *testing.T) {
 				masterRequiredMsg,
 				"unsupported version",
 				"is not user configurable",
-				invalidSanIPErrMsg,
+				validation.InvalidSanIPErrMsg,
 			},
 		},
 	}
@@ -228,3 +229,26 @@ func TestValidate(t *testing.T) {
 
 	}
 }
+
+func TestValidateUpdate(t *testing.T) {
+	current := getEsCluster()
+	current.Name = "es"
+	current.Spec.NodeSets[0].Count = 1
+	downgrade := current.DeepCopy()
+	downgrade.Spec.Version = "7.1.0"
+	invalidVersion := current.DeepCopy()
+	invalidVersion.Spec.Version = "7.x"
+
+	// creation
+	results, err := ValidateUpdate(nil, *current)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	// update, validated against the current spec
+	results, err = ValidateUpdate(current, *downgrade)
+	require.NoError(t, err)
+	require.Equal(t, []validation.Result{{Allowed: false, Reason: noDowngradesMsg}}, results)
+
+	_, err = ValidateUpdate(current, *invalidVersion)
+	require.Error(t, err)
+}
@@ -5,9 +5,7 @@
 package validation
 
 import (
-	"errors"
 	"fmt"
-	"net"
 	"reflect"
 	"strconv"
 	"strings"
@@ -15,24 +13,39 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	common "github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/validation"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/driver"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodeattr"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	esversion "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/version"
-	netutil "github.com/elastic/cloud-on-k8s/pkg/utils/net"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/set"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // Validations are all registered Elasticsearch validations.
 var Validations = []Validation{
 	validName,
-	specUpdatedToBeta,
+	validNodeSets,
 	hasMaster,
 	supportedVersion,
 	noDowngrades,
 	validUpgradePath,
 	noBlacklistedSettings,
 	validSanIP,
+	validKeyParams,
 	pvcModification,
+	validSnapshotSpec,
+	validSnapshotRestore,
+	validUpgradePredicates,
+	validNodeAttributes,
+	validIndexLifecycle,
+	validSecurity,
+	validRemoteClusters,
+	validTransport,
 }
 
 // validName checks whether the name is valid.
@@ -43,14 +56,10 @@ func validName(ctx Context) validation.Result {
 	return validation.OK
 }
 
-func specUpdatedToBeta(ctx Context) validation.Result {
-	oldAPIVersion := "elasticsearch.k8s.elastic.co/v1alpha1"
-
+// validNodeSets checks that at least one node set is defined, and that node sets are not empty.
+// v1alpha1 resources are converted to v1beta1 by the conversion webhook, their nodes are validated as node sets.
+func validNodeSets(ctx Context) validation.Result {
 	es := ctx.Proposed.Elasticsearch
-	if es.APIVersion == oldAPIVersion {
-		return validation.Result{Reason: fmt.Sprintf("%s: outdated APIVersion", validationFailedMsg)}
-	}
-
 	if len(es.Spec.NodeSets) == 0 {
 		return validation.Result{Reason: fmt.Sprintf("%s: at least one nodeSet must be defined", validationFailedMsg)}
 	}
@@ -145,26 +154,16 @@ func noBlacklistedSettings(ctx Context) validation.Result {
 }
 
 func validSanIP(ctx Context) validation.Result {
-	selfSignedCerts := ctx.Proposed.Elasticsearch.Spec.HTTP.TLS.SelfSignedCertificate
-	if selfSignedCerts != nil {
-		for _, san := range selfSignedCerts.SubjectAlternativeNames {
-			if san.IP != "" {
-				ip := netutil.MaybeIPTo4(net.ParseIP(san.IP))
-				if ip == nil {
-					msg := fmt.Sprintf("%s: %s", invalidSanIPErrMsg, san.IP)
-					return validation.Result{
-						Error:   errors.New(msg),
-						Reason:  msg,
-						Allowed: false,
-					}
-				}
-			}
-		}
-	}
-	return validation.OK
+	return validation.ValidSanIP(ctx.Proposed.Elasticsearch.Spec.HTTP.TLS)
+}
+
+// validKeyParams checks that the key size of the self-signed certificate is supported by its key algorithm.
+func validKeyParams(ctx Context) validation.Result {
+	return validation.ValidKeyParams(ctx.Proposed.Elasticsearch.Spec.HTTP.TLS)
 }
 
-// pvcModification ensures no PVCs are changed, as volume claim templates are immutable in stateful sets
+// pvcModification ensures PVCs are not changed, except for storage increases applied by expanding the existing
+// volumes, as volume claim templates are immutable in stateful sets.
 func pvcModification(ctx Context) validation.Result {
 	if ctx.Current == nil {
 		return validation.OK
@@ -177,8 +176,10 @@ func pvcModification(ctx Context) validation.Result {
 		}
 
 		// ssets do not allow modifications to fields other than 'replicas', 'template', and 'updateStrategy'
-		// reflection isn't ideal, but okay here since the ES object does not have the status of the claims
-		if !reflect.DeepEqual(node.VolumeClaimTemplates, currNodeSet.VolumeClaimTemplates) {
+		// reflection isn't ideal, but okay here since the ES object does not have the status of the claims,
+		// except for the claims that could not be expanded
+		actualStorage := unexpandableStorage(ctx.Current.Elasticsearch, node.Name)
+		if err := volume.ValidateClaimsUpdate(currNodeSet.VolumeClaimTemplates, node.VolumeClaimTemplates, actualStorage); err != nil {
 			return validation.Result{
 				Allowed: false,
 				Reason:  pvcImmutableMsg,
@@ -188,6 +189,21 @@ func pvcModification(ctx Context) validation.Result {
 	return validation.OK
 }
 
+// unexpandableStorage returns the storage of the volume claim templates of the given NodeSet, if its volumes
+// could not be expanded.
+func unexpandableStorage(es v1beta1.Elasticsearch, nodeSetName string) map[string]resource.Quantity {
+	if es.Status.VolumeExpansion == nil {
+		return nil
+	}
+	ssetName := name.StatefulSet(es.Name, nodeSetName)
+	for _, unexpandable := range es.Status.VolumeExpansion.UnexpandableStatefulSets {
+		if unexpandable.Name == ssetName {
+			return unexpandable.Storage
+		}
+	}
+	return nil
+}
+
 func getNodeSet(name string, es v1beta1.Elasticsearch) *v1beta1.NodeSet {
 	for i := range es.Spec.NodeSets {
 		if es.Spec.NodeSets[i].Name == name {
@@ -196,3 +212,308 @@ func getNodeSet(name string, es v1beta1.Elasticsearch) *v1beta1.NodeSet {
 	}
 	return nil
 }
+
+// validSnapshotSpec checks that snapshot repositories are uniquely named and that the snapshot schedule
+// references one of them with a positive interval.
+func validSnapshotSpec(ctx Context) validation.Result {
+	snapshot := ctx.Proposed.Elasticsearch.Spec.Snapshot
+	if snapshot == nil {
+		return validation.OK
+	}
+	names := set.StringSet{}
+	for _, r := range snapshot.Repositories {
+		if r.Name == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: snapshot repository name is required", invalidSnapshotMsg)}
+		}
+		if names.Has(r.Name) {
+			return validation.Result{Reason: fmt.Sprintf("%s: duplicate snapshot repository %s", invalidSnapshotMsg, r.Name)}
+		}
+		names.Add(r.Name)
+		if !isSupportedRepositoryType(r.Type) {
+			return validation.Result{Reason: fmt.Sprintf("%s: unsupported type %s for snapshot repository %s", invalidSnapshotMsg, r.Type, r.Name)}
+		}
+	}
+	if schedule := snapshot.Schedule; schedule != nil {
+		if !names.Has(schedule.Repository) {
+			return validation.Result{Reason: fmt.Sprintf("%s: unknown snapshot repository %s", invalidSnapshotMsg, schedule.Repository)}
+		}
+		if schedule.Interval.Duration <= 0 {
+			return validation.Result{Reason: fmt.Sprintf("%s: snapshot interval must be positive", invalidSnapshotMsg)}
+		}
+	}
+	return validation.OK
+}
+
+func isSupportedRepositoryType(t v1beta1.SnapshotRepositoryType) bool {
+	for _, supported := range v1beta1.SupportedSnapshotRepositoryTypes {
+		if t == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// validSnapshotRestore checks that the snapshot to restore is stored in a specified repository, and that
+// the restore is only specified on cluster creation.
+func validSnapshotRestore(ctx Context) validation.Result {
+	restore := ctx.Proposed.Elasticsearch.Spec.Restore
+	if !ctx.isCreate() && !reflect.DeepEqual(restore, ctx.Current.Elasticsearch.Spec.Restore) {
+		return validation.Result{Reason: fmt.Sprintf("%s: snapshot restore can only be specified on cluster creation", invalidSnapshotMsg)}
+	}
+	if restore == nil {
+		return validation.OK
+	}
+	snapshot := ctx.Proposed.Elasticsearch.Spec.Snapshot
+	if snapshot == nil || snapshot.Repository(restore.Repository) == nil {
+		return validation.Result{Reason: fmt.Sprintf("%s: unknown snapshot repository %s", invalidSnapshotMsg, restore.Repository)}
+	}
+	if restore.Snapshot == "" {
+		return validation.Result{Reason: fmt.Sprintf("%s: snapshot to restore is required", invalidSnapshotMsg)}
+	}
+	return validation.OK
+}
+
+// validUpgradePredicates checks that disabled predicates are built-in predicates, and that upgrade predicates have
+// a unique name and exactly one condition.
+func validUpgradePredicates(ctx Context) validation.Result {
+	builtIn := set.Make(driver.PredicateNames()...)
+	for _, name := range ctx.Proposed.Elasticsearch.Spec.UpdateStrategy.DisabledPredicates {
+		if !builtIn.Has(name) {
+			return validation.Result{Reason: fmt.Sprintf("%s: unknown disabled predicate %s", invalidPredicateMsg, name)}
+		}
+	}
+	names := set.StringSet{}
+	for _, p := range ctx.Proposed.Elasticsearch.Spec.UpdateStrategy.Predicates {
+		if p.Name == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: name is required", invalidPredicateMsg)}
+		}
+		if names.Has(p.Name) {
+			return validation.Result{Reason: fmt.Sprintf("%s: duplicate predicate %s", invalidPredicateMsg, p.Name)}
+		}
+		names.Add(p.Name)
+		conditions := 0
+		if p.IndicesGreen != nil {
+			conditions++
+			if len(p.IndicesGreen.Indices) == 0 {
+				return validation.Result{Reason: fmt.Sprintf("%s: predicate %s has no indices", invalidPredicateMsg, p.Name)}
+			}
+		}
+		if p.OneNodePerZone != nil {
+			conditions++
+		}
+		if conditions != 1 {
+			return validation.Result{Reason: fmt.Sprintf("%s: predicate %s must specify exactly one condition", invalidPredicateMsg, p.Name)}
+		}
+	}
+	return validation.OK
+}
+
+// validNodeAttributes checks that node attributes are uniquely named, do not conflict with each other or with the
+// zone attribute set when zone awareness is enabled, and that shard allocation awareness attributes are not also set in the
+// configuration, since they would be replaced by the generated ones.
+func validNodeAttributes(ctx Context) validation.Result {
+	es := ctx.Proposed.Elasticsearch
+	awareness := nodeattr.Awareness(es)
+	for _, nodeSet := range es.Spec.NodeSets {
+		if len(awareness) > 0 && nodeSet.Config != nil {
+			// invalid configurations are reported by noBlacklistedSettings
+			if config, err := common.NewCanonicalConfigFrom(nodeSet.Config.Data); err == nil &&
+				len(config.HasKeys([]string{settings.ClusterRoutingAllocationAwarenessAttributes})) > 0 {
+				return validation.Result{Reason: fmt.Sprintf("%s: %s in nodeSet %s conflicts with the awareness attributes %s",
+					invalidNodeAttributeMsg, settings.ClusterRoutingAllocationAwarenessAttributes, nodeSet.Name, strings.Join(awareness, ","))}
+			}
+		}
+		names := set.StringSet{}
+		envVars := make(map[string]string)
+		if es.Spec.IsZoneAware() {
+			envVars[nodeattr.EnvVarName(nodeattr.ZoneAttribute)] = nodeattr.ZoneAttribute
+		}
+		for _, attribute := range nodeSet.NodeAttributes {
+			if attribute.Name == "" || attribute.NodeLabel == "" {
+				return validation.Result{Reason: fmt.Sprintf("%s: name and node label are required in nodeSet %s", invalidNodeAttributeMsg, nodeSet.Name)}
+			}
+			if names.Has(attribute.Name) {
+				return validation.Result{Reason: fmt.Sprintf("%s: duplicate attribute %s in nodeSet %s", invalidNodeAttributeMsg, attribute.Name, nodeSet.Name)}
+			}
+			names.Add(attribute.Name)
+			if es.Spec.IsZoneAware() && attribute.Name == nodeattr.ZoneAttribute {
+				return validation.Result{Reason: fmt.Sprintf("%s: attribute %s in nodeSet %s is already set by zone awareness", invalidNodeAttributeMsg, attribute.Name, nodeSet.Name)}
+			}
+			// attribute values are passed to Elasticsearch through environment variables, which must not collide
+			envVar := nodeattr.EnvVarName(attribute.Name)
+			if other, exists := envVars[envVar]; exists {
+				return validation.Result{Reason: fmt.Sprintf("%s: attributes %s and %s in nodeSet %s map to the same environment variable %s",
+					invalidNodeAttributeMsg, other, attribute.Name, nodeSet.Name, envVar)}
+			}
+			envVars[envVar] = attribute.Name
+		}
+	}
+	return validation.OK
+}
+
+// ilmMinVersion is the first Elasticsearch version supporting ILM policies.
+var ilmMinVersion = version.MustParse("6.6.0")
+
+// validIndexLifecycle checks that ILM policies and index templates are uniquely named and complete, and that
+// policies are supported by the Elasticsearch version.
+func validIndexLifecycle(ctx Context) validation.Result {
+	lifecycle := ctx.Proposed.Elasticsearch.Spec.IndexLifecycle
+	if lifecycle == nil {
+		return validation.OK
+	}
+	if len(lifecycle.Policies) > 0 && !ctx.Proposed.Version.IsSameOrAfter(ilmMinVersion) {
+		return validation.Result{Reason: fmt.Sprintf("%s: ILM policies require Elasticsearch %s or later", invalidIndexLifecycleMsg, ilmMinVersion)}
+	}
+	names := set.StringSet{}
+	for _, p := range lifecycle.Policies {
+		if p.Name == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: policy name is required", invalidIndexLifecycleMsg)}
+		}
+		if names.Has(p.Name) {
+			return validation.Result{Reason: fmt.Sprintf("%s: duplicate policy %s", invalidIndexLifecycleMsg, p.Name)}
+		}
+		names.Add(p.Name)
+		if p.Phases == nil || len(p.Phases.Data) == 0 {
+			return validation.Result{Reason: fmt.Sprintf("%s: policy %s has no phases", invalidIndexLifecycleMsg, p.Name)}
+		}
+	}
+	names = set.StringSet{}
+	for _, t := range lifecycle.Templates {
+		if t.Name == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: template name is required", invalidIndexLifecycleMsg)}
+		}
+		if names.Has(t.Name) {
+			return validation.Result{Reason: fmt.Sprintf("%s: duplicate template %s", invalidIndexLifecycleMsg, t.Name)}
+		}
+		names.Add(t.Name)
+		if len(t.IndexPatterns) == 0 {
+			return validation.Result{Reason: fmt.Sprintf("%s: template %s has no index patterns", invalidIndexLifecycleMsg, t.Name)}
+		}
+	}
+	return validation.OK
+}
+
+// reservedUsernames are the users that cannot be declared in the native realm: built-in users, and users of the
+// file realm managed by the operator.
+var reservedUsernames = set.Make(
+	"elastic", "kibana", "logstash_system", "beats_system", "apm_system", "remote_monitoring_user",
+	user.InternalControllerUserName, user.InternalProbeUserName, user.InternalPreviousProbeUserName,
+	user.InternalKeystoreUserName,
+)
+
+// validSecurity checks that roles, role mappings, native users and file realm secrets are uniquely named and
+// complete, and that they do not conflict with the roles and users managed by the operator.
+func validSecurity(ctx Context) validation.Result {
+	security := ctx.Proposed.Elasticsearch.Spec.Security
+	if security == nil {
+		return validation.OK
+	}
+	names := set.StringSet{}
+	for _, r := range security.Roles {
+		if r.Name == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: role name is required", invalidSecurityMsg)}
+		}
+		if _, predefined := user.PredefinedRoles[r.Name]; predefined || names.Has(r.Name) {
+			return validation.Result{Reason: fmt.Sprintf("%s: duplicate role %s", invalidSecurityMsg, r.Name)}
+		}
+		names.Add(r.Name)
+	}
+	names = set.StringSet{}
+	for _, m := range security.RoleMappings {
+		if m.Name == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: role mapping name is required", invalidSecurityMsg)}
+		}
+		if names.Has(m.Name) {
+			return validation.Result{Reason: fmt.Sprintf("%s: duplicate role mapping %s", invalidSecurityMsg, m.Name)}
+		}
+		names.Add(m.Name)
+		if len(m.Roles) == 0 || m.Rules == nil || len(m.Rules.Data) == 0 {
+			return validation.Result{Reason: fmt.Sprintf("%s: role mapping %s requires roles and rules", invalidSecurityMsg, m.Name)}
+		}
+	}
+	names = set.StringSet{}
+	for _, u := range security.NativeUsers {
+		if u.Username == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: native user username is required", invalidSecurityMsg)}
+		}
+		if reservedUsernames.Has(u.Username) {
+			return validation.Result{Reason: fmt.Sprintf("%s: username %s is reserved", invalidSecurityMsg, u.Username)}
+		}
+		if names.Has(u.Username) {
+			return validation.Result{Reason: fmt.Sprintf("%s: duplicate native user %s", invalidSecurityMsg, u.Username)}
+		}
+		names.Add(u.Username)
+		if u.PasswordSecret.Name == "" || u.PasswordSecret.Key == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: native user %s requires a password secret name and key", invalidSecurityMsg, u.Username)}
+		}
+	}
+	names = set.StringSet{}
+	for _, name := range security.FileRealmSecretNames() {
+		if name == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: file realm secret name is required", invalidSecurityMsg)}
+		}
+		if names.Has(name) {
+			return validation.Result{Reason: fmt.Sprintf("%s: duplicate file realm secret %s", invalidSecurityMsg, name)}
+		}
+		names.Add(name)
+	}
+	return validation.OK
+}
+
+// validRemoteClusters checks that remote clusters and auto-follow patterns are uniquely named, and that remote
+// clusters reference another Elasticsearch cluster.
+func validRemoteClusters(ctx Context) validation.Result {
+	es := ctx.Proposed.Elasticsearch
+	names := set.StringSet{}
+	patterns := set.StringSet{}
+	for _, rc := range es.Spec.RemoteClusters {
+		if rc.Name == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: remote cluster name is required", invalidRemoteClustersMsg)}
+		}
+		if names.Has(rc.Name) {
+			return validation.Result{Reason: fmt.Sprintf("%s: duplicate remote cluster %s", invalidRemoteClustersMsg, rc.Name)}
+		}
+		names.Add(rc.Name)
+		if rc.ElasticsearchRef.Name == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: remote cluster %s requires an Elasticsearch reference", invalidRemoteClustersMsg, rc.Name)}
+		}
+		if rc.RemoteClusterRef(es.Namespace) == k8s.ExtractNamespacedName(&es) {
+			return validation.Result{Reason: fmt.Sprintf("%s: remote cluster %s references the cluster itself", invalidRemoteClustersMsg, rc.Name)}
+		}
+		for _, p := range rc.AutoFollowPatterns {
+			if p.Name == "" {
+				return validation.Result{Reason: fmt.Sprintf("%s: auto-follow pattern name is required", invalidRemoteClustersMsg)}
+			}
+			if patterns.Has(p.Name) {
+				return validation.Result{Reason: fmt.Sprintf("%s: duplicate auto-follow pattern %s", invalidRemoteClustersMsg, p.Name)}
+			}
+			patterns.Add(p.Name)
+			if len(p.LeaderIndexPatterns) == 0 {
+				return validation.Result{Reason: fmt.Sprintf("%s: auto-follow pattern %s requires leader index patterns", invalidRemoteClustersMsg, p.Name)}
+			}
+		}
+	}
+	return validation.OK
+}
+
+// validTransport checks that the additional transport CAs reference distinct secrets.
+func validTransport(ctx Context) validation.Result {
+	tls := ctx.Proposed.Elasticsearch.Spec.Transport.TLS
+	if tls.IssuerRef != nil && tls.Certificate.SecretName != "" {
+		return validation.Result{Reason: fmt.Sprintf("%s: certificate and issuerRef are mutually exclusive", invalidTransportMsg)}
+	}
+	if tls.IssuerRef != nil && tls.IssuerRef.Name == "" {
+		return validation.Result{Reason: fmt.Sprintf("%s: issuerRef name is required", invalidTransportMsg)}
+	}
+	names := set.StringSet{}
+	for _, name := range ctx.Proposed.Elasticsearch.Spec.Transport.TLS.CertificateAuthoritiesSecretNames() {
+		if name == "" {
+			return validation.Result{Reason: fmt.Sprintf("%s: certificate authorities secret name is required", invalidTransportMsg)}
+		}
+		if names.Has(name) {
+			return validation.Result{Reason: fmt.Sprintf("%s: duplicate certificate authorities secret %s", invalidTransportMsg, name)}
+		}
+		names.Add(name)
+	}
+	return validation.OK
+}
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 
@@ -124,7 +125,7 @@ func Test_hasMaster(t *testing.T) {
 	}
 }
 
-func Test_specUpdatedToBeta(t *testing.T) {
+func Test_validNodeSets(t *testing.T) {
 	type args struct {
 		name        string
 		es          v1beta1.Elasticsearch
@@ -166,7 +167,7 @@ func Test_specUpdatedToBeta(t *testing.T) {
 			wantAllowed: false,
 		},
 		{
-			name: "alpha instead of beta version",
+			name: "converted from v1alpha1",
 			es: v1beta1.Elasticsearch{
 				TypeMeta: metav1.TypeMeta{APIVersion: "elasticsearch.k8s.elastic.co/v1alpha1"},
 				Spec: estype.ElasticsearchSpec{
@@ -174,8 +175,7 @@ func Test_specUpdatedToBeta(t *testing.T) {
 					NodeSets: []estype.NodeSet{{Count: 1}},
 				},
 			},
-			wantReason:  validationFailedMsg,
-			wantAllowed: false,
+			wantAllowed: true,
 		},
 	}
 
@@ -183,12 +183,12 @@ func Test_specUpdatedToBeta(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx, err := NewValidationContext(nil, tt.es)
 			require.NoError(t, err)
-			got := specUpdatedToBeta(*ctx)
+			got := validNodeSets(*ctx)
 			if got.Allowed != tt.wantAllowed {
-				t.Errorf("specUpdatedToBeta() = %v, want %v", got.Allowed, tt.wantAllowed)
+				t.Errorf("validNodeSets() = %v, want %v", got.Allowed, tt.wantAllowed)
 			}
 			if !strings.Contains(got.Reason, tt.wantReason) {
-				t.Errorf("specUpdatedToBeta() = %v, want %v", got.Reason, tt.wantReason)
+				t.Errorf("validNodeSets() = %v, want %v", got.Reason, tt.wantReason)
 			}
 		})
 	}
@@ -518,7 +518,22 @@ func Test_validSanIP(t *testing.T) {
 
 func Test_pvcModified(t *testing.T) {
 	failedValidation := validation.Result{Allowed: false, Reason: pvcImmutableMsg}
+	storageClassName := "fast"
 	current := getEsCluster()
+	// the claims of the master NodeSet could not be expanded from 2Gi to 5Gi
+	unexpandable := getEsCluster()
+	unexpandable.Name = "es"
+	unexpandable.Status.VolumeExpansion = &v1beta1.VolumeExpansionStatus{
+		UnexpandableStatefulSets: []v1beta1.UnexpandableStatefulSet{{
+			Name:    "es-es-master",
+			Storage: map[string]resource.Quantity{"elasticsearch-data": resource.MustParse("2Gi")},
+		}},
+	}
+	withStorage := func(storage string) v1beta1.Elasticsearch {
+		es := *getEsCluster()
+		es.Spec.NodeSets[0].VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(storage)
+		return es
+	}
 	tests := []struct {
 		name     string
 		current  *v1beta1.Elasticsearch
@@ -526,7 +541,19 @@ func Test_pvcModified(t *testing.T) {
 		want     validation.Result
 	}{
 		{
-			name:    "resize fails",
+			name:     "storage revert to the unexpanded claims accepted",
+			current:  unexpandable,
+			proposed: withStorage("2Gi"),
+			want:     validation.OK,
+		},
+		{
+			name:     "storage decrease below the unexpanded claims rejected",
+			current:  unexpandable,
+			proposed: withStorage("1Gi"),
+			want:     failedValidation,
+		},
+		{
+			name:    "storage increase accepted",
 			current: current,
 			proposed: v1beta1.Elasticsearch{
 				Spec: v1beta1.ElasticsearchSpec{
@@ -552,6 +579,67 @@ func Test_pvcModified(t *testing.T) {
 					},
 				},
 			},
+			want: validation.OK,
+		},
+
+		{
+			name:    "storage decrease rejected",
+			current: current,
+			proposed: v1beta1.Elasticsearch{
+				Spec: v1beta1.ElasticsearchSpec{
+					Version: "7.2.0",
+					NodeSets: []v1beta1.NodeSet{
+						{
+							Name: "master",
+							VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+								{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "elasticsearch-data",
+									},
+									Spec: corev1.PersistentVolumeClaimSpec{
+										Resources: corev1.ResourceRequirements{
+											Requests: corev1.ResourceList{
+												corev1.ResourceStorage: resource.MustParse("2Gi"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: failedValidation,
+		},
+
+		{
+			name:    "storage class change rejected",
+			current: current,
+			proposed: v1beta1.Elasticsearch{
+				Spec: v1beta1.ElasticsearchSpec{
+					Version: "7.2.0",
+					NodeSets: []v1beta1.NodeSet{
+						{
+							Name: "master",
+							VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+								{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "elasticsearch-data",
+									},
+									Spec: corev1.PersistentVolumeClaimSpec{
+										StorageClassName: &storageClassName,
+										Resources: corev1.ResourceRequirements{
+											Requests: corev1.ResourceList{
+												corev1.ResourceStorage: resource.MustParse("5Gi"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 			want: failedValidation,
 		},
 
@@ -714,7 +802,7 @@ func Test_pvcModified(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx, err := NewValidationContext(current, tt.proposed)
+			ctx, err := NewValidationContext(tt.current, tt.proposed)
 			require.NoError(t, err)
 			require.Equal(t, tt.want, pvcModification(*ctx))
 		})
@@ -748,3 +836,629 @@ func getEsCluster() *v1beta1.Elasticsearch {
 		},
 	}
 }
+
+func Test_validSnapshotSpec(t *testing.T) {
+	esWithSnapshot := func(snapshot *v1beta1.SnapshotSpec) v1beta1.Elasticsearch {
+		return v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{Version: "7.4.0", Snapshot: snapshot}}
+	}
+	repository := v1beta1.SnapshotRepository{Name: "backups", Type: v1beta1.S3RepositoryType}
+	tests := []struct {
+		name string
+		es   v1beta1.Elasticsearch
+		want bool
+	}{
+		{
+			name: "no snapshot spec: OK",
+			es:   esWithSnapshot(nil),
+			want: true,
+		},
+		{
+			name: "valid repository and schedule: OK",
+			es: esWithSnapshot(&v1beta1.SnapshotSpec{
+				Repositories: []v1beta1.SnapshotRepository{repository},
+				Schedule:     &v1beta1.SnapshotSchedule{Repository: "backups", Interval: metav1.Duration{Duration: time.Hour}},
+			}),
+			want: true,
+		},
+		{
+			name: "duplicate repository: NOT OK",
+			es: esWithSnapshot(&v1beta1.SnapshotSpec{
+				Repositories: []v1beta1.SnapshotRepository{repository, repository},
+			}),
+			want: false,
+		},
+		{
+			name: "unsupported repository type: NOT OK",
+			es: esWithSnapshot(&v1beta1.SnapshotSpec{
+				Repositories: []v1beta1.SnapshotRepository{{Name: "backups", Type: "hdfs"}},
+			}),
+			want: false,
+		},
+		{
+			name: "schedule on unknown repository: NOT OK",
+			es: esWithSnapshot(&v1beta1.SnapshotSpec{
+				Repositories: []v1beta1.SnapshotRepository{repository},
+				Schedule:     &v1beta1.SnapshotSchedule{Repository: "other", Interval: metav1.Duration{Duration: time.Hour}},
+			}),
+			want: false,
+		},
+		{
+			name: "schedule without interval: NOT OK",
+			es: esWithSnapshot(&v1beta1.SnapshotSpec{
+				Repositories: []v1beta1.SnapshotRepository{repository},
+				Schedule:     &v1beta1.SnapshotSchedule{Repository: "backups"},
+			}),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := NewValidationContext(nil, tt.es)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, validSnapshotSpec(*ctx).Allowed)
+		})
+	}
+}
+
+func Test_validSnapshotRestore(t *testing.T) {
+	snapshot := &v1beta1.SnapshotSpec{
+		Repositories: []v1beta1.SnapshotRepository{{Name: "backups", Type: v1beta1.FsRepositoryType}},
+	}
+	esWithRestore := func(restore *v1beta1.SnapshotRestore) *v1beta1.Elasticsearch {
+		return &v1beta1.Elasticsearch{
+			Spec: v1beta1.ElasticsearchSpec{Version: "7.4.0", Snapshot: snapshot, Restore: restore},
+		}
+	}
+	restore := &v1beta1.SnapshotRestore{Repository: "backups", Snapshot: "snap"}
+	tests := []struct {
+		name     string
+		current  *v1beta1.Elasticsearch
+		proposed *v1beta1.Elasticsearch
+		want     bool
+	}{
+		{
+			name:     "no restore: OK",
+			proposed: esWithRestore(nil),
+			want:     true,
+		},
+		{
+			name:     "restore on creation: OK",
+			proposed: esWithRestore(restore),
+			want:     true,
+		},
+		{
+			name:     "unchanged restore on update: OK",
+			current:  esWithRestore(restore),
+			proposed: esWithRestore(restore),
+			want:     true,
+		},
+		{
+			name:     "restore added on update: NOT OK",
+			current:  esWithRestore(nil),
+			proposed: esWithRestore(restore),
+			want:     false,
+		},
+		{
+			name:     "unknown repository: NOT OK",
+			proposed: esWithRestore(&v1beta1.SnapshotRestore{Repository: "other", Snapshot: "snap"}),
+			want:     false,
+		},
+		{
+			name:     "missing snapshot name: NOT OK",
+			proposed: esWithRestore(&v1beta1.SnapshotRestore{Repository: "backups"}),
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := NewValidationContext(tt.current, *tt.proposed)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, validSnapshotRestore(*ctx).Allowed)
+		})
+	}
+}
+
+func Test_validUpgradePredicates(t *testing.T) {
+	esWithPredicates := func(predicates ...v1beta1.UpgradePredicate) v1beta1.Elasticsearch {
+		return v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{
+			Version:        "7.4.0",
+			UpdateStrategy: v1beta1.UpdateStrategy{Predicates: predicates},
+		}}
+	}
+	esWithDisabledPredicates := func(names ...string) v1beta1.Elasticsearch {
+		return v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{
+			Version:        "7.4.0",
+			UpdateStrategy: v1beta1.UpdateStrategy{DisabledPredicates: names},
+		}}
+	}
+	indicesGreen := &v1beta1.IndicesGreenPredicate{Indices: []string{"logs-*"}}
+	oneNodePerZone := &v1beta1.OneNodePerZonePredicate{}
+	tests := []struct {
+		name     string
+		proposed v1beta1.Elasticsearch
+		want     bool
+	}{
+		{
+			name:     "no predicate: OK",
+			proposed: esWithPredicates(),
+			want:     true,
+		},
+		{
+			name: "valid predicates: OK",
+			proposed: esWithPredicates(
+				v1beta1.UpgradePredicate{Name: "logs", IndicesGreen: indicesGreen},
+				v1beta1.UpgradePredicate{Name: "zones", OneNodePerZone: oneNodePerZone},
+			),
+			want: true,
+		},
+		{
+			name:     "missing name: NOT OK",
+			proposed: esWithPredicates(v1beta1.UpgradePredicate{IndicesGreen: indicesGreen}),
+			want:     false,
+		},
+		{
+			name: "duplicate name: NOT OK",
+			proposed: esWithPredicates(
+				v1beta1.UpgradePredicate{Name: "logs", IndicesGreen: indicesGreen},
+				v1beta1.UpgradePredicate{Name: "logs", OneNodePerZone: oneNodePerZone},
+			),
+			want: false,
+		},
+		{
+			name:     "no condition: NOT OK",
+			proposed: esWithPredicates(v1beta1.UpgradePredicate{Name: "logs"}),
+			want:     false,
+		},
+		{
+			name: "several conditions: NOT OK",
+			proposed: esWithPredicates(
+				v1beta1.UpgradePredicate{Name: "logs", IndicesGreen: indicesGreen, OneNodePerZone: oneNodePerZone},
+			),
+			want: false,
+		},
+		{
+			name: "no indices: NOT OK",
+			proposed: esWithPredicates(
+				v1beta1.UpgradePredicate{Name: "logs", IndicesGreen: &v1beta1.IndicesGreenPredicate{}},
+			),
+			want: false,
+		},
+		{
+			name:     "disabled built-in predicate: OK",
+			proposed: esWithDisabledPredicates("do_not_restart_healthy_node_if_not_green"),
+			want:     true,
+		},
+		{
+			name:     "unknown disabled predicate: NOT OK",
+			proposed: esWithDisabledPredicates("do_not_restart_healthy_node_if_not_green", "unknown"),
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := NewValidationContext(nil, tt.proposed)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, validUpgradePredicates(*ctx).Allowed)
+		})
+	}
+}
+
+func Test_validNodeAttributes(t *testing.T) {
+	esWithAttributes := func(zoneAwareness *v1beta1.ZoneAwareness, attributes ...v1beta1.NodeAttribute) v1beta1.Elasticsearch {
+		return v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{
+			Version:       "7.4.0",
+			ZoneAwareness: zoneAwareness,
+			NodeSets:      []v1beta1.NodeSet{{Name: "default", NodeAttributes: attributes}},
+		}}
+	}
+	withAwarenessConfig := func(es v1beta1.Elasticsearch) v1beta1.Elasticsearch {
+		es.Spec.NodeSets[0].Config = &common.Config{Data: map[string]interface{}{
+			"cluster": map[string]interface{}{"routing.allocation.awareness.attributes": "rack"},
+		}}
+		return es
+	}
+	instanceType := v1beta1.NodeAttribute{Name: "instance_type", NodeLabel: "node.kubernetes.io/instance-type", Awareness: true}
+	tests := []struct {
+		name     string
+		proposed v1beta1.Elasticsearch
+		want     bool
+	}{
+		{
+			name:     "no attribute: OK",
+			proposed: esWithAttributes(nil),
+			want:     true,
+		},
+		{
+			name:     "valid attributes with zone awareness: OK",
+			proposed: esWithAttributes(&v1beta1.ZoneAwareness{}, instanceType),
+			want:     true,
+		},
+		{
+			name:     "missing node label: NOT OK",
+			proposed: esWithAttributes(nil, v1beta1.NodeAttribute{Name: "rack"}),
+			want:     false,
+		},
+		{
+			name:     "duplicate name: NOT OK",
+			proposed: esWithAttributes(nil, instanceType, instanceType),
+			want:     false,
+		},
+		{
+			name:     "zone attribute without zone awareness: OK",
+			proposed: esWithAttributes(nil, v1beta1.NodeAttribute{Name: "zone", NodeLabel: "zone-label"}),
+			want:     true,
+		},
+		{
+			name:     "zone attribute with zone awareness: NOT OK",
+			proposed: esWithAttributes(&v1beta1.ZoneAwareness{}, v1beta1.NodeAttribute{Name: "zone", NodeLabel: "zone-label"}),
+			want:     false,
+		},
+		{
+			name: "attributes mapped to the same environment variable: NOT OK",
+			proposed: esWithAttributes(nil,
+				v1beta1.NodeAttribute{Name: "instance-type", NodeLabel: "label-1"},
+				v1beta1.NodeAttribute{Name: "instance_type", NodeLabel: "label-2"},
+			),
+			want: false,
+		},
+		{
+			name:     "attribute mapped to the environment variable of the zone attribute: NOT OK",
+			proposed: esWithAttributes(&v1beta1.ZoneAwareness{}, v1beta1.NodeAttribute{Name: "Zone", NodeLabel: "zone-label"}),
+			want:     false,
+		},
+		{
+			name:     "awareness attributes in the configuration without generated awareness: OK",
+			proposed: withAwarenessConfig(esWithAttributes(nil, v1beta1.NodeAttribute{Name: "rack", NodeLabel: "rack-label"})),
+			want:     true,
+		},
+		{
+			name:     "awareness attributes in the configuration with generated awareness: NOT OK",
+			proposed: withAwarenessConfig(esWithAttributes(nil, instanceType)),
+			want:     false,
+		},
+		{
+			name:     "awareness attributes in the configuration with zone awareness: NOT OK",
+			proposed: withAwarenessConfig(esWithAttributes(&v1beta1.ZoneAwareness{})),
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := NewValidationContext(nil, tt.proposed)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, validNodeAttributes(*ctx).Allowed)
+		})
+	}
+}
+
+func Test_validIndexLifecycle(t *testing.T) {
+	esWithLifecycle := func(version string, lifecycle v1beta1.IndexLifecycleSpec) v1beta1.Elasticsearch {
+		return v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{Version: version, IndexLifecycle: &lifecycle}}
+	}
+	phases := common.NewConfig(map[string]interface{}{"hot": map[string]interface{}{"actions": map[string]interface{}{}}})
+	policy := v1beta1.LifecyclePolicy{Name: "logs", Phases: &phases}
+	template := v1beta1.IndexTemplate{Name: "logs", IndexPatterns: []string{"logs-*"}}
+	tests := []struct {
+		name     string
+		proposed v1beta1.Elasticsearch
+		want     bool
+	}{
+		{
+			name:     "no index lifecycle: OK",
+			proposed: v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{Version: "7.4.0"}},
+			want:     true,
+		},
+		{
+			name:     "valid policy and template: OK",
+			proposed: esWithLifecycle("7.4.0", v1beta1.IndexLifecycleSpec{Policies: []v1beta1.LifecyclePolicy{policy}, Templates: []v1beta1.IndexTemplate{template}}),
+			want:     true,
+		},
+		{
+			name:     "policy before 6.6.0: NOT OK",
+			proposed: esWithLifecycle("6.5.4", v1beta1.IndexLifecycleSpec{Policies: []v1beta1.LifecyclePolicy{policy}}),
+			want:     false,
+		},
+		{
+			name:     "template before 6.6.0: OK",
+			proposed: esWithLifecycle("6.5.4", v1beta1.IndexLifecycleSpec{Templates: []v1beta1.IndexTemplate{template}}),
+			want:     true,
+		},
+		{
+			name:     "duplicate policy: NOT OK",
+			proposed: esWithLifecycle("7.4.0", v1beta1.IndexLifecycleSpec{Policies: []v1beta1.LifecyclePolicy{policy, policy}}),
+			want:     false,
+		},
+		{
+			name:     "policy without phases: NOT OK",
+			proposed: esWithLifecycle("7.4.0", v1beta1.IndexLifecycleSpec{Policies: []v1beta1.LifecyclePolicy{{Name: "logs"}}}),
+			want:     false,
+		},
+		{
+			name:     "template without index patterns: NOT OK",
+			proposed: esWithLifecycle("7.4.0", v1beta1.IndexLifecycleSpec{Templates: []v1beta1.IndexTemplate{{Name: "logs"}}}),
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := NewValidationContext(nil, tt.proposed)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, validIndexLifecycle(*ctx).Allowed)
+		})
+	}
+}
+
+func Test_validSecurity(t *testing.T) {
+	esWithSecurity := func(security v1beta1.SecuritySpec) v1beta1.Elasticsearch {
+		return v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{Version: "7.4.0", Security: &security}}
+	}
+	rules := common.NewConfig(map[string]interface{}{"field": map[string]interface{}{"realm.name": "saml1"}})
+	mapping := v1beta1.RoleMapping{Name: "saml", Roles: []string{"viewer"}, Rules: &rules}
+	nativeUser := v1beta1.NativeUser{
+		Username:       "jane",
+		Roles:          []string{"viewer"},
+		PasswordSecret: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "jane"}, Key: "password"},
+	}
+	tests := []struct {
+		name     string
+		proposed v1beta1.Elasticsearch
+		want     bool
+	}{
+		{
+			name:     "no security: OK",
+			proposed: v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{Version: "7.4.0"}},
+			want:     true,
+		},
+		{
+			name: "valid roles, role mappings and native users: OK",
+			proposed: esWithSecurity(v1beta1.SecuritySpec{
+				Roles:        []v1beta1.Role{{Name: "viewer", Cluster: []string{"monitor"}}},
+				RoleMappings: []v1beta1.RoleMapping{mapping},
+				NativeUsers:  []v1beta1.NativeUser{nativeUser},
+			}),
+			want: true,
+		},
+		{
+			name:     "predefined role: NOT OK",
+			proposed: esWithSecurity(v1beta1.SecuritySpec{Roles: []v1beta1.Role{{Name: "elastic_internal_probe_user"}}}),
+			want:     false,
+		},
+		{
+			name:     "duplicate role mapping: NOT OK",
+			proposed: esWithSecurity(v1beta1.SecuritySpec{RoleMappings: []v1beta1.RoleMapping{mapping, mapping}}),
+			want:     false,
+		},
+		{
+			name:     "role mapping without rules: NOT OK",
+			proposed: esWithSecurity(v1beta1.SecuritySpec{RoleMappings: []v1beta1.RoleMapping{{Name: "saml", Roles: []string{"viewer"}}}}),
+			want:     false,
+		},
+		{
+			name: "reserved username: NOT OK",
+			proposed: esWithSecurity(v1beta1.SecuritySpec{NativeUsers: []v1beta1.NativeUser{
+				{Username: "elastic", PasswordSecret: nativeUser.PasswordSecret},
+			}}),
+			want: false,
+		},
+		{
+			name:     "duplicate file realm secret: NOT OK",
+			proposed: esWithSecurity(v1beta1.SecuritySpec{FileRealm: []common.SecretRef{{SecretName: "users"}, {SecretName: "users"}}}),
+			want:     false,
+		},
+		{
+			name:     "native user without password secret: NOT OK",
+			proposed: esWithSecurity(v1beta1.SecuritySpec{NativeUsers: []v1beta1.NativeUser{{Username: "jane"}}}),
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := NewValidationContext(nil, tt.proposed)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, validSecurity(*ctx).Allowed)
+		})
+	}
+}
+
+func Test_validRemoteClusters(t *testing.T) {
+	esWithRemoteClusters := func(remoteClusters ...v1beta1.RemoteCluster) v1beta1.Elasticsearch {
+		return v1beta1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "follower"},
+			Spec:       v1beta1.ElasticsearchSpec{Version: "7.4.0", RemoteClusters: remoteClusters},
+		}
+	}
+	leader := v1beta1.RemoteCluster{
+		Name:               "leader",
+		ElasticsearchRef:   common.ObjectSelector{Name: "leader", Namespace: "other"},
+		AutoFollowPatterns: []v1beta1.AutoFollowPattern{{Name: "logs", LeaderIndexPatterns: []string{"logs-*"}}},
+	}
+	tests := []struct {
+		name     string
+		proposed v1beta1.Elasticsearch
+		want     bool
+	}{
+		{
+			name:     "no remote clusters: OK",
+			proposed: esWithRemoteClusters(),
+			want:     true,
+		},
+		{
+			name:     "valid remote cluster: OK",
+			proposed: esWithRemoteClusters(leader),
+			want:     true,
+		},
+		{
+			name: "duplicate remote cluster: NOT OK",
+			proposed: esWithRemoteClusters(leader, v1beta1.RemoteCluster{
+				Name: "leader", ElasticsearchRef: common.ObjectSelector{Name: "another"},
+			}),
+			want: false,
+		},
+		{
+			name:     "remote cluster without reference: NOT OK",
+			proposed: esWithRemoteClusters(v1beta1.RemoteCluster{Name: "leader"}),
+			want:     false,
+		},
+		{
+			name: "remote cluster referencing the cluster itself: NOT OK",
+			proposed: esWithRemoteClusters(v1beta1.RemoteCluster{
+				Name: "leader", ElasticsearchRef: common.ObjectSelector{Name: "follower"},
+			}),
+			want: false,
+		},
+		{
+			name: "duplicate auto-follow pattern: NOT OK",
+			proposed: esWithRemoteClusters(leader, v1beta1.RemoteCluster{
+				Name:               "another",
+				ElasticsearchRef:   common.ObjectSelector{Name: "another"},
+				AutoFollowPatterns: leader.AutoFollowPatterns,
+			}),
+			want: false,
+		},
+		{
+			name: "auto-follow pattern without leader index patterns: NOT OK",
+			proposed: esWithRemoteClusters(v1beta1.RemoteCluster{
+				Name:               "leader",
+				ElasticsearchRef:   leader.ElasticsearchRef,
+				AutoFollowPatterns: []v1beta1.AutoFollowPattern{{Name: "logs"}},
+			}),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := NewValidationContext(nil, tt.proposed)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, validRemoteClusters(*ctx).Allowed)
+		})
+	}
+}
+
+func Test_validTransport(t *testing.T) {
+	esWithCAs := func(cas ...common.SecretRef) v1beta1.Elasticsearch {
+		return v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{
+			Version:   "7.4.0",
+			Transport: v1beta1.TransportConfig{TLS: v1beta1.TransportTLSOptions{CertificateAuthorities: cas}},
+		}}
+	}
+	tests := []struct {
+		name     string
+		proposed v1beta1.Elasticsearch
+		want     bool
+	}{
+		{
+			name:     "no additional CAs: OK",
+			proposed: esWithCAs(),
+			want:     true,
+		},
+		{
+			name:     "additional CAs: OK",
+			proposed: esWithCAs(common.SecretRef{SecretName: "on-prem-ca"}, common.SecretRef{SecretName: "other-ca"}),
+			want:     true,
+		},
+		{
+			name:     "missing secret name: NOT OK",
+			proposed: esWithCAs(common.SecretRef{}),
+			want:     false,
+		},
+		{
+			name:     "duplicate secret: NOT OK",
+			proposed: esWithCAs(common.SecretRef{SecretName: "on-prem-ca"}, common.SecretRef{SecretName: "on-prem-ca"}),
+			want:     false,
+		},
+		{
+			name: "cert-manager issuer: OK",
+			proposed: v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{
+				Version:   "7.4.0",
+				Transport: v1beta1.TransportConfig{TLS: v1beta1.TransportTLSOptions{IssuerRef: &common.IssuerRef{Name: "ca-issuer"}}},
+			}},
+			want: true,
+		},
+		{
+			name: "cert-manager issuer without name: NOT OK",
+			proposed: v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{
+				Version:   "7.4.0",
+				Transport: v1beta1.TransportConfig{TLS: v1beta1.TransportTLSOptions{IssuerRef: &common.IssuerRef{}}},
+			}},
+			want: false,
+		},
+		{
+			name: "both user-provided certificate and cert-manager issuer: NOT OK",
+			proposed: v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{
+				Version: "7.4.0",
+				Transport: v1beta1.TransportConfig{TLS: v1beta1.TransportTLSOptions{
+					Certificate: common.SecretRef{SecretName: "my-certs"},
+					IssuerRef:   &common.IssuerRef{Name: "ca-issuer"},
+				}},
+			}},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := NewValidationContext(nil, tt.proposed)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, validTransport(*ctx).Allowed)
+		})
+	}
+}
+
+func Test_validKeyParams(t *testing.T) {
+	esWithKey := func(algorithm string, size int) v1beta1.Elasticsearch {
+		return v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{
+			Version: "7.4.0",
+			HTTP: common.HTTPConfig{TLS: common.TLSOptions{SelfSignedCertificate: &common.SelfSignedCertificate{
+				KeyAlgorithm: algorithm,
+				KeySize:      size,
+			}}},
+		}}
+	}
+	tests := []struct {
+		name     string
+		proposed v1beta1.Elasticsearch
+		want     bool
+	}{
+		{
+			name:     "no self-signed certificate: OK",
+			proposed: v1beta1.Elasticsearch{Spec: v1beta1.ElasticsearchSpec{Version: "7.4.0"}},
+			want:     true,
+		},
+		{
+			name:     "algorithm only: OK",
+			proposed: esWithKey("ecdsa", 0),
+			want:     true,
+		},
+		{
+			name:     "size only: OK",
+			proposed: esWithKey("", 384),
+			want:     true,
+		},
+		{
+			name:     "RSA key: OK",
+			proposed: esWithKey("rsa", 4096),
+			want:     true,
+		},
+		{
+			name:     "ECDSA key: OK",
+			proposed: esWithKey("ecdsa", 384),
+			want:     true,
+		},
+		{
+			name:     "ECDSA key with an RSA size: NOT OK",
+			proposed: esWithKey("ecdsa", 2048),
+			want:     false,
+		},
+		{
+			name:     "RSA key with an ECDSA size: NOT OK",
+			proposed: esWithKey("rsa", 256),
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := NewValidationContext(nil, tt.proposed)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, validKeyParams(*ctx).Allowed)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package volume
+
+import (
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// DefaultStorageClassAnnotation marks the storage class used by claims that do not specify any.
+	DefaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+	// BetaDefaultStorageClassAnnotation is the beta version of DefaultStorageClassAnnotation, still set by some providers.
+	BetaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// StorageRequest returns the storage requested by the given claim, or zero if not specified.
+func StorageRequest(claim corev1.PersistentVolumeClaim) resource.Quantity {
+	return claim.Spec.Resources.Requests[corev1.ResourceStorage]
+}
+
+// ValidateClaimsUpdate checks that the proposed volume claim templates only differ from the current ones
+// by larger storage requests, which can be applied by expanding the existing volumes. Storage requests can also
+// be reverted to the given actual storage, indexed by claim template name, of volumes that could not be expanded.
+func ValidateClaimsUpdate(current, proposed []corev1.PersistentVolumeClaim, actualStorage map[string]resource.Quantity) error {
+	if len(current) != len(proposed) {
+		return fmt.Errorf("volume claim templates cannot be added or removed")
+	}
+	for i := range proposed {
+		currentStorage := StorageRequest(current[i])
+		proposedStorage := StorageRequest(proposed[i])
+		minStorage := currentStorage
+		if actual, exists := actualStorage[proposed[i].Name]; exists && actual.Cmp(minStorage) < 0 {
+			minStorage = actual
+		}
+		if proposedStorage.Cmp(minStorage) < 0 {
+			return fmt.Errorf("storage request of volume claim template %s cannot be decreased", proposed[i].Name)
+		}
+		// compare the claims with the same storage request
+		withCurrentStorage := *proposed[i].DeepCopy()
+		if withCurrentStorage.Spec.Resources.Requests != nil {
+			withCurrentStorage.Spec.Resources.Requests[corev1.ResourceStorage] = currentStorage
+		}
+		if !reflect.DeepEqual(current[i], withCurrentStorage) {
+			return fmt.Errorf("volume claim template %s can only be modified to increase its storage request", proposed[i].Name)
+		}
+	}
+	return nil
+}
+
+// ClaimsToExpand returns the expected volume claim templates requesting more storage than the actual
+// claim template with the same name.
+func ClaimsToExpand(actual, expected []corev1.PersistentVolumeClaim) []corev1.PersistentVolumeClaim {
+	var toExpand []corev1.PersistentVolumeClaim
+	for _, expectedClaim := range expected {
+		for _, actualClaim := range actual {
+			if actualClaim.Name != expectedClaim.Name {
+				continue
+			}
+			expectedStorage := StorageRequest(expectedClaim)
+			if expectedStorage.Cmp(StorageRequest(actualClaim)) > 0 {
+				toExpand = append(toExpand, expectedClaim)
+			}
+		}
+	}
+	return toExpand
+}
+
+// IsDefaultStorageClass returns true if the given storage class is annotated as the default one.
+func IsDefaultStorageClass(sc storagev1.StorageClass) bool {
+	return sc.Annotations[DefaultStorageClassAnnotation] == "true" ||
+		sc.Annotations[BetaDefaultStorageClassAnnotation] == "true"
+}
+
+// AllowsExpansion returns true if the volumes of the given storage class can be expanded.
+func AllowsExpansion(sc storagev1.StorageClass) bool {
+	return sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion
+}
+
+// FileSystemResizePending returns true if the volume of the given claim was expanded, but its file system
+// is only resized when the Pod using it restarts.
+func FileSystemResizePending(claim corev1.PersistentVolumeClaim) bool {
+	for _, condition := range claim.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -35,4 +35,8 @@ const (
 
 	ScriptsVolumeName      = "elastic-internal-scripts"
 	ScriptsVolumeMountPath = "/mnt/elastic-internal/scripts"
+
+	PodAnnotationsVolumeName      = "elastic-internal-pod-annotations"
+	PodAnnotationsVolumeMountPath = "/mnt/elastic-internal/pod-annotations"
+	PodAnnotationsFile            = "annotations"
 )
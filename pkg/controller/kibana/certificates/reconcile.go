@@ -7,6 +7,7 @@ package certificates
 import (
 	"time"
 
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates/http"
@@ -23,15 +24,18 @@ func Reconcile(
 	kb v1beta1.Kibana,
 	services []coverv1.Service,
 	rotation certificates.RotationParams,
-) *reconciler.Results {
+	keyParams certificates.KeyParams,
+) (*commonv1beta1.CertificatesStatus, *reconciler.Results) {
 	selfSignedCert := kb.Spec.HTTP.TLS.SelfSignedCertificate
 	if selfSignedCert != nil && selfSignedCert.Disabled {
-		return nil
+		return nil, nil
 	}
 	results := reconciler.Results{}
 
 	labels := label.NewLabels(kb.Name)
 
+	keyParams = http.SelfSignedKeyParams(kb.Spec.HTTP.TLS, keyParams)
+
 	// reconcile CA certs first
 	httpCa, err := certificates.ReconcileCAForOwner(
 		d.K8sClient(),
@@ -41,9 +45,10 @@ func Reconcile(
 		labels,
 		certificates.HTTPCAType,
 		rotation,
+		keyParams,
 	)
 	if err != nil {
-		return results.WithError(err)
+		return nil, results.WithError(err)
 	}
 
 	// handle CA expiry via requeue
@@ -61,11 +66,23 @@ func Reconcile(
 		labels,
 		services,
 		rotation, // todo correct rotation
+		keyParams,
 	)
 	if err != nil {
-		return results.WithError(err)
+		return nil, results.WithError(err)
 	}
 	// reconcile http public cert secret
-	results.WithError(http.ReconcileHTTPCertsPublicSecret(d.K8sClient(), d.Scheme(), &kb, name.KBNamer, httpCertificates))
-	return &results
+	if err := http.ReconcileHTTPCertsPublicSecret(d.K8sClient(), d.Scheme(), &kb, name.KBNamer, httpCertificates); err != nil {
+		return nil, results.WithError(err)
+	}
+
+	expiration, err := httpCertificates.Expiration()
+	if err != nil {
+		return nil, results.WithError(err)
+	}
+	// requeue to warn about user-provided certificates before they expire
+	results.WithResult(reconcile.Result{
+		RequeueAfter: certificates.ReportExpiration(d.Recorder(), &kb, certificates.HTTPLayer, expiration, rotation.RotateBefore),
+	})
+	return &commonv1beta1.CertificatesStatus{HTTP: &expiration}, &results
 }
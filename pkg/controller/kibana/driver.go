@@ -226,10 +226,11 @@ func (d *driver) Reconcile(
 		return results.WithError(err)
 	}
 
-	results.WithResults(kbcerts.Reconcile(d, *kb, []corev1.Service{*svc}, params.CACertRotation))
-	if results.HasError() {
+	certificatesStatus, res := kbcerts.Reconcile(d, *kb, []corev1.Service{*svc}, params.CACertRotation, params.KeyParams)
+	if results.WithResults(res).HasError() {
 		return &results
 	}
+	state.UpdateCertificatesStatus(certificatesStatus)
 
 	kbSettings, err := config.NewConfigSettings(d.client, *kb)
 	if err != nil {
@@ -256,6 +257,16 @@ func (d *driver) Reconcile(
 		return results.WithError(err)
 	}
 	state.UpdateKibanaState(reconciledDp)
+
+	var fingerprint string
+	if kb.Spec.HTTP.TLS.Enabled() {
+		// Kibana is rolled with the new certificate through the config checksum
+		fingerprint, err = http.CertificateFingerprint(d.client, kbname.KBNamer, kb)
+		if err != nil {
+			return results.WithError(err)
+		}
+	}
+	state.UpdateHTTPStatus(reconciledDp, fingerprint)
 	return &results
 }
 
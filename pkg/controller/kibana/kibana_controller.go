@@ -12,10 +12,12 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/annotation"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/finalizer"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/kibana/label"
@@ -72,7 +74,10 @@ func add(mgr manager.Manager, r reconcile.Reconciler) (controller.Controller, er
 
 func addWatches(c controller.Controller, r *ReconcileKibana) error {
 	// Watch for changes to Kibana
-	if err := c.Watch(&source.Kind{Type: &kibanav1beta1.Kibana{}}, &handler.EnqueueRequestForObject{}); err != nil {
+	if err := c.Watch(
+		&source.Kind{Type: &kibanav1beta1.Kibana{}}, &handler.EnqueueRequestForObject{},
+		operator.ResourceSelectorPredicate(r.params.ResourceSelector),
+	); err != nil {
 		return err
 	}
 
@@ -129,6 +134,7 @@ type ReconcileKibana struct {
 // in the Kibana.Spec
 func (r *ReconcileKibana) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	defer common.LogReconciliationRun(log, request, &r.iteration)()
+	defer reconciler.ObserveDuration(kibanav1beta1.Kind, "reconcile")()
 
 	// retrieve the kibana object
 	var kb kibanav1beta1.Kibana
@@ -136,6 +142,13 @@ func (r *ReconcileKibana) Reconcile(request reconcile.Request) (reconcile.Result
 		return reconcile.Result{}, err
 	}
 
+	if !operator.MatchesResourceSelector(r.params.ResourceSelector, kb.Labels) {
+		// the resource is reconciled by another operator: stop watching its secrets
+		log.Info("Releasing resource not matching the resource selector", "namespace", kb.Namespace, "kibana_name", kb.Name)
+		certificates.ForgetExpiration(kb.Kind, k8s.ExtractNamespacedName(&kb))
+		return reconcile.Result{}, r.finalizers.Release(&kb, r.finalizersFor(&kb)...)
+	}
+
 	// skip reconciliation if paused
 	if common.IsPaused(kb.ObjectMeta) {
 		log.Info("Object is paused. Skipping reconciliation", "namespace", kb.Namespace, "kibana_name", kb.Name)
@@ -160,6 +173,7 @@ func (r *ReconcileKibana) Reconcile(request reconcile.Request) (reconcile.Result
 
 	// Kibana will be deleted nothing to do other than run finalizers
 	if kb.IsMarkedForDeletion() {
+		certificates.ForgetExpiration(kb.Kind, k8s.ExtractNamespacedName(&kb))
 		return reconcile.Result{}, nil
 	}
 
@@ -175,7 +189,7 @@ func (r *ReconcileKibana) Reconcile(request reconcile.Request) (reconcile.Result
 
 func (r *ReconcileKibana) isCompatible(kb *kibanav1beta1.Kibana) (bool, error) {
 	selector := map[string]string{label.KibanaNameLabelName: kb.Name}
-	compat, err := annotation.ReconcileCompatibility(r.Client, kb, selector, r.params.OperatorInfo.BuildInfo.Version)
+	compat, err := annotation.ReconcileCompatibility(r.Client, kb, selector, r.params.OperatorInfo.BuildInfo.Version, r.params.ResourceSelector)
 	if err != nil {
 		k8s.EmitErrorEvent(r.recorder, err, kb, events.EventCompatCheckError, "Error during compatibility check: %v", err)
 	}
@@ -191,6 +205,7 @@ func (r *ReconcileKibana) doReconcile(request reconcile.Request, kb *kibanav1bet
 	}
 
 	state := NewState(request, kb)
+	state.Kibana.Status.Operator = r.params.Name
 	driver, err := newDriver(r, r.scheme, *ver, r.dynamicWatches, r.recorder)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -5,7 +5,9 @@
 package kibana
 
 import (
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/deployment"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -36,3 +38,22 @@ func (s State) UpdateKibanaState(deployment v1.Deployment) {
 		}
 	}
 }
+
+// UpdateHTTPStatus reports the fingerprint of the HTTP certificate served by Kibana, once the given deployment
+// is rolled out with this certificate. An empty fingerprint means that TLS is disabled.
+func (s State) UpdateHTTPStatus(dep v1.Deployment, fingerprint string) {
+	if fingerprint == "" {
+		s.Kibana.Status.HTTP = nil
+		return
+	}
+	if !deployment.IsRolledOut(dep) {
+		// some pods may still serve the previous certificate
+		return
+	}
+	s.Kibana.Status.HTTP = &commonv1beta1.HTTPStatus{CertificateFingerprint: fingerprint}
+}
+
+// UpdateCertificatesStatus reports when the HTTP certificates expire.
+func (s State) UpdateCertificatesStatus(status *commonv1beta1.CertificatesStatus) {
+	s.Kibana.Status.Certificates = status
+}
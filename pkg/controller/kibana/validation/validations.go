@@ -0,0 +1,69 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"fmt"
+
+	kbtype "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/validation"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+)
+
+const parseVersionErrMsg = "Cannot parse Kibana version"
+
+// Validations are all registered Kibana validations.
+var Validations = []Validation{
+	validVersion,
+	validSanIP,
+	validKeyParams,
+}
+
+// Validation is a function from a currently stored Kibana spec and proposed new spec
+// (both inside a Context struct) to a validation.Result.
+type Validation func(ctx Context) validation.Result
+
+// Context is structured input for validation functions.
+type Context struct {
+	// Current is the Kibana stored in the api server. Can be nil on create.
+	Current *kbtype.Kibana
+	// Proposed is the Kibana submitted for validation.
+	Proposed kbtype.Kibana
+}
+
+// Validate runs all validations against the proposed Kibana, and returns the failed ones.
+func Validate(current *kbtype.Kibana, proposed kbtype.Kibana) []validation.Result {
+	vCtx := Context{
+		Current:  current,
+		Proposed: proposed,
+	}
+	var errs []validation.Result
+	for _, v := range Validations {
+		r := v(vCtx)
+		if r.Allowed {
+			continue
+		}
+		errs = append(errs, r)
+	}
+	return errs
+}
+
+func validVersion(ctx Context) validation.Result {
+	if ctx.Proposed.Spec.Version == "" {
+		return validation.OK
+	}
+	if _, err := version.Parse(ctx.Proposed.Spec.Version); err != nil {
+		return validation.Result{Reason: fmt.Sprintf("%s: %v", parseVersionErrMsg, err)}
+	}
+	return validation.OK
+}
+
+func validSanIP(ctx Context) validation.Result {
+	return validation.ValidSanIP(ctx.Proposed.Spec.HTTP.TLS)
+}
+
+func validKeyParams(ctx Context) validation.Result {
+	return validation.ValidKeyParams(ctx.Proposed.Spec.HTTP.TLS)
+}
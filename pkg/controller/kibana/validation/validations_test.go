@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validation
+
+import (
+	"testing"
+
+	common "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	kbtype "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	withSelfSignedCertificate := func(cert common.SelfSignedCertificate) kbtype.Kibana {
+		return kbtype.Kibana{Spec: kbtype.KibanaSpec{
+			Version: "7.4.0",
+			HTTP:    common.HTTPConfig{TLS: common.TLSOptions{SelfSignedCertificate: &cert}},
+		}}
+	}
+	tests := []struct {
+		name     string
+		proposed kbtype.Kibana
+		want     []string
+	}{
+		{
+			name:     "valid",
+			proposed: kbtype.Kibana{Spec: kbtype.KibanaSpec{Version: "7.4.0"}},
+		},
+		{
+			name:     "invalid version",
+			proposed: kbtype.Kibana{Spec: kbtype.KibanaSpec{Version: "7.x"}},
+			want:     []string{`Cannot parse Kibana version: version string has too few segments for version 7.x`},
+		},
+		{
+			name: "invalid SAN IP",
+			proposed: withSelfSignedCertificate(common.SelfSignedCertificate{
+				SubjectAlternativeNames: []common.SubjectAlternativeName{{IP: "notanip"}},
+			}),
+			want: []string{"invalid SAN IP address: notanip"},
+		},
+		{
+			name:     "invalid key size",
+			proposed: withSelfSignedCertificate(common.SelfSignedCertificate{KeyAlgorithm: "ecdsa", KeySize: 2048}),
+			want:     []string{"Invalid self-signed certificate key: unsupported ECDSA key size 2048, expected 256 or 384"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reasons []string
+			for _, r := range Validate(nil, tt.proposed) {
+				reasons = append(reasons, r.Reason)
+			}
+			require.Equal(t, tt.want, reasons)
+		})
+	}
+}
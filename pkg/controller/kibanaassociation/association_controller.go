@@ -18,6 +18,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/finalizer"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/user"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
 	esname "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/name"
@@ -120,15 +121,23 @@ type ReconcileAssociation struct {
 // the Association.Spec
 func (r *ReconcileAssociation) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	defer common.LogReconciliationRun(log, request, &r.iteration)()
+	defer reconciler.ObserveDuration(kbtype.Kind, "association")()
 
 	var kibana kbtype.Kibana
 	if ok, err := association.FetchWithAssociation(r.Client, request, &kibana); !ok {
 		return reconcile.Result{}, err
 	}
 
-	// register or execute watch finalizers
 	h := finalizer.NewHandler(r)
 	kbName := k8s.ExtractNamespacedName(&kibana)
+	if !operator.MatchesResourceSelector(r.ResourceSelector, kibana.Labels) {
+		// the resource is reconciled by another operator: stop watching the referenced cluster, the association user
+		// is left to the other operator
+		log.Info("Releasing resource not matching the resource selector", "namespace", kibana.Namespace, "kibana_name", kibana.Name)
+		return reconcile.Result{}, h.Release(&kibana, watchFinalizer(kbName, r.watches))
+	}
+
+	// register or execute watch finalizers
 	err := h.Handle(
 		&kibana,
 		watchFinalizer(kbName, r.watches),
@@ -197,7 +206,7 @@ func resultFromStatus(status commonv1beta1.AssociationStatus) reconcile.Result {
 
 func (r *ReconcileAssociation) isCompatible(kibana *kbtype.Kibana) (bool, error) {
 	selector := map[string]string{label.KibanaNameLabelName: kibana.Name}
-	compat, err := annotation.ReconcileCompatibility(r.Client, kibana, selector, r.OperatorInfo.BuildInfo.Version)
+	compat, err := annotation.ReconcileCompatibility(r.Client, kibana, selector, r.OperatorInfo.BuildInfo.Version, r.ResourceSelector)
 	if err != nil {
 		k8s.EmitErrorEvent(r.recorder, err, kibana, events.EventCompatCheckError, "Error during compatibility check: %v", err)
 	}
@@ -237,16 +246,6 @@ func (r *ReconcileAssociation) reconcileInternal(kibana *kbtype.Kibana) (commonv
 		return commonv1beta1.AssociationFailed, err
 	}
 
-	userSecretKey := association.UserKey(kibana, kibanaUserSuffix)
-	// watch the user secret in the ES namespace
-	if err := r.watches.Secrets.AddHandler(watches.NamedWatch{
-		Name:    elasticsearchWatchName(kibanaKey),
-		Watched: []types.NamespacedName{userSecretKey},
-		Watcher: kibanaKey,
-	}); err != nil {
-		return commonv1beta1.AssociationFailed, err
-	}
-
 	var es estype.Elasticsearch
 	if err := r.Get(esRefKey, &es); err != nil {
 		k8s.EmitErrorEvent(r.recorder, err, kibana, events.EventAssociationError, "Failed to find referenced backend %s: %v", esRefKey, err)
@@ -267,6 +266,21 @@ func (r *ReconcileAssociation) reconcileInternal(kibana *kbtype.Kibana) (commonv
 		return commonv1beta1.AssociationFailed, err
 	}
 
+	rotation, err := association.UserRotation(r.Client, kibana, kibanaUserSuffix, es)
+	if err != nil {
+		return commonv1beta1.AssociationPending, err
+	}
+
+	userSecretKey := association.UserKey(kibana, kibanaUserSuffix, rotation)
+	// watch the user secret in the ES namespace
+	if err := r.watches.Secrets.AddHandler(watches.NamedWatch{
+		Name:    elasticsearchWatchName(kibanaKey),
+		Watched: []types.NamespacedName{userSecretKey},
+		Watcher: kibanaKey,
+	}); err != nil {
+		return commonv1beta1.AssociationFailed, err
+	}
+
 	if err := association.ReconcileEsUser(
 		r.Client,
 		r.scheme,
@@ -277,6 +291,7 @@ func (r *ReconcileAssociation) reconcileInternal(kibana *kbtype.Kibana) (commonv
 		},
 		elasticsearchuser.KibanaSystemUserBuiltinRole,
 		kibanaUserSuffix,
+		rotation,
 		es); err != nil {
 		return commonv1beta1.AssociationPending, err
 	}
@@ -287,7 +302,7 @@ func (r *ReconcileAssociation) reconcileInternal(kibana *kbtype.Kibana) (commonv
 	}
 
 	// construct the expected association configuration
-	authSecret := association.ClearTextSecretKeySelector(kibana, kibanaUserSuffix)
+	authSecret := association.ClearTextSecretKeySelector(kibana, kibanaUserSuffix, rotation)
 	expectedESAssoc := &commonv1beta1.AssociationConf{
 		AuthSecretName: authSecret.Name,
 		AuthSecretKey:  authSecret.Key,
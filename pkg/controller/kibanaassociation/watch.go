@@ -8,6 +8,7 @@ import (
 	estype "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	kbtype "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/finalizer"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -18,7 +19,10 @@ import (
 
 func addWatches(c controller.Controller, r *ReconcileAssociation) error {
 	// Watch for changes to Kibana resources
-	if err := c.Watch(&source.Kind{Type: &kbtype.Kibana{}}, &handler.EnqueueRequestForObject{}); err != nil {
+	if err := c.Watch(
+		&source.Kind{Type: &kbtype.Kibana{}}, &handler.EnqueueRequestForObject{},
+		operator.ResourceSelectorPredicate(r.ResourceSelector),
+	); err != nil {
 		return err
 	}
 
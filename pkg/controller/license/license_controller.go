@@ -21,6 +21,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -48,22 +49,24 @@ var log = logf.Log.WithName(name)
 // This happens independently from any watch triggered reconcile request.
 func (r *ReconcileLicenses) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	defer common.LogReconciliationRun(log, request, &r.iteration)()
+	defer reconciler.ObserveDuration(v1beta1.Kind, "license")()
 	return r.reconcileInternal(request)
 }
 
 // Add creates a new EnterpriseLicense Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, p operator.Parameters) error {
-	return add(mgr, newReconciler(mgr, p))
+	return add(mgr, newReconciler(mgr, p), p.ResourceSelector)
 }
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileLicenses {
 	c := k8s.WrapClient(mgr.GetClient())
 	return &ReconcileLicenses{
-		Client:  c,
-		scheme:  mgr.GetScheme(),
-		checker: license.NewLicenseChecker(c, params.OperatorNamespace),
+		Client:           c,
+		scheme:           mgr.GetScheme(),
+		checker:          license.NewLicenseChecker(c, params.OperatorNamespace),
+		resourceSelector: params.ResourceSelector,
 	}
 }
 
@@ -89,16 +92,17 @@ func nextReconcileRelativeTo(now, expiry time.Time, safety time.Duration) reconc
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r reconcile.Reconciler, resourceSelector labels.Selector) error {
 	// Create a new controller
 	c, err := controller.New(name, mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
 
-	// Watch for changes to Elasticsearch clusters.
+	// Watch for changes to Elasticsearch clusters reconciled by this operator.
 	if err := c.Watch(
 		&source.Kind{Type: &v1beta1.Elasticsearch{}}, &handler.EnqueueRequestForObject{},
+		operator.ResourceSelectorPredicate(resourceSelector),
 	); err != nil {
 		return err
 	}
@@ -147,6 +151,8 @@ type ReconcileLicenses struct {
 	// iteration is the number of times this controller has run its Reconcile method
 	iteration uint64
 	checker   license.Checker
+	// resourceSelector restricts the reconciled clusters to the ones matching it, unless nil
+	resourceSelector labels.Selector
 }
 
 // findLicense tries to find the best license available.
@@ -212,12 +218,14 @@ func (r *ReconcileLicenses) reconcileClusterLicense(cluster v1beta1.Elasticsearc
 	}
 	if !found {
 		// no license, nothing to do
+		expirations.forget(k8s.ExtractNamespacedName(&cluster))
 		return noResult, nil
 	}
 	// make sure the signature secret is created in the cluster's namespace
 	if err = reconcileSecret(r, cluster, parent, matchingSpec); err != nil {
 		return noResult, err
 	}
+	expirations.observe(k8s.ExtractNamespacedName(&cluster), matchingSpec)
 	return matchingSpec.ExpiryTime(), err
 }
 
@@ -228,6 +236,7 @@ func (r *ReconcileLicenses) reconcileInternal(request reconcile.Request) (reconc
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// nothing to do no cluster
+			expirations.forget(request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		return reconcile.Result{}, err
@@ -235,6 +244,13 @@ func (r *ReconcileLicenses) reconcileInternal(request reconcile.Request) (reconc
 
 	if !cluster.DeletionTimestamp.IsZero() {
 		// cluster is being deleted nothing to do
+		expirations.forget(request.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+
+	if !operator.MatchesResourceSelector(r.resourceSelector, cluster.Labels) {
+		// the cluster license is managed by another operator
+		expirations.forget(request.NamespacedName)
 		return reconcile.Result{}, nil
 	}
 
@@ -42,7 +42,7 @@ func TestReconcile(t *testing.T) {
 			Client:  k8s.WrapClient(mgr.GetClient()),
 			scheme:  mgr.GetScheme(),
 			checker: license.MockChecker{},
-		})
+		}, nil)
 	}, operator.Parameters{})
 	defer stop()
 
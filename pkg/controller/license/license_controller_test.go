@@ -20,6 +20,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -119,6 +120,7 @@ func TestReconcileLicenses_reconcileInternal(t *testing.T) {
 		name             string
 		cluster          *v1beta1.Elasticsearch
 		k8sResources     []runtime.Object
+		resourceSelector labels.Selector
 		wantErr          string
 		wantNewLicense   bool
 		wantRequeue      bool
@@ -157,6 +159,19 @@ func TestReconcileLicenses_reconcileInternal(t *testing.T) {
 			wantRequeue:      false,
 			wantRequeueAfter: true,
 		},
+		{
+			name:    "cluster reconciled by another operator",
+			cluster: cluster,
+			k8sResources: []runtime.Object{
+				enterpriseLicense(t, commonlicense.ElasticsearchLicenseTypeGold, 1, false),
+				cluster,
+			},
+			resourceSelector: labels.SelectorFromSet(map[string]string{"shard": "a"}),
+			wantErr:          "",
+			wantNewLicense:   false,
+			wantRequeue:      false,
+			wantRequeueAfter: false,
+		},
 		{
 			name:    "existing license expired",
 			cluster: cluster,
@@ -175,9 +190,10 @@ func TestReconcileLicenses_reconcileInternal(t *testing.T) {
 			require.NoError(t, v1beta1.AddToScheme(scheme.Scheme))
 			client := k8s.WrapClient(fake.NewFakeClient(tt.k8sResources...))
 			r := &ReconcileLicenses{
-				Client:  client,
-				scheme:  scheme.Scheme,
-				checker: commonlicense.MockChecker{},
+				Client:           client,
+				scheme:           scheme.Scheme,
+				checker:          commonlicense.MockChecker{},
+				resourceSelector: tt.resourceSelector,
 			}
 			nsn := k8s.ExtractNamespacedName(tt.cluster)
 			res, err := r.reconcileInternal(reconcile.Request{NamespacedName: nsn})
@@ -0,0 +1,77 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package license
+
+import (
+	"sync"
+	"time"
+
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	expiryDesc = prometheus.NewDesc(
+		"elastic_license_expiry_seconds",
+		"Number of seconds before the license applied by the operator to the Elasticsearch cluster expires.",
+		[]string{"namespace", "name", "type"},
+		nil,
+	)
+
+	expirations = newExpirationCollector(time.Now)
+)
+
+func init() {
+	metrics.Registry.MustRegister(expirations)
+}
+
+// expirationCollector is a Prometheus collector computing the time left before the licenses applied to the clusters
+// expire when the metrics are collected, so that they do not depend on the reconciliation frequency.
+type expirationCollector struct {
+	mutex    sync.RWMutex
+	licenses map[types.NamespacedName]esclient.License
+	now      func() time.Time
+}
+
+func newExpirationCollector(now func() time.Time) *expirationCollector {
+	return &expirationCollector{
+		licenses: make(map[types.NamespacedName]esclient.License),
+		now:      now,
+	}
+}
+
+func (c *expirationCollector) observe(cluster types.NamespacedName, license esclient.License) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.licenses[cluster] = license
+}
+
+func (c *expirationCollector) forget(cluster types.NamespacedName) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.licenses, cluster)
+}
+
+// Describe implements prometheus.Collector.
+func (c *expirationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- expiryDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *expirationCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	now := c.now()
+	for cluster, license := range c.licenses {
+		ch <- prometheus.MustNewConstMetric(
+			expiryDesc,
+			prometheus.GaugeValue,
+			license.ExpiryTime().Sub(now).Seconds(),
+			cluster.Namespace, cluster.Name, license.Type,
+		)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package license
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExpirationCollector(t *testing.T) {
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	expiringIn := func(licenseType string, d time.Duration) esclient.License {
+		return esclient.License{Type: licenseType, ExpiryDateInMillis: now.Add(d).UnixNano() / int64(time.Millisecond)}
+	}
+	collector := newExpirationCollector(func() time.Time { return now })
+	collector.observe(types.NamespacedName{Namespace: "ns", Name: "es1"}, expiringIn("platinum", 24*time.Hour))
+	collector.observe(types.NamespacedName{Namespace: "ns", Name: "es2"}, expiringIn("gold", -time.Hour))
+	collector.observe(types.NamespacedName{Namespace: "ns", Name: "es3"}, expiringIn("gold", time.Hour))
+	collector.forget(types.NamespacedName{Namespace: "ns", Name: "es3"})
+
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP elastic_license_expiry_seconds Number of seconds before the license applied by the operator to the Elasticsearch cluster expires.
+# TYPE elastic_license_expiry_seconds gauge
+elastic_license_expiry_seconds{name="es1",namespace="ns",type="platinum"} 86400
+elastic_license_expiry_seconds{name="es2",namespace="ns",type="gold"} -3600
+`)))
+}
@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package remotecluster
+
+import (
+	"reflect"
+
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates/transport"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// isRemoteCluster returns true if the given cluster uses the remote cluster as one of its remote clusters.
+func isRemoteCluster(es v1beta1.Elasticsearch, remote types.NamespacedName) bool {
+	for _, rc := range es.Spec.RemoteClusters {
+		if rc.RemoteClusterRef(es.Namespace) == remote {
+			return true
+		}
+	}
+	return false
+}
+
+// trustedClusters returns the existing clusters whose transport CA must be trusted by the given cluster: the remote
+// clusters it references. A cluster never trusts the CA of a cluster referencing it unless it references that cluster
+// too, so that any cluster cannot be granted access to another one by only listing it as a remote cluster.
+func trustedClusters(es v1beta1.Elasticsearch, clusters []v1beta1.Elasticsearch) []types.NamespacedName {
+	esNSN := k8s.ExtractNamespacedName(&es)
+	var trusted []types.NamespacedName
+	for _, cluster := range clusters {
+		clusterNSN := k8s.ExtractNamespacedName(&cluster)
+		if clusterNSN == esNSN {
+			continue
+		}
+		if isRemoteCluster(es, clusterNSN) {
+			trusted = append(trusted, clusterNSN)
+		}
+	}
+	return trusted
+}
+
+// remoteCACopies returns the copies of the transport CA of the given cluster in the namespaces of other clusters.
+func remoteCACopies(c k8s.Client, es types.NamespacedName) ([]corev1.Secret, error) {
+	var secrets corev1.SecretList
+	matchLabels := client.MatchingLabels(map[string]string{
+		transport.RemoteClusterNameLabelName:      es.Name,
+		transport.RemoteClusterNamespaceLabelName: es.Namespace,
+	})
+	if err := c.List(&secrets, matchLabels); err != nil {
+		return nil, err
+	}
+	return secrets.Items, nil
+}
+
+// reconcileRemoteCAs copies the transport CAs of the given remote clusters in the namespace of the cluster es,
+// and removes the CAs of the clusters it does not trust anymore.
+func reconcileRemoteCAs(c k8s.Client, scheme *runtime.Scheme, es v1beta1.Elasticsearch, remotes []types.NamespacedName) error {
+	expectedSecrets := make(map[string]struct{}, len(remotes))
+	for _, remote := range remotes {
+		var remoteCA corev1.Secret
+		if err := c.Get(transport.PublicCertsSecretRef(remote), &remoteCA); err != nil {
+			if errors.IsNotFound(err) {
+				// the remote cluster is not ready yet, reconciled again once its transport CA is created
+				log.V(1).Info("Transport CA of remote cluster not found", "namespace", es.Namespace, "es_name", es.Name,
+					"remote_namespace", remote.Namespace, "remote_name", remote.Name)
+				continue
+			}
+			return err
+		}
+
+		expected := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: es.Namespace,
+				Name:      transport.RemoteCASecretName(es.Name, remote),
+				Labels:    transport.RemoteCALabels(es.Name, remote),
+			},
+			Data: map[string][]byte{
+				certificates.CAFileName: remoteCA.Data[certificates.CAFileName],
+			},
+		}
+		expectedSecrets[expected.Name] = struct{}{}
+		reconciled := &corev1.Secret{}
+		if err := reconciler.ReconcileResource(reconciler.Params{
+			Client:     c,
+			Scheme:     scheme,
+			Owner:      &es,
+			Expected:   expected,
+			Reconciled: reconciled,
+			NeedsUpdate: func() bool {
+				return !reflect.DeepEqual(expected.Labels, reconciled.Labels) ||
+					!reflect.DeepEqual(expected.Data, reconciled.Data)
+			},
+			UpdateReconciled: func() {
+				reconciled.Labels = expected.Labels
+				reconciled.Data = expected.Data
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	existing, err := transport.RemoteCASecrets(c, k8s.ExtractNamespacedName(&es))
+	if err != nil {
+		return err
+	}
+	for i := range existing {
+		if _, ok := expectedSecrets[existing[i].Name]; ok {
+			continue
+		}
+		log.Info("Removing transport CA of untrusted remote cluster", "namespace", es.Namespace, "es_name", es.Name,
+			"secret_name", existing[i].Name)
+		if err := c.Delete(&existing[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package remotecluster
+
+import (
+	"testing"
+
+	commonv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates/transport"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func es(namespace, name string, remotes ...commonv1beta1.ObjectSelector) *v1beta1.Elasticsearch {
+	cluster := v1beta1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	for _, remote := range remotes {
+		cluster.Spec.RemoteClusters = append(cluster.Spec.RemoteClusters, v1beta1.RemoteCluster{
+			Name:             remote.Name,
+			ElasticsearchRef: remote,
+		})
+	}
+	return &cluster
+}
+
+func transportCA(namespace, name, ca string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: k8s.ToObjectMeta(transport.PublicCertsSecretRef(types.NamespacedName{Namespace: namespace, Name: name})),
+		Data:       map[string][]byte{certificates.CAFileName: []byte(ca)},
+	}
+}
+
+func Test_trustedClusters(t *testing.T) {
+	a := es("ns1", "a", commonv1beta1.ObjectSelector{Name: "b"}, commonv1beta1.ObjectSelector{Name: "c", Namespace: "ns2"})
+	b := es("ns1", "b")
+	c := es("ns2", "c", commonv1beta1.ObjectSelector{Name: "a", Namespace: "ns1"})
+	d := es("ns2", "d", commonv1beta1.ObjectSelector{Name: "a", Namespace: "ns1"})
+	e := es("ns2", "e")
+	clusters := []v1beta1.Elasticsearch{*a, *b, *c, *d, *e}
+
+	require.Equal(t, []types.NamespacedName{{Namespace: "ns1", Name: "b"}, {Namespace: "ns2", Name: "c"}},
+		trustedClusters(*a, clusters))
+	// b does not reference a: it does not trust it
+	require.Empty(t, trustedClusters(*b, clusters))
+	// c and a reference each other
+	require.Equal(t, []types.NamespacedName{{Namespace: "ns1", Name: "a"}}, trustedClusters(*c, clusters))
+	require.Equal(t, []types.NamespacedName{{Namespace: "ns1", Name: "a"}}, trustedClusters(*d, clusters))
+	require.Empty(t, trustedClusters(*e, clusters))
+}
+
+func TestReconcileRemoteClusters(t *testing.T) {
+	require.NoError(t, v1beta1.AddToScheme(scheme.Scheme))
+	a := es("ns1", "a", commonv1beta1.ObjectSelector{Name: "b", Namespace: "ns2"})
+	b := es("ns2", "b")
+	c := k8s.WrapClient(fake.NewFakeClient(a, b, transportCA("ns1", "a", "ca-a"), transportCA("ns2", "b", "ca-b")))
+	r := &ReconcileRemoteClusters{Client: c, scheme: scheme.Scheme}
+
+	remoteCAs := func(es runtime.Object) map[string]string {
+		secrets, err := transport.RemoteCASecrets(c, k8s.ExtractNamespacedName(es.(metav1.Object)))
+		require.NoError(t, err)
+		cas := map[string]string{}
+		for _, s := range secrets {
+			cas[s.Labels[transport.RemoteClusterNamespaceLabelName]+"/"+s.Labels[transport.RemoteClusterNameLabelName]] =
+				string(s.Data[certificates.CAFileName])
+		}
+		return cas
+	}
+	reconcileAll := func() {
+		for _, cluster := range []*v1beta1.Elasticsearch{a, b} {
+			_, err := r.Reconcile(reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(cluster)})
+			require.NoError(t, err)
+		}
+	}
+
+	// a references b: a trusts b, but b does not get the CA of a
+	reconcileAll()
+	require.Equal(t, map[string]string{"ns2/b": "ca-b"}, remoteCAs(a))
+	require.Empty(t, remoteCAs(b))
+
+	// b references a too: both clusters trust each other
+	b.Spec.RemoteClusters = []v1beta1.RemoteCluster{{Name: "a", ElasticsearchRef: commonv1beta1.ObjectSelector{Name: "a", Namespace: "ns1"}}}
+	require.NoError(t, c.Update(b))
+	reconcileAll()
+	require.Equal(t, map[string]string{"ns2/b": "ca-b"}, remoteCAs(a))
+	require.Equal(t, map[string]string{"ns1/a": "ca-a"}, remoteCAs(b))
+
+	// changes to the CA of b are reported to the cluster referencing it
+	require.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "ns2", Name: "b"}},
+		{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "a"}},
+	}, r.relatedRequests(k8s.ExtractNamespacedName(b)))
+
+	// a removes its remote cluster: a does not trust b anymore, b still trusts a
+	a.Spec.RemoteClusters = nil
+	require.NoError(t, c.Update(a))
+	requests := r.relatedRequests(k8s.ExtractNamespacedName(a))
+	requests = append(requests, r.relatedRequests(k8s.ExtractNamespacedName(b))...)
+	for _, request := range requests {
+		_, err := r.Reconcile(request)
+		require.NoError(t, err)
+	}
+	require.Empty(t, remoteCAs(a))
+	require.Equal(t, map[string]string{"ns1/a": "ca-a"}, remoteCAs(b))
+}
+
+func TestReconcileRemoteClusters_ResourceSelector(t *testing.T) {
+	require.NoError(t, v1beta1.AddToScheme(scheme.Scheme))
+	a := es("ns1", "a", commonv1beta1.ObjectSelector{Name: "b", Namespace: "ns2"})
+	b := es("ns2", "b")
+	c := k8s.WrapClient(fake.NewFakeClient(a, b, transportCA("ns2", "b", "ca-b")))
+	r := &ReconcileRemoteClusters{Client: c, scheme: scheme.Scheme, resourceSelector: labels.SelectorFromSet(map[string]string{"shard": "a"})}
+
+	// a is reconciled by another operator
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(a)})
+	require.NoError(t, err)
+	secrets, err := transport.RemoteCASecrets(c, k8s.ExtractNamespacedName(a))
+	require.NoError(t, err)
+	require.Empty(t, secrets)
+}
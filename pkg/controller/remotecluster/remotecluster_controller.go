@@ -0,0 +1,159 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package remotecluster
+
+import (
+	"github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates/transport"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const name = "remotecluster-controller"
+
+var log = logf.Log.WithName(name)
+
+// Add creates a new RemoteCluster Controller and adds it to the Manager with default RBAC. The Manager will set fields
+// on the Controller and Start it when the Manager is Started.
+// The controller copies the transport CAs of the remote clusters of Elasticsearch clusters, possibly across namespaces,
+// so that their nodes trust each other when both clusters reference each other.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	c, err := controller.New(name, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	return addWatches(c, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileRemoteClusters {
+	return &ReconcileRemoteClusters{
+		Client:           k8s.WrapClient(mgr.GetClient()),
+		scheme:           mgr.GetScheme(),
+		resourceSelector: params.ResourceSelector,
+	}
+}
+
+func addWatches(c controller.Controller, r *ReconcileRemoteClusters) error {
+	// Watch for changes to Elasticsearch clusters and their remote clusters
+	if err := c.Watch(&source.Kind{Type: &v1beta1.Elasticsearch{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(object handler.MapObject) []reconcile.Request {
+			return r.relatedRequests(k8s.ExtractNamespacedName(object.Meta))
+		}),
+	}, operator.ResourceSelectorPredicate(r.resourceSelector)); err != nil {
+		return err
+	}
+
+	// Watch for changes to the transport CAs, and to the copies of these CAs
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(object handler.MapObject) []reconcile.Request {
+			labels := object.Meta.GetLabels()
+			clusterName, ok := labels[label.ClusterNameLabelName]
+			if !ok {
+				return nil
+			}
+			es := types.NamespacedName{Namespace: object.Meta.GetNamespace(), Name: clusterName}
+			switch {
+			case labels[common.TypeLabelName] == transport.RemoteCAType:
+				return []reconcile.Request{{NamespacedName: es}}
+			case object.Meta.GetName() == transport.PublicCertsSecretRef(es).Name:
+				return r.relatedRequests(es)
+			default:
+				return nil
+			}
+		}),
+	})
+}
+
+var _ reconcile.Reconciler = &ReconcileRemoteClusters{}
+
+// ReconcileRemoteClusters reconciles the transport CAs of the remote clusters of Elasticsearch clusters.
+type ReconcileRemoteClusters struct {
+	k8s.Client
+	scheme *runtime.Scheme
+	// resourceSelector restricts the reconciled clusters to the ones matching it, unless nil
+	resourceSelector labels.Selector
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile copies the transport CAs of the remote clusters of the given Elasticsearch cluster in the namespace of
+// the cluster.
+func (r *ReconcileRemoteClusters) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	defer common.LogReconciliationRun(log, request, &r.iteration)()
+	defer reconciler.ObserveDuration(v1beta1.Kind, "remote-clusters")()
+
+	var es v1beta1.Elasticsearch
+	if err := r.Get(request.NamespacedName, &es); err != nil {
+		if errors.IsNotFound(err) {
+			// remote CA secrets are garbage collected with the cluster
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if !es.DeletionTimestamp.IsZero() {
+		// cluster is being deleted, nothing to do
+		return reconcile.Result{}, nil
+	}
+	if !operator.MatchesResourceSelector(r.resourceSelector, es.Labels) {
+		// the remote CAs of the cluster are managed by another operator
+		return reconcile.Result{}, nil
+	}
+
+	var clusters v1beta1.ElasticsearchList
+	if err := r.List(&clusters); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := reconcileRemoteCAs(r.Client, r.scheme, es, trustedClusters(es, clusters.Items)); err != nil {
+		return reconcile.Result{Requeue: true}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// relatedRequests returns reconcile requests for the given cluster and for all the clusters trusting or having trusted it.
+func (r *ReconcileRemoteClusters) relatedRequests(es types.NamespacedName) []reconcile.Request {
+	related := map[types.NamespacedName]struct{}{es: {}}
+
+	var clusters v1beta1.ElasticsearchList
+	if err := r.List(&clusters); err != nil {
+		log.Error(err, "failed to list clusters in watch handler", "namespace", es.Namespace, "es_name", es.Name)
+	}
+	for _, cluster := range clusters.Items {
+		if isRemoteCluster(cluster, es) {
+			// clusters referencing the cluster, which is not listed anymore once deleted
+			related[k8s.ExtractNamespacedName(&cluster)] = struct{}{}
+		}
+	}
+
+	// clusters still holding the CA of the cluster, which may not trust it anymore
+	copies, err := remoteCACopies(r.Client, es)
+	if err != nil {
+		log.Error(err, "failed to list remote CAs in watch handler", "namespace", es.Namespace, "es_name", es.Name)
+	}
+	for _, secret := range copies {
+		related[types.NamespacedName{Namespace: secret.Namespace, Name: secret.Labels[label.ClusterNameLabelName]}] = struct{}{}
+	}
+
+	requests := make([]reconcile.Request, 0, len(related))
+	for nsn := range related {
+		requests = append(requests, reconcile.Request{NamespacedName: nsn})
+	}
+	return requests
+}
@@ -0,0 +1,75 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package k8s
+
+import (
+	"bytes"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+// PodExecutor runs commands in the containers of running Pods.
+type PodExecutor interface {
+	// Exec runs the given command in a container of the given Pod, and returns its standard and error outputs.
+	// If the command ran but exited with a non-zero code, the returned error can be inspected with ExitCode.
+	Exec(pod types.NamespacedName, container string, command []string) (stdout string, stderr string, err error)
+}
+
+type podExecutor struct {
+	cfg       *rest.Config
+	clientset kubernetes.Interface
+}
+
+// NewPodExecutor returns a PodExecutor running commands through the exec subresource of the Kubernetes API.
+func NewPodExecutor(cfg *rest.Config) (PodExecutor, error) {
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &podExecutor{cfg: cfg, clientset: clientset}, nil
+}
+
+func (e *podExecutor) Exec(pod types.NamespacedName, container string, command []string) (string, string, error) {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec").
+		VersionedParams(
+			&corev1.PodExecOptions{
+				Container: container,
+				Command:   command,
+				Stdout:    true,
+				Stderr:    true,
+			},
+			runtime.NewParameterCodec(scheme.Scheme),
+		)
+	executor, err := remotecommand.NewSPDYExecutor(e.cfg, "POST", req.URL())
+	if err != nil {
+		return "", "", err
+	}
+	var stdout, stderr bytes.Buffer
+	err = executor.Stream(remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	return stdout.String(), stderr.String(), err
+}
+
+// ExitCode returns the exit code of a command run by a PodExecutor, if the given error was caused by
+// a non-zero exit code.
+func ExitCode(err error) (int, bool) {
+	if exitErr, ok := err.(exec.ExitError); ok {
+		return exitErr.ExitStatus(), true
+	}
+	return 0, false
+}
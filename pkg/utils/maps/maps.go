@@ -4,6 +4,8 @@
 
 package maps
 
+import "fmt"
+
 // IsSubset compares two maps to determine if one of them is fully contained in the other.
 func IsSubset(toCheck, fullSet map[string]string) bool {
 	if len(toCheck) > len(fullSet) {
@@ -34,3 +36,24 @@ func Merge(dest, src map[string]string) map[string]string {
 
 	return dest
 }
+
+// Flatten returns the string representation of the values of the given nested map, with dotted keys.
+// Empty nested maps are kept, since they can be meaningful (eg. in index mappings).
+func Flatten(m map[string]interface{}) map[string]string {
+	return flatten("", m, map[string]string{})
+}
+
+func flatten(prefix string, m map[string]interface{}, out map[string]string) map[string]string {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, isMap := v.(map[string]interface{}); isMap && len(nested) > 0 {
+			flatten(key, nested, out)
+			continue
+		}
+		out[key] = fmt.Sprint(v)
+	}
+	return out
+}
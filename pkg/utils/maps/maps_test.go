@@ -156,3 +156,35 @@ func TestMerge(t *testing.T) {
 		})
 	}
 }
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name string
+		m    map[string]interface{}
+		want map[string]string
+	}{
+		{
+			name: "when the map is nil",
+			want: map[string]string{},
+		},
+		{
+			name: "when values are nested",
+			m: map[string]interface{}{
+				"a": map[string]interface{}{"b": map[string]interface{}{"c": 1}, "d": true},
+				"e": "f",
+			},
+			want: map[string]string{"a.b.c": "1", "a.d": "true", "e": "f"},
+		},
+		{
+			name: "when a nested map is empty",
+			m:    map[string]interface{}{"a": map[string]interface{}{}},
+			want: map[string]string{"a": "map[]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Flatten(tt.m))
+		})
+	}
+}
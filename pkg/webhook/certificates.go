@@ -0,0 +1,187 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package webhook
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// serverCertificates are the PEM encoded certificates of the webhook server.
+type serverCertificates struct {
+	// CA is the certificate of the CA the API server trusts to call the webhooks.
+	CA []byte
+	// Cert is the certificate served by the webhook server, issued by the CA.
+	Cert []byte
+	// Key is the private key of Cert.
+	Key []byte
+	// NotAfter is the expiration date of Cert.
+	NotAfter time.Time
+}
+
+// reconcileCertificates ensures the secret of the webhook server holds a CA and a server certificate valid for the
+// webhook service, and returns them. New certificates are issued if they are missing, invalid, about to expire,
+// or if their private key does not match the key params.
+func reconcileCertificates(c k8s.Client, scheme *runtime.Scheme, params Parameters) (*serverCertificates, error) {
+	secretName := types.NamespacedName{Namespace: params.Namespace, Name: params.SecretName}
+	var secret corev1.Secret
+	if err := c.Get(secretName, &secret); err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if certs, ok := reusableCertificates(secret, params); ok {
+		return certs, nil
+	}
+
+	log.Info("Issuing new webhook server certificates", "namespace", secretName.Namespace, "secret_name", secretName.Name)
+	certs, err := newCertificates(params)
+	if err != nil {
+		return nil, err
+	}
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: secretName.Namespace, Name: secretName.Name},
+		Data: map[string][]byte{
+			certificates.CAFileName:   certs.CA,
+			certificates.CertFileName: certs.Cert,
+			certificates.KeyFileName:  certs.Key,
+		},
+	}
+	reconciled := corev1.Secret{}
+	if err := reconciler.ReconcileResource(reconciler.Params{
+		Client:           c,
+		Scheme:           scheme,
+		Expected:         &expected,
+		Reconciled:       &reconciled,
+		NeedsUpdate:      func() bool { return true },
+		UpdateReconciled: func() { reconciled.Data = expected.Data },
+	}); err != nil {
+		if !apierrors.IsAlreadyExists(err) && !apierrors.IsConflict(err) {
+			return nil, err
+		}
+		// another operator replica issued certificates concurrently, use them instead
+		if err := c.Get(secretName, &secret); err != nil {
+			return nil, err
+		}
+		if concurrentCerts, ok := reusableCertificates(secret, params); ok {
+			return concurrentCerts, nil
+		}
+		return nil, err
+	}
+	return certs, nil
+}
+
+// reusableCertificates returns the certificates stored in the given secret if they can still be served.
+func reusableCertificates(secret corev1.Secret, params Parameters) (*serverCertificates, bool) {
+	certs := serverCertificates{
+		CA:   secret.Data[certificates.CAFileName],
+		Cert: secret.Data[certificates.CertFileName],
+		Key:  secret.Data[certificates.KeyFileName],
+	}
+	caCerts, err := certificates.ParsePEMCerts(certs.CA)
+	if err != nil || len(caCerts) == 0 {
+		return nil, false
+	}
+	serverCerts, err := certificates.ParsePEMCerts(certs.Cert)
+	if err != nil || len(serverCerts) == 0 {
+		return nil, false
+	}
+	privateKey, err := certificates.ParsePEMPrivateKey(certs.Key)
+	if err != nil || !params.KeyParams.Matches(privateKey) ||
+		!certificates.PrivateMatchesPublicKey(serverCerts[0].PublicKey, privateKey) {
+		return nil, false
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCerts[0])
+	if _, err := serverCerts[0].Verify(x509.VerifyOptions{
+		DNSName: serviceDNSNames(params)[0],
+		Roots:   pool,
+		// the certificate must not expire before the next rotation
+		CurrentTime: time.Now().Add(params.CertRotation.RotateBefore),
+	}); err != nil {
+		log.Info("Webhook server certificate cannot be reused", "reason", err.Error())
+		return nil, false
+	}
+	certs.NotAfter = serverCerts[0].NotAfter
+	return &certs, true
+}
+
+// newCertificates issues a server certificate for the webhook service, from a new self-signed CA.
+func newCertificates(params Parameters) (*serverCertificates, error) {
+	ca, err := certificates.NewSelfSignedCA(certificates.CABuilderOptions{
+		Subject:   pkix.Name{CommonName: ServiceName + "-ca", OrganizationalUnit: []string{ServiceName}},
+		ExpireIn:  &params.CertRotation.Validity,
+		KeyParams: &params.KeyParams,
+	})
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := params.KeyParams.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	dnsNames := serviceDNSNames(params)
+	notAfter := time.Now().Add(params.CertRotation.Validity)
+	certData, err := ca.CreateCertificate(certificates.ValidatedCertificateTemplate(x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:         dnsNames[0],
+			OrganizationalUnit: []string{ServiceName},
+		},
+		DNSNames:    dnsNames,
+		NotBefore:   time.Now().Add(-10 * time.Minute),
+		NotAfter:    notAfter,
+		PublicKey:   privateKey.Public(),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}))
+	if err != nil {
+		return nil, err
+	}
+	key, err := certificates.EncodePEMPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &serverCertificates{
+		CA:       certificates.EncodePEMCert(ca.Cert.Raw),
+		Cert:     certificates.EncodePEMCert(certData),
+		Key:      key,
+		NotAfter: notAfter,
+	}, nil
+}
+
+// writeCertificates writes the server certificate and private key in the directory the webhook server reads them from.
+// Files are only written if their content changes, since the webhook server reloads them on each write.
+func writeCertificates(dir string, certs serverCertificates) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for fileName, content := range map[string][]byte{
+		certificates.CertFileName: certs.Cert,
+		certificates.KeyFileName:  certs.Key,
+	} {
+		path := filepath.Join(dir, fileName)
+		if existing, err := ioutil.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+			continue
+		}
+		if err := ioutil.WriteFile(path, content, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package webhook
+
+import (
+	"reflect"
+
+	apmv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1beta1"
+	esv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	kbv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ConversionPath is the path of the conversion webhook in the webhook server.
+const ConversionPath = "/convert"
+
+// convertedResources are the resources whose versions are converted by the conversion webhook.
+var convertedResources = []schema.GroupResource{
+	esv1beta1.GroupVersion.WithResource("elasticsearches").GroupResource(),
+	kbv1beta1.GroupVersion.WithResource("kibanas").GroupResource(),
+	apmv1beta1.GroupVersion.WithResource("apmservers").GroupResource(),
+}
+
+// newConversion returns the conversion of the custom resource definitions, delegated to the conversion webhook.
+func newConversion(params Parameters, caBundle []byte) *apiextensionsv1beta1.CustomResourceConversion {
+	path := ConversionPath
+	return &apiextensionsv1beta1.CustomResourceConversion{
+		Strategy: apiextensionsv1beta1.WebhookConverter,
+		WebhookClientConfig: &apiextensionsv1beta1.WebhookClientConfig{
+			Service: &apiextensionsv1beta1.ServiceReference{
+				Namespace: params.Namespace,
+				Name:      ServiceName,
+				Path:      &path,
+			},
+			CABundle: caBundle,
+		},
+		ConversionReviewVersions: []string{"v1beta1"},
+	}
+}
+
+// reconcileConversion configures the custom resource definitions of the converted resources to call the conversion
+// webhook. Custom resource definitions are not created by the operator: missing ones are skipped.
+// They are handled as unstructured objects, so that their fields unknown to this client version are not dropped.
+func reconcileConversion(c k8s.Client, expected *apiextensionsv1beta1.CustomResourceConversion) error {
+	expectedConversion, err := runtime.DefaultUnstructuredConverter.ToUnstructured(expected)
+	if err != nil {
+		return err
+	}
+	for _, resource := range convertedResources {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(apiextensionsv1beta1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+		if err := c.Get(types.NamespacedName{Name: resource.String()}, crd); err != nil {
+			if apierrors.IsNotFound(err) {
+				log.Info("Custom resource definition not found, skipping conversion webhook configuration", "name", resource.String())
+				continue
+			}
+			return err
+		}
+		conversion, _, err := unstructured.NestedMap(crd.Object, "spec", "conversion")
+		if err != nil {
+			return err
+		}
+		preserveUnknownFields, found, err := unstructured.NestedBool(crd.Object, "spec", "preserveUnknownFields")
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(conversion, expectedConversion) && found && !preserveUnknownFields {
+			continue
+		}
+		log.Info("Configuring conversion webhook", "name", crd.GetName())
+		if err := unstructured.SetNestedMap(crd.Object, expectedConversion, "spec", "conversion"); err != nil {
+			return err
+		}
+		// the API server rejects the webhook conversion of custom resources preserving unknown fields
+		if err := unstructured.SetNestedField(crd.Object, false, "spec", "preserveUnknownFields"); err != nil {
+			return err
+		}
+		if err := c.Update(crd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
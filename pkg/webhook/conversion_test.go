@@ -0,0 +1,81 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package webhook
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// loadCRD returns the custom resource definition of the given resource, as installed from the manifests.
+func loadCRD(t *testing.T, resource string) *unstructured.Unstructured {
+	data, err := ioutil.ReadFile(filepath.Join("..", "..", "config", "crds", resource+".yaml"))
+	require.NoError(t, err)
+	crd := &unstructured.Unstructured{}
+	require.NoError(t, yaml.Unmarshal(data, &crd.Object))
+	return crd
+}
+
+// validateCRD returns the errors the API server reports when validating the conversion of the given custom resource
+// definition.
+func validateCRD(t *testing.T, crd *unstructured.Unstructured) []string {
+	var typed apiextensionsv1beta1.CustomResourceDefinition
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(crd.Object, &typed))
+	conversion := typed.Spec.Conversion
+	if conversion == nil || conversion.Strategy != apiextensionsv1beta1.WebhookConverter {
+		return nil
+	}
+	var errs []string
+	if conversion.WebhookClientConfig == nil || (conversion.WebhookClientConfig.Service == nil) == (conversion.WebhookClientConfig.URL == nil) {
+		errs = append(errs, "spec.conversion.webhookClientConfig: exactly one of url or service is required")
+	}
+	if !stringsutil.StringInSlice("v1beta1", conversion.ConversionReviewVersions) {
+		errs = append(errs, "spec.conversion.conversionReviewVersions: must include at least one of v1beta1")
+	}
+	preserveUnknownFields, found, err := unstructured.NestedBool(crd.Object, "spec", "preserveUnknownFields")
+	require.NoError(t, err)
+	// preserveUnknownFields defaults to true in v1beta1
+	if !found || preserveUnknownFields {
+		errs = append(errs, "spec.conversion.strategy: Invalid value: \"Webhook\": must be None if spec.preserveUnknownFields is true")
+	}
+	return errs
+}
+
+func Test_reconcileConversion(t *testing.T) {
+	scheme, err := installerScheme()
+	require.NoError(t, err)
+	var crds []runtime.Object
+	for _, resource := range convertedResources {
+		crds = append(crds, loadCRD(t, resource.Group+"_"+resource.Resource))
+	}
+	c := k8s.WrapClient(fake.NewFakeClientWithScheme(scheme, crds...))
+	conversion := newConversion(Parameters{Namespace: "elastic-system"}, []byte("ca"))
+
+	require.NoError(t, reconcileConversion(c, conversion))
+	for _, resource := range convertedResources {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(apiextensionsv1beta1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+		require.NoError(t, c.Get(types.NamespacedName{Name: resource.String()}, crd))
+		require.Empty(t, validateCRD(t, crd), resource.String())
+		// the fields unknown to the client version are kept
+		_, found, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		require.NoError(t, err)
+		require.True(t, found)
+	}
+
+	// the custom resource definitions are only updated when the conversion changes
+	require.NoError(t, reconcileConversion(c, conversion))
+}
@@ -0,0 +1,175 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package webhook
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// retryAfter is the delay before the next reconciliation of the webhook installation, after a failed one.
+const retryAfter = 1 * time.Minute
+
+// installer reconciles the certificates, service and configuration of the webhooks.
+type installer struct {
+	client   k8s.Client
+	scheme   *runtime.Scheme
+	params   Parameters
+	webhooks []validatingWebhook
+}
+
+var (
+	_ manager.Runnable               = &installer{}
+	_ manager.LeaderElectionRunnable = &installer{}
+)
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: the webhook server runs on every operator replica,
+// each of them must write the current certificates in its certificates directory.
+func (i *installer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start reconciles the webhook installation before the server certificate needs to be rotated, until the stop
+// channel is closed.
+func (i *installer) Start(stop <-chan struct{}) error {
+	for {
+		requeueIn, err := i.reconcile()
+		if err != nil {
+			log.Error(err, "Failed to reconcile the webhook installation")
+			requeueIn = retryAfter
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(requeueIn):
+		}
+	}
+}
+
+// reconcileCertificates reconciles the certificates of the webhook server and writes them in its certificates directory.
+func (i *installer) reconcileCertificates() (*serverCertificates, error) {
+	certs, err := reconcileCertificates(i.client, i.scheme, i.params)
+	if err != nil {
+		return nil, err
+	}
+	return certs, writeCertificates(i.params.CertDir, *certs)
+}
+
+// reconcile installs the webhooks, and returns the duration after which the server certificate should be rotated.
+func (i *installer) reconcile() (time.Duration, error) {
+	certs, err := i.reconcileCertificates()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := common.ReconcileService(i.client, i.scheme, newService(i.params), nil); err != nil {
+		return 0, err
+	}
+	if err := reconcileConfiguration(i.client, i.scheme, newConfiguration(i.params, i.webhooks, certs.CA)); err != nil {
+		return 0, err
+	}
+	if err := reconcileConversion(i.client, newConversion(i.params, certs.CA)); err != nil {
+		return 0, err
+	}
+	return certificates.ShouldRotateIn(time.Now(), certs.NotAfter, i.params.CertRotation.RotateBefore), nil
+}
+
+// newService returns the service exposing the webhook server of the operator pods.
+func newService(params Parameters) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: params.Namespace,
+			Name:      ServiceName,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"control-plane": params.ServiceSelector},
+			Ports: []corev1.ServicePort{
+				{
+					// the API server only calls webhook services on port 443
+					Port:       443,
+					TargetPort: intstr.FromInt(WebhookPort),
+				},
+			},
+		},
+	}
+}
+
+// serviceDNSNames returns the names the API server uses to reach the webhook service.
+func serviceDNSNames(params Parameters) []string {
+	return k8s.GetServiceDNSName(*newService(params))
+}
+
+// newConfiguration returns the configuration registering the given webhooks to the API server.
+func newConfiguration(params Parameters, webhooks []validatingWebhook, caBundle []byte) *admissionv1beta1.ValidatingWebhookConfiguration {
+	// resources are still validated by the operator if the webhook server is unreachable
+	failurePolicy := admissionv1beta1.Ignore
+	sideEffects := admissionv1beta1.SideEffectClassNone
+	// fields defaulted by the API server are set explicitly, to only update the configuration when it changes
+	scope := admissionv1beta1.AllScopes
+	timeoutSeconds := int32(30)
+	expected := admissionv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ConfigurationName,
+		},
+	}
+	for _, w := range webhooks {
+		path := w.path
+		expected.Webhooks = append(expected.Webhooks, admissionv1beta1.Webhook{
+			Name: w.name,
+			ClientConfig: admissionv1beta1.WebhookClientConfig{
+				Service: &admissionv1beta1.ServiceReference{
+					Namespace: params.Namespace,
+					Name:      ServiceName,
+					Path:      &path,
+				},
+				CABundle: caBundle,
+			},
+			Rules: []admissionv1beta1.RuleWithOperations{
+				{
+					Operations: []admissionv1beta1.OperationType{admissionv1beta1.Create, admissionv1beta1.Update},
+					Rule: admissionv1beta1.Rule{
+						APIGroups:   []string{w.resource.Group},
+						APIVersions: w.apiVersions(),
+						Resources:   []string{w.resource.Resource},
+						Scope:       &scope,
+					},
+				},
+			},
+			FailurePolicy:           &failurePolicy,
+			NamespaceSelector:       &metav1.LabelSelector{},
+			SideEffects:             &sideEffects,
+			TimeoutSeconds:          &timeoutSeconds,
+			AdmissionReviewVersions: []string{"v1beta1"},
+		})
+	}
+	return &expected
+}
+
+// reconcileConfiguration creates or updates the given webhook configuration.
+func reconcileConfiguration(c k8s.Client, scheme *runtime.Scheme, expected *admissionv1beta1.ValidatingWebhookConfiguration) error {
+	reconciled := &admissionv1beta1.ValidatingWebhookConfiguration{}
+	return reconciler.ReconcileResource(reconciler.Params{
+		Client:     c,
+		Scheme:     scheme,
+		Expected:   expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return !reflect.DeepEqual(expected.Webhooks, reconciled.Webhooks)
+		},
+		UpdateReconciled: func() {
+			reconciled.Webhooks = expected.Webhooks
+		},
+	})
+}
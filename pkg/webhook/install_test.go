@@ -0,0 +1,234 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package webhook
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testInstaller(t *testing.T, c k8s.Client) *installer {
+	certDir, err := ioutil.TempDir("", "webhook-certs")
+	require.NoError(t, err)
+	scheme, err := installerScheme()
+	require.NoError(t, err)
+	return &installer{
+		client: c,
+		scheme: scheme,
+		params: Parameters{
+			Namespace:       "elastic-system",
+			SecretName:      "webhook-server-secret",
+			ServiceSelector: "elastic-operator",
+			CertDir:         certDir,
+			AutoInstall:     true,
+			CertRotation: certificates.RotationParams{
+				Validity:     certificates.DefaultCertValidity,
+				RotateBefore: certificates.DefaultRotateBefore,
+			},
+			KeyParams: certificates.DefaultKeyParams,
+		},
+		webhooks: validatingWebhooks(func(string) (bool, error) { return true, nil }),
+	}
+}
+
+func Test_installer_reconcile(t *testing.T) {
+	scheme, err := installerScheme()
+	require.NoError(t, err)
+	esCRD := &apiextensionsv1beta1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearches.elasticsearch.k8s.elastic.co"},
+	}
+	c := k8s.WrapClient(fake.NewFakeClientWithScheme(scheme, esCRD))
+	i := testInstaller(t, c)
+	defer os.RemoveAll(i.params.CertDir)
+
+	requeueIn, err := i.reconcile()
+	require.NoError(t, err)
+	require.InDelta(t, float64(certificates.DefaultCertValidity-certificates.DefaultRotateBefore), float64(requeueIn), float64(time.Minute))
+
+	// the certificates are stored in the secret and written in the certificates directory
+	var secret corev1.Secret
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: "elastic-system", Name: "webhook-server-secret"}, &secret))
+	for _, fileName := range []string{certificates.CertFileName, certificates.KeyFileName} {
+		data, err := ioutil.ReadFile(filepath.Join(i.params.CertDir, fileName))
+		require.NoError(t, err)
+		require.Equal(t, secret.Data[fileName], data)
+	}
+
+	// the served certificate is trusted by the CA bundle of the webhooks, for the name of the service
+	var config admissionv1beta1.ValidatingWebhookConfiguration
+	require.NoError(t, c.Get(types.NamespacedName{Name: ConfigurationName}, &config))
+	require.Len(t, config.Webhooks, 3)
+	// resources are validated whatever the version they are submitted in
+	require.Equal(t, []string{"v1alpha1", "v1beta1"}, config.Webhooks[0].Rules[0].APIVersions)
+	caCerts, err := certificates.ParsePEMCerts(config.Webhooks[0].ClientConfig.CABundle)
+	require.NoError(t, err)
+	certs, err := certificates.ParsePEMCerts(secret.Data[certificates.CertFileName])
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(caCerts[0])
+	_, err = certs[0].Verify(x509.VerifyOptions{DNSName: "elastic-webhook-server.elastic-system.svc", Roots: pool})
+	require.NoError(t, err)
+
+	var svc corev1.Service
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: "elastic-system", Name: ServiceName}, &svc))
+	require.Equal(t, map[string]string{"control-plane": "elastic-operator"}, svc.Spec.Selector)
+	require.Equal(t, int32(443), svc.Spec.Ports[0].Port)
+
+	// the existing custom resource definitions are converted by the webhook, missing ones are skipped
+	var crd apiextensionsv1beta1.CustomResourceDefinition
+	require.NoError(t, c.Get(types.NamespacedName{Name: esCRD.Name}, &crd))
+	require.Equal(t, apiextensionsv1beta1.WebhookConverter, crd.Spec.Conversion.Strategy)
+	require.Equal(t, ConversionPath, *crd.Spec.Conversion.WebhookClientConfig.Service.Path)
+	require.Equal(t, config.Webhooks[0].ClientConfig.CABundle, crd.Spec.Conversion.WebhookClientConfig.CABundle)
+
+	// valid certificates are reused
+	_, err = i.reconcile()
+	require.NoError(t, err)
+	var reconciledSecret corev1.Secret
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: "elastic-system", Name: "webhook-server-secret"}, &reconciledSecret))
+	require.Equal(t, secret.Data, reconciledSecret.Data)
+
+	// certificates are issued again if the key params change
+	i.params.KeyParams = certificates.KeyParams{Algorithm: certificates.ECDSAKeyAlgorithm, Size: 256}
+	_, err = i.reconcile()
+	require.NoError(t, err)
+	require.NoError(t, c.Get(types.NamespacedName{Namespace: "elastic-system", Name: "webhook-server-secret"}, &reconciledSecret))
+	require.NotEqual(t, secret.Data[certificates.CAFileName], reconciledSecret.Data[certificates.CAFileName])
+	require.NoError(t, c.Get(types.NamespacedName{Name: ConfigurationName}, &config))
+	require.Equal(t, reconciledSecret.Data[certificates.CAFileName], config.Webhooks[0].ClientConfig.CABundle)
+	require.NoError(t, c.Get(types.NamespacedName{Name: esCRD.Name}, &crd))
+	require.Equal(t, reconciledSecret.Data[certificates.CAFileName], crd.Spec.Conversion.WebhookClientConfig.CABundle)
+	key, err := ioutil.ReadFile(filepath.Join(i.params.CertDir, certificates.KeyFileName))
+	require.NoError(t, err)
+	require.Equal(t, reconciledSecret.Data[certificates.KeyFileName], key)
+}
+
+func Test_reusableCertificates(t *testing.T) {
+	i := testInstaller(t, nil)
+	defer os.RemoveAll(i.params.CertDir)
+	certs, err := newCertificates(i.params)
+	require.NoError(t, err)
+	secret := func(certs serverCertificates) corev1.Secret {
+		return corev1.Secret{Data: map[string][]byte{
+			certificates.CAFileName:   certs.CA,
+			certificates.CertFileName: certs.Cert,
+			certificates.KeyFileName:  certs.Key,
+		}}
+	}
+	otherCerts, err := newCertificates(i.params)
+	require.NoError(t, err)
+	otherNamespace := i.params
+	otherNamespace.Namespace = "other-ns"
+	shortLived := i.params
+	shortLived.CertRotation.Validity = time.Hour
+	shortLivedCerts, err := newCertificates(shortLived)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		secret corev1.Secret
+		params Parameters
+		want   bool
+	}{
+		{
+			name:   "valid certificates",
+			secret: secret(*certs),
+			params: i.params,
+			want:   true,
+		},
+		{
+			name:   "empty secret",
+			secret: corev1.Secret{},
+			params: i.params,
+			want:   false,
+		},
+		{
+			name:   "certificate issued by another CA",
+			secret: secret(serverCertificates{CA: otherCerts.CA, Cert: certs.Cert, Key: certs.Key}),
+			params: i.params,
+			want:   false,
+		},
+		{
+			name:   "key not matching the certificate",
+			secret: secret(serverCertificates{CA: certs.CA, Cert: certs.Cert, Key: otherCerts.Key}),
+			params: i.params,
+			want:   false,
+		},
+		{
+			name:   "certificate for another service",
+			secret: secret(*certs),
+			params: otherNamespace,
+			want:   false,
+		},
+		{
+			name:   "certificate to rotate",
+			secret: secret(*shortLivedCerts),
+			params: shortLived,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := reusableCertificates(tt.secret, tt.params)
+			require.Equal(t, tt.want, ok)
+			if tt.want {
+				require.Equal(t, certs.Cert, got.Cert)
+			}
+		})
+	}
+}
+
+// concurrentReplicaClient simulates another operator replica storing its certificates right before the secret
+// is created.
+type concurrentReplicaClient struct {
+	k8s.Client
+	concurrent *corev1.Secret
+}
+
+func (c concurrentReplicaClient) Create(obj runtime.Object, opts ...client.CreateOption) error {
+	if err := c.Client.Create(c.concurrent); err != nil {
+		return err
+	}
+	return c.Client.Create(obj, opts...)
+}
+
+func Test_reconcileCertificates_concurrentReplicas(t *testing.T) {
+	i := testInstaller(t, nil)
+	defer os.RemoveAll(i.params.CertDir)
+	concurrentCerts, err := newCertificates(i.params)
+	require.NoError(t, err)
+	concurrent := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: i.params.Namespace, Name: i.params.SecretName},
+		Data: map[string][]byte{
+			certificates.CAFileName:   concurrentCerts.CA,
+			certificates.CertFileName: concurrentCerts.Cert,
+			certificates.KeyFileName:  concurrentCerts.Key,
+		},
+	}
+	c := concurrentReplicaClient{Client: k8s.WrapClient(fake.NewFakeClientWithScheme(i.scheme)), concurrent: concurrent}
+
+	// the certificates of the other replica are used
+	certs, err := reconcileCertificates(c, i.scheme, i.params)
+	require.NoError(t, err)
+	require.Equal(t, concurrentCerts.Cert, certs.Cert)
+	require.Equal(t, concurrentCerts.CA, certs.CA)
+}
@@ -0,0 +1,226 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	apmv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1alpha1"
+	apmv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1beta1"
+	esv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1alpha1"
+	esv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
+	kbv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1alpha1"
+	kbv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
+	apmvalidation "github.com/elastic/cloud-on-k8s/pkg/controller/apmserver/validation"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/validation"
+	esvalidation "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/validation"
+	kbvalidation "github.com/elastic/cloud-on-k8s/pkg/controller/kibana/validation"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// validatingWebhook validates a kind of resource at admission time.
+type validatingWebhook struct {
+	// name of the webhook in the ValidatingWebhookConfiguration
+	name string
+	// path of the webhook in the webhook server
+	path string
+	// resource validated by the webhook, in the version the handler validates
+	resource schema.GroupVersionResource
+	handler  *validatingHandler
+}
+
+// validatingWebhooks returns the webhooks validating the Elasticsearch, Kibana and APM Server resources.
+// Resources outside of the namespaces managed by the operator are not validated.
+func validatingWebhooks(isManaged func(namespace string) (bool, error)) []validatingWebhook {
+	return []validatingWebhook{
+		{
+			name:     "elastic-es-validation-v1beta1.k8s.elastic.co",
+			path:     "/validate-elasticsearch-k8s-elastic-co-v1beta1-elasticsearch",
+			resource: esv1beta1.GroupVersion.WithResource("elasticsearches"),
+			handler: &validatingHandler{
+				isManaged: isManaged,
+				newObject: func() runtime.Object { return &esv1beta1.Elasticsearch{} },
+				convertibles: map[string]func() conversion.Convertible{
+					esv1alpha1.GroupVersion.Version: func() conversion.Convertible { return &esv1alpha1.Elasticsearch{} },
+				},
+				validate: validateElasticsearch,
+			},
+		},
+		{
+			name:     "elastic-kb-validation-v1beta1.k8s.elastic.co",
+			path:     "/validate-kibana-k8s-elastic-co-v1beta1-kibana",
+			resource: kbv1beta1.GroupVersion.WithResource("kibanas"),
+			handler: &validatingHandler{
+				isManaged: isManaged,
+				newObject: func() runtime.Object { return &kbv1beta1.Kibana{} },
+				convertibles: map[string]func() conversion.Convertible{
+					kbv1alpha1.GroupVersion.Version: func() conversion.Convertible { return &kbv1alpha1.Kibana{} },
+				},
+				validate: validateKibana,
+			},
+		},
+		{
+			name:     "elastic-apm-validation-v1beta1.k8s.elastic.co",
+			path:     "/validate-apm-k8s-elastic-co-v1beta1-apmserver",
+			resource: apmv1beta1.GroupVersion.WithResource("apmservers"),
+			handler: &validatingHandler{
+				isManaged: isManaged,
+				newObject: func() runtime.Object { return &apmv1beta1.ApmServer{} },
+				convertibles: map[string]func() conversion.Convertible{
+					apmv1alpha1.GroupVersion.Version: func() conversion.Convertible { return &apmv1alpha1.ApmServer{} },
+				},
+				validate: validateApmServer,
+			},
+		},
+	}
+}
+
+func validateElasticsearch(current runtime.Object, proposed runtime.Object) ([]validation.Result, error) {
+	var currentES *esv1beta1.Elasticsearch
+	if current != nil {
+		currentES = current.(*esv1beta1.Elasticsearch)
+	}
+	return esvalidation.ValidateUpdate(currentES, *proposed.(*esv1beta1.Elasticsearch))
+}
+
+func validateKibana(current runtime.Object, proposed runtime.Object) ([]validation.Result, error) {
+	var currentKb *kbv1beta1.Kibana
+	if current != nil {
+		currentKb = current.(*kbv1beta1.Kibana)
+	}
+	return kbvalidation.Validate(currentKb, *proposed.(*kbv1beta1.Kibana)), nil
+}
+
+func validateApmServer(current runtime.Object, proposed runtime.Object) ([]validation.Result, error) {
+	var currentAs *apmv1beta1.ApmServer
+	if current != nil {
+		currentAs = current.(*apmv1beta1.ApmServer)
+	}
+	return apmvalidation.Validate(currentAs, *proposed.(*apmv1beta1.ApmServer)), nil
+}
+
+// validatingHandler runs the validations of a kind of resource on its creation and on the updates of its spec.
+type validatingHandler struct {
+	// isManaged returns true if the resources of the given namespace are managed by the operator.
+	isManaged func(namespace string) (bool, error)
+	// newObject returns an empty resource of the validated kind, in the validated version.
+	newObject func() runtime.Object
+	// convertibles return an empty resource of the validated kind for each other version of the resource,
+	// converted to the validated version before validation.
+	convertibles map[string]func() conversion.Convertible
+	// validate returns the failed validations of the proposed resource. The current resource is nil on creation.
+	validate func(current runtime.Object, proposed runtime.Object) ([]validation.Result, error)
+	decoder  *admission.Decoder
+}
+
+var _ admission.Handler = &validatingHandler{}
+var _ admission.DecoderInjector = &validatingHandler{}
+
+// InjectDecoder injects the decoder of the webhook server scheme.
+func (h *validatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle validates the resource of the admission request.
+func (h *validatingHandler) Handle(_ context.Context, req admission.Request) admission.Response {
+	managed, err := h.isManaged(req.Namespace)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !managed {
+		return admission.Allowed("resource not managed by this operator")
+	}
+
+	proposed, err := h.decode(req.Object, req.Kind.Version)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if accessor, err := meta.Accessor(proposed); err == nil && accessor.GetDeletionTimestamp() != nil {
+		// do not prevent the removal of the finalizers of resources being deleted
+		return admission.Allowed("resource being deleted")
+	}
+
+	var current runtime.Object
+	if req.Operation == admissionv1beta1.Update {
+		if specUnchanged(req) {
+			// metadata and status updates are not validated, which lets the operator update resources stored
+			// before the webhook was installed
+			return admission.Allowed("spec unchanged")
+		}
+		current, err = h.decode(req.OldObject, req.Kind.Version)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	results, err := h.validate(current, proposed)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+	if len(results) > 0 {
+		reasons := make([]string, 0, len(results))
+		for _, r := range results {
+			reasons = append(reasons, r.Reason)
+		}
+		log.Info("Rejecting resource",
+			"kind", req.Kind.Kind, "namespace", req.Namespace, "name", req.Name, "reasons", reasons)
+		return admission.Denied(strings.Join(reasons, "; "))
+	}
+	return admission.Allowed("")
+}
+
+// decode decodes the given resource of the given version, and converts it to the validated version.
+func (h *validatingHandler) decode(raw runtime.RawExtension, version string) (runtime.Object, error) {
+	obj := h.newObject()
+	newConvertible, isConvertible := h.convertibles[version]
+	if !isConvertible {
+		return obj, h.decoder.DecodeRaw(raw, obj)
+	}
+	convertible := newConvertible()
+	if err := h.decoder.DecodeRaw(raw, convertible); err != nil {
+		return nil, err
+	}
+	hub, ok := obj.(conversion.Hub)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a conversion hub", obj)
+	}
+	return obj, convertible.ConvertTo(hub)
+}
+
+// apiVersions returns the versions of the resource validated by the webhook.
+func (w validatingWebhook) apiVersions() []string {
+	versions := []string{w.resource.Version}
+	for version := range w.handler.convertibles {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// specUnchanged returns true if the update request does not modify the spec of the resource.
+func specUnchanged(req admission.Request) bool {
+	var current, proposed struct {
+		Spec interface{} `json:"spec"`
+	}
+	if err := json.Unmarshal(req.OldObject.Raw, &current); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(req.Object.Raw, &proposed); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(current.Spec, proposed.Spec)
+}
@@ -0,0 +1,151 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	kbv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1alpha1"
+	kbv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/scheme"
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func kibana(version string, count int32) *kbv1beta1.Kibana {
+	return &kbv1beta1.Kibana{
+		TypeMeta:   metav1.TypeMeta{APIVersion: kbv1beta1.GroupVersion.String(), Kind: "Kibana"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Spec:       kbv1beta1.KibanaSpec{Version: version, Count: count},
+	}
+}
+
+func kibanaV1alpha1(version string, count int32) *kbv1alpha1.Kibana {
+	return &kbv1alpha1.Kibana{
+		TypeMeta:   metav1.TypeMeta{APIVersion: kbv1alpha1.GroupVersion.String(), Kind: "Kibana"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Spec:       kbv1alpha1.KibanaSpec{Version: version, NodeCount: count},
+	}
+}
+
+func kibanaRequest(t *testing.T, op admissionv1beta1.Operation, current, proposed runtime.Object) admission.Request {
+	encode := func(obj runtime.Object) runtime.RawExtension {
+		if obj == nil {
+			return runtime.RawExtension{}
+		}
+		data, err := json.Marshal(obj)
+		require.NoError(t, err)
+		return runtime.RawExtension{Raw: data}
+	}
+	accessor, err := meta.Accessor(proposed)
+	require.NoError(t, err)
+	gvk := proposed.GetObjectKind().GroupVersionKind()
+	req := admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+		Namespace: accessor.GetNamespace(),
+		Name:      accessor.GetName(),
+		Operation: op,
+		Object:    encode(proposed),
+	}}
+	if current != nil {
+		req.OldObject = encode(current)
+	}
+	return req
+}
+
+func Test_validatingHandler_Handle(t *testing.T) {
+	require.NoError(t, scheme.SetupScheme())
+	decoder, err := admission.NewDecoder(clientgoscheme.Scheme)
+	require.NoError(t, err)
+
+	deleted := kibana("7.x", 1)
+	deleted.DeletionTimestamp = &metav1.Time{}
+
+	tests := []struct {
+		name             string
+		managedNamespace string
+		req              admission.Request
+		wantAllowed      bool
+		wantReason       string
+	}{
+		{
+			name:        "valid creation",
+			req:         kibanaRequest(t, admissionv1beta1.Create, nil, kibana("7.4.0", 1)),
+			wantAllowed: true,
+		},
+		{
+			name:        "invalid creation",
+			req:         kibanaRequest(t, admissionv1beta1.Create, nil, kibana("7.x", 1)),
+			wantAllowed: false,
+			wantReason:  "Cannot parse Kibana version: version string has too few segments for version 7.x",
+		},
+		{
+			name:             "invalid creation outside of the managed namespace",
+			managedNamespace: "other-ns",
+			req:              kibanaRequest(t, admissionv1beta1.Create, nil, kibana("7.x", 1)),
+			wantAllowed:      true,
+		},
+		{
+			name:        "valid update",
+			req:         kibanaRequest(t, admissionv1beta1.Update, kibana("7.4.0", 1), kibana("7.4.0", 3)),
+			wantAllowed: true,
+		},
+		{
+			name:        "invalid update",
+			req:         kibanaRequest(t, admissionv1beta1.Update, kibana("7.4.0", 1), kibana("7.x", 1)),
+			wantAllowed: false,
+			wantReason:  "Cannot parse Kibana version: version string has too few segments for version 7.x",
+		},
+		{
+			name:        "invalid v1alpha1 creation",
+			req:         kibanaRequest(t, admissionv1beta1.Create, nil, kibanaV1alpha1("7.x", 1)),
+			wantAllowed: false,
+			wantReason:  "Cannot parse Kibana version: version string has too few segments for version 7.x",
+		},
+		{
+			name:        "valid v1alpha1 update",
+			req:         kibanaRequest(t, admissionv1beta1.Update, kibanaV1alpha1("7.4.0", 1), kibanaV1alpha1("7.4.0", 3)),
+			wantAllowed: true,
+		},
+		{
+			name:        "update of an invalid resource without spec change",
+			req:         kibanaRequest(t, admissionv1beta1.Update, kibana("7.x", 1), kibana("7.x", 1)),
+			wantAllowed: true,
+		},
+		{
+			name:        "update of an invalid resource being deleted",
+			req:         kibanaRequest(t, admissionv1beta1.Update, kibana("7.x", 1), deleted),
+			wantAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &validatingHandler{
+				isManaged: func(namespace string) (bool, error) {
+					return tt.managedNamespace == "" || namespace == tt.managedNamespace, nil
+				},
+				newObject: func() runtime.Object { return &kbv1beta1.Kibana{} },
+				convertibles: map[string]func() conversion.Convertible{
+					kbv1alpha1.GroupVersion.Version: func() conversion.Convertible { return &kbv1alpha1.Kibana{} },
+				},
+				validate: validateKibana,
+			}
+			require.NoError(t, h.InjectDecoder(decoder))
+			resp := h.Handle(context.Background(), tt.req)
+			require.Equal(t, tt.wantAllowed, resp.Allowed)
+			if tt.wantReason != "" {
+				require.Equal(t, tt.wantReason, string(resp.Result.Reason))
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package webhook serves the admission webhooks validating the resources managed by the operator, and the
+// webhook converting them between API versions.
+package webhook
+
+import (
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/namespaces"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+const (
+	// WebhookPort is the port the webhook server listens on.
+	WebhookPort = 9876
+	// DefaultCertDir is the directory the webhook server reads its certificate and private key from.
+	DefaultCertDir = "/tmp/cert"
+	// ServiceName is the name of the service exposing the webhook server to the API server.
+	ServiceName = "elastic-webhook-server"
+	// ConfigurationName is the name of the ValidatingWebhookConfiguration registering the webhooks.
+	ConfigurationName = "elastic-webhook.k8s.elastic.co"
+)
+
+var log = logf.Log.WithName("webhook")
+
+// Parameters are the parameters of the webhook server.
+type Parameters struct {
+	// Namespace is the namespace of the operator, where the webhook service and secret are reconciled.
+	Namespace string
+	// ManagedNamespaces restricts the validation to the resources of the namespaces managed by the operator.
+	ManagedNamespaces namespaces.Config
+	// SecretName is the name of the secret holding the certificates of the webhook server.
+	SecretName string
+	// ServiceSelector is the value of the control-plane label of the operator pods serving the webhooks.
+	ServiceSelector string
+	// CertDir is the directory the webhook server reads its certificate and private key from.
+	CertDir string
+	// AutoInstall enables the reconciliation of the webhook certificates, service and configuration.
+	AutoInstall bool
+	// CertRotation defines the rotation params of the webhook server certificates.
+	CertRotation certificates.RotationParams
+	// KeyParams defines the algorithm and size of the webhook server private keys.
+	KeyParams certificates.KeyParams
+}
+
+// AddToManager registers the validating and conversion webhooks to the webhook server of the manager, if the operator
+// has the webhook role. If auto-install is enabled, the webhook certificates are reconciled before the manager starts,
+// then the certificates, service and configuration of the webhooks are reconciled periodically to rotate the certificates.
+func AddToManager(mgr manager.Manager, roles []string, newParameters func() (*Parameters, error)) error {
+	if !operator.HasRole(operator.WebhookServer, roles) {
+		return nil
+	}
+	params, err := newParameters()
+	if err != nil {
+		return err
+	}
+	if params.CertDir == "" {
+		params.CertDir = DefaultCertDir
+	}
+
+	server := mgr.GetWebhookServer()
+	server.Port = WebhookPort
+	server.CertDir = params.CertDir
+	// namespaces matching a label selector are retrieved from the cache of the manager
+	managerClient := k8s.WrapClient(mgr.GetClient())
+	webhooks := validatingWebhooks(func(namespace string) (bool, error) {
+		return params.ManagedNamespaces.IsManaged(managerClient, namespace)
+	})
+	for _, w := range webhooks {
+		server.Register(w.path, &webhook.Admission{Handler: w.handler})
+	}
+	// objects are decoded and converted with the scheme of the manager
+	server.Register(ConversionPath, &conversion.Webhook{})
+
+	if !params.AutoInstall {
+		return nil
+	}
+	// the manager client is backed by a cache restricted to the managed namespace, which cannot serve the
+	// cluster-scoped webhook configuration and custom resource definitions, nor the resources of the operator namespace
+	scheme, err := installerScheme()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(mgr.GetConfig(), client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	i := &installer{
+		client:   k8s.WrapClient(c),
+		scheme:   scheme,
+		params:   *params,
+		webhooks: webhooks,
+	}
+	// the webhook server does not start without a certificate
+	if _, err := i.reconcileCertificates(); err != nil {
+		return err
+	}
+	return mgr.Add(i)
+}
+
+// installerScheme returns the scheme of the resources reconciled by the installer.
+func installerScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := apiextensionsv1beta1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}